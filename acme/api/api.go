@@ -3,9 +3,11 @@ package api
 import (
 	"bytes"
 	"crypto"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -15,9 +17,13 @@ import (
 	"github.com/go-acme/lego/v4/acme/api/internal/secure"
 	"github.com/go-acme/lego/v4/acme/api/internal/sender"
 	"github.com/go-acme/lego/v4/log"
+	jose "github.com/go-jose/go-jose/v4"
 )
 
 // Core ACME/LE core API.
+//
+// A Core is safe for concurrent use: the orders of an account can be created and solved by several goroutines sharing the Core
+// (the nonces are shared, each nonce is used only once).
 type Core struct {
 	doer         *sender.Doer
 	nonceManager *nonces.Manager
@@ -25,6 +31,16 @@ type Core struct {
 	directory    acme.Directory
 	HTTPClient   *http.Client
 
+	transportRetries int
+
+	freshAccountNonce bool
+	initialNonce      string
+	nonceSource       NonceSource
+
+	maxCertSize int64
+
+	rateLimits *rateLimitTracker
+
 	common         service // Reuse a single struct instead of allocating one for each service on the heap.
 	Accounts       *AccountService
 	Authorizations *AuthorizationService
@@ -33,20 +49,259 @@ type Core struct {
 	Orders         *OrderService
 }
 
-// New Creates a new Core.
-func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey crypto.PrivateKey) (*Core, error) {
-	doer := sender.NewDoer(httpClient, userAgent)
+// Option is an option of the Core.
+type Option func(*Core) error
 
-	dir, err := getDirectory(doer, caDirURL)
-	if err != nil {
-		return nil, err
+// WithInitialNonce provides a nonce (e.g. the last unused nonce of a previous process, see Core.PopNonce)
+// to use for the first request, instead of fetching a new nonce.
+// If the nonce is rejected by the server (badNonce), a new nonce is fetched.
+// It cannot be combined with WithNonceSource.
+func WithInitialNonce(nonce string) Option {
+	return func(c *Core) error {
+		c.initialNonce = nonce
+		return nil
 	}
+}
+
+// NonceSource provides the nonces of the signed requests (anti-replay nonces).
+// The default source fetches the nonces from the newNonce endpoint,
+// the nonces returned by the server (and the initial nonce, see WithInitialNonce) are used first.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-6.5
+type NonceSource = nonces.Source
+
+// WithNonceSource replaces the default nonce source (the newNonce endpoint) by the source.
+// All the nonces come from the source: the nonces returned by the server are not reused.
+// It allows deterministic nonces (e.g. in tests), or sharing a pool of nonces between clients.
+// The source is also used when a nonce is rejected by the server (badNonce),
+// and for the account creation with WithFreshAccountNonce.
+// It cannot be combined with WithInitialNonce.
+func WithNonceSource(source NonceSource) Option {
+	return func(c *Core) error {
+		if source == nil {
+			return errors.New("the nonce source cannot be nil")
+		}
 
-	nonceManager := nonces.NewManager(doer, dir.NewNonceURL)
+		c.nonceSource = source
+
+		return nil
+	}
+}
+
+// setupNonces configures the nonce manager with the nonce options.
+func (a *Core) setupNonces() error {
+	if a.nonceSource == nil {
+		if a.initialNonce != "" {
+			a.nonceManager.Push(a.initialNonce)
+		}
+
+		return nil
+	}
+
+	if a.initialNonce != "" {
+		return errors.New("the initial nonce cannot be used with a nonce source: all the nonces come from the source")
+	}
+
+	a.nonceManager.SetSource(a.nonceSource)
+
+	return nil
+}
+
+// WithFreshAccountNonce always uses a new nonce of the nonce source for the account creation (newAccount) requests,
+// for the CAs rejecting the cached nonces (see WithInitialNonce) on this endpoint.
+// The cached nonces are kept for the following requests.
+//
+// Without this option, the account creation, usually the first request of a session, uses the cached nonce if any:
+// if the nonce is rejected by the server (badNonce), the request is retried with the nonce of the error response, or a new nonce.
+func WithFreshAccountNonce() Option {
+	return func(c *Core) error {
+		c.freshAccountNonce = true
+		return nil
+	}
+}
+
+// WithTransportRetry enables the retry of the requests failing because of a transient transport error
+// (connection reset, timeout, etc.), up to attempts times.
+//
+// The GET, HEAD (newNonce), and POST-as-GET requests are always retried.
+// The other POST requests (e.g. finalize) are only retried when the request has not been sent,
+// because they may have been applied by the server.
+// The delay between the attempts starts at backoff and doubles at each attempt.
+func WithTransportRetry(attempts int, backoff time.Duration) Option {
+	return func(c *Core) error {
+		if attempts < 0 {
+			return fmt.Errorf("invalid number of transport retry attempts: %d", attempts)
+		}
+
+		c.transportRetries = attempts
+		c.doer.SetRetry(attempts, backoff)
+
+		return nil
+	}
+}
+
+// WithJWSAlgorithm overrides the algorithm used to sign the requests (e.g. "RS384"), instead of the one derived from the key.
+// The algorithm must be compatible with the account key.
+// This is only useful to interoperate with non-compliant servers.
+func WithJWSAlgorithm(alg string) Option {
+	return func(c *Core) error {
+		return c.jws.SetAlgorithm(alg)
+	}
+}
+
+// WithJWSExtraHeaders adds headers to the protected header of the signed requests.
+// The headers defined by RFC 8555 (alg, jwk, kid, nonce, url) cannot be overridden.
+// This is only useful to interoperate with non-compliant servers.
+func WithJWSExtraHeaders(headers map[string]interface{}) Option {
+	return func(c *Core) error {
+		return c.jws.SetExtraHeaders(headers)
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the TLS certificate of the ACME server.
+//
+// DANGEROUS: this option makes the connection vulnerable to man-in-the-middle attacks,
+// it must only be used for local development (e.g. with Pebble and its self-signed certificate).
+// Only the transport of the ACME client is modified (the HTTP client is cloned),
+// the transport must be an *http.Transport (or nil to use a clone of http.DefaultTransport).
+func WithInsecureSkipVerify() Option {
+	return func(c *Core) error {
+		err := c.updateTransport(func(transport *http.Transport) {
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{}
+			}
+
+			//nolint:gosec // explicitly requested, for development only.
+			transport.TLSClientConfig.InsecureSkipVerify = true
+		})
+		if err != nil {
+			return fmt.Errorf("insecure skip verify: %w", err)
+		}
+
+		log.Warnf("acme: the TLS certificate verification of the ACME server is disabled, this must only be used for development")
+
+		return nil
+	}
+}
+
+// WithConnectionPool defines the maximum number of idle (keep-alive) connections to the ACME server,
+// and enables the TLS session resumption (with a cache of the same size).
+// It reduces the latency of the high-volume issuance, by avoiding a TLS handshake per request.
+// Only the transport of the ACME client is modified (the HTTP client is cloned),
+// the transport must be an *http.Transport (or nil to use a clone of http.DefaultTransport).
+func WithConnectionPool(size int) Option {
+	return func(c *Core) error {
+		if size < 1 {
+			return fmt.Errorf("connection pool: invalid size: %d", size)
+		}
+
+		err := c.updateTransport(func(transport *http.Transport) {
+			transport.DisableKeepAlives = false
+			transport.MaxIdleConnsPerHost = size
+
+			if transport.MaxIdleConns != 0 && transport.MaxIdleConns < size {
+				transport.MaxIdleConns = size
+			}
+
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{}
+			}
+
+			transport.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(size)
+		})
+		if err != nil {
+			return fmt.Errorf("connection pool: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// WithMaxCertSize defines the maximum size (in bytes) of the certificates (chains) downloaded from the server (1 MiB by default).
+// The download is streamed and aborted as soon as the size is exceeded (ErrCertificateTooLarge),
+// to protect the process against a broken or malicious CA.
+// The size applies to the decompressed body.
+func WithMaxCertSize(size int) Option {
+	return func(c *Core) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid maximum certificate size: %d", size)
+		}
+
+		c.maxCertSize = int64(size)
+
+		return nil
+	}
+}
+
+// WithTranscriptWriter writes the transcript of the HTTP exchanges with the ACME server to w:
+// the request line, the headers, and the body of each request, and the status, the headers, and the body of each response.
+// This is only intended to debug the interoperability with a CA, and is independent of the logger.
+//
+// The sensitive headers (Authorization, Cookie, etc.) are always redacted.
+// The payload and the signature of the signed requests (JWS) are redacted, unless unsafe is true:
+// a full transcript contains the CSRs, the contacts, and the inner JWS of the key changes and the external account bindings.
+func WithTranscriptWriter(w io.Writer, unsafe bool) Option {
+	return func(c *Core) error {
+		if w == nil {
+			return errors.New("transcript: the writer cannot be nil")
+		}
+
+		if unsafe {
+			log.Warnf("acme: the HTTP transcript contains the signed payloads, it must be handled as a secret")
+		}
+
+		c.doer.SetTranscript(w, unsafe)
+
+		return nil
+	}
+}
+
+// updateTransport clones the HTTP client and its transport, and applies the update to the cloned transport.
+func (a *Core) updateTransport(update func(transport *http.Transport)) error {
+	if a.HTTPClient == nil {
+		return errors.New("the HTTP client cannot be nil")
+	}
+
+	var transport *http.Transport
+
+	switch t := a.HTTPClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return fmt.Errorf("unsupported transport type %T", a.HTTPClient.Transport)
+	}
+
+	update(transport)
+
+	client := *a.HTTPClient
+	client.Transport = transport
+
+	a.HTTPClient = &client
+	a.doer.SetHTTPClient(&client)
+
+	return nil
+}
+
+// New Creates a new Core.
+func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey crypto.PrivateKey, opts ...Option) (*Core, error) {
+	doer := sender.NewDoer(httpClient, userAgent)
+
+	// The nonce URL is defined after the directory fetching.
+	nonceManager := nonces.NewManager(doer, "")
 
 	jws := secure.NewJWS(privateKey, kid, nonceManager)
 
-	c := &Core{doer: doer, nonceManager: nonceManager, jws: jws, directory: dir, HTTPClient: httpClient}
+	c := &Core{
+		doer:         doer,
+		nonceManager: nonceManager,
+		jws:          jws,
+		HTTPClient:   httpClient,
+		rateLimits:   newRateLimitTracker(),
+		maxCertSize:  maxBodySize,
+	}
+
+	doer.SetResponseObserver(c.rateLimits.observe)
 
 	c.common.core = c
 	c.Accounts = (*AccountService)(&c.common)
@@ -55,6 +310,27 @@ func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey cr
 	c.Challenges = (*ChallengeService)(&c.common)
 	c.Orders = (*OrderService)(&c.common)
 
+	// The options are applied before the directory fetching, because they can modify the transport.
+	for _, opt := range opts {
+		err := opt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := c.setupNonces()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := getDirectory(doer, caDirURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.directory = dir
+	nonceManager.SetNonceURL(dir.NewNonceURL)
+
 	return c, nil
 }
 
@@ -83,6 +359,8 @@ func (a *Core) retrievablePost(uri string, content []byte, response interface{})
 	bo.MaxElapsedTime = 20 * time.Second
 
 	var resp *http.Response
+	var transportAttempts int
+	var algorithmNegotiated bool
 	operation := func() error {
 		var err error
 		resp, err = a.signedPost(uri, content, response)
@@ -93,6 +371,27 @@ func (a *Core) retrievablePost(uri string, content []byte, response interface{})
 				return err
 			}
 
+			// Retry once with an algorithm supported by the server.
+			var problem *acme.ProblemDetails
+			if !algorithmNegotiated && errors.As(err, &problem) && problem.Type == acme.BadSignatureAlgorithmErr {
+				algorithmNegotiated = true
+
+				alg, errN := a.jws.NegotiateAlgorithm(problem.Algorithms)
+				if errN != nil {
+					return backoff.Permanent(fmt.Errorf("%w: %w", errN, err))
+				}
+
+				log.Infof("acme: the server rejected the signature algorithm, switching to %s", alg)
+
+				return err
+			}
+
+			// POST-as-GET requests are idempotent: retry on transient transport errors (with a new nonce).
+			if len(content) == 0 && transportAttempts < a.transportRetries && sender.IsTransientError(err) {
+				transportAttempts++
+				return err
+			}
+
 			return backoff.Permanent(err)
 		}
 
@@ -112,7 +411,14 @@ func (a *Core) retrievablePost(uri string, content []byte, response interface{})
 }
 
 func (a *Core) signedPost(uri string, content []byte, response interface{}) (*http.Response, error) {
-	signedContent, err := a.jws.SignContent(uri, content)
+	var signedContent *jose.JSONWebSignature
+	var err error
+
+	if a.freshAccountNonce && uri == a.directory.NewAccountURL {
+		signedContent, err = a.jws.SignContentWithNonceSource(uri, content, a.nonceManager.Fresh())
+	} else {
+		signedContent, err = a.jws.SignContent(uri, content)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to post JWS message: failed to sign content: %w", err)
 	}
@@ -139,11 +445,22 @@ func (a *Core) signEABContent(newAccountURL, kid string, hmac []byte) ([]byte, e
 	return []byte(eabJWS.FullSerialize()), nil
 }
 
+// PopNonce removes and returns an unused nonce, if any.
+// The nonce can be reused by another process through WithInitialNonce.
+func (a *Core) PopNonce() (string, bool) {
+	return a.nonceManager.Pop()
+}
+
 // GetKeyAuthorization Gets the key authorization.
 func (a *Core) GetKeyAuthorization(token string) (string, error) {
 	return a.jws.GetKeyAuthorization(token)
 }
 
+// GetAccountURI returns the URI of the account (empty if the account is not registered yet).
+func (a *Core) GetAccountURI() string {
+	return a.jws.GetKid()
+}
+
 func (a *Core) GetDirectory() acme.Directory {
 	return a.directory
 }
@@ -0,0 +1,829 @@
+package api
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nonceServer is a fake ACME server recording the nonces used by the signed requests.
+type nonceServer struct {
+	URL string
+
+	mu          sync.Mutex
+	fetched     int
+	usedNonces  []string
+	validNonces map[string]bool
+}
+
+func setupNonceServer(t *testing.T, validNonces ...string) *nonceServer {
+	t.Helper()
+
+	ns := &nonceServer{validNonces: map[string]bool{"fetched": true}}
+	for _, nonce := range validNonces {
+		ns.validNonces[nonce] = true
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	ns.URL = server.URL
+
+	mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.Directory{
+			NewNonceURL:   server.URL + "/nonce",
+			NewAccountURL: server.URL + "/account",
+			NewOrderURL:   server.URL + "/newOrder",
+		})
+	})
+
+	mux.HandleFunc("HEAD /nonce", func(w http.ResponseWriter, _ *http.Request) {
+		ns.mu.Lock()
+		ns.fetched++
+		ns.mu.Unlock()
+
+		w.Header().Set("Replay-Nonce", "fetched")
+	})
+
+	mux.HandleFunc("POST /order/1", ns.handle(acme.Order{Status: acme.StatusValid}))
+
+	mux.HandleFunc("POST /account", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Location", server.URL+"/account/1")
+		ns.handle(acme.Account{Status: acme.StatusValid})(w, req)
+	})
+
+	return ns
+}
+
+// handle verifies the nonce of the signed request, and writes the response (or a badNonce error).
+func (ns *nonceServer) handle(response any) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		jws, err := jose.ParseSigned(string(body), []jose.SignatureAlgorithm{jose.RS256})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		nonce := jws.Signatures[0].Protected.Nonce
+
+		ns.mu.Lock()
+		ns.usedNonces = append(ns.usedNonces, nonce)
+		valid := ns.validNonces[nonce]
+		ns.mu.Unlock()
+
+		if !valid {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+
+			_ = json.NewEncoder(w).Encode(acme.ProblemDetails{Type: acme.BadNonceErr, Detail: "bad nonce"})
+
+			return
+		}
+
+		_ = tester.WriteJSONResponse(w, response)
+	}
+}
+
+func TestWithInitialNonce(t *testing.T) {
+	ns := setupNonceServer(t, "initial")
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey, WithInitialNonce("initial"))
+	require.NoError(t, err)
+
+	_, err = core.Orders.Get(ns.URL + "/order/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"initial"}, ns.usedNonces)
+	assert.Zero(t, ns.fetched)
+}
+
+func TestWithInitialNonce_badNonce(t *testing.T) {
+	ns := setupNonceServer(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey, WithInitialNonce("expired"))
+	require.NoError(t, err)
+
+	_, err = core.Orders.Get(ns.URL + "/order/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"expired", "fetched"}, ns.usedNonces)
+	assert.Equal(t, 1, ns.fetched)
+}
+
+func TestWithInitialNonce_newAccount(t *testing.T) {
+	ns := setupNonceServer(t, "initial")
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey, WithInitialNonce("initial"))
+	require.NoError(t, err)
+
+	account, err := core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, ns.URL+"/account/1", account.Location)
+	assert.Equal(t, []string{"initial"}, ns.usedNonces)
+	assert.Zero(t, ns.fetched)
+}
+
+func TestWithInitialNonce_newAccount_badNonce(t *testing.T) {
+	ns := setupNonceServer(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey, WithInitialNonce("expired"))
+	require.NoError(t, err)
+
+	account, err := core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, ns.URL+"/account/1", account.Location)
+	assert.Equal(t, []string{"expired", "fetched"}, ns.usedNonces)
+	assert.Equal(t, 1, ns.fetched)
+}
+
+func TestWithFreshAccountNonce(t *testing.T) {
+	ns := setupNonceServer(t, "initial")
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey,
+		WithInitialNonce("initial"), WithFreshAccountNonce())
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	// the cached nonce is kept for the next request.
+	_, err = core.Orders.Get(ns.URL + "/order/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"fetched", "initial"}, ns.usedNonces)
+	assert.Equal(t, 1, ns.fetched)
+}
+
+func TestCore_PopNonce(t *testing.T) {
+	ns := setupNonceServer(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey, WithInitialNonce("initial"))
+	require.NoError(t, err)
+
+	nonce, ok := core.PopNonce()
+	require.True(t, ok)
+	assert.Equal(t, "initial", nonce)
+
+	_, ok = core.PopNonce()
+	assert.False(t, ok)
+}
+
+func TestWithJWSAlgorithm_incompatible(t *testing.T) {
+	ns := setupNonceServer(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	_, err = New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey, WithJWSAlgorithm("ES256"))
+	require.Error(t, err)
+}
+
+func TestWithTransportRetry(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	var orderCalls, finalizeCalls atomic.Int32
+
+	reset := func(w http.ResponseWriter) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		_ = conn.(*net.TCPConn).SetLinger(0)
+		_ = conn.Close()
+	}
+
+	mux.HandleFunc("POST /order/1", func(w http.ResponseWriter, _ *http.Request) {
+		if orderCalls.Add(1) <= 2 {
+			reset(w)
+			return
+		}
+
+		w.Header().Set("Replay-Nonce", "12345")
+		_ = tester.WriteJSONResponse(w, acme.Order{Status: acme.StatusValid})
+	})
+
+	mux.HandleFunc("POST /finalize/1", func(w http.ResponseWriter, _ *http.Request) {
+		finalizeCalls.Add(1)
+		reset(w)
+	})
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	core, err := New(client, "lego-test", apiURL+"/dir", "", privateKey, WithTransportRetry(3, time.Millisecond))
+	require.NoError(t, err)
+
+	// POST-as-GET: retried.
+	order, err := core.Orders.Get(apiURL + "/order/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, acme.StatusValid, order.Status)
+	assert.Equal(t, int32(3), orderCalls.Load())
+
+	// finalize: not retried because the request may have been applied.
+	_, err = core.Orders.UpdateForCSR(apiURL+"/finalize/1", []byte("csr"))
+	require.Error(t, err)
+
+	assert.Equal(t, int32(1), finalizeCalls.Load())
+}
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.Directory{
+			NewNonceURL:   server.URL + "/nonce",
+			NewAccountURL: server.URL + "/account",
+			NewOrderURL:   server.URL + "/newOrder",
+		})
+	})
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+
+	// The certificate of the server is self-signed.
+	_, err = New(client, "lego-test", server.URL+"/dir", "", privateKey)
+	require.ErrorContains(t, err, "certificate")
+
+	core, err := New(client, "lego-test", server.URL+"/dir", "", privateKey, WithInsecureSkipVerify())
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL+"/newOrder", core.GetDirectory().NewOrderURL)
+
+	// The original client is not modified.
+	assert.NotSame(t, client, core.HTTPClient)
+	if tlsConfig := client.Transport.(*http.Transport).TLSClientConfig; tlsConfig != nil {
+		assert.False(t, tlsConfig.InsecureSkipVerify)
+	}
+
+	_, err = New(client, "lego-test", server.URL+"/dir", "", privateKey)
+	require.ErrorContains(t, err, "certificate")
+}
+
+func TestWithConnectionPool(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewUnstartedServer(mux)
+
+	var newConns, resumed atomic.Int32
+
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.Directory{
+			NewNonceURL:   server.URL + "/nonce",
+			NewAccountURL: server.URL + "/account",
+			NewOrderURL:   server.URL + "/newOrder",
+		})
+	})
+
+	mux.HandleFunc("HEAD /nonce", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Replay-Nonce", "12345")
+	})
+
+	mux.HandleFunc("POST /order/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && r.TLS.DidResume {
+			resumed.Add(1)
+		}
+
+		w.Header().Set("Replay-Nonce", "12345")
+		_ = tester.WriteJSONResponse(w, acme.Order{Status: acme.StatusValid})
+	})
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}}}
+
+	core, err := New(client, "lego-test", server.URL+"/dir", "", privateKey, WithConnectionPool(4))
+	require.NoError(t, err)
+
+	transport := core.HTTPClient.Transport.(*http.Transport)
+	assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+	assert.NotNil(t, transport.TLSClientConfig.ClientSessionCache)
+
+	for range 20 {
+		_, err = core.Orders.Get(server.URL + "/order/1")
+		require.NoError(t, err)
+	}
+
+	// A single connection (and TLS handshake) for all the requests.
+	assert.EqualValues(t, 1, newConns.Load())
+
+	// The new connections resume the TLS session.
+	core.HTTPClient.CloseIdleConnections()
+
+	_, err = core.Orders.Get(server.URL + "/order/1")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, newConns.Load())
+	assert.EqualValues(t, 1, resumed.Load())
+}
+
+func TestWithConnectionPool_invalidSize(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	_, err = New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey, WithConnectionPool(0))
+	require.EqualError(t, err, "connection pool: invalid size: 0")
+}
+
+func TestWithTranscriptWriter(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		unsafe bool
+	}{
+		{desc: "redacted"},
+		{desc: "unsafe", unsafe: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			ns := setupNonceServer(t)
+
+			privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+			require.NoError(t, err)
+
+			buf := &bytes.Buffer{}
+
+			core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey, WithTranscriptWriter(buf, test.unsafe))
+			require.NoError(t, err)
+
+			account, err := core.Accounts.New(acme.Account{Contact: []string{"mailto:secret@example.com"}, TermsOfServiceAgreed: true})
+			require.NoError(t, err)
+
+			assert.Equal(t, ns.URL+"/account/1", account.Location)
+
+			transcript := buf.String()
+
+			assert.Contains(t, transcript, "--> GET "+ns.URL+"/dir\n")
+			assert.Contains(t, transcript, "<-- 200 OK GET "+ns.URL+"/dir\n")
+			assert.Contains(t, transcript, "--> HEAD "+ns.URL+"/nonce\n")
+			assert.Contains(t, transcript, "Replay-Nonce: fetched\n")
+			assert.Contains(t, transcript, "--> POST "+ns.URL+"/account\n")
+			assert.Contains(t, transcript, "Content-Type: application/jose+json\n")
+			assert.Contains(t, transcript, "<-- 200 OK POST "+ns.URL+"/account\n")
+			assert.Contains(t, transcript, "Location: "+ns.URL+"/account/1\n")
+			assert.Contains(t, transcript, `"status":"valid"`)
+
+			payload := base64.RawURLEncoding.EncodeToString([]byte(`{"contact":["mailto:secret@example.com"],"termsOfServiceAgreed":true}`))
+
+			if test.unsafe {
+				assert.Contains(t, transcript, payload)
+			} else {
+				assert.NotContains(t, transcript, payload)
+				assert.Contains(t, transcript, `"payload":"[REDACTED]"`)
+				assert.Contains(t, transcript, `"url":"`+ns.URL+`/account"`)
+			}
+		})
+	}
+}
+
+func TestWithTranscriptWriter_nil(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	_, err = New(http.DefaultClient, "lego-test", "http://127.0.0.1/dir", "", privateKey, WithTranscriptWriter(nil, false))
+	require.EqualError(t, err, "transcript: the writer cannot be nil")
+}
+
+func TestCore_badSignatureAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		key           crypto.PrivateKey
+		supported     []string
+		expectedAlgs  []string
+		expectedError string
+	}{
+		{
+			desc:         "retry with a compatible algorithm",
+			key:          rsaKey,
+			supported:    []string{"ES256", "PS256"},
+			expectedAlgs: []string{"RS256", "PS256"},
+		},
+		{
+			desc:         "no compatible algorithm",
+			key:          ecKey,
+			supported:    []string{"RS256", "PS256"},
+			expectedAlgs: []string{"ES256"},
+			expectedError: "none of the signature algorithms supported by the server [RS256, PS256] is compatible with the key (*ecdsa.PrivateKey) and different from ES256: " +
+				"acme: error: 400 :: POST :: %s/order/1 :: urn:ietf:params:acme:error:badSignatureAlgorithm :: unsupported algorithm",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+				_ = tester.WriteJSONResponse(w, acme.Directory{
+					NewNonceURL:   server.URL + "/nonce",
+					NewAccountURL: server.URL + "/account",
+					NewOrderURL:   server.URL + "/newOrder",
+				})
+			})
+
+			mux.HandleFunc("HEAD /nonce", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Replay-Nonce", "nonce")
+			})
+
+			var mu sync.Mutex
+			var algs []string
+
+			mux.HandleFunc("POST /order/1", func(w http.ResponseWriter, req *http.Request) {
+				body, _ := io.ReadAll(req.Body)
+
+				jws, err := jose.ParseSigned(string(body), []jose.SignatureAlgorithm{jose.RS256, jose.PS256, jose.ES256})
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				alg := jws.Signatures[0].Protected.Algorithm
+
+				mu.Lock()
+				algs = append(algs, alg)
+				mu.Unlock()
+
+				w.Header().Set("Replay-Nonce", "nonce")
+
+				if !slices.Contains(test.supported, alg) {
+					w.Header().Set("Content-Type", "application/problem+json")
+					w.WriteHeader(http.StatusBadRequest)
+
+					_ = json.NewEncoder(w).Encode(acme.ProblemDetails{
+						Type:       acme.BadSignatureAlgorithmErr,
+						Detail:     "unsupported algorithm",
+						Algorithms: test.supported,
+					})
+
+					return
+				}
+
+				_ = tester.WriteJSONResponse(w, acme.Order{Status: acme.StatusValid})
+			})
+
+			core, err := New(http.DefaultClient, "lego-test", server.URL+"/dir", "", test.key)
+			require.NoError(t, err)
+
+			_, err = core.Orders.Get(server.URL + "/order/1")
+			if test.expectedError != "" {
+				require.EqualError(t, err, fmt.Sprintf(test.expectedError, server.URL))
+			} else {
+				require.NoError(t, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			assert.Equal(t, test.expectedAlgs, algs)
+		})
+	}
+}
+
+// scriptedNonceSource returns the nonces in order.
+type scriptedNonceSource struct {
+	mu     sync.Mutex
+	nonces []string
+}
+
+func (s *scriptedNonceSource) Nonce() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.nonces) == 0 {
+		return "", errors.New("no more nonces")
+	}
+
+	nonce := s.nonces[0]
+	s.nonces = s.nonces[1:]
+
+	return nonce, nil
+}
+
+func TestWithNonceSource(t *testing.T) {
+	ns := setupNonceServer(t, "nonce-1", "nonce-2", "nonce-4")
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	source := &scriptedNonceSource{nonces: []string{"nonce-1", "nonce-2", "nonce-3", "nonce-4"}}
+
+	// the account creation with a fresh nonce uses the source too.
+	core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey,
+		WithFreshAccountNonce(), WithNonceSource(source))
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	_, err = core.Orders.Get(ns.URL + "/order/1")
+	require.NoError(t, err)
+
+	// nonce-3 is rejected (badNonce): the request is retried with the next nonce of the source.
+	_, err = core.Orders.Get(ns.URL + "/order/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"nonce-1", "nonce-2", "nonce-3", "nonce-4"}, ns.usedNonces)
+	assert.Zero(t, ns.fetched)
+
+	_, err = core.Orders.Get(ns.URL + "/order/1")
+	require.ErrorContains(t, err, "no more nonces")
+}
+
+func TestWithNonceSource_nil(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	_, err = New(http.DefaultClient, "lego-test", "http://127.0.0.1/dir", "", privateKey, WithNonceSource(nil))
+	require.EqualError(t, err, "the nonce source cannot be nil")
+}
+
+func TestWithNonceSource_initialNonce(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	source := &scriptedNonceSource{nonces: []string{"nonce-1"}}
+
+	_, err = New(http.DefaultClient, "lego-test", "http://127.0.0.1/dir", "", privateKey,
+		WithNonceSource(source), WithInitialNonce("initial"))
+	require.EqualError(t, err, "the initial nonce cannot be used with a nonce source: all the nonces come from the source")
+}
+
+// strictNonceServer is a fake ACME server accepting each nonce only once (like a real CA),
+// and recording the badNonce errors and the requests signed with an unexpected key identifier.
+type strictNonceServer struct {
+	URL string
+
+	mu        sync.Mutex
+	issued    int
+	valid     map[string]bool
+	badNonces int
+	badKIDs   int
+	orders    int
+}
+
+func setupStrictNonceServer(t *testing.T) *strictNonceServer {
+	t.Helper()
+
+	ns := &strictNonceServer{valid: make(map[string]bool)}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	ns.URL = server.URL
+
+	mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.Directory{
+			NewNonceURL:   server.URL + "/nonce",
+			NewAccountURL: server.URL + "/account",
+			NewOrderURL:   server.URL + "/newOrder",
+		})
+	})
+
+	mux.HandleFunc("HEAD /nonce", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Replay-Nonce", ns.newNonce())
+	})
+
+	mux.HandleFunc("POST /account", func(w http.ResponseWriter, req *http.Request) {
+		if !ns.verify(w, req, "") {
+			return
+		}
+
+		w.Header().Set("Location", server.URL+"/account/1")
+		w.WriteHeader(http.StatusCreated)
+
+		_ = json.NewEncoder(w).Encode(acme.Account{Status: acme.StatusValid})
+	})
+
+	mux.HandleFunc("POST /newOrder", func(w http.ResponseWriter, req *http.Request) {
+		if !ns.verify(w, req, server.URL+"/account/1") {
+			return
+		}
+
+		ns.mu.Lock()
+		ns.orders++
+		id := ns.orders
+		ns.mu.Unlock()
+
+		w.Header().Set("Location", fmt.Sprintf("%s/order/%d", server.URL, id))
+		w.WriteHeader(http.StatusCreated)
+
+		_ = json.NewEncoder(w).Encode(acme.Order{
+			Status:         acme.StatusPending,
+			Authorizations: []string{fmt.Sprintf("%s/authz/%d", server.URL, id)},
+		})
+	})
+
+	mux.HandleFunc("POST /order/{id}", func(w http.ResponseWriter, req *http.Request) {
+		if !ns.verify(w, req, server.URL+"/account/1") {
+			return
+		}
+
+		_ = tester.WriteJSONResponse(w, acme.Order{Status: acme.StatusReady})
+	})
+
+	mux.HandleFunc("POST /authz/{id}", func(w http.ResponseWriter, req *http.Request) {
+		if !ns.verify(w, req, server.URL+"/account/1") {
+			return
+		}
+
+		_ = tester.WriteJSONResponse(w, acme.Authorization{Status: acme.StatusValid})
+	})
+
+	return ns
+}
+
+func (ns *strictNonceServer) newNonce() string {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	ns.issued++
+	nonce := fmt.Sprintf("nonce-%d", ns.issued)
+	ns.valid[nonce] = true
+
+	return nonce
+}
+
+// verify consumes the nonce of the signed request, and checks its key identifier.
+func (ns *strictNonceServer) verify(w http.ResponseWriter, req *http.Request, kid string) bool {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	jws, err := jose.ParseSigned(string(body), []jose.SignatureAlgorithm{jose.ES256})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	protected := jws.Signatures[0].Protected
+
+	ns.mu.Lock()
+	valid := ns.valid[protected.Nonce]
+	delete(ns.valid, protected.Nonce)
+
+	if !valid {
+		ns.badNonces++
+	}
+
+	if protected.KeyID != kid {
+		ns.badKIDs++
+	}
+	ns.mu.Unlock()
+
+	w.Header().Set("Replay-Nonce", ns.newNonce())
+
+	if !valid {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		_ = json.NewEncoder(w).Encode(acme.ProblemDetails{Type: acme.BadNonceErr, Detail: "bad nonce"})
+
+		return false
+	}
+
+	return true
+}
+
+// The Core is shared by the goroutines (one account), each goroutine creates and polls its own orders.
+// To be run with -race.
+func TestCore_concurrentOrders(t *testing.T) {
+	ns := setupStrictNonceServer(t)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	core, err := New(http.DefaultClient, "lego-test", ns.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	const (
+		workers = 20
+		orders  = 10
+	)
+
+	errs := make(chan error, workers*orders)
+
+	var wg sync.WaitGroup
+
+	for i := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range orders {
+				order, errO := core.Orders.New([]string{fmt.Sprintf("%d-%d.example.com", i, j)})
+				if errO != nil {
+					errs <- errO
+					continue
+				}
+
+				_, errO = core.Authorizations.Get(order.Authorizations[0])
+				if errO != nil {
+					errs <- errO
+					continue
+				}
+
+				_, errO = core.Orders.Get(order.Location)
+				if errO != nil {
+					errs <- errO
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for errO := range errs {
+		assert.NoError(t, errO)
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	assert.Equal(t, workers*orders, ns.orders)
+	assert.Zero(t, ns.badNonces)
+	assert.Zero(t, ns.badKIDs)
+}
@@ -6,8 +6,35 @@ import (
 	"github.com/go-acme/lego/v4/acme"
 )
 
+// ErrNoPreAuthorization is returned when the server doesn't support the pre-authorization (newAuthz).
+var ErrNoPreAuthorization = errors.New("authorization[new]: server does not support pre-authorization (newAuthz)")
+
 type AuthorizationService service
 
+// New creates an authorization for the identifier, outside of any order (pre-authorization).
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.4.1
+func (c *AuthorizationService) New(identifier acme.Identifier) (acme.ExtendedAuthorization, error) {
+	newAuthzURL := c.core.GetDirectory().NewAuthzURL
+	if newAuthzURL == "" {
+		return acme.ExtendedAuthorization{}, ErrNoPreAuthorization
+	}
+
+	req := struct {
+		Identifier acme.Identifier `json:"identifier"`
+	}{Identifier: identifier}
+
+	var authz acme.Authorization
+	resp, err := c.core.post(newAuthzURL, req, &authz)
+	if err != nil {
+		return acme.ExtendedAuthorization{}, err
+	}
+
+	return acme.ExtendedAuthorization{
+		Authorization: authz,
+		Location:      resp.Header.Get("Location"),
+	}, nil
+}
+
 // Get Gets an authorization.
 func (c *AuthorizationService) Get(authzURL string) (acme.Authorization, error) {
 	if authzURL == "" {
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
@@ -16,6 +17,9 @@ import (
 // maxBodySize is the maximum size of body that we will read.
 const maxBodySize = 1024 * 1024
 
+// ErrCertificateTooLarge is returned when the certificate (chain) returned by the server exceeds the maximum size (see WithMaxCertSize).
+var ErrCertificateTooLarge = errors.New("the certificate exceeds the maximum size")
+
 type CertificateService service
 
 // Get Returns the certificate and the issuer certificate.
@@ -55,6 +59,40 @@ func (c *CertificateService) GetAll(certURL string, bundle bool) (map[string]*ac
 	return certs, nil
 }
 
+// GetShortTerm Returns the current short-term certificate of a STAR order, and the issuer certificate.
+// If 'allowGet' is true (the order has been created with allow-certificate-get),
+// the certificate is fetched with an unauthenticated GET request, otherwise with a POST-as-GET request.
+// 'bundle' is only applied if the issuer is provided by the 'up' link.
+// - https://www.rfc-editor.org/rfc/rfc8739.html#section-3.3
+func (c *CertificateService) GetShortTerm(starCertURL string, bundle, allowGet bool) ([]byte, []byte, error) {
+	if !allowGet {
+		return c.Get(starCertURL, bundle)
+	}
+
+	if starCertURL == "" {
+		return nil, nil, errors.New("certificate[get]: empty URL")
+	}
+
+	resp, err := c.core.HTTPClient.Get(starCertURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("certificate[get]: unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := c.readCertificate(resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("certificate[get]: %w", err)
+	}
+
+	cert := c.getCertificateChain(data, resp.Header, bundle, starCertURL)
+
+	return cert.Cert, cert.Issuer, nil
+}
+
 // Revoke Revokes a certificate.
 func (c *CertificateService) Revoke(req acme.RevokeCertMessage) error {
 	_, err := c.core.post(c.core.GetDirectory().RevokeCertURL, req, nil)
@@ -72,9 +110,11 @@ func (c *CertificateService) get(certURL string, bundle bool) (*acme.RawCertific
 		return nil, nil, err
 	}
 
-	data, err := io.ReadAll(http.MaxBytesReader(nil, resp.Body, maxBodySize))
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := c.readCertificate(resp)
 	if err != nil {
-		return nil, resp.Header, err
+		return nil, resp.Header, fmt.Errorf("certificate[get]: %w", err)
 	}
 
 	cert := c.getCertificateChain(data, resp.Header, bundle, certURL)
@@ -83,11 +123,12 @@ func (c *CertificateService) get(certURL string, bundle bool) (*acme.RawCertific
 }
 
 // getCertificateChain Returns the certificate and the issuer certificate.
+// The issuer certificate never contains the leaf certificate.
 func (c *CertificateService) getCertificateChain(cert []byte, headers http.Header, bundle bool, certURL string) *acme.RawCertificate {
 	// Get issuerCert from bundled response from Let's Encrypt
 	// See https://community.letsencrypt.org/t/acme-v2-no-up-link-in-response/64962
-	_, issuer := pem.Decode(cert)
-	if issuer != nil {
+	block, issuer := pem.Decode(cert)
+	if block != nil && len(bytes.TrimSpace(issuer)) > 0 {
 		// If bundle is false, we want to return a single certificate.
 		// To do this, we remove the issuer cert(s) from the issued cert.
 		if !bundle {
@@ -117,6 +158,7 @@ func (c *CertificateService) getCertificateChain(cert []byte, headers http.Heade
 }
 
 // getIssuerFromLink requests the issuer certificate.
+// The response can be a PEM chain (application/pem-certificate-chain) or a DER certificate (application/pkix-cert).
 func (c *CertificateService) getIssuerFromLink(up string) ([]byte, error) {
 	if up == "" {
 		return nil, nil
@@ -124,15 +166,52 @@ func (c *CertificateService) getIssuerFromLink(up string) ([]byte, error) {
 
 	log.Infof("acme: Requesting issuer cert from %s", up)
 
-	cert, _, err := c.get(up, false)
+	resp, err := c.core.postAsGet(up, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = x509.ParseCertificate(cert.Cert)
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := c.readCertificate(resp)
 	if err != nil {
 		return nil, err
 	}
 
-	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(cert.Cert)), nil
+	if block, _ := pem.Decode(data); block != nil {
+		_, err = certcrypto.ParsePEMBundle(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return data, nil
+	}
+
+	_, err = x509.ParseCertificate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(data)), nil
+}
+
+// readCertificate reads the body of the response, up to the maximum size of the certificates (see WithMaxCertSize).
+// The body is streamed: a larger response is rejected without being entirely read.
+func (c *CertificateService) readCertificate(resp *http.Response) ([]byte, error) {
+	limit := c.core.maxCertSize
+
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("%w: %d bytes (maximum %d bytes)", ErrCertificateTooLarge, resp.ContentLength, limit)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w (maximum %d bytes)", ErrCertificateTooLarge, limit)
+	}
+
+	return data, nil
 }
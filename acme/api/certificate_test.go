@@ -5,8 +5,11 @@ import (
 	"crypto/rsa"
 	"encoding/pem"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -128,3 +131,184 @@ func TestCertificateService_Get_embeddedIssuer(t *testing.T) {
 	assert.Equal(t, certResponseMock, string(cert), "Certificate")
 	assert.Equal(t, issuerMock, string(issuer), "IssuerCertificate")
 }
+
+func TestCertificateService_Get_separateIssuer(t *testing.T) {
+	leafMock := strings.TrimSuffix(certResponseMock, issuerMock)
+
+	testCases := []struct {
+		desc   string
+		issuer func() []byte
+	}{
+		{
+			desc:   "PEM issuer",
+			issuer: func() []byte { return []byte(issuerMock) },
+		},
+		{
+			desc: "DER issuer",
+			issuer: func() []byte {
+				p, _ := pem.Decode([]byte(issuerMock))
+				return p.Bytes
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			mux, apiURL := tester.SetupFakeAPI(t)
+
+			// the CA returns only the leaf certificate, the issuer is provided by the "up" link.
+			mux.HandleFunc("/certificate", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Link", "<"+apiURL+`/issuer>; rel="up"`)
+				_, _ = w.Write([]byte(leafMock))
+			})
+
+			mux.HandleFunc("/issuer", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write(test.issuer())
+			})
+
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			require.NoError(t, err, "Could not generate test key")
+
+			core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+			require.NoError(t, err)
+
+			cert, issuer, err := core.Certificates.Get(apiURL+"/certificate", false)
+			require.NoError(t, err)
+
+			assert.Equal(t, leafMock, string(cert), "Certificate")
+			assert.Equal(t, issuerMock, string(issuer), "IssuerCertificate")
+
+			assertIssuerChain(t, cert, issuer)
+
+			bundle, issuer, err := core.Certificates.Get(apiURL+"/certificate", true)
+			require.NoError(t, err)
+
+			assert.Equal(t, certResponseMock, string(bundle), "Certificate")
+			assert.Equal(t, issuerMock, string(issuer), "IssuerCertificate")
+		})
+	}
+}
+
+func TestCertificateService_Get_embeddedIssuer_notBundled(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("/certificate", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(certResponseMock))
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	cert, issuer, err := core.Certificates.Get(apiURL+"/certificate", false)
+	require.NoError(t, err)
+
+	assertIssuerChain(t, cert, issuer)
+}
+
+// assertIssuerChain asserts that the issuer chain excludes the leaf certificate, and starts with the issuer of the leaf.
+func assertIssuerChain(t *testing.T, cert, issuer []byte) {
+	t.Helper()
+
+	leaf, err := certcrypto.ParsePEMCertificate(cert)
+	require.NoError(t, err)
+
+	chain, err := certcrypto.ParsePEMBundle(issuer)
+	require.NoError(t, err)
+	require.NotEmpty(t, chain)
+
+	for _, c := range chain {
+		assert.False(t, c.Equal(leaf), "the issuer chain contains the leaf certificate")
+	}
+
+	assert.Equal(t, leaf.RawIssuer, chain[0].RawSubject)
+	require.NoError(t, leaf.CheckSignatureFrom(chain[0]))
+}
+
+func TestCertificateService_GetShortTerm(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("/star/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		_, err := w.Write([]byte(certResponseMock))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	cert, issuer, err := core.Certificates.GetShortTerm(apiURL+"/star/1", false, true)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode([]byte(certResponseMock))
+	assert.Equal(t, string(pem.EncodeToMemory(block)), string(cert), "Certificate")
+	assert.Equal(t, issuerMock, string(issuer), "IssuerCertificate")
+
+	_, _, err = core.Certificates.GetShortTerm(apiURL+"/star/2", false, true)
+	require.EqualError(t, err, "certificate[get]: unexpected status code: 404")
+}
+
+func TestCertificateService_Get_maxCertSize(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		chunked  bool
+		expected string
+	}{
+		{
+			desc:     "content length",
+			expected: "certificate[get]: the certificate exceeds the maximum size: 2242 bytes (maximum 2048 bytes)",
+		},
+		{
+			desc:     "streamed",
+			chunked:  true,
+			expected: "certificate[get]: the certificate exceeds the maximum size (maximum 2048 bytes)",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			mux, apiURL := tester.SetupFakeAPI(t)
+
+			mux.HandleFunc("/certificate", func(w http.ResponseWriter, _ *http.Request) {
+				if test.chunked {
+					// no Content-Length: the response is streamed.
+					_, _ = w.Write([]byte(certResponseMock[:100]))
+					w.(http.Flusher).Flush()
+					_, _ = w.Write([]byte(certResponseMock[100:]))
+
+					return
+				}
+
+				w.Header().Set("Content-Length", strconv.Itoa(len(certResponseMock)))
+				_, _ = w.Write([]byte(certResponseMock))
+			})
+
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			require.NoError(t, err, "Could not generate test key")
+
+			core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key, WithMaxCertSize(2048))
+			require.NoError(t, err)
+
+			_, _, err = core.Certificates.Get(apiURL+"/certificate", true)
+			require.ErrorIs(t, err, ErrCertificateTooLarge)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestWithMaxCertSize_invalid(t *testing.T) {
+	err := WithMaxCertSize(0)(&Core{})
+	require.EqualError(t, err, "invalid maximum certificate size: 0")
+}
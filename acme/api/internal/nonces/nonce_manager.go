@@ -9,20 +9,55 @@ import (
 	"github.com/go-acme/lego/v4/acme/api/internal/sender"
 )
 
+// Source provides nonces (same as jose.NonceSource).
+type Source interface {
+	Nonce() (string, error)
+}
+
 // Manager Manages nonces.
+// The nonces returned by the server are stored (see Push), and used before the nonces of the source:
+// by default, the nonces fetched from the newNonce endpoint (see SetSource).
 type Manager struct {
 	do       *sender.Doer
 	nonceURL string
 	nonces   []string
+	source   Source
+	// false when the nonces only come from the source (see SetSource).
+	store bool
 	sync.Mutex
 }
 
 // NewManager Creates a new Manager.
 func NewManager(do *sender.Doer, nonceURL string) *Manager {
-	return &Manager{
+	n := &Manager{
 		do:       do,
 		nonceURL: nonceURL,
+		store:    true,
 	}
+
+	n.source = &fetchSource{manager: n}
+
+	return n
+}
+
+// SetNonceURL sets the URL used to fetch new nonces.
+func (n *Manager) SetNonceURL(nonceURL string) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.nonceURL = nonceURL
+}
+
+// SetSource replaces the default source (the newNonce endpoint) by the source.
+// All the nonces come from the source: the nonces returned by the server are not stored anymore,
+// and the stored nonces are dropped.
+func (n *Manager) SetSource(source Source) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.source = source
+	n.store = false
+	n.nonces = nil
 }
 
 // Pop Pops a nonce.
@@ -40,9 +75,15 @@ func (n *Manager) Pop() (string, bool) {
 }
 
 // Push Pushes a nonce.
+// The nonce is ignored if the nonces only come from the source (see SetSource).
 func (n *Manager) Push(nonce string) {
 	n.Lock()
 	defer n.Unlock()
+
+	if !n.store {
+		return
+	}
+
 	n.nonces = append(n.nonces, nonce)
 }
 
@@ -51,11 +92,33 @@ func (n *Manager) Nonce() (string, error) {
 	if nonce, ok := n.Pop(); ok {
 		return nonce, nil
 	}
-	return n.getNonce()
+
+	return n.Fresh().Nonce()
+}
+
+// Fresh returns the source of the manager: the stored nonces are ignored.
+func (n *Manager) Fresh() Source {
+	n.Lock()
+	defer n.Unlock()
+
+	return n.source
 }
 
-func (n *Manager) getNonce() (string, error) {
-	resp, err := n.do.Head(n.nonceURL)
+func (n *Manager) getNonceURL() string {
+	n.Lock()
+	defer n.Unlock()
+
+	return n.nonceURL
+}
+
+// fetchSource is the default source: it fetches a new nonce from the newNonce endpoint.
+type fetchSource struct {
+	manager *Manager
+}
+
+// Nonce implement jose.NonceSource.
+func (s *fetchSource) Nonce() (string, error) {
+	resp, err := s.manager.do.Head(s.manager.getNonceURL())
 	if err != nil {
 		return "", fmt.Errorf("failed to get nonce from HTTP HEAD: %w", err)
 	}
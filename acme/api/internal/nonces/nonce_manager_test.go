@@ -9,6 +9,8 @@ import (
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api/internal/sender"
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNotHoldingLockWhileMakingHTTPRequests(t *testing.T) {
@@ -53,3 +55,55 @@ func TestNotHoldingLockWhileMakingHTTPRequests(t *testing.T) {
 		t.Fatal("JWS is probably holding a lock while making HTTP request")
 	}
 }
+
+type constantSource string
+
+func (s constantSource) Nonce() (string, error) {
+	return string(s), nil
+}
+
+func TestManager_Nonce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Replay-Nonce", "fetched")
+	}))
+	t.Cleanup(server.Close)
+
+	manager := NewManager(sender.NewDoer(http.DefaultClient, "lego-test"), server.URL)
+	manager.Push("stored")
+
+	// the stored nonces are used first, then the nonces of the default source (newNonce endpoint).
+	nonce, err := manager.Nonce()
+	require.NoError(t, err)
+	assert.Equal(t, "stored", nonce)
+
+	nonce, err = manager.Nonce()
+	require.NoError(t, err)
+	assert.Equal(t, "fetched", nonce)
+
+	manager.Push("stored")
+
+	nonce, err = manager.Fresh().Nonce()
+	require.NoError(t, err)
+	assert.Equal(t, "fetched", nonce)
+}
+
+func TestManager_SetSource(t *testing.T) {
+	manager := NewManager(nil, "")
+	manager.Push("stored")
+
+	manager.SetSource(constantSource("custom"))
+
+	// the nonces returned by the server are not stored anymore.
+	manager.Push("returned")
+
+	nonce, err := manager.Nonce()
+	require.NoError(t, err)
+	assert.Equal(t, "custom", nonce)
+
+	nonce, err = manager.Fresh().Nonce()
+	require.NoError(t, err)
+	assert.Equal(t, "custom", nonce)
+
+	_, ok := manager.Pop()
+	assert.False(t, ok)
+}
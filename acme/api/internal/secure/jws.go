@@ -7,16 +7,24 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/go-acme/lego/v4/acme/api/internal/nonces"
 	jose "github.com/go-jose/go-jose/v4"
 )
 
 // JWS Represents a JWS.
+// A JWS can be used concurrently (e.g. the orders of an account created by several goroutines).
 type JWS struct {
 	privKey crypto.PrivateKey
-	kid     string // Key identifier
 	nonces  *nonces.Manager
+
+	mu           sync.Mutex
+	kid          string                  // Key identifier
+	alg          jose.SignatureAlgorithm // overrides the algorithm based on the key.
+	extraHeaders map[jose.HeaderKey]interface{}
 }
 
 // NewJWS Create a new JWS.
@@ -30,36 +38,158 @@ func NewJWS(privateKey crypto.PrivateKey, kid string, nonceManager *nonces.Manag
 
 // SetKid Sets a key identifier.
 func (j *JWS) SetKid(kid string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
 	j.kid = kid
 }
 
-// SignContent Signs a content with the JWS.
-func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, error) {
-	var alg jose.SignatureAlgorithm
+// GetKid Gets the key identifier (the account URL).
+func (j *JWS) GetKid() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.kid
+}
+
+// SetAlgorithm overrides the signature algorithm.
+// The algorithm must be compatible with the key.
+func (j *JWS) SetAlgorithm(alg string) error {
+	algorithm := jose.SignatureAlgorithm(alg)
+
+	if !slices.Contains(j.compatibleAlgorithms(), algorithm) {
+		return fmt.Errorf("the algorithm %s is not compatible with the key (%T)", alg, j.privKey)
+	}
+
+	j.mu.Lock()
+	j.alg = algorithm
+	j.mu.Unlock()
+
+	return nil
+}
+
+// NegotiateAlgorithm switches to the first algorithm supported by the server (badSignatureAlgorithm error)
+// which is compatible with the key and different from the current algorithm.
+// If the current algorithm is already supported (i.e. negotiated by a concurrent request), it is kept.
+func (j *JWS) NegotiateAlgorithm(supported []string) (string, error) {
+	current := j.algorithm()
+	compatible := j.compatibleAlgorithms()
+
+	if slices.Contains(supported, string(current)) && slices.Contains(compatible, current) {
+		return string(current), nil
+	}
+
+	for _, alg := range supported {
+		algorithm := jose.SignatureAlgorithm(alg)
+
+		if algorithm == current || !slices.Contains(compatible, algorithm) {
+			continue
+		}
+
+		j.mu.Lock()
+		j.alg = algorithm
+		j.mu.Unlock()
+
+		return alg, nil
+	}
+
+	return "", fmt.Errorf("none of the signature algorithms supported by the server [%s] is compatible with the key (%T) and different from %s",
+		strings.Join(supported, ", "), j.privKey, current)
+}
+
+// compatibleAlgorithms returns the signature algorithms compatible with the key.
+func (j *JWS) compatibleAlgorithms() []jose.SignatureAlgorithm {
+	switch k := j.privKey.(type) {
+	case *rsa.PrivateKey:
+		return []jose.SignatureAlgorithm{jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512}
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return []jose.SignatureAlgorithm{jose.ES256}
+		case elliptic.P384():
+			return []jose.SignatureAlgorithm{jose.ES384}
+		case elliptic.P521():
+			return []jose.SignatureAlgorithm{jose.ES512}
+		}
+	}
+
+	return nil
+}
+
+// algorithm returns the signature algorithm: the overridden one, or the one based on the key.
+func (j *JWS) algorithm() jose.SignatureAlgorithm {
+	j.mu.Lock()
+	alg := j.alg
+	j.mu.Unlock()
+
+	if alg != "" {
+		return alg
+	}
+
 	switch k := j.privKey.(type) {
 	case *rsa.PrivateKey:
-		alg = jose.RS256
+		return jose.RS256
 	case *ecdsa.PrivateKey:
 		if k.Curve == elliptic.P256() {
-			alg = jose.ES256
+			return jose.ES256
 		} else if k.Curve == elliptic.P384() {
-			alg = jose.ES384
+			return jose.ES384
+		}
+	}
+
+	return ""
+}
+
+// SetExtraHeaders defines additional headers for the protected header of the signed contents.
+// The headers managed by the JWS (alg, jwk, kid, nonce, url) cannot be overridden.
+func (j *JWS) SetExtraHeaders(headers map[string]interface{}) error {
+	extraHeaders := make(map[jose.HeaderKey]interface{}, len(headers))
+
+	for k, v := range headers {
+		switch k {
+		case "alg", "jwk", "kid", "nonce", "url":
+			return fmt.Errorf("the header %q cannot be overridden", k)
 		}
+
+		extraHeaders[jose.HeaderKey(k)] = v
 	}
 
+	j.mu.Lock()
+	j.extraHeaders = extraHeaders
+	j.mu.Unlock()
+
+	return nil
+}
+
+// SignContent Signs a content with the JWS.
+func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, error) {
+	return j.SignContentWithNonceSource(url, content, j.nonces)
+}
+
+// SignContentWithNonceSource Signs a content with the JWS, using the given nonce source instead of the nonce manager.
+func (j *JWS) SignContentWithNonceSource(url string, content []byte, nonceSource jose.NonceSource) (*jose.JSONWebSignature, error) {
+	j.mu.Lock()
+	kid := j.kid
+	extraHeaders := j.extraHeaders
+	j.mu.Unlock()
+
 	signKey := jose.SigningKey{
-		Algorithm: alg,
-		Key:       jose.JSONWebKey{Key: j.privKey, KeyID: j.kid},
+		Algorithm: j.algorithm(),
+		Key:       jose.JSONWebKey{Key: j.privKey, KeyID: kid},
 	}
 
 	options := jose.SignerOptions{
-		NonceSource: j.nonces,
+		NonceSource: nonceSource,
 		ExtraHeaders: map[jose.HeaderKey]interface{}{
 			"url": url,
 		},
 	}
 
-	if j.kid == "" {
+	for k, v := range extraHeaders {
+		options.ExtraHeaders[k] = v
+	}
+
+	if kid == "" {
 		options.EmbedJWK = true
 	}
 
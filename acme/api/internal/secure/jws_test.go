@@ -1,8 +1,14 @@
 package secure
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +16,9 @@ import (
 	"github.com/go-acme/lego/v4/acme/api/internal/nonces"
 	"github.com/go-acme/lego/v4/acme/api/internal/sender"
 	"github.com/go-acme/lego/v4/platform/tester"
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNotHoldingLockWhileMakingHTTPRequests(t *testing.T) {
@@ -54,3 +63,137 @@ func TestNotHoldingLockWhileMakingHTTPRequests(t *testing.T) {
 		t.Fatal("JWS is probably holding a lock while making HTTP request")
 	}
 }
+
+func TestJWS_SetAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc        string
+		key         crypto.PrivateKey
+		alg         string
+		expectedErr bool
+	}{
+		{desc: "RSA key with RS384", key: rsaKey, alg: "RS384"},
+		{desc: "RSA key with PS256", key: rsaKey, alg: "PS256"},
+		{desc: "RSA key with ES256", key: rsaKey, alg: "ES256", expectedErr: true},
+		{desc: "EC P-256 key with ES256", key: ecKey, alg: "ES256"},
+		{desc: "EC P-256 key with ES384", key: ecKey, alg: "ES384", expectedErr: true},
+		{desc: "EC P-256 key with RS256", key: ecKey, alg: "RS256", expectedErr: true},
+		{desc: "unknown algorithm", key: rsaKey, alg: "foo", expectedErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			j := NewJWS(test.key, "", nil)
+
+			err := j.SetAlgorithm(test.alg)
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestJWS_SignContent_customized(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Replay-Nonce", "12345")
+	}))
+	t.Cleanup(server.Close)
+
+	j := NewJWS(privateKey, "kid", nonces.NewManager(sender.NewDoer(http.DefaultClient, "lego-test"), server.URL))
+
+	err = j.SetAlgorithm("RS512")
+	require.NoError(t, err)
+
+	err = j.SetExtraHeaders(map[string]interface{}{"x-vendor": "value"})
+	require.NoError(t, err)
+
+	signed, err := j.SignContent("https://example.com/acme", []byte("{}"))
+	require.NoError(t, err)
+
+	raw := signed.FullSerialize()
+
+	parsed, err := jose.ParseSigned(raw, []jose.SignatureAlgorithm{jose.RS512})
+	require.NoError(t, err)
+
+	protected := parsed.Signatures[0].Protected
+
+	assert.Equal(t, "RS512", protected.Algorithm)
+	assert.Equal(t, "12345", protected.Nonce)
+	assert.Equal(t, "value", protected.ExtraHeaders["x-vendor"])
+	assert.Equal(t, "https://example.com/acme", protected.ExtraHeaders["url"])
+}
+
+func TestJWS_SetExtraHeaders_reserved(t *testing.T) {
+	j := NewJWS(nil, "", nil)
+
+	for _, header := range []string{"alg", "jwk", "kid", "nonce", "url"} {
+		err := j.SetExtraHeaders(map[string]interface{}{header: "value"})
+		require.Error(t, err, header)
+	}
+}
+
+func TestJWS_NegotiateAlgorithm(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	j := NewJWS(privateKey, "", nil)
+
+	alg, err := j.NegotiateAlgorithm([]string{"ES256", "PS256", "PS384"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "PS256", alg)
+
+	// the algorithm negotiated by a concurrent request is kept.
+	alg, err = j.NegotiateAlgorithm([]string{"ES256", "PS256", "PS384"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "PS256", alg)
+}
+
+func TestJWS_concurrent(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Replay-Nonce", "12345")
+	}))
+	t.Cleanup(server.Close)
+
+	j := NewJWS(privateKey, "", nonces.NewManager(sender.NewDoer(http.DefaultClient, "lego-test"), server.URL))
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			j.SetKid("https://example.com/acme/account/1")
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, errS := j.SignContent("https://example.com/acme", []byte("{}"))
+			assert.NoError(t, errS)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, "https://example.com/acme/account/1", j.GetKid())
+}
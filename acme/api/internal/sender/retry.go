@@ -0,0 +1,90 @@
+package sender
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"syscall"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// SetRetry enables the retry of the requests failing because of a transient transport error (see IsTransientError).
+// The GET and HEAD requests are always retried,
+// the other requests are only retried when the request has not been sent (e.g. connection refused, TLS handshake timeout).
+// The delay between the attempts starts at backoff and doubles at each attempt.
+func (d *Doer) SetRetry(attempts int, backoff time.Duration) {
+	d.retryAttempts = attempts
+	d.retryBackoff = backoff
+}
+
+// send sends the request, and retries it on transient transport errors, if enabled.
+func (d *Doer) send(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		var wrote bool
+
+		trace := &httptrace.ClientTrace{
+			WroteRequest: func(httptrace.WroteRequestInfo) { wrote = true },
+		}
+
+		if d.transcript != nil {
+			d.transcript.request(req)
+		}
+
+		resp, err := d.httpClient.Do(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+
+		if d.transcript != nil {
+			d.transcript.response(req, resp, err)
+		}
+
+		if err == nil || attempt >= d.retryAttempts || !IsTransientError(err) {
+			return resp, err
+		}
+
+		if wrote && req.Method != http.MethodGet && req.Method != http.MethodHead {
+			// The request may have been applied by the server.
+			return resp, err
+		}
+
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, err
+			}
+
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		delay := d.retryBackoff << attempt
+
+		log.Infof("retry %s %s in %s due to: %v", req.Method, req.URL, delay, err)
+
+		time.Sleep(delay)
+	}
+}
+
+// IsTransientError returns true if the error is a transient transport error (connection reset, timeout, etc.).
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,163 @@
+package sender
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupResettingServer returns a server resetting the connection of the first `resets` requests.
+func setupResettingServer(t *testing.T, resets int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= resets {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			_ = conn.(*net.TCPConn).SetLinger(0)
+			_ = conn.Close()
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestDoer_SetRetry(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		attempts      int
+		call          func(doer *Doer, u string) error
+		expectedCalls int32
+		expectedError bool
+	}{
+		{
+			desc:     "GET",
+			attempts: 3,
+			call: func(doer *Doer, u string) error {
+				_, err := doer.Get(u, nil)
+				return err
+			},
+			expectedCalls: 3,
+		},
+		{
+			desc:     "HEAD",
+			attempts: 3,
+			call: func(doer *Doer, u string) error {
+				_, err := doer.Head(u)
+				return err
+			},
+			expectedCalls: 3,
+		},
+		{
+			desc:     "GET not enough attempts",
+			attempts: 1,
+			call: func(doer *Doer, u string) error {
+				_, err := doer.Get(u, nil)
+				return err
+			},
+			expectedCalls: 2,
+			expectedError: true,
+		},
+		{
+			desc: "GET without retry",
+			call: func(doer *Doer, u string) error {
+				_, err := doer.Get(u, nil)
+				return err
+			},
+			expectedCalls: 1,
+			expectedError: true,
+		},
+		{
+			desc:     "POST already sent",
+			attempts: 3,
+			call: func(doer *Doer, u string) error {
+				_, err := doer.Post(u, strings.NewReader("content"), "text/plain", nil)
+				return err
+			},
+			expectedCalls: 1,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server, calls := setupResettingServer(t, 2)
+
+			doer := NewDoer(&http.Client{Transport: &http.Transport{DisableKeepAlives: true}}, "")
+			doer.SetRetry(test.attempts, time.Millisecond)
+
+			err := test.call(doer, server.URL)
+			if test.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestDoer_SetRetry_notSent(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listener.Addr().String()
+
+	// The port is closed: the connection is refused.
+	require.NoError(t, listener.Close())
+
+	doer := NewDoer(http.DefaultClient, "")
+	doer.SetRetry(2, 100*time.Millisecond)
+
+	var calls atomic.Int32
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			_, _ = w.Write([]byte(`{}`))
+		})}
+
+		l, errL := net.Listen("tcp", addr)
+		if errL != nil {
+			return
+		}
+
+		t.Cleanup(func() { _ = server.Close() })
+
+		_ = server.Serve(l)
+	}()
+
+	_, err = doer.Post("http://"+addr, strings.NewReader("content"), "text/plain", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestIsTransientError(t *testing.T) {
+	assert.False(t, IsTransientError(nil))
+	assert.True(t, IsTransientError(&net.OpError{Op: "read"}))
+	assert.False(t, IsTransientError(assert.AnError))
+}
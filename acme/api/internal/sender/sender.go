@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 )
@@ -23,6 +24,13 @@ func contentType(ct string) RequestOption {
 type Doer struct {
 	httpClient *http.Client
 	userAgent  string
+
+	retryAttempts int
+	retryBackoff  time.Duration
+
+	observe func(resp *http.Response)
+
+	transcript *transcript
 }
 
 // NewDoer Creates a new Doer.
@@ -33,6 +41,17 @@ func NewDoer(client *http.Client, userAgent string) *Doer {
 	}
 }
 
+// SetHTTPClient replaces the HTTP client used to send the requests.
+func (d *Doer) SetHTTPClient(client *http.Client) {
+	d.httpClient = client
+}
+
+// SetResponseObserver defines a function called with each response (before the error handling),
+// to read the headers (e.g. the rate-limit headers). The body must not be read.
+func (d *Doer) SetResponseObserver(observe func(resp *http.Response)) {
+	d.observe = observe
+}
+
 // Get performs a GET request with a proper User-Agent string.
 // If "response" is not provided, callers should close resp.Body when done reading from it.
 func (d *Doer) Get(url string, response interface{}) (*http.Response, error) {
@@ -85,11 +104,15 @@ func (d *Doer) newRequest(method, uri string, body io.Reader, opts ...RequestOpt
 }
 
 func (d *Doer) do(req *http.Request, response interface{}) (*http.Response, error) {
-	resp, err := d.httpClient.Do(req)
+	resp, err := d.send(req)
 	if err != nil {
 		return nil, err
 	}
 
+	if d.observe != nil {
+		d.observe(resp)
+	}
+
 	if err = checkError(req, resp); err != nil {
 		return resp, err
 	}
@@ -0,0 +1,180 @@
+package sender
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are the headers redacted from the transcript.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// transcript writes the HTTP requests and responses to a writer, for debugging.
+type transcript struct {
+	mu     sync.Mutex
+	w      io.Writer
+	unsafe bool
+}
+
+// SetTranscript writes each request and response to w.
+// The sensitive headers are always redacted.
+// The payload and the signature of the JWS bodies are redacted, unless unsafe is true.
+func (d *Doer) SetTranscript(w io.Writer, unsafe bool) {
+	if w == nil {
+		d.transcript = nil
+		return
+	}
+
+	d.transcript = &transcript{w: w, unsafe: unsafe}
+}
+
+func (t *transcript) request(req *http.Request) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		body = []byte(fmt.Sprintf("(unable to read the body: %v)", err))
+	} else if !t.unsafe {
+		body = redactJWS(body)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, _ = fmt.Fprintf(t.w, "--> %s %s\n", req.Method, req.URL)
+	writeHeaders(t.w, req.Header)
+	writeBody(t.w, body)
+}
+
+func (t *transcript) response(req *http.Request, resp *http.Response, respErr error) {
+	if respErr != nil {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		_, _ = fmt.Fprintf(t.w, "<-- %s %s: error: %v\n\n", req.Method, req.URL, respErr)
+
+		return
+	}
+
+	var body []byte
+
+	if resp.Body != nil {
+		raw, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		// The body is restored for the caller, including the read error if any.
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(raw), &errReader{err: err}))
+
+		body = raw
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, _ = fmt.Fprintf(t.w, "<-- %s %s %s\n", resp.Status, req.Method, req.URL)
+	writeHeaders(t.w, resp.Header)
+	writeBody(t.w, body)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errors.New("the body of the request cannot be read twice")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = body.Close() }()
+
+	return io.ReadAll(body)
+}
+
+func writeHeaders(w io.Writer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		value := strings.Join(header[k], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+			value = redacted
+		}
+
+		_, _ = fmt.Fprintf(w, "%s: %s\n", k, value)
+	}
+}
+
+func writeBody(w io.Writer, body []byte) {
+	if len(body) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%s\n", body)
+	}
+
+	_, _ = fmt.Fprintln(w)
+}
+
+// redactJWS replaces the payload and the signature of a flattened JWS by a placeholder,
+// the protected header (alg, kid, nonce, url) is decoded to stay readable.
+// A body which is not a JWS is returned unchanged.
+func redactJWS(body []byte) []byte {
+	var jws struct {
+		Protected string  `json:"protected"`
+		Payload   *string `json:"payload"`
+		Signature string  `json:"signature"`
+	}
+
+	err := json.Unmarshal(body, &jws)
+	if err != nil || jws.Protected == "" || jws.Payload == nil {
+		return body
+	}
+
+	protected := json.RawMessage(`"` + redacted + `"`)
+
+	header, err := base64.RawURLEncoding.DecodeString(jws.Protected)
+	if err == nil && json.Valid(header) {
+		protected = header
+	}
+
+	redactedJWS, err := json.Marshal(map[string]any{
+		"protected": protected,
+		"payload":   redacted,
+		"signature": redacted,
+	})
+	if err != nil {
+		return []byte(redacted)
+	}
+
+	return redactedJWS
+}
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read(_ []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return 0, io.EOF
+}
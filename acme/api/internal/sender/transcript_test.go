@@ -0,0 +1,78 @@
+package sender
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoer_SetTranscript(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		_, _ = w.Write([]byte(`{"status":"valid"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	buf := &bytes.Buffer{}
+
+	doer := NewDoer(http.DefaultClient, "")
+	doer.SetTranscript(buf, false)
+
+	req, err := doer.newRequest(http.MethodPost, server.URL, strings.NewReader(`{"hello":"world"}`), contentType("application/json"))
+	require.NoError(t, err)
+
+	req.Header.Set("Authorization", "Bearer secret")
+
+	var response map[string]string
+
+	_, err = doer.do(req, &response)
+	require.NoError(t, err)
+
+	// the response body is still readable by the caller.
+	assert.Equal(t, map[string]string{"status": "valid"}, response)
+
+	transcript := buf.String()
+
+	assert.Contains(t, transcript, "--> POST "+server.URL+"\n")
+	assert.Contains(t, transcript, "Authorization: [REDACTED]\n")
+	assert.Contains(t, transcript, "Set-Cookie: [REDACTED]\n")
+	assert.Contains(t, transcript, `{"hello":"world"}`)
+	assert.Contains(t, transcript, "<-- 200 OK POST "+server.URL+"\n")
+	assert.Contains(t, transcript, `{"status":"valid"}`)
+	assert.NotContains(t, transcript, "secret")
+}
+
+func Test_redactJWS(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		body     string
+		expected string
+	}{
+		{
+			desc:     "JWS",
+			body:     `{"protected":"eyJhbGciOiJSUzI1NiIsIm5vbmNlIjoibiJ9","payload":"eyJjc3IiOiJzZWNyZXQifQ","signature":"c2ln"}`,
+			expected: `{"payload":"[REDACTED]","protected":{"alg":"RS256","nonce":"n"},"signature":"[REDACTED]"}`,
+		},
+		{
+			desc:     "POST-as-GET",
+			body:     `{"protected":"eyJhbGciOiJSUzI1NiJ9","payload":"","signature":"c2ln"}`,
+			expected: `{"payload":"[REDACTED]","protected":{"alg":"RS256"},"signature":"[REDACTED]"}`,
+		},
+		{
+			desc:     "not a JWS",
+			body:     `{"hello":"world"}`,
+			expected: `{"hello":"world"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, string(redactJWS([]byte(test.body))))
+		})
+	}
+}
@@ -3,12 +3,20 @@ package api
 import (
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 )
 
+// ErrNoAutoRenewal is returned when the server does not support the STAR (auto-renewal) extension.
+var ErrNoAutoRenewal = errors.New("order[new]: server does not support STAR (auto-renewal)")
+
+// ErrNoOrderEAB is returned when the server doesn't support External Account Binding on orders.
+var ErrNoOrderEAB = errors.New("order[new]: server does not support external account binding on orders")
+
 // OrderOptions used to create an order (optional).
 type OrderOptions struct {
 	NotBefore time.Time
@@ -17,6 +25,39 @@ type OrderOptions struct {
 	// order is intended to replace.
 	// - https://datatracker.ietf.org/doc/html/draft-ietf-acme-ari-03#section-5
 	ReplacesCertID string
+
+	// AutoRenewal requests a STAR (short-term, automatically renewed) certificate.
+	// NotBefore and NotAfter are ignored when AutoRenewal is defined.
+	// - https://www.rfc-editor.org/rfc/rfc8739.html
+	AutoRenewal *AutoRenewalOptions
+
+	// ExternalAccountBinding attaches EAB credentials to the order (non-standard).
+	// The server must advertise the support through the "orderExternalAccountBinding" meta field,
+	// CAs supporting only account-level EAB (RFC 8555 section 7.3.4) return ErrNoOrderEAB.
+	ExternalAccountBinding *EABOptions
+}
+
+// EABOptions the External Account Binding credentials.
+type EABOptions struct {
+	// Kid the key identifier provided by the CA.
+	Kid string
+	// HmacEncoded the MAC key provided by the CA (base64url encoded).
+	HmacEncoded string
+}
+
+// AutoRenewalOptions the STAR parameters of an order.
+// - https://www.rfc-editor.org/rfc/rfc8739.html#section-3.1.1
+type AutoRenewalOptions struct {
+	// StartDate the earliest date of validity of the first certificate (optional).
+	StartDate time.Time
+	// EndDate the latest date of validity of the last certificate.
+	EndDate time.Time
+	// Lifetime the maximum validity period of each certificate.
+	Lifetime time.Duration
+	// LifetimeAdjust the "left pad" added to each certificate (optional).
+	LifetimeAdjust time.Duration
+	// AllowCertificateGet allows unauthenticated GET requests to the star-certificate URL.
+	AllowCertificateGet bool
 }
 
 type OrderService service
@@ -32,8 +73,12 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 	for _, domain := range domains {
 		ident := acme.Identifier{Value: domain, Type: "dns"}
 
-		if net.ParseIP(domain) != nil {
+		switch {
+		case net.ParseIP(domain) != nil:
 			ident.Type = "ip"
+		case strings.Contains(domain, "@"):
+			// https://www.rfc-editor.org/rfc/rfc8823.html#section-3
+			ident.Type = "email"
 		}
 
 		identifiers = append(identifiers, ident)
@@ -41,7 +86,13 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 
 	orderReq := acme.Order{Identifiers: identifiers}
 
-	if opts != nil {
+	if opts != nil && opts.AutoRenewal != nil {
+		if o.core.GetDirectory().Meta.AutoRenewal == nil {
+			return acme.ExtendedOrder{}, ErrNoAutoRenewal
+		}
+
+		orderReq.AutoRenewal = newAutoRenewal(opts.AutoRenewal)
+	} else if opts != nil {
 		if !opts.NotAfter.IsZero() {
 			orderReq.NotAfter = opts.NotAfter.Format(time.RFC3339)
 		}
@@ -55,18 +106,50 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 		}
 	}
 
+	if opts != nil && opts.ExternalAccountBinding != nil {
+		eab, err := o.signEAB(opts.ExternalAccountBinding)
+		if err != nil {
+			return acme.ExtendedOrder{}, err
+		}
+
+		orderReq.ExternalAccountBinding = eab
+	}
+
 	var order acme.Order
 	resp, err := o.core.post(o.core.GetDirectory().NewOrderURL, orderReq, &order)
 	if err != nil {
 		return acme.ExtendedOrder{}, err
 	}
 
+	// The server has ignored the auto-renewal object.
+	if orderReq.AutoRenewal != nil && order.AutoRenewal == nil {
+		return acme.ExtendedOrder{}, ErrNoAutoRenewal
+	}
+
 	return acme.ExtendedOrder{
 		Order:    order,
 		Location: resp.Header.Get("Location"),
 	}, nil
 }
 
+func (o *OrderService) signEAB(opts *EABOptions) ([]byte, error) {
+	if !o.core.GetDirectory().Meta.OrderExternalAccountBinding {
+		return nil, ErrNoOrderEAB
+	}
+
+	hmac, err := base64.RawURLEncoding.DecodeString(opts.HmacEncoded)
+	if err != nil {
+		return nil, fmt.Errorf("order[new]: could not decode hmac key: %w", err)
+	}
+
+	eabJWS, err := o.core.signEABContent(o.core.GetDirectory().NewOrderURL, opts.Kid, hmac)
+	if err != nil {
+		return nil, fmt.Errorf("order[new]: error signing eab content: %w", err)
+	}
+
+	return eabJWS, nil
+}
+
 // Get Gets an order.
 func (o *OrderService) Get(orderURL string) (acme.ExtendedOrder, error) {
 	if orderURL == "" {
@@ -82,14 +165,67 @@ func (o *OrderService) Get(orderURL string) (acme.ExtendedOrder, error) {
 	return acme.ExtendedOrder{Order: order}, nil
 }
 
+// GetIdentifiers Gets the identifiers of an order, with the challenge types offered by their authorizations.
+// The identifiers are in the same order as the authorizations of the order.
+func (o *OrderService) GetIdentifiers(order acme.ExtendedOrder) ([]acme.OrderIdentifier, error) {
+	identifiers := make([]acme.OrderIdentifier, 0, len(order.Authorizations))
+
+	for _, authzURL := range order.Authorizations {
+		authz, err := o.core.Authorizations.Get(authzURL)
+		if err != nil {
+			return nil, err
+		}
+
+		ident := acme.OrderIdentifier{
+			Identifier:       authz.Identifier,
+			Wildcard:         authz.Wildcard || strings.HasPrefix(authz.Identifier.Value, "*."),
+			AuthorizationURL: authzURL,
+		}
+
+		if ident.Wildcard && !strings.HasPrefix(ident.Value, "*.") {
+			ident.Value = "*." + ident.Value
+		}
+
+		for _, chlg := range authz.Challenges {
+			ident.ChallengeTypes = append(ident.ChallengeTypes, chlg.Type)
+		}
+
+		identifiers = append(identifiers, ident)
+	}
+
+	return identifiers, nil
+}
+
+// FinalizeOption customizes the payload of the finalize request of an order.
+type FinalizeOption func(payload map[string]any) error
+
+// WithFinalizeExtra merges extra fields into the payload of the finalize request.
+// This is intended for the CAs accepting vendor-specific finalize parameters (e.g. a preferred chain),
+// the fields are ignored by the other CAs.
+// The "csr" field cannot be overridden.
+func WithFinalizeExtra(extra map[string]any) FinalizeOption {
+	return func(payload map[string]any) error {
+		for k, v := range extra {
+			if _, ok := payload[k]; ok {
+				return fmt.Errorf("the finalize field %q cannot be overridden", k)
+			}
+
+			payload[k] = v
+		}
+
+		return nil
+	}
+}
+
 // UpdateForCSR Updates an order for a CSR.
-func (o *OrderService) UpdateForCSR(orderURL string, csr []byte) (acme.ExtendedOrder, error) {
-	csrMsg := acme.CSRMessage{
-		Csr: base64.RawURLEncoding.EncodeToString(csr),
+func (o *OrderService) UpdateForCSR(orderURL string, csr []byte, opts ...FinalizeOption) (acme.ExtendedOrder, error) {
+	csrMsg, err := newFinalizePayload(csr, opts)
+	if err != nil {
+		return acme.ExtendedOrder{}, fmt.Errorf("order[finalize]: %w", err)
 	}
 
 	var order acme.Order
-	_, err := o.core.post(orderURL, csrMsg, &order)
+	_, err = o.core.post(orderURL, csrMsg, &order)
 	if err != nil {
 		return acme.ExtendedOrder{}, err
 	}
@@ -100,3 +236,39 @@ func (o *OrderService) UpdateForCSR(orderURL string, csr []byte) (acme.ExtendedO
 
 	return acme.ExtendedOrder{Order: order}, nil
 }
+
+func newFinalizePayload(csr []byte, opts []FinalizeOption) (any, error) {
+	csrMsg := acme.CSRMessage{
+		Csr: base64.RawURLEncoding.EncodeToString(csr),
+	}
+
+	if len(opts) == 0 {
+		return csrMsg, nil
+	}
+
+	payload := map[string]any{"csr": csrMsg.Csr}
+
+	for _, opt := range opts {
+		err := opt(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return payload, nil
+}
+
+func newAutoRenewal(opts *AutoRenewalOptions) *acme.AutoRenewal {
+	autoRenewal := &acme.AutoRenewal{
+		EndDate:             opts.EndDate.Format(time.RFC3339),
+		Lifetime:            int(opts.Lifetime.Seconds()),
+		LifetimeAdjust:      int(opts.LifetimeAdjust.Seconds()),
+		AllowCertificateGet: opts.AllowCertificateGet,
+	}
+
+	if !opts.StartDate.IsZero() {
+		autoRenewal.StartDate = opts.StartDate.Format(time.RFC3339)
+	}
+
+	return autoRenewal
+}
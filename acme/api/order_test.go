@@ -3,9 +3,11 @@ package api
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -105,6 +107,245 @@ func TestOrderService_NewWithOptions(t *testing.T) {
 	}
 }
 
+func TestOrderService_New_identifiers(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, errK, "Could not generate test key")
+
+	mux.HandleFunc("/newOrder", func(w http.ResponseWriter, r *http.Request) {
+		body, err := readSignedBody(r, privateKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		order := acme.Order{}
+		err = json.Unmarshal(body, &order)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = tester.WriteJSONResponse(w, acme.Order{Status: acme.StatusPending, Identifiers: order.Identifiers})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	order, err := core.Orders.New([]string{"example.com", "192.0.2.1", "alice@example.com"})
+	require.NoError(t, err)
+
+	expected := []acme.Identifier{
+		{Type: "dns", Value: "example.com"},
+		{Type: "ip", Value: "192.0.2.1"},
+		{Type: "email", Value: "alice@example.com"},
+	}
+
+	assert.Equal(t, expected, order.Identifiers)
+}
+
+func TestOrderService_NewWithOptions_autoRenewal(t *testing.T) {
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, errK, "Could not generate test key")
+
+	testCases := []struct {
+		desc        string
+		meta        *acme.AutoRenewalMeta
+		ignored     bool
+		expectedErr error
+	}{
+		{
+			desc: "supported",
+			meta: &acme.AutoRenewalMeta{MinLifetime: 86400, MaxDuration: 31536000},
+		},
+		{
+			desc:        "not advertised",
+			expectedErr: ErrNoAutoRenewal,
+		},
+		{
+			desc:        "ignored by the server",
+			meta:        &acme.AutoRenewalMeta{MinLifetime: 86400, MaxDuration: 31536000},
+			ignored:     true,
+			expectedErr: ErrNoAutoRenewal,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+				_ = tester.WriteJSONResponse(w, acme.Directory{
+					NewNonceURL:   server.URL + "/nonce",
+					NewAccountURL: server.URL + "/account",
+					NewOrderURL:   server.URL + "/newOrder",
+					Meta:          acme.Meta{AutoRenewal: test.meta},
+				})
+			})
+
+			mux.HandleFunc("HEAD /nonce", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Replay-Nonce", "12345")
+			})
+
+			mux.HandleFunc("POST /newOrder", func(w http.ResponseWriter, r *http.Request) {
+				body, err := readSignedBody(r, privateKey)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				order := acme.Order{}
+				err = json.Unmarshal(body, &order)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				order.Status = acme.StatusPending
+				if test.ignored {
+					order.AutoRenewal = nil
+				}
+
+				_ = tester.WriteJSONResponse(w, order)
+			})
+
+			core, err := New(http.DefaultClient, "lego-test", server.URL+"/dir", "", privateKey)
+			require.NoError(t, err)
+
+			opts := &OrderOptions{
+				NotAfter: time.Date(2023, 1, 2, 1, 0, 0, 0, time.UTC),
+				AutoRenewal: &AutoRenewalOptions{
+					EndDate:             time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					Lifetime:            4 * 24 * time.Hour,
+					AllowCertificateGet: true,
+				},
+			}
+
+			order, err := core.Orders.NewWithOptions([]string{"example.com"}, opts)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			expected := &acme.AutoRenewal{
+				EndDate:             "2024-01-01T00:00:00Z",
+				Lifetime:            345600,
+				AllowCertificateGet: true,
+			}
+
+			assert.Equal(t, expected, order.AutoRenewal)
+			assert.Empty(t, order.NotAfter)
+		})
+	}
+}
+
+func TestOrderService_NewWithOptions_externalAccountBinding(t *testing.T) {
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, errK, "Could not generate test key")
+
+	testCases := []struct {
+		desc        string
+		supported   bool
+		expectedErr error
+	}{
+		{
+			desc:      "supported",
+			supported: true,
+		},
+		{
+			desc:        "account-level only",
+			expectedErr: ErrNoOrderEAB,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+				_ = tester.WriteJSONResponse(w, acme.Directory{
+					NewNonceURL:   server.URL + "/nonce",
+					NewAccountURL: server.URL + "/account",
+					NewOrderURL:   server.URL + "/newOrder",
+					Meta:          acme.Meta{OrderExternalAccountBinding: test.supported},
+				})
+			})
+
+			mux.HandleFunc("HEAD /nonce", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Replay-Nonce", "12345")
+			})
+
+			var received acme.Order
+
+			mux.HandleFunc("POST /newOrder", func(w http.ResponseWriter, r *http.Request) {
+				body, err := readSignedBody(r, privateKey)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				err = json.Unmarshal(body, &received)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				order := received
+				order.Status = acme.StatusPending
+				order.ExternalAccountBinding = nil
+
+				_ = tester.WriteJSONResponse(w, order)
+			})
+
+			core, err := New(http.DefaultClient, "lego-test", server.URL+"/dir", "", privateKey)
+			require.NoError(t, err)
+
+			hmac := []byte("0123456789abcdef0123456789abcdef")
+
+			opts := &OrderOptions{
+				ExternalAccountBinding: &EABOptions{
+					Kid:         "tenant-a",
+					HmacEncoded: base64.RawURLEncoding.EncodeToString(hmac),
+				},
+			}
+
+			_, err = core.Orders.NewWithOptions([]string{"example.com"}, opts)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotEmpty(t, received.ExternalAccountBinding)
+
+			eab, err := jose.ParseSigned(string(received.ExternalAccountBinding), []jose.SignatureAlgorithm{jose.HS256})
+			require.NoError(t, err)
+
+			require.Len(t, eab.Signatures, 1)
+			assert.Equal(t, "tenant-a", eab.Signatures[0].Header.KeyID)
+			assert.Equal(t, server.URL+"/newOrder", eab.Signatures[0].Header.ExtraHeaders["url"])
+
+			_, err = eab.Verify(hmac)
+			require.NoError(t, err)
+		})
+	}
+}
+
 func readSignedBody(r *http.Request, privateKey *rsa.PrivateKey) ([]byte, error) {
 	reqBody, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -127,3 +368,136 @@ func readSignedBody(r *http.Request, privateKey *rsa.PrivateKey) ([]byte, error)
 
 	return body, nil
 }
+
+func TestOrderService_GetIdentifiers(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, errK, "Could not generate test key")
+
+	authorizations := map[string]acme.Authorization{
+		"1": {
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: "http-01"}, {Type: "dns-01"}, {Type: "tls-alpn-01"}},
+		},
+		"2": {
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+			Wildcard:   true,
+			Challenges: []acme.Challenge{{Type: "dns-01"}},
+		},
+	}
+
+	mux.HandleFunc("POST /authz/{id}", func(w http.ResponseWriter, r *http.Request) {
+		authz, ok := authorizations[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		_ = tester.WriteJSONResponse(w, authz)
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	order := acme.ExtendedOrder{
+		Order: acme.Order{
+			Identifiers:    []acme.Identifier{{Type: "dns", Value: "example.com"}, {Type: "dns", Value: "*.example.com"}},
+			Authorizations: []string{apiURL + "/authz/1", apiURL + "/authz/2"},
+		},
+	}
+
+	identifiers, err := core.Orders.GetIdentifiers(order)
+	require.NoError(t, err)
+
+	expected := []acme.OrderIdentifier{
+		{
+			Identifier:       acme.Identifier{Type: "dns", Value: "example.com"},
+			AuthorizationURL: apiURL + "/authz/1",
+			ChallengeTypes:   []string{"http-01", "dns-01", "tls-alpn-01"},
+		},
+		{
+			Identifier:       acme.Identifier{Type: "dns", Value: "*.example.com"},
+			Wildcard:         true,
+			AuthorizationURL: apiURL + "/authz/2",
+			ChallengeTypes:   []string{"dns-01"},
+		},
+	}
+
+	assert.Equal(t, expected, identifiers)
+}
+
+func TestOrderService_UpdateForCSR_finalizeExtra(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	// small value keeps test fast
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, errK, "Could not generate test key")
+
+	var payload map[string]any
+
+	mux.HandleFunc("POST /finalize", func(w http.ResponseWriter, r *http.Request) {
+		body, err := readSignedBody(r, privateKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		payload = nil
+
+		err = json.Unmarshal(body, &payload)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_ = tester.WriteJSONResponse(w, acme.Order{Status: acme.StatusProcessing})
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		opts          []FinalizeOption
+		expected      map[string]any
+		expectedError string
+	}{
+		{
+			desc:     "without extra",
+			expected: map[string]any{"csr": "Y3Ny"},
+		},
+		{
+			desc: "with extra",
+			opts: []FinalizeOption{WithFinalizeExtra(map[string]any{
+				"preferredChain": "ISRG Root X1",
+				"profile":        map[string]any{"name": "tlsserver"},
+			})},
+			expected: map[string]any{
+				"csr":            "Y3Ny",
+				"preferredChain": "ISRG Root X1",
+				"profile":        map[string]any{"name": "tlsserver"},
+			},
+		},
+		{
+			desc:          "override the CSR",
+			opts:          []FinalizeOption{WithFinalizeExtra(map[string]any{"csr": "foo"})},
+			expectedError: `order[finalize]: the finalize field "csr" cannot be overridden`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			order, err := core.Orders.UpdateForCSR(apiURL+"/finalize", []byte("csr"), test.opts...)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, acme.StatusProcessing, order.Status)
+			assert.Equal(t, test.expected, payload)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitInfo is the rate-limit status of the ACME server, derived from the headers of the last response exposing it.
+// Supported headers:
+//   - `RateLimit-Limit`, `RateLimit-Remaining`, `RateLimit-Reset` (IETF draft, the reset is a number of seconds).
+//   - `X-RateLimit-Limit`, `X-RateLimit-Remaining`, `X-RateLimit-Reset` (the reset is a number of seconds, or a Unix timestamp).
+//   - `Retry-After` (on a rateLimited error, HTTP 429).
+//
+// The numeric fields are -1 when unknown.
+type RateLimitInfo struct {
+	// Known is false if no response of the server contained rate-limit headers.
+	Known bool
+
+	// Limit is the number of requests allowed during the current window.
+	Limit int
+
+	// Remaining is the number of requests remaining in the current window.
+	Remaining int
+
+	// Reset is the end of the current window (zero if unknown).
+	Reset time.Time
+
+	// RetryAfter is the time before which the requests will be rejected (zero if unknown or already passed).
+	RetryAfter time.Time
+
+	// Policy is the raw value of the `RateLimit-Policy` header, if any.
+	Policy string
+
+	// ObservedAt is the date of the response containing the headers.
+	ObservedAt time.Time
+}
+
+func unknownRateLimit() RateLimitInfo {
+	return RateLimitInfo{Limit: -1, Remaining: -1}
+}
+
+type rateLimitTracker struct {
+	mu   sync.Mutex
+	info RateLimitInfo
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{info: unknownRateLimit()}
+}
+
+func (t *rateLimitTracker) observe(resp *http.Response) {
+	info, ok := parseRateLimitHeaders(resp, time.Now())
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	t.info = info
+	t.mu.Unlock()
+}
+
+func (t *rateLimitTracker) get() RateLimitInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info := t.info
+
+	if !info.RetryAfter.IsZero() && time.Now().After(info.RetryAfter) {
+		info.RetryAfter = time.Time{}
+	}
+
+	return info
+}
+
+// RateLimitStatus returns the rate-limit status observed in the responses of the server.
+// If no response contained rate-limit headers yet, a nonce is fetched to observe the headers of the server:
+// if the server doesn't expose its rate limits, the returned status is unknown (RateLimitInfo.Known is false).
+func (a *Core) RateLimitStatus() (RateLimitInfo, error) {
+	if info := a.rateLimits.get(); info.Known {
+		return info, nil
+	}
+
+	resp, err := a.doer.Head(a.directory.NewNonceURL)
+	if err != nil {
+		return unknownRateLimit(), err
+	}
+
+	// keep the nonce for the next request.
+	if nonce := resp.Header.Get("Replay-Nonce"); nonce != "" {
+		a.nonceManager.Push(nonce)
+	}
+
+	return a.rateLimits.get(), nil
+}
+
+func parseRateLimitHeaders(resp *http.Response, now time.Time) (RateLimitInfo, bool) {
+	header := resp.Header
+
+	info := unknownRateLimit()
+
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		if v, ok := parseHeaderInt(header, prefix+"Limit"); ok && info.Limit < 0 {
+			info.Limit = v
+			info.Known = true
+		}
+
+		if v, ok := parseHeaderInt(header, prefix+"Remaining"); ok && info.Remaining < 0 {
+			info.Remaining = v
+			info.Known = true
+		}
+
+		if v, ok := parseHeaderInt(header, prefix+"Reset"); ok && info.Reset.IsZero() {
+			info.Reset = resetTime(v, now)
+			info.Known = true
+		}
+	}
+
+	if policy := header.Get("RateLimit-Policy"); policy != "" {
+		info.Policy = policy
+		info.Known = true
+	}
+
+	// the Retry-After header is also used to pace the polling (e.g. of an order).
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := parseRetryAfter(header.Get("Retry-After"), now); !retryAfter.IsZero() {
+			info.RetryAfter = retryAfter
+			info.Known = true
+		}
+	}
+
+	if !info.Known {
+		return info, false
+	}
+
+	info.ObservedAt = now
+
+	return info, true
+}
+
+func parseHeaderInt(header http.Header, key string) (int, bool) {
+	value := strings.TrimSpace(header.Get(key))
+	if value == "" {
+		return 0, false
+	}
+
+	// e.g. `100, 100;w=3600` (the first value is the current limit)
+	value, _, _ = strings.Cut(value, ",")
+	value, _, _ = strings.Cut(value, ";")
+
+	v, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || v < 0 {
+		return 0, false
+	}
+
+	return v, true
+}
+
+// resetTime converts a reset value to a time: a number of seconds, or a Unix timestamp for the large values.
+func resetTime(v int, now time.Time) time.Time {
+	// 2001-09-09, no window lasts that long.
+	if v >= 1_000_000_000 {
+		return time.Unix(int64(v), 0)
+	}
+
+	return now.Add(time.Duration(v) * time.Second)
+}
+
+// parseRetryAfter parses a `Retry-After` header: a number of seconds, or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return date
+}
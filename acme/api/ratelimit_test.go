@@ -0,0 +1,186 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseRateLimitHeaders(t *testing.T) {
+	now := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		status   int
+		headers  map[string]string
+		expected RateLimitInfo
+		known    bool
+	}{
+		{
+			desc:     "no headers",
+			status:   http.StatusOK,
+			expected: RateLimitInfo{Limit: -1, Remaining: -1},
+		},
+		{
+			desc:   "IETF draft headers",
+			status: http.StatusOK,
+			headers: map[string]string{
+				"RateLimit-Limit":     "300",
+				"RateLimit-Remaining": "42",
+				"RateLimit-Reset":     "60",
+				"RateLimit-Policy":    "300;w=10800",
+			},
+			expected: RateLimitInfo{
+				Known:      true,
+				Limit:      300,
+				Remaining:  42,
+				Reset:      now.Add(60 * time.Second),
+				Policy:     "300;w=10800",
+				ObservedAt: now,
+			},
+			known: true,
+		},
+		{
+			desc:   "X-RateLimit headers with a Unix timestamp",
+			status: http.StatusOK,
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "50, 50;w=604800",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     "1733050800",
+			},
+			expected: RateLimitInfo{
+				Known:      true,
+				Limit:      50,
+				Remaining:  0,
+				Reset:      time.Unix(1733050800, 0),
+				ObservedAt: now,
+			},
+			known: true,
+		},
+		{
+			desc:   "rate limited with Retry-After",
+			status: http.StatusTooManyRequests,
+			headers: map[string]string{
+				"Retry-After": "Sun, 01 Dec 2024 11:00:00 GMT",
+			},
+			expected: RateLimitInfo{
+				Known:      true,
+				Limit:      -1,
+				Remaining:  -1,
+				RetryAfter: time.Date(2024, 12, 1, 11, 0, 0, 0, time.UTC),
+				ObservedAt: now,
+			},
+			known: true,
+		},
+		{
+			desc:   "Retry-After of a polling",
+			status: http.StatusOK,
+			headers: map[string]string{
+				"Retry-After": "3",
+			},
+			expected: RateLimitInfo{Limit: -1, Remaining: -1},
+		},
+		{
+			desc:   "invalid values",
+			status: http.StatusOK,
+			headers: map[string]string{
+				"RateLimit-Limit":     "a lot",
+				"RateLimit-Remaining": "-1",
+			},
+			expected: RateLimitInfo{Limit: -1, Remaining: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{StatusCode: test.status, Header: http.Header{}}
+			for k, v := range test.headers {
+				resp.Header.Set(k, v)
+			}
+
+			info, known := parseRateLimitHeaders(resp, now)
+
+			assert.Equal(t, test.known, known)
+			assert.Equal(t, test.expected, info)
+		})
+	}
+}
+
+func TestCore_RateLimitStatus(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		headers           map[string]string
+		expectedKnown     bool
+		expectedRemaining int
+	}{
+		{
+			desc:              "exposed rate limits",
+			headers:           map[string]string{"RateLimit-Limit": "300", "RateLimit-Remaining": "299"},
+			expectedKnown:     true,
+			expectedRemaining: 299,
+		},
+		{
+			desc:              "unknown rate limits",
+			expectedRemaining: -1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+				_ = tester.WriteJSONResponse(w, acme.Directory{
+					NewNonceURL:   server.URL + "/nonce",
+					NewAccountURL: server.URL + "/account",
+					NewOrderURL:   server.URL + "/newOrder",
+					RevokeCertURL: server.URL + "/revokeCert",
+					KeyChangeURL:  server.URL + "/keyChange",
+				})
+			})
+
+			var nonces int
+			mux.HandleFunc("HEAD /nonce", func(w http.ResponseWriter, _ *http.Request) {
+				nonces++
+
+				for k, v := range test.headers {
+					w.Header().Set(k, v)
+				}
+
+				w.Header().Set("Replay-Nonce", "nonce")
+			})
+
+			privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+			require.NoError(t, err)
+
+			core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+			require.NoError(t, err)
+
+			info, err := core.RateLimitStatus()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedKnown, info.Known)
+			assert.Equal(t, test.expectedRemaining, info.Remaining)
+			assert.Equal(t, 1, nonces)
+
+			// the fetched nonce is kept for the next request.
+			nonce, ok := core.PopNonce()
+			require.True(t, ok)
+			assert.Equal(t, "nonce", nonce)
+		})
+	}
+}
@@ -9,7 +9,9 @@ import (
 
 // ACME status values of Account, Order, Authorization and Challenge objects.
 // See https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.6 for details.
+// The status "canceled" is defined by RFC 8739 (STAR).
 const (
+	StatusCanceled    = "canceled"
 	StatusDeactivated = "deactivated"
 	StatusExpired     = "expired"
 	StatusInvalid     = "invalid"
@@ -74,6 +76,32 @@ type Meta struct {
 	// then the CA requires that all new-account requests include an "externalAccountBinding" field
 	// associating the new account with an external account.
 	ExternalAccountRequired bool `json:"externalAccountRequired"`
+
+	// auto-renewal (optional, object):
+	// If this field is present, the server supports the STAR extension.
+	// - https://www.rfc-editor.org/rfc/rfc8739.html#section-3.1.2
+	AutoRenewal *AutoRenewalMeta `json:"auto-renewal,omitempty"`
+
+	// orderExternalAccountBinding (optional, boolean):
+	// Non-standard: if this field is present and set to "true",
+	// then the CA accepts an "externalAccountBinding" field in new-order requests.
+	OrderExternalAccountBinding bool `json:"orderExternalAccountBinding,omitempty"`
+}
+
+// AutoRenewalMeta the STAR capabilities of the server (related to Meta).
+// - https://www.rfc-editor.org/rfc/rfc8739.html#section-3.1.2
+type AutoRenewalMeta struct {
+	// min-lifetime (required, integer):
+	// Minimum acceptable value for auto-renewal lifetime, in seconds.
+	MinLifetime int `json:"min-lifetime"`
+
+	// max-duration (required, integer):
+	// Maximum allowed delta between the end-date and start-date attributes of the order's auto-renewal object, in seconds.
+	MaxDuration int `json:"max-duration"`
+
+	// allow-certificate-get (optional, boolean):
+	// If this field is present and set to "true", the server allows GET (and HEAD) requests to star-certificate URLs.
+	AllowCertificateGet bool `json:"allow-certificate-get,omitempty"`
 }
 
 // ExtendedAccount an extended Account.
@@ -187,6 +215,45 @@ type Order struct {
 	// previously-issued certificate which this order is intended to replace.
 	// - https://datatracker.ietf.org/doc/html/draft-ietf-acme-ari-03#section-5
 	Replaces string `json:"replaces,omitempty"`
+
+	// auto-renewal (optional, object):
+	// The STAR (short-term, automatically renewed) certificate parameters.
+	// - https://www.rfc-editor.org/rfc/rfc8739.html#section-3.1.1
+	AutoRenewal *AutoRenewal `json:"auto-renewal,omitempty"`
+
+	// star-certificate (optional, string):
+	// A URL for the short-term certificate that is automatically renewed by the server.
+	// - https://www.rfc-editor.org/rfc/rfc8739.html#section-3.1.1
+	StarCertificate string `json:"star-certificate,omitempty"`
+
+	// externalAccountBinding (optional, object):
+	// Non-standard: an External Account Binding attached to the order (instead of the account).
+	// It allows several tenants to share one account but get a tenant-specific issuance policy.
+	ExternalAccountBinding json.RawMessage `json:"externalAccountBinding,omitempty"`
+}
+
+// AutoRenewal the STAR parameters of an order.
+// - https://www.rfc-editor.org/rfc/rfc8739.html#section-3.1.1
+type AutoRenewal struct {
+	// start-date (optional, string):
+	// The earliest date of validity of the first certificate issued, in RFC 3339 format.
+	StartDate string `json:"start-date,omitempty"`
+
+	// end-date (required, string):
+	// The latest date of validity of the last certificate issued, in RFC 3339 format.
+	EndDate string `json:"end-date"`
+
+	// lifetime (required, integer):
+	// The maximum validity period of each STAR certificate, in seconds.
+	Lifetime int `json:"lifetime"`
+
+	// lifetime-adjust (optional, integer):
+	// Amount of "left pad" added to each STAR certificate, in seconds.
+	LifetimeAdjust int `json:"lifetime-adjust,omitempty"`
+
+	// allow-certificate-get (optional, boolean):
+	// See Section 3.4 of RFC 8739 for details.
+	AllowCertificateGet bool `json:"allow-certificate-get,omitempty"`
 }
 
 // Authorization the ACME authorization object.
@@ -222,6 +289,14 @@ type Authorization struct {
 	Wildcard bool `json:"wildcard,omitempty"`
 }
 
+// ExtendedAuthorization an extended Authorization (created by a pre-authorization).
+type ExtendedAuthorization struct {
+	Authorization
+
+	// The authorization URL, contains the value of the response header `Location`
+	Location string `json:"-"`
+}
+
 // ExtendedChallenge a extended Challenge.
 type ExtendedChallenge struct {
 	Challenge
@@ -272,6 +347,11 @@ type Challenge struct {
 
 	// https://www.rfc-editor.org/rfc/rfc8555.html#section-8.1
 	KeyAuthorization string `json:"keyAuthorization"`
+
+	// from (required for email-reply-00, string):
+	// The email address from which the server sends the challenge email.
+	// https://www.rfc-editor.org/rfc/rfc8823.html#section-3
+	From string `json:"from,omitempty"`
 }
 
 // Identifier the ACME identifier object.
@@ -281,6 +361,21 @@ type Identifier struct {
 	Value string `json:"value"`
 }
 
+// OrderIdentifier an identifier of an order, with the information of its authorization.
+type OrderIdentifier struct {
+	// Identifier is the identifier as requested in the order (i.e. with the `*.` prefix for a wildcard).
+	Identifier
+
+	// Wildcard is true if the identifier is a wildcard.
+	Wildcard bool
+
+	// AuthorizationURL is the URL of the authorization of the identifier.
+	AuthorizationURL string
+
+	// ChallengeTypes are the types of the challenges offered by the authorization.
+	ChallengeTypes []string
+}
+
 // CSRMessage Certificate Signing Request.
 // - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.4
 type CSRMessage struct {
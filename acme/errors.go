@@ -8,6 +8,16 @@ import (
 const (
 	errNS       = "urn:ietf:params:acme:error:"
 	BadNonceErr = errNS + "badNonce"
+	BadCSRErr   = errNS + "badCSR"
+
+	OrderNotReadyErr = errNS + "orderNotReady"
+
+	BadSignatureAlgorithmErr = errNS + "badSignatureAlgorithm"
+
+	RateLimitedErr    = errNS + "rateLimited"
+	ServerInternalErr = errNS + "serverInternal"
+
+	AccountDoesNotExistErr = errNS + "accountDoesNotExist"
 )
 
 // ProblemDetails the problem details object.
@@ -20,6 +30,10 @@ type ProblemDetails struct {
 	Instance    string       `json:"instance,omitempty"`
 	SubProblems []SubProblem `json:"subproblems,omitempty"`
 
+	// Algorithms are the signature algorithms supported by the server (badSignatureAlgorithm error).
+	// https://www.rfc-editor.org/rfc/rfc8555.html#section-6.2
+	Algorithms []string `json:"algorithms,omitempty"`
+
 	// additional values to have a better error message (Not defined by the RFC)
 	Method string `json:"method,omitempty"`
 	URL    string `json:"url,omitempty"`
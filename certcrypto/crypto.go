@@ -135,21 +135,96 @@ func GeneratePrivateKey(keyType KeyType) (crypto.PrivateKey, error) {
 	return nil, fmt.Errorf("invalid KeyType: %s", keyType)
 }
 
-func GenerateCSR(privateKey crypto.PrivateKey, domain string, san []string, mustStaple bool) ([]byte, error) {
+// CSROption customizes the template of a CSR (see GenerateCSR).
+type CSROption func(template *x509.CertificateRequest) error
+
+// WithoutCommonName omits the common name: the subject of the CSR is empty, the domains are only in the SANs
+// (as recommended by the CA/Browser Forum Baseline Requirements).
+// Some CAs still require a common name and reject such CSR.
+func WithoutCommonName() CSROption {
+	return func(template *x509.CertificateRequest) error {
+		template.Subject.CommonName = ""
+		return nil
+	}
+}
+
+// WithCSRSignatureAlgorithm signs the CSR with the algorithm, instead of the algorithm derived from the key
+// (e.g. x509.SHA384WithRSA for an RSA 2048 key, required by the policy of some CAs).
+// The algorithm must be compatible with the type of the key.
+func WithCSRSignatureAlgorithm(algorithm x509.SignatureAlgorithm) CSROption {
+	return func(template *x509.CertificateRequest) error {
+		if _, ok := signatureKeyAlgorithms[algorithm]; !ok {
+			return fmt.Errorf("unsupported CSR signature algorithm: %s", algorithm)
+		}
+
+		template.SignatureAlgorithm = algorithm
+		return nil
+	}
+}
+
+// signatureKeyAlgorithms are the signature algorithms supported for the CSR, and their key algorithm.
+var signatureKeyAlgorithms = map[x509.SignatureAlgorithm]x509.PublicKeyAlgorithm{
+	x509.SHA256WithRSA:    x509.RSA,
+	x509.SHA384WithRSA:    x509.RSA,
+	x509.SHA512WithRSA:    x509.RSA,
+	x509.SHA256WithRSAPSS: x509.RSA,
+	x509.SHA384WithRSAPSS: x509.RSA,
+	x509.SHA512WithRSAPSS: x509.RSA,
+	x509.ECDSAWithSHA256:  x509.ECDSA,
+	x509.ECDSAWithSHA384:  x509.ECDSA,
+	x509.ECDSAWithSHA512:  x509.ECDSA,
+	x509.PureEd25519:      x509.Ed25519,
+}
+
+// checkSignatureAlgorithm checks that the signature algorithm (if defined) is compatible with the type of the key.
+func checkSignatureAlgorithm(privateKey crypto.PrivateKey, algorithm x509.SignatureAlgorithm) error {
+	if algorithm == x509.UnknownSignatureAlgorithm {
+		return nil
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type: %T", privateKey)
+	}
+
+	var keyAlgorithm x509.PublicKeyAlgorithm
+
+	switch signer.Public().(type) {
+	case *rsa.PublicKey:
+		keyAlgorithm = x509.RSA
+	case *ecdsa.PublicKey:
+		keyAlgorithm = x509.ECDSA
+	case ed25519.PublicKey:
+		keyAlgorithm = x509.Ed25519
+	}
+
+	if signatureKeyAlgorithms[algorithm] != keyAlgorithm {
+		return fmt.Errorf("the CSR signature algorithm %s is incompatible with the %s key", algorithm, keyAlgorithm)
+	}
+
+	return nil
+}
+
+// GenerateCSR creates a CSR for the domain (common name) and the SANs.
+func GenerateCSR(privateKey crypto.PrivateKey, domain string, san []string, mustStaple bool, opts ...CSROption) ([]byte, error) {
 	var dnsNames []string
 	var ipAddresses []net.IP
+	var emailAddresses []string
 	for _, altname := range san {
 		if ip := net.ParseIP(altname); ip != nil {
 			ipAddresses = append(ipAddresses, ip)
+		} else if strings.Contains(altname, "@") {
+			emailAddresses = append(emailAddresses, altname)
 		} else {
 			dnsNames = append(dnsNames, altname)
 		}
 	}
 
 	template := x509.CertificateRequest{
-		Subject:     pkix.Name{CommonName: domain},
-		DNSNames:    dnsNames,
-		IPAddresses: ipAddresses,
+		Subject:        pkix.Name{CommonName: domain},
+		DNSNames:       dnsNames,
+		IPAddresses:    ipAddresses,
+		EmailAddresses: emailAddresses,
 	}
 
 	if mustStaple {
@@ -159,11 +234,55 @@ func GenerateCSR(privateKey crypto.PrivateKey, domain string, san []string, must
 		})
 	}
 
+	for _, opt := range opts {
+		err := opt(&template)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := checkSignatureAlgorithm(privateKey, template.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
 	return x509.CreateCertificateRequest(rand.Reader, &template, privateKey)
 }
 
+// GenerateCSRFromSigner creates a CSR for the domains, signed by a crypto.Signer.
+// The signer can be an opaque handle (HSM, PKCS#11, KMS, ...): the private key is only used through the Sign method.
+// The first domain is used as common name (if it's short enough), all the domains are added as SANs.
+func GenerateCSRFromSigner(signer crypto.Signer, domains []string, mustStaple bool, opts ...CSROption) (*x509.CertificateRequest, error) {
+	if signer == nil {
+		return nil, errors.New("signer is nil")
+	}
+
+	if len(domains) == 0 {
+		return nil, errors.New("no domains")
+	}
+
+	commonName := ""
+	if len(domains[0]) <= 64 {
+		commonName = domains[0]
+	}
+
+	raw, err := GenerateCSR(signer, commonName, domains, mustStaple, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificateRequest(raw)
+}
+
+// PEMEncode encodes the data to PEM.
+// Returns nil if the type of data is not supported (ex: a private key only available through a crypto.Signer).
 func PEMEncode(data interface{}) []byte {
-	return pem.EncodeToMemory(PEMBlock(data))
+	block := PEMBlock(data)
+	if block == nil {
+		return nil
+	}
+
+	return pem.EncodeToMemory(block)
 }
 
 func PEMBlock(data interface{}) *pem.Block {
@@ -258,6 +377,12 @@ func ExtractDomains(cert *x509.Certificate) []string {
 		}
 	}
 
+	for _, sanEmail := range cert.EmailAddresses {
+		if sanEmail != cert.Subject.CommonName {
+			domains = append(domains, sanEmail)
+		}
+	}
+
 	return domains
 }
 
@@ -285,10 +410,18 @@ func ExtractDomainsCSR(csr *x509.CertificateRequest) []string {
 		}
 	}
 
+	for _, sanEmail := range csr.EmailAddresses {
+		if !slices.Contains(domains, sanEmail) {
+			domains = append(domains, sanEmail)
+		}
+	}
+
 	return domains
 }
 
-func GeneratePemCert(privateKey *rsa.PrivateKey, domain string, extensions []pkix.Extension) ([]byte, error) {
+// GeneratePemCert generates a self-signed certificate for the domain, with the extensions (e.g. for the tls-alpn-01 challenge).
+// The key can be an RSA or an ECDSA key.
+func GeneratePemCert(privateKey crypto.Signer, domain string, extensions []pkix.Extension) ([]byte, error) {
 	derBytes, err := generateDerCert(privateKey, time.Time{}, domain, extensions)
 	if err != nil {
 		return nil, err
@@ -297,7 +430,7 @@ func GeneratePemCert(privateKey *rsa.PrivateKey, domain string, extensions []pki
 	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes}), nil
 }
 
-func generateDerCert(privateKey *rsa.PrivateKey, expiration time.Time, domain string, extensions []pkix.Extension) ([]byte, error) {
+func generateDerCert(privateKey crypto.Signer, expiration time.Time, domain string, extensions []pkix.Extension) ([]byte, error) {
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
 	if err != nil {
@@ -321,6 +454,11 @@ func generateDerCert(privateKey *rsa.PrivateKey, expiration time.Time, domain st
 		ExtraExtensions:       extensions,
 	}
 
+	if _, ok := privateKey.(*ecdsa.PrivateKey); ok {
+		// The key encipherment is only defined for the RSA keys.
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+	}
+
 	// handling SAN filling as type suspected
 	if ip := net.ParseIP(domain); ip != nil {
 		template.IPAddresses = []net.IP{ip}
@@ -328,5 +466,5 @@ func generateDerCert(privateKey *rsa.PrivateKey, expiration time.Time, domain st
 		template.DNSNames = []string{domain}
 	}
 
-	return x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	return x509.CreateCertificate(rand.Reader, &template, &template, privateKey.Public(), privateKey)
 }
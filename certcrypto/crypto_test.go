@@ -3,9 +3,15 @@ package certcrypto
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
 	"encoding/pem"
+	"errors"
+	"io"
 	"regexp"
 	"testing"
 	"time"
@@ -103,6 +109,166 @@ func TestGenerateCSR(t *testing.T) {
 	}
 }
 
+// opaqueSigner is a crypto.Signer which doesn't expose its private key (like an HSM).
+type opaqueSigner struct {
+	t   *testing.T
+	key *ecdsa.PrivateKey
+}
+
+func (s *opaqueSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(rand, digest, opts)
+}
+
+// Bytes simulates the export of the raw private key.
+func (s *opaqueSigner) Bytes() ([]byte, error) {
+	s.t.Error("the private key must not be exported")
+
+	return nil, errors.New("the private key is not exportable")
+}
+
+func TestGenerateCSR_withoutCommonName(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	raw, err := GenerateCSR(privateKey, "example.com", []string{"example.com", "www.example.com"}, false, WithoutCommonName())
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+
+	assert.Empty(t, csr.Subject.CommonName)
+	assert.Empty(t, csr.Subject.String())
+	assert.Equal(t, []string{"example.com", "www.example.com"}, csr.DNSNames)
+}
+
+func TestGenerateCSR_emailAddresses(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	raw, err := GenerateCSR(privateKey, "alice@example.com", []string{"alice@example.com", "bob@example.com", "example.com"}, false)
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, csr.EmailAddresses)
+	assert.Equal(t, []string{"example.com"}, csr.DNSNames)
+
+	assert.Equal(t, []string{"alice@example.com", "example.com", "bob@example.com"}, ExtractDomainsCSR(csr))
+}
+
+func TestGenerateCSR_signatureAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		privateKey    crypto.PrivateKey
+		algorithm     x509.SignatureAlgorithm
+		expectedError string
+	}{
+		{
+			desc:       "RSA key with SHA-384",
+			privateKey: rsaKey,
+			algorithm:  x509.SHA384WithRSA,
+		},
+		{
+			desc:       "RSA key with RSA-PSS",
+			privateKey: rsaKey,
+			algorithm:  x509.SHA256WithRSAPSS,
+		},
+		{
+			desc:       "ECDSA key with SHA-384",
+			privateKey: ecdsaKey,
+			algorithm:  x509.ECDSAWithSHA384,
+		},
+		{
+			desc:       "Ed25519 key",
+			privateKey: ed25519Key,
+			algorithm:  x509.PureEd25519,
+		},
+		{
+			desc:          "ECDSA key with an RSA algorithm",
+			privateKey:    ecdsaKey,
+			algorithm:     x509.SHA384WithRSA,
+			expectedError: "the CSR signature algorithm SHA384-RSA is incompatible with the ECDSA key",
+		},
+		{
+			desc:          "RSA key with an ECDSA algorithm",
+			privateKey:    rsaKey,
+			algorithm:     x509.ECDSAWithSHA256,
+			expectedError: "the CSR signature algorithm ECDSA-SHA256 is incompatible with the RSA key",
+		},
+		{
+			desc:          "unsupported algorithm",
+			privateKey:    rsaKey,
+			algorithm:     x509.SHA1WithRSA,
+			expectedError: "unsupported CSR signature algorithm: SHA1-RSA",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			raw, err := GenerateCSR(test.privateKey, "example.com", []string{"example.com"}, false, WithCSRSignatureAlgorithm(test.algorithm))
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+
+			csr, err := x509.ParseCertificateRequest(raw)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.algorithm, csr.SignatureAlgorithm)
+			require.NoError(t, csr.CheckSignature())
+		})
+	}
+}
+
+func TestGenerateCSRFromSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signer := &opaqueSigner{t: t, key: key}
+
+	csr, err := GenerateCSRFromSigner(signer, []string{"example.com", "www.example.com", "127.0.0.1"}, false)
+	require.NoError(t, err)
+
+	require.NoError(t, csr.CheckSignature())
+
+	assert.Equal(t, key.Public(), csr.PublicKey)
+	assert.Equal(t, "example.com", csr.Subject.CommonName)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, csr.DNSNames)
+	require.Len(t, csr.IPAddresses, 1)
+	assert.Equal(t, "127.0.0.1", csr.IPAddresses[0].String())
+
+	assert.Nil(t, PEMEncode(signer))
+}
+
+func TestGenerateCSRFromSigner_errors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = GenerateCSRFromSigner(nil, []string{"example.com"}, false)
+	require.EqualError(t, err, "signer is nil")
+
+	_, err = GenerateCSRFromSigner(&opaqueSigner{t: t, key: key}, nil, false)
+	require.EqualError(t, err, "no domains")
+}
+
 func TestPEMEncode(t *testing.T) {
 	buf := bytes.NewBufferString("TestingRSAIsSoMuchFun")
 
@@ -0,0 +1,193 @@
+package certcrypto
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+)
+
+var (
+	subjectAltNameOID      = asn1.ObjectIdentifier{2, 5, 29, 17}
+	certificatePoliciesOID = asn1.ObjectIdentifier{2, 5, 29, 32}
+
+	// UPNOID is the type ID of the User Principal Name otherName SAN (Microsoft).
+	UPNOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 20, 2, 3}
+)
+
+// GeneralName tags (RFC 5280, section 4.2.1.6).
+const (
+	nameTypeOther = 0
+	nameTypeEmail = 1
+	nameTypeDNS   = 2
+	nameTypeURI   = 6
+	nameTypeIP    = 7
+)
+
+// OtherName is a SAN of type otherName (RFC 5280, section 4.2.1.6).
+type OtherName struct {
+	TypeID asn1.ObjectIdentifier
+
+	// Value is the DER encoding of the value (ex: an UTF8String for a UPN).
+	Value []byte
+}
+
+// NewUPN returns an otherName SAN containing a User Principal Name (ex: `user@example.com`).
+func NewUPN(upn string) (OtherName, error) {
+	value, err := asn1.MarshalWithParams(upn, "utf8")
+	if err != nil {
+		return OtherName{}, fmt.Errorf("marshal UPN: %w", err)
+	}
+
+	return OtherName{TypeID: UPNOID, Value: value}, nil
+}
+
+// NewCertificatePoliciesExtension returns a certificate policies extension (RFC 5280, section 4.2.1.4)
+// containing the policy OIDs, without qualifiers.
+func NewCertificatePoliciesExtension(policies ...asn1.ObjectIdentifier) (pkix.Extension, error) {
+	if len(policies) == 0 {
+		return pkix.Extension{}, errors.New("no certificate policies")
+	}
+
+	type policyInformation struct {
+		Policy asn1.ObjectIdentifier
+	}
+
+	var infos []policyInformation
+	for _, policy := range policies {
+		infos = append(infos, policyInformation{Policy: policy})
+	}
+
+	value, err := asn1.Marshal(infos)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("marshal certificate policies: %w", err)
+	}
+
+	return pkix.Extension{Id: certificatePoliciesOID, Value: value}, nil
+}
+
+// WithExtensions adds arbitrary extensions to the CSR.
+// The CA decides which extensions of the CSR are copied to the certificate.
+// The SANs must be defined with the domains (and WithOtherNames), not with a subjectAltName extension.
+func WithExtensions(extensions ...pkix.Extension) CSROption {
+	return func(template *x509.CertificateRequest) error {
+		for _, ext := range extensions {
+			if ext.Id.Equal(subjectAltNameOID) {
+				return errors.New("the subjectAltName extension cannot be defined directly: use the domains and WithOtherNames")
+			}
+
+			if slices.ContainsFunc(template.ExtraExtensions, func(e pkix.Extension) bool { return e.Id.Equal(ext.Id) }) {
+				return fmt.Errorf("duplicate extension: %s", ext.Id)
+			}
+
+			template.ExtraExtensions = append(template.ExtraExtensions, ext)
+		}
+
+		return nil
+	}
+}
+
+// WithOtherNames adds SANs of type otherName (ex: a UPN, see NewUPN) to the CSR, in addition to the DNS and IP SANs.
+func WithOtherNames(names ...OtherName) CSROption {
+	return func(template *x509.CertificateRequest) error {
+		if len(names) == 0 {
+			return nil
+		}
+
+		var generalNames []asn1.RawValue
+
+		index := slices.IndexFunc(template.ExtraExtensions, func(e pkix.Extension) bool { return e.Id.Equal(subjectAltNameOID) })
+		if index >= 0 {
+			// the otherNames of a previous option.
+			_, err := asn1.Unmarshal(template.ExtraExtensions[index].Value, &generalNames)
+			if err != nil {
+				return fmt.Errorf("unmarshal subjectAltName: %w", err)
+			}
+		} else {
+			generalNames = templateGeneralNames(template)
+		}
+
+		for _, name := range names {
+			raw, err := marshalOtherName(name)
+			if err != nil {
+				return err
+			}
+
+			generalNames = append(generalNames, raw)
+		}
+
+		value, err := asn1.Marshal(generalNames)
+		if err != nil {
+			return fmt.Errorf("marshal subjectAltName: %w", err)
+		}
+
+		// x509.CreateCertificateRequest uses this extension instead of building one from the template.
+		ext := pkix.Extension{Id: subjectAltNameOID, Value: value}
+
+		if index >= 0 {
+			template.ExtraExtensions[index] = ext
+		} else {
+			template.ExtraExtensions = append(template.ExtraExtensions, ext)
+		}
+
+		return nil
+	}
+}
+
+// templateGeneralNames returns the SANs of the template, as encoded by x509.CreateCertificateRequest.
+func templateGeneralNames(template *x509.CertificateRequest) []asn1.RawValue {
+	var names []asn1.RawValue
+
+	for _, name := range template.DNSNames {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: nameTypeDNS, Bytes: []byte(name)})
+	}
+
+	for _, email := range template.EmailAddresses {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: nameTypeEmail, Bytes: []byte(email)})
+	}
+
+	for _, ip := range template.IPAddresses {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: nameTypeIP, Bytes: normalizeIP(ip)})
+	}
+
+	for _, uri := range template.URIs {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: nameTypeURI, Bytes: []byte(uri.String())})
+	}
+
+	return names
+}
+
+func normalizeIP(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+
+	return ip
+}
+
+// marshalOtherName encodes an otherName: [0] IMPLICIT SEQUENCE { type-id OBJECT IDENTIFIER, value [0] EXPLICIT ANY }.
+func marshalOtherName(name OtherName) (asn1.RawValue, error) {
+	if len(name.TypeID) == 0 {
+		return asn1.RawValue{}, errors.New("otherName: missing type ID")
+	}
+
+	typeID, err := asn1.Marshal(name.TypeID)
+	if err != nil {
+		return asn1.RawValue{}, fmt.Errorf("otherName: marshal type ID: %w", err)
+	}
+
+	value, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: name.Value})
+	if err != nil {
+		return asn1.RawValue{}, fmt.Errorf("otherName: marshal value: %w", err)
+	}
+
+	return asn1.RawValue{
+		Class:      asn1.ClassContextSpecific,
+		Tag:        nameTypeOther,
+		IsCompound: true,
+		Bytes:      append(typeID, value...),
+	}, nil
+}
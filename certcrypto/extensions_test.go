@@ -0,0 +1,145 @@
+package certcrypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCSR_extensions(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	policy := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 55555, 1, 2}
+
+	policies, err := NewCertificatePoliciesExtension(policy)
+	require.NoError(t, err)
+
+	upn, err := NewUPN("user@example.com")
+	require.NoError(t, err)
+
+	raw, err := GenerateCSR(privateKey, "example.com", []string{"example.com", "127.0.0.1"}, true,
+		WithExtensions(policies), WithOtherNames(upn))
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+
+	require.NoError(t, csr.CheckSignature())
+
+	// the DNS and IP SANs are preserved.
+	assert.Equal(t, []string{"example.com"}, csr.DNSNames)
+	require.Len(t, csr.IPAddresses, 1)
+	assert.Equal(t, "127.0.0.1", csr.IPAddresses[0].String())
+
+	extensions := map[string]pkix.Extension{}
+	for _, ext := range csr.Extensions {
+		extensions[ext.Id.String()] = ext
+	}
+
+	// must staple, certificate policies, subjectAltName.
+	assert.Len(t, csr.Extensions, 3)
+
+	// certificate policies
+	require.Contains(t, extensions, certificatePoliciesOID.String())
+
+	var infos []struct {
+		Policy asn1.ObjectIdentifier
+	}
+	_, err = asn1.Unmarshal(extensions[certificatePoliciesOID.String()].Value, &infos)
+	require.NoError(t, err)
+
+	require.Len(t, infos, 1)
+	assert.True(t, infos[0].Policy.Equal(policy))
+
+	// otherName SAN
+	require.Contains(t, extensions, subjectAltNameOID.String())
+
+	var names []asn1.RawValue
+	_, err = asn1.Unmarshal(extensions[subjectAltNameOID.String()].Value, &names)
+	require.NoError(t, err)
+
+	require.Len(t, names, 3)
+
+	other := names[2]
+	assert.Equal(t, asn1.ClassContextSpecific, other.Class)
+	assert.Equal(t, nameTypeOther, other.Tag)
+
+	var otherName struct {
+		TypeID asn1.ObjectIdentifier
+		Value  string `asn1:"explicit,tag:0,utf8"`
+	}
+	rest, err := asn1.UnmarshalWithParams(other.FullBytes, &otherName, "tag:0")
+	require.NoError(t, err)
+	assert.Empty(t, rest)
+
+	assert.True(t, otherName.TypeID.Equal(UPNOID))
+	assert.Equal(t, "user@example.com", otherName.Value)
+}
+
+func TestWithOtherNames_multiple(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	upn1, err := NewUPN("a@example.com")
+	require.NoError(t, err)
+
+	upn2, err := NewUPN("b@example.com")
+	require.NoError(t, err)
+
+	raw, err := GenerateCSR(privateKey, "example.com", []string{"example.com"}, false,
+		WithOtherNames(upn1), WithOtherNames(upn2))
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com"}, csr.DNSNames)
+
+	for _, ext := range csr.Extensions {
+		if !ext.Id.Equal(subjectAltNameOID) {
+			continue
+		}
+
+		var names []asn1.RawValue
+		_, err = asn1.Unmarshal(ext.Value, &names)
+		require.NoError(t, err)
+
+		assert.Len(t, names, 3)
+	}
+}
+
+func TestWithExtensions_errors(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		extensions []pkix.Extension
+		expected   string
+	}{
+		{
+			desc:       "subjectAltName",
+			extensions: []pkix.Extension{{Id: subjectAltNameOID}},
+			expected:   "the subjectAltName extension cannot be defined directly: use the domains and WithOtherNames",
+		},
+		{
+			desc:       "duplicate",
+			extensions: []pkix.Extension{{Id: certificatePoliciesOID}, {Id: certificatePoliciesOID}},
+			expected:   "duplicate extension: 2.5.29.32",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := WithExtensions(test.extensions...)(&x509.CertificateRequest{})
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
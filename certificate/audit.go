@@ -0,0 +1,54 @@
+package certificate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+)
+
+// AuditRecord describes an issued certificate, for an issuance audit log (see CertifierOptions.IssuanceAudit).
+type AuditRecord struct {
+	Domains []string
+	// SerialNumber is the serial number of the certificate, in hexadecimal.
+	SerialNumber string
+	// Issuer is the distinguished name of the issuer of the certificate.
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+
+	AccountURL string
+	OrderURL   string
+	CertURL    string
+}
+
+// audited sends the record of the issued certificate to the issuance audit callback (if defined),
+// and returns the resource only if the record has been accepted.
+func (c *Certifier) audited(orderURL string, certRes *Resource) (*Resource, error) {
+	if c.options.IssuanceAudit == nil {
+		return certRes, nil
+	}
+
+	leaf, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("issuance audit: %w", err)
+	}
+
+	record := AuditRecord{
+		Domains:      certcrypto.ExtractDomains(leaf),
+		SerialNumber: fmt.Sprintf("%x", leaf.SerialNumber),
+		Issuer:       leaf.Issuer.String(),
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+		AccountURL:   c.core.GetAccountURI(),
+		OrderURL:     orderURL,
+		CertURL:      certRes.CertURL,
+	}
+
+	err = c.options.IssuanceAudit(record)
+	if err != nil {
+		return nil, fmt.Errorf("issuance audit: %w", err)
+	}
+
+	return certRes, nil
+}
@@ -0,0 +1,69 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_Obtain_issuanceAudit(t *testing.T) {
+	ca := newCAMock(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", ca.url+"/dir", ca.url+"/account/1", key)
+	require.NoError(t, err)
+
+	var records []AuditRecord
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{
+		KeyType: certcrypto.EC256,
+		IssuanceAudit: func(record AuditRecord) error {
+			records = append(records, record)
+			return nil
+		},
+	})
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com", "www.example.com"}})
+	require.NoError(t, err)
+
+	leaf, err := parseLeaf(certRes.Certificate)
+	require.NoError(t, err)
+
+	require.Len(t, records, 1)
+
+	record := records[0]
+
+	assert.Equal(t, []string{"example.com", "www.example.com"}, record.Domains)
+	assert.Equal(t, leaf.SerialNumber.Text(16), record.SerialNumber)
+	assert.Equal(t, "CN=Mock Intermediate CA", record.Issuer)
+	assert.Equal(t, leaf.NotBefore, record.NotBefore)
+	assert.Equal(t, leaf.NotAfter, record.NotAfter)
+	assert.Equal(t, ca.url+"/account/1", record.AccountURL)
+	assert.True(t, strings.HasPrefix(record.OrderURL, ca.url+"/order/"), record.OrderURL)
+	assert.Equal(t, certRes.CertURL, record.CertURL)
+}
+
+func TestCertifier_Obtain_issuanceAudit_error(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{
+		IssuanceAudit: func(_ AuditRecord) error {
+			return errors.New("audit log unavailable")
+		},
+	})
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}})
+	require.ErrorContains(t, err, "issuance audit: audit log unavailable")
+
+	assert.Nil(t, certRes)
+}
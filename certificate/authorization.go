@@ -1,53 +1,76 @@
 package certificate
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/log"
 )
 
+// getAuthorizations fetches the authorizations of the order concurrently (see CertifierOptions.AuthorizationWorkers).
+// The authorizations are returned in the order of the order.
 func (c *Certifier) getAuthorizations(order acme.ExtendedOrder) ([]acme.Authorization, error) {
-	resc, errc := make(chan acme.Authorization), make(chan domainError)
+	workers := c.options.AuthorizationWorkers
+	if workers <= 0 {
+		workers = DefaultAuthorizationWorkers
+	}
 
 	delay := time.Second / time.Duration(c.overallRequestLimit)
 
-	for _, authzURL := range order.Authorizations {
-		time.Sleep(delay)
+	authorizations := make([]acme.Authorization, len(order.Authorizations))
+	errs := make([]error, len(order.Authorizations))
+
+	sem := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
 
-		go func(authzURL string) {
-			authz, err := c.core.Authorizations.Get(authzURL)
-			if err != nil {
-				errc <- domainError{Domain: authz.Identifier.Value, Error: err}
-				return
-			}
+	for i, authzURL := range order.Authorizations {
+		c.clock.Sleep(delay)
 
-			resc <- authz
-		}(authzURL)
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+
+			authorizations[i], errs[i] = c.core.Authorizations.Get(authzURL)
+		}()
 	}
 
+	wg.Wait()
+
 	var responses []acme.Authorization
 
 	failures := newObtainError()
-	for range len(order.Authorizations) {
-		select {
-		case res := <-resc:
-			responses = append(responses, res)
-		case err := <-errc:
-			failures.Add(err.Domain, err.Error)
+
+	for i, authzURL := range order.Authorizations {
+		if errs[i] != nil {
+			failures.Add(authorizationDomain(order, i), fmt.Errorf("get authorization %s: %w", authzURL, errs[i]))
+			continue
 		}
+
+		responses = append(responses, authorizations[i])
 	}
 
 	for i, auth := range order.Authorizations {
-		log.Infof("[%s] AuthURL: %s", order.Identifiers[i].Value, auth)
+		log.Infof("[%s] AuthURL: %s", authorizationDomain(order, i), auth)
 	}
 
-	close(resc)
-	close(errc)
-
 	return responses, failures.Join()
 }
 
+// authorizationDomain returns the identifier of the i-th authorization of the order,
+// or the authorization URL if the identifiers don't match the authorizations.
+func authorizationDomain(order acme.ExtendedOrder, i int) string {
+	if len(order.Identifiers) != len(order.Authorizations) {
+		return order.Authorizations[i]
+	}
+
+	return order.Identifiers[i].Value
+}
+
 func (c *Certifier) deactivateAuthorizations(order acme.ExtendedOrder, force bool) {
 	for _, authzURL := range order.Authorizations {
 		auth, err := c.core.Authorizations.Get(authzURL)
@@ -0,0 +1,91 @@
+package certificate
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_getAuthorizations(t *testing.T) {
+	ca := newCAMock(t)
+
+	var inFlight, maxInFlight atomic.Int32
+
+	ca.authzHook = func(_ acme.Identifier) *acme.ProblemDetails {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		return nil
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{OverallRequestLimit: 1000, AuthorizationWorkers: 4})
+
+	var domains []string
+	for i := range 20 {
+		domains = append(domains, fmt.Sprintf("%02d.example.com", i))
+	}
+
+	order, err := certifier.core.Orders.New(domains)
+	require.NoError(t, err)
+
+	authz, err := certifier.getAuthorizations(order)
+	require.NoError(t, err)
+
+	// the order of the authorizations is preserved.
+	require.Len(t, authz, len(domains))
+
+	for i, domain := range domains {
+		assert.Equal(t, domain, authz[i].Identifier.Value)
+	}
+
+	// fetched concurrently, within the limit of workers.
+	assert.Greater(t, maxInFlight.Load(), int32(1))
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(4))
+}
+
+func TestCertifier_getAuthorizations_failure(t *testing.T) {
+	ca := newCAMock(t)
+
+	ca.authzHook = func(identifier acme.Identifier) *acme.ProblemDetails {
+		if identifier.Value != "07.example.com" {
+			return nil
+		}
+
+		return &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:malformed", Detail: "authorization unavailable", HTTPStatus: http.StatusNotFound}
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{OverallRequestLimit: 1000})
+
+	var domains []string
+	for i := range 10 {
+		domains = append(domains, fmt.Sprintf("%02d.example.com", i))
+	}
+
+	order, err := certifier.core.Orders.New(domains)
+	require.NoError(t, err)
+
+	authz, err := certifier.getAuthorizations(order)
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "07.example.com: get authorization "+ca.url+"/authz/1/7: acme: error: 404 :: POST :: "+ca.url+"/authz/1/7 :: urn:ietf:params:acme:error:malformed :: authorization unavailable")
+
+	assert.NotContains(t, err.Error(), "06.example.com")
+
+	// the other authorizations are returned.
+	assert.Len(t, authz, 9)
+}
@@ -0,0 +1,513 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/require"
+)
+
+// caMock is a minimal fake ACME server able to issue certificates.
+// All the authorizations are created valid.
+type caMock struct {
+	t   *testing.T
+	mux *http.ServeMux
+	url string
+
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+
+	mu     sync.Mutex
+	serial int64
+	orders map[string]*acme.Order
+
+	// rejectOrder allows to reject the creation of an order.
+	rejectOrder func(order acme.Order) *acme.ProblemDetails
+
+	// renewalInfo allows to define the response of the renewalInfo endpoint.
+	renewalInfo func(certID string) *acme.RenewalInfoResponse
+	// renewalInfoRetryAfter is the Retry-After header of the renewalInfo responses (if defined).
+	renewalInfoRetryAfter time.Duration
+
+	// processing keeps the finalized orders in the processing state.
+	processing bool
+	// invalidOrder makes the finalized orders invalid (with this error) when they are polled.
+	invalidOrder *acme.ProblemDetails
+
+	// rejectCSR allows to reject the CSR of a finalization.
+	rejectCSR func(csr *x509.CertificateRequest) *acme.ProblemDetails
+
+	// authzHook is called for each authorization request, and allows to reject it.
+	authzHook func(identifier acme.Identifier) *acme.ProblemDetails
+
+	// pendingAuthzs keeps the authorizations (and so the new orders) pending.
+	pendingAuthzs bool
+
+	// maxValidity clamps the validity of the issued certificates (if defined).
+	maxValidity time.Duration
+
+	// preAuthzs are the authorizations created by pre-authorization (newAuthz), indexed by identifier value.
+	preAuthzs map[string]*acme.Authorization
+}
+
+func newCAMock(t *testing.T) *caMock {
+	t.Helper()
+
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Mock Intermediate CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	ca := &caMock{
+		t:      t,
+		mux:    mux,
+		url:    apiURL,
+		key:    key,
+		cert:   cert,
+		serial: 100,
+		orders: map[string]*acme.Order{},
+
+		preAuthzs: map[string]*acme.Authorization{},
+	}
+
+	mux.HandleFunc("POST /newOrder", ca.handleNewOrder)
+	mux.HandleFunc("POST /order/{id}", ca.handleOrder)
+	mux.HandleFunc("POST /authz/{id}/{index}", ca.handleAuthz)
+	mux.HandleFunc("POST /newAuthz", ca.handleNewAuthz)
+	mux.HandleFunc("POST /preauthz/{value}", ca.handlePreAuthz)
+	mux.HandleFunc("POST /finalize/{id}", ca.handleFinalize)
+	mux.HandleFunc("POST /cert/{id}", ca.handleCert)
+	mux.HandleFunc("GET /renewalInfo/{id}", ca.handleRenewalInfo)
+
+	return ca
+}
+
+func (m *caMock) newCertifier(options CertifierOptions) *Certifier {
+	m.t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(m.t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", m.url+"/dir", "", key)
+	require.NoError(m.t, err)
+
+	if options.KeyType == "" {
+		options.KeyType = certcrypto.EC256
+	}
+
+	return NewCertifier(core, &resolverMock{}, options)
+}
+
+// issue creates a PEM encoded certificate (bundled with the issuer).
+func (m *caMock) issue(pub any, domains []string, notBefore, notAfter time.Time) []byte {
+	m.t.Helper()
+
+	m.mu.Lock()
+	m.serial++
+	serial := m.serial
+	m.mu.Unlock()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		DNSNames:     domains,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	if len(domains) > 0 {
+		template.Subject = pkix.Name{CommonName: domains[0]}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, m.cert, pub, m.key)
+	require.NoError(m.t, err)
+
+	return append(certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der)), m.issuerPEM()...)
+}
+
+// issueForDomains creates a PEM encoded certificate with a new key.
+func (m *caMock) issueForDomains(domains []string, notAfter time.Time) []byte {
+	m.t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(m.t, err)
+
+	return m.issue(key.Public(), domains, time.Now().Add(-time.Hour), notAfter)
+}
+
+func (m *caMock) issuerPEM() []byte {
+	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(m.cert.Raw))
+}
+
+func (m *caMock) handleNewOrder(w http.ResponseWriter, req *http.Request) {
+	var order acme.Order
+
+	err := readJWSPayload(req, &order)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if m.rejectOrder != nil {
+		if problem := m.rejectOrder(order); problem != nil {
+			writeProblem(w, problem)
+			return
+		}
+	}
+
+	m.mu.Lock()
+	id := strconv.Itoa(len(m.orders) + 1)
+	m.orders[id] = &order
+	m.mu.Unlock()
+
+	order.Status = acme.StatusReady
+	if m.pendingAuthzs {
+		order.Status = acme.StatusPending
+	}
+
+	order.Finalize = m.url + "/finalize/" + id
+
+	order.Authorizations = nil
+	for i, ident := range order.Identifiers {
+		if m.isPreAuthorized(ident.Value) {
+			// the valid pre-authorization is reused.
+			order.Authorizations = append(order.Authorizations, m.url+"/preauthz/"+ident.Value)
+			continue
+		}
+
+		order.Authorizations = append(order.Authorizations, fmt.Sprintf("%s/authz/%s/%d", m.url, id, i))
+	}
+
+	w.Header().Set("Location", m.url+"/order/"+id)
+	w.WriteHeader(http.StatusCreated)
+
+	_ = json.NewEncoder(w).Encode(order)
+}
+
+func (m *caMock) handleOrder(w http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+
+	m.mu.Lock()
+	if order, ok := m.orders[id]; ok && order.Status == acme.StatusProcessing && m.invalidOrder != nil {
+		order.Status = acme.StatusInvalid
+		order.Error = m.invalidOrder
+	}
+
+	m.completeOrder(id)
+	m.mu.Unlock()
+
+	order, ok := m.getOrder(id)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	_ = tester.WriteJSONResponse(w, order)
+}
+
+func (m *caMock) handleAuthz(w http.ResponseWriter, req *http.Request) {
+	order, ok := m.getOrder(req.PathValue("id"))
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	index, err := strconv.Atoi(req.PathValue("index"))
+	if err != nil || index >= len(order.Identifiers) {
+		http.NotFound(w, req)
+		return
+	}
+
+	ident := order.Identifiers[index]
+
+	if m.authzHook != nil {
+		if problem := m.authzHook(ident); problem != nil {
+			writeProblem(w, problem)
+			return
+		}
+	}
+
+	status := acme.StatusValid
+	if m.pendingAuthzs {
+		status = acme.StatusPending
+	}
+
+	authz := acme.Authorization{
+		Status:     status,
+		Identifier: acme.Identifier{Type: ident.Type, Value: strings.TrimPrefix(ident.Value, "*.")},
+		Wildcard:   strings.HasPrefix(ident.Value, "*."),
+		Expires:    time.Now().Add(24 * time.Hour),
+		Challenges: []acme.Challenge{
+			{Type: "http-01", Status: status, URL: m.url + "/chlg/" + req.PathValue("id"), Token: "token"},
+		},
+	}
+
+	_ = tester.WriteJSONResponse(w, authz)
+}
+
+func (m *caMock) handleNewAuthz(w http.ResponseWriter, req *http.Request) {
+	var msg struct {
+		Identifier acme.Identifier `json:"identifier"`
+	}
+
+	err := readJWSPayload(req, &msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authz := &acme.Authorization{
+		Status:     acme.StatusPending,
+		Identifier: msg.Identifier,
+		Expires:    time.Now().Add(24 * time.Hour),
+		Challenges: []acme.Challenge{
+			{Type: "http-01", Status: acme.StatusPending, URL: m.url + "/chlg/pre-" + msg.Identifier.Value, Token: "token"},
+		},
+	}
+
+	m.mu.Lock()
+	m.preAuthzs[msg.Identifier.Value] = authz
+	m.mu.Unlock()
+
+	w.Header().Set("Location", m.url+"/preauthz/"+msg.Identifier.Value)
+	w.WriteHeader(http.StatusCreated)
+
+	_ = json.NewEncoder(w).Encode(authz)
+}
+
+func (m *caMock) handlePreAuthz(w http.ResponseWriter, req *http.Request) {
+	m.mu.Lock()
+	authz, ok := m.preAuthzs[req.PathValue("value")]
+
+	var response acme.Authorization
+	if ok {
+		response = *authz
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	_ = tester.WriteJSONResponse(w, response)
+}
+
+// validatePreAuthz marks the pre-authorization of the identifier as valid (i.e. its challenge is solved).
+func (m *caMock) validatePreAuthz(value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if authz, ok := m.preAuthzs[value]; ok {
+		authz.Status = acme.StatusValid
+		authz.Challenges[0].Status = acme.StatusValid
+	}
+}
+
+func (m *caMock) isPreAuthorized(value string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	authz, ok := m.preAuthzs[value]
+
+	return ok && authz.Status == acme.StatusValid
+}
+
+func (m *caMock) handleFinalize(w http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+
+	order, ok := m.getOrder(id)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	if order.Status == acme.StatusPending {
+		writeProblem(w, &acme.ProblemDetails{
+			Type:       acme.OrderNotReadyErr,
+			Detail:     "Order's status (\"pending\") is not acceptable for finalization",
+			HTTPStatus: http.StatusForbidden,
+		})
+		return
+	}
+
+	var msg acme.CSRMessage
+
+	err := readJWSPayload(req, &msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(msg.Csr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if m.rejectCSR != nil {
+		if problem := m.rejectCSR(csr); problem != nil {
+			writeProblem(w, problem)
+			return
+		}
+	}
+
+	notBefore := time.Now().Add(-time.Minute)
+	if order.NotBefore != "" {
+		notBefore, _ = time.Parse(time.RFC3339, order.NotBefore)
+	}
+
+	notAfter := time.Now().Add(90 * 24 * time.Hour)
+	if order.NotAfter != "" {
+		notAfter, _ = time.Parse(time.RFC3339, order.NotAfter)
+	}
+
+	if m.maxValidity > 0 && notAfter.Sub(notBefore) > m.maxValidity {
+		notAfter = notBefore.Add(m.maxValidity)
+	}
+
+	certPEM := m.issue(csr.PublicKey, certcrypto.ExtractDomainsCSR(csr), notBefore, notAfter)
+
+	m.mu.Lock()
+	m.orders["cert-"+id] = &acme.Order{Certificate: string(certPEM)}
+	m.orders[id].Status = acme.StatusProcessing
+	m.completeOrder(id)
+	result := *m.orders[id]
+	m.mu.Unlock()
+
+	_ = tester.WriteJSONResponse(w, result)
+}
+
+// completeOrder makes a finalized order valid, unless the processing mode is enabled.
+// The caller must hold the lock.
+func (m *caMock) completeOrder(id string) {
+	order, ok := m.orders[id]
+	if !ok || order.Status != acme.StatusProcessing || m.processing {
+		return
+	}
+
+	order.Status = acme.StatusValid
+	order.Certificate = m.url + "/cert/" + id
+}
+
+func (m *caMock) setProcessing(processing bool) {
+	m.mu.Lock()
+	m.processing = processing
+	m.mu.Unlock()
+}
+
+func (m *caMock) handleCert(w http.ResponseWriter, req *http.Request) {
+	order, ok := m.getOrder("cert-" + req.PathValue("id"))
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pem-certificate-chain")
+	_, _ = w.Write([]byte(order.Certificate))
+}
+
+func (m *caMock) handleRenewalInfo(w http.ResponseWriter, req *http.Request) {
+	if m.renewalInfo == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	info := m.renewalInfo(req.PathValue("id"))
+	if info == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	if m.renewalInfoRetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(m.renewalInfoRetryAfter.Seconds())))
+	}
+
+	_ = tester.WriteJSONResponse(w, info)
+}
+
+func (m *caMock) getOrder(id string) (acme.Order, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	order, ok := m.orders[id]
+	if !ok {
+		return acme.Order{}, false
+	}
+
+	return *order, true
+}
+
+// readJWSPayload decodes the payload of a JWS (flattened JSON serialization) without verifying it.
+func readJWSPayload(req *http.Request, v any) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+
+	err = json.Unmarshal(body, &jws)
+	if err != nil {
+		return err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, v)
+}
+
+func writeProblem(w http.ResponseWriter, problem *acme.ProblemDetails) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.HTTPStatus)
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
@@ -2,6 +2,7 @@ package certificate
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/x509"
 	"encoding/base64"
@@ -9,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,7 +19,7 @@ import (
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/log"
-	"github.com/go-acme/lego/v4/platform/wait"
+	"github.com/go-acme/lego/v4/platform/clock"
 	"golang.org/x/crypto/ocsp"
 	"golang.org/x/net/idna"
 )
@@ -31,6 +33,13 @@ const (
 	// ZeroSSL has a limit of 7.
 	// https://help.zerossl.com/hc/en-us/articles/17864245480093-Advantages-over-Using-Let-s-Encrypt#h_01HT4Z1JCJFJQFJ1M3P7S085Q9
 	DefaultOverallRequestLimit = 18
+
+	// DefaultAuthorizationWorkers is the default maximum number of authorizations fetched concurrently.
+	DefaultAuthorizationWorkers = 8
+
+	// DefaultOrderNotReadyRetries is the default maximum number of retries of the finalization of an order
+	// rejected with orderNotReady.
+	DefaultOrderNotReadyRetries = 3
 )
 
 // maxBodySize is the maximum size of body that we will read.
@@ -41,6 +50,8 @@ const maxBodySize = 1024 * 1024
 // already PEM encoded and can be directly written to disk.
 // Certificate may be a certificate bundle,
 // depending on the options supplied to create it.
+// IssuerCertificate is the issuer chain without the leaf certificate,
+// whether the CA returns the full chain or provides the issuer through an "up" link.
 type Resource struct {
 	Domain            string `json:"domain"`
 	CertURL           string `json:"certUrl"`
@@ -49,6 +60,154 @@ type Resource struct {
 	Certificate       []byte `json:"-"`
 	IssuerCertificate []byte `json:"-"`
 	CSR               []byte `json:"-"`
+
+	// ChallengeTypes is the type of the challenge which validated each domain (e.g. "dns-01" for "*.example.com"),
+	// if the resolver reports it (see resolver.Prober.SolveWithReport).
+	ChallengeTypes map[string]challenge.Type `json:"challengeTypes,omitempty"`
+
+	// Signer is the private key supplied by the caller to obtain the certificate (see ObtainRequest.WithPrivateKey),
+	// it is the only reference to the key when the key is opaque (HSM, PKCS#11, ...).
+	Signer crypto.Signer `json:"-"`
+
+	// NotBefore and NotAfter are the validity of the issued certificate (the leaf),
+	// as set by the CA: it may differ from the requested one (e.g. clamped to the maximum validity of the CA).
+	NotBefore time.Time `json:"-"`
+	NotAfter  time.Time `json:"-"`
+}
+
+// SplitChain returns the leaf certificate and each intermediate certificate as separate PEM blocks,
+// preserving the order of the chain.
+// If Certificate is not a bundle, the intermediates are read from IssuerCertificate
+// (the leaf is ignored if IssuerCertificate contains it).
+func (r *Resource) SplitChain() (leaf []byte, intermediates [][]byte, err error) {
+	certs, err := r.chain()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, cert := range certs[1:] {
+		intermediates = append(intermediates, certcrypto.PEMEncode(certcrypto.DERCertificateBytes(cert.Raw)))
+	}
+
+	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(certs[0].Raw)), intermediates, nil
+}
+
+// CertificateDER returns the DER encoding of each certificate of the chain:
+// the leaf certificate first, then the intermediates (same order and rules as SplitChain).
+func (r *Resource) CertificateDER() ([][]byte, error) {
+	certs, err := r.chain()
+	if err != nil {
+		return nil, err
+	}
+
+	ders := make([][]byte, 0, len(certs))
+	for _, cert := range certs {
+		ders = append(ders, cert.Raw)
+	}
+
+	return ders, nil
+}
+
+// VerifyChain verifies that the certificate (the leaf) chains up to one of the roots,
+// through the intermediates of the chain (see SplitChain), at the current time.
+// If roots is nil, the system roots are used.
+// The error details the failure: expired certificate, missing intermediate, untrusted root, etc.
+func (r *Resource) VerifyChain(roots *x509.CertPool) error {
+	certs, err := r.chain()
+	if err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err == nil {
+		return nil
+	}
+
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired {
+		return fmt.Errorf("invalid chain: expired or not yet valid certificate %q: %w", invalidErr.Cert.Subject, err)
+	}
+
+	var authorityErr x509.UnknownAuthorityError
+	if errors.As(err, &authorityErr) {
+		top := chainTop(certs)
+
+		if top.CheckSignatureFrom(top) == nil {
+			return fmt.Errorf("invalid chain: untrusted root %q: %w", top.Subject, err)
+		}
+
+		return fmt.Errorf("invalid chain: the issuer %q of %q is neither in the chain nor a trusted root (missing intermediate?): %w",
+			top.Issuer, top.Subject, err)
+	}
+
+	return fmt.Errorf("invalid chain: %w", err)
+}
+
+// chainTop follows the issuers of the leaf certificate through the chain, and returns the last certificate reached.
+func chainTop(certs []*x509.Certificate) *x509.Certificate {
+	current := certs[0]
+
+	for range certs {
+		var issuer *x509.Certificate
+
+		for _, cert := range certs[1:] {
+			if cert != current && current.CheckSignatureFrom(cert) == nil {
+				issuer = cert
+				break
+			}
+		}
+
+		if issuer == nil {
+			return current
+		}
+
+		current = issuer
+	}
+
+	return current
+}
+
+// chain returns the leaf certificate followed by the intermediates.
+func (r *Resource) chain() ([]*x509.Certificate, error) {
+	certs, err := certcrypto.ParsePEMBundle(r.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	if certs[0].IsCA {
+		return nil, errors.New("certificate bundle starts with a CA certificate")
+	}
+
+	leaf := certs[0]
+	chain := certs[1:]
+
+	if len(chain) == 0 && len(r.IssuerCertificate) > 0 {
+		chain, err = certcrypto.ParsePEMBundle(r.IssuerCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("issuer certificate: %w", err)
+		}
+	}
+
+	result := []*x509.Certificate{leaf}
+
+	for _, cert := range chain {
+		if cert.Equal(leaf) {
+			continue
+		}
+
+		result = append(result, cert)
+	}
+
+	return result, nil
 }
 
 // ObtainRequest The request to obtain certificate.
@@ -59,6 +218,8 @@ type Resource struct {
 // A new private key is generated for every invocation of the function Obtain.
 // If you do not want that you can supply your own private key in the privateKey parameter.
 // If this parameter is non-nil it will be used instead of generating a new one.
+// The private key can be an opaque crypto.Signer (HSM, PKCS#11, ...):
+// in this case, the PrivateKey field of the resulting Resource is empty.
 //
 // If `Bundle` is true, the `[]byte` contains both the issuer certificate and your issued certificate as a bundle.
 //
@@ -78,6 +239,38 @@ type ObtainRequest struct {
 	// order is intended to replace.
 	// - https://datatracker.ietf.org/doc/html/draft-ietf-acme-ari-03#section-5
 	ReplacesCertID string
+	// AutoRenewal requests a STAR (short-term, automatically renewed) certificate,
+	// the CertURL of the resulting Resource is the star-certificate URL (see Certifier.GetShortTerm).
+	// - https://www.rfc-editor.org/rfc/rfc8739.html
+	AutoRenewal *api.AutoRenewalOptions
+	// ExternalAccountBinding attaches EAB credentials to the order (non-standard, see api.OrderOptions).
+	ExternalAccountBinding *api.EABOptions
+	// FinalizeTimeout is the maximum time to wait for the order to become valid after the finalization.
+	// Overrides CertifierOptions.Timeout.
+	// When the timeout is reached, a *FinalizeTimeoutError is returned.
+	FinalizeTimeout time.Duration
+	// FinalizeExtra are extra (vendor-specific) fields merged into the payload of the finalize request
+	// (e.g. a chain preference for the CAs supporting a finalize-time hint, see api.WithFinalizeExtra).
+	FinalizeExtra map[string]any
+	// CSROptions customizes the generated CSR
+	// (e.g. certcrypto.WithoutCommonName, certcrypto.WithExtensions, certcrypto.WithOtherNames).
+	CSROptions []certcrypto.CSROption
+}
+
+// WithPrivateKey returns a copy of the request using the private key supplied by the caller
+// (e.g. generated with a specific RNG or by a FIPS module) to build the CSR, instead of a generated key.
+// The key is recorded in the Signer field of the resulting Resource.
+func (r ObtainRequest) WithPrivateKey(signer crypto.Signer) ObtainRequest {
+	r.PrivateKey = signer
+	return r
+}
+
+// WithLifetime returns a copy of the request asking for a certificate valid from now for the duration:
+// NotBefore is set to now, and NotAfter to now + lifetime.
+// The CA may clamp the validity (see Resource.NotAfter), or reject the order if it doesn't support these fields.
+func (r ObtainRequest) WithLifetime(lifetime time.Duration) ObtainRequest {
+	r.NotBefore, r.NotAfter = lifetimeValidity(lifetime)
+	return r
 }
 
 // ObtainForCSRRequest The request to obtain a certificate matching the CSR passed into it.
@@ -88,6 +281,9 @@ type ObtainRequest struct {
 // See https://datatracker.ietf.org/doc/html/rfc8555#section-7.5.2.
 type ObtainForCSRRequest struct {
 	CSR *x509.CertificateRequest
+	// Domains are the domains expected in the CSR.
+	// If defined, the CSR is checked with ValidateCSR before the creation of the order.
+	Domains []string
 
 	NotBefore                      time.Time
 	NotAfter                       time.Time
@@ -98,16 +294,70 @@ type ObtainForCSRRequest struct {
 	// order is intended to replace.
 	// - https://datatracker.ietf.org/doc/html/draft-ietf-acme-ari-03#section-5
 	ReplacesCertID string
+	// AutoRenewal requests a STAR (short-term, automatically renewed) certificate,
+	// the CertURL of the resulting Resource is the star-certificate URL (see Certifier.GetShortTerm).
+	// - https://www.rfc-editor.org/rfc/rfc8739.html
+	AutoRenewal *api.AutoRenewalOptions
+	// ExternalAccountBinding attaches EAB credentials to the order (non-standard, see api.OrderOptions).
+	ExternalAccountBinding *api.EABOptions
+	// FinalizeTimeout is the maximum time to wait for the order to become valid after the finalization.
+	// Overrides CertifierOptions.Timeout.
+	// When the timeout is reached, a *FinalizeTimeoutError is returned.
+	FinalizeTimeout time.Duration
+	// FinalizeExtra are extra (vendor-specific) fields merged into the payload of the finalize request
+	// (e.g. a chain preference for the CAs supporting a finalize-time hint, see api.WithFinalizeExtra).
+	FinalizeExtra map[string]any
+}
+
+// WithLifetime returns a copy of the request asking for a certificate valid from now for the duration
+// (see ObtainRequest.WithLifetime).
+func (r ObtainForCSRRequest) WithLifetime(lifetime time.Duration) ObtainForCSRRequest {
+	r.NotBefore, r.NotAfter = lifetimeValidity(lifetime)
+	return r
+}
+
+// lifetimeValidity returns the validity period starting now, truncated to the second (the precision of the order fields).
+func lifetimeValidity(lifetime time.Duration) (notBefore, notAfter time.Time) {
+	now := time.Now().UTC().Truncate(time.Second)
+	return now, now.Add(lifetime)
 }
 
 type resolver interface {
 	Solve(authorizations []acme.Authorization) error
 }
 
+// Interface for the resolvers reporting the type of the challenge which validated each authorization.
+type reporter interface {
+	SolveWithReport(authorizations []acme.Authorization) (map[string]challenge.Type, error)
+}
+
 type CertifierOptions struct {
 	KeyType             certcrypto.KeyType
 	Timeout             time.Duration
 	OverallRequestLimit int
+
+	// AuthorizationWorkers is the maximum number of authorizations of an order fetched concurrently
+	// (DefaultAuthorizationWorkers by default).
+	// The requests are still paced by the OverallRequestLimit.
+	AuthorizationWorkers int
+
+	// OrderNotReadyRetries is the maximum number of retries of the finalization
+	// when the CA rejects it with orderNotReady (DefaultOrderNotReadyRetries by default, a negative value disables the retries).
+	// Before each retry, the status of the order is polled, with an exponential backoff.
+	OrderNotReadyRetries int
+
+	// IssuanceAudit is called with the record of each issued certificate (obtained, renewed, or resumed),
+	// before the certificate is returned.
+	// If it returns an error, the certificate is not returned: a certificate is never delivered without being audited.
+	IssuanceAudit func(AuditRecord) error
+
+	// Clock provides the time of the renewal decisions and of the waits (clock.Real by default),
+	// e.g. a clock.Fake for deterministic tests.
+	Clock clock.Clock
+
+	// RenewBefore is the remaining validity under which Certifier.RenewIfDue renews a certificate
+	// when the renewalInfo endpoint is not available (DefaultRenewBefore by default).
+	RenewBefore time.Duration
 }
 
 // Certifier A service to obtain/renew/revoke certificates.
@@ -116,6 +366,7 @@ type Certifier struct {
 	resolver            resolver
 	options             CertifierOptions
 	overallRequestLimit int
+	clock               clock.Clock
 }
 
 // NewCertifier creates a Certifier.
@@ -124,6 +375,7 @@ func NewCertifier(core *api.Core, resolver resolver, options CertifierOptions) *
 		core:     core,
 		resolver: resolver,
 		options:  options,
+		clock:    clock.OrReal(options.Clock),
 	}
 
 	c.overallRequestLimit = options.OverallRequestLimit
@@ -139,6 +391,11 @@ func NewCertifier(core *api.Core, resolver resolver, options CertifierOptions) *
 // This function will never return a partial certificate.
 // If one domain in the list fails, the whole certificate will fail.
 func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
+	return c.ObtainWithContext(context.Background(), request)
+}
+
+// ObtainWithContext is like Obtain, but the wait for the finalization of the order can be canceled through the context.
+func (c *Certifier) ObtainWithContext(ctx context.Context, request ObtainRequest) (*Resource, error) {
 	if len(request.Domains) == 0 {
 		return nil, errors.New("no domains to obtain a certificate for")
 	}
@@ -155,6 +412,9 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		NotBefore:      request.NotBefore,
 		NotAfter:       request.NotAfter,
 		ReplacesCertID: request.ReplacesCertID,
+		AutoRenewal:    request.AutoRenewal,
+
+		ExternalAccountBinding: request.ExternalAccountBinding,
 	}
 
 	order, err := c.core.Orders.NewWithOptions(domains, orderOpts)
@@ -169,7 +429,7 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		return nil, err
 	}
 
-	err = c.resolver.Solve(authz)
+	challengeTypes, err := c.solve(authz)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
 		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
@@ -179,13 +439,25 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 	log.Infof("[%s] acme: Validations succeeded; requesting certificates", strings.Join(domains, ", "))
 
 	failures := newObtainError()
-	cert, err := c.getForOrder(domains, order, request.Bundle, request.PrivateKey, request.MustStaple, request.PreferredChain)
+	opts := finalizeOptions{
+		bundle:         request.Bundle,
+		preferredChain: request.PreferredChain,
+		timeout:        request.FinalizeTimeout,
+		csrOptions:     request.CSROptions,
+		extra:          request.FinalizeExtra,
+	}
+
+	cert, err := c.getForOrder(ctx, domains, order, request.PrivateKey, request.MustStaple, opts)
 	if err != nil {
 		for _, auth := range authz {
 			failures.Add(challenge.GetTargetedDomain(auth), err)
 		}
 	}
 
+	if cert != nil {
+		cert.ChallengeTypes = challengeTypes
+	}
+
 	if request.AlwaysDeactivateAuthorizations {
 		c.deactivateAuthorizations(order, true)
 	}
@@ -196,17 +468,30 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 // ObtainForCSR tries to obtain a certificate matching the CSR passed into it.
 //
 // The domains are inferred from the CommonName and SubjectAltNames, if any.
-// The private key for this CSR is not required.
+// The private key for this CSR is not required (and never used):
+// the CSR can be built by an external signer (see certcrypto.GenerateCSRFromSigner).
 //
 // If bundle is true, the []byte contains both the issuer certificate and your issued certificate as a bundle.
 //
 // This function will never return a partial certificate.
 // If one domain in the list fails, the whole certificate will fail.
 func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error) {
+	return c.ObtainForCSRWithContext(context.Background(), request)
+}
+
+// ObtainForCSRWithContext is like ObtainForCSR, but the wait for the finalization of the order can be canceled through the context.
+func (c *Certifier) ObtainForCSRWithContext(ctx context.Context, request ObtainForCSRRequest) (*Resource, error) {
 	if request.CSR == nil {
 		return nil, errors.New("cannot obtain resource for CSR: CSR is missing")
 	}
 
+	if len(request.Domains) > 0 {
+		err := ValidateCSR(request.CSR, request.Domains)
+		if err != nil {
+			return nil, fmt.Errorf("cannot obtain resource for CSR: %w", err)
+		}
+	}
+
 	// figure out what domains it concerns
 	// start with the common name
 	domains := certcrypto.ExtractDomainsCSR(request.CSR)
@@ -221,6 +506,9 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		NotBefore:      request.NotBefore,
 		NotAfter:       request.NotAfter,
 		ReplacesCertID: request.ReplacesCertID,
+		AutoRenewal:    request.AutoRenewal,
+
+		ExternalAccountBinding: request.ExternalAccountBinding,
 	}
 
 	order, err := c.core.Orders.NewWithOptions(domains, orderOpts)
@@ -235,7 +523,7 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		return nil, err
 	}
 
-	err = c.resolver.Solve(authz)
+	challengeTypes, err := c.solve(authz)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
 		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
@@ -245,7 +533,14 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 	log.Infof("[%s] acme: Validations succeeded; requesting certificates", strings.Join(domains, ", "))
 
 	failures := newObtainError()
-	cert, err := c.getForCSR(domains, order, request.Bundle, request.CSR.Raw, nil, request.PreferredChain)
+	opts := finalizeOptions{
+		bundle:         request.Bundle,
+		preferredChain: request.PreferredChain,
+		timeout:        request.FinalizeTimeout,
+		extra:          request.FinalizeExtra,
+	}
+
+	cert, err := c.getForCSR(ctx, domains, order, request.CSR.Raw, nil, opts)
 	if err != nil {
 		for _, auth := range authz {
 			failures.Add(challenge.GetTargetedDomain(auth), err)
@@ -259,18 +554,46 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 	if cert != nil {
 		// Add the CSR to the certificate so that it can be used for renewals.
 		cert.CSR = certcrypto.PEMEncode(request.CSR)
+		cert.ChallengeTypes = challengeTypes
 	}
 
 	return cert, failures.Join()
 }
 
-func (c *Certifier) getForOrder(domains []string, order acme.ExtendedOrder, bundle bool, privateKey crypto.PrivateKey, mustStaple bool, preferredChain string) (*Resource, error) {
+// solve solves the challenges of the authorizations,
+// and returns the type of the challenge which validated each authorization if the resolver reports it.
+func (c *Certifier) solve(authz []acme.Authorization) (map[string]challenge.Type, error) {
+	if solvr, ok := c.resolver.(reporter); ok {
+		return solvr.SolveWithReport(authz)
+	}
+
+	return nil, c.resolver.Solve(authz)
+}
+
+// finalizeOptions are the options related to the retrieval of the certificate after the finalization of the order.
+type finalizeOptions struct {
+	bundle         bool
+	preferredChain string
+	timeout        time.Duration
+	csrOptions     []certcrypto.CSROption
+	extra          map[string]any
+}
+
+func (c *Certifier) getForOrder(ctx context.Context, domains []string, order acme.ExtendedOrder, privateKey crypto.PrivateKey, mustStaple bool, opts finalizeOptions) (*Resource, error) {
+	var signer crypto.Signer
+
 	if privateKey == nil {
 		var err error
 		privateKey, err = certcrypto.GeneratePrivateKey(c.options.KeyType)
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		var ok bool
+		signer, ok = privateKey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type: %T", privateKey)
+		}
 	}
 
 	commonName := ""
@@ -296,17 +619,52 @@ func (c *Certifier) getForOrder(domains []string, order acme.ExtendedOrder, bund
 		}
 	}
 
-	// TODO: should the CSR be customizable?
-	csr, err := certcrypto.GenerateCSR(privateKey, commonName, san, mustStaple)
+	csr, err := certcrypto.GenerateCSR(privateKey, commonName, san, mustStaple, opts.csrOptions...)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.getForCSR(domains, order, bundle, csr, certcrypto.PEMEncode(privateKey), preferredChain)
+	certRes, err := c.getForCSR(ctx, domains, order, csr, certcrypto.PEMEncode(privateKey), opts)
+	if err != nil && isRejectedWithoutCommonName(csr, err) {
+		return nil, fmt.Errorf("the CA rejected the CSR without common name (it may require a common name): %w", err)
+	}
+
+	if err != nil || signer == nil {
+		return certRes, err
+	}
+
+	err = checkPublicKey(certRes.Certificate, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	certRes.Signer = signer
+
+	return certRes, nil
+}
+
+// checkPublicKey checks that the leaf certificate has been issued for the public key.
+func checkPublicKey(certPEM []byte, pub crypto.PublicKey) error {
+	leaf, err := certcrypto.ParsePEMCertificate(certPEM)
+	if err != nil {
+		return err
+	}
+
+	key, ok := pub.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !key.Equal(leaf.PublicKey) {
+		return errors.New("the public key of the certificate doesn't match the private key")
+	}
+
+	return nil
 }
 
-func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle bool, csr, privateKeyPem []byte, preferredChain string) (*Resource, error) {
-	respOrder, err := c.core.Orders.UpdateForCSR(order.Finalize, csr)
+func (c *Certifier) getForCSR(ctx context.Context, domains []string, order acme.ExtendedOrder, csr, privateKeyPem []byte, opts finalizeOptions) (*Resource, error) {
+	var finalizeOpts []api.FinalizeOption
+	if len(opts.extra) > 0 {
+		finalizeOpts = append(finalizeOpts, api.WithFinalizeExtra(opts.extra))
+	}
+
+	respOrder, err := c.finalize(ctx, order, csr, finalizeOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -319,36 +677,206 @@ func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle
 
 	if respOrder.Status == acme.StatusValid {
 		// if the certificate is available right away, shortcut!
-		ok, errR := c.checkResponse(respOrder, certRes, bundle, preferredChain)
+		ok, errR := c.checkResponse(respOrder, certRes, opts.bundle, opts.preferredChain)
 		if errR != nil {
 			return nil, errR
 		}
 
 		if ok {
-			return certRes, nil
+			errV := setValidity(certRes, order.NotAfter)
+			if errV != nil {
+				return nil, errV
+			}
+
+			return c.audited(order.Location, certRes)
 		}
 	}
 
-	timeout := c.options.Timeout
-	if c.options.Timeout <= 0 {
+	timeout := opts.timeout
+	if timeout <= 0 {
+		timeout = c.options.Timeout
+	}
+
+	if timeout <= 0 {
 		timeout = 30 * time.Second
 	}
 
-	err = wait.For("certificate", timeout, timeout/60, func() (bool, error) {
+	err = waitForOrder(ctx, c.clock, timeout, timeout/60, func() (bool, error) {
 		ord, errW := c.core.Orders.Get(order.Location)
 		if errW != nil {
 			return false, errW
 		}
 
-		done, errW := c.checkResponse(ord, certRes, bundle, preferredChain)
+		if ord.Status == acme.StatusInvalid {
+			// the order will never become valid: stop waiting.
+			if ord.Error != nil {
+				return true, ord.Error
+			}
+
+			return true, errors.New("the order is invalid")
+		}
+
+		done, errW := c.checkResponse(ord, certRes, opts.bundle, opts.preferredChain)
 		if errW != nil {
 			return false, errW
 		}
 
 		return done, nil
 	})
+	if err != nil {
+		// only the finalize timeout is resumable:
+		// the invalid orders and the cancellation of the context are returned unchanged.
+		if ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
 
-	return certRes, err
+		return nil, &FinalizeTimeoutError{
+			Domain:     certRes.Domain,
+			OrderURL:   order.Location,
+			PrivateKey: privateKeyPem,
+			Err:        err,
+		}
+	}
+
+	err = setValidity(certRes, order.NotAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.audited(order.Location, certRes)
+}
+
+// setValidity sets the validity of the issued certificate in the resource,
+// and warns if the CA didn't honor the requested expiration date (notAfter of the order).
+func setValidity(certRes *Resource, requestedNotAfter string) error {
+	leaf, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return err
+	}
+
+	certRes.NotBefore = leaf.NotBefore
+	certRes.NotAfter = leaf.NotAfter
+
+	if requestedNotAfter == "" {
+		return nil
+	}
+
+	requested, err := time.Parse(time.RFC3339, requestedNotAfter)
+	if err == nil && !requested.Equal(leaf.NotAfter) {
+		log.Warnf("[%s] acme: the certificate expires at %s instead of the requested %s",
+			certRes.Domain, leaf.NotAfter.Format(time.RFC3339), requested.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// isRejectedWithoutCommonName returns true if the CSR has no common name and has been rejected by the CA (badCSR).
+func isRejectedWithoutCommonName(csr []byte, err error) bool {
+	var problem *acme.ProblemDetails
+	if !errors.As(err, &problem) || problem.Type != acme.BadCSRErr {
+		return false
+	}
+
+	parsed, errP := x509.ParseCertificateRequest(csr)
+	if errP != nil {
+		return false
+	}
+
+	return parsed.Subject.CommonName == ""
+}
+
+// waitForOrder polls the given function f, once every interval, until it returns true, the timeout is reached, or the context is canceled.
+// The timeout is measured by the clock (context.DeadlineExceeded is returned when it is reached).
+// When f returns true with an error, the error is returned immediately.
+func waitForOrder(ctx context.Context, clk clock.Clock, timeout, interval time.Duration, f func() (bool, error)) error {
+	log.Infof("Wait for certificate [timeout: %s, interval: %s]", timeout, interval)
+
+	deadline := clk.Now().Add(timeout)
+
+	var lastErr error
+
+	for {
+		stop, err := f()
+		if stop {
+			return err
+		}
+
+		if err != nil {
+			lastErr = err
+		}
+
+		errW := ctx.Err()
+
+		if errW == nil {
+			remaining := deadline.Sub(clk.Now())
+			if remaining <= 0 {
+				errW = context.DeadlineExceeded
+			} else {
+				errW = sleep(ctx, clk, min(interval, remaining))
+			}
+		}
+
+		if errW == nil {
+			continue
+		}
+
+		if lastErr == nil {
+			return errW
+		}
+
+		return fmt.Errorf("%w: last error: %w", errW, lastErr)
+	}
+}
+
+// sleep pauses for the duration d (measured by the clock), or until the context is canceled.
+func sleep(ctx context.Context, clk clock.Clock, d time.Duration) error {
+	done := make(chan struct{})
+
+	go func() {
+		clk.Sleep(d)
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
+// ResumeOrder retrieves the certificate of an order for which the wait for the finalization has been abandoned (see FinalizeTimeoutError).
+// It returns an error if the order is still not valid.
+func (c *Certifier) ResumeOrder(timeoutErr *FinalizeTimeoutError, bundle bool, preferredChain string) (*Resource, error) {
+	if timeoutErr == nil {
+		return nil, errors.New("missing finalize timeout error")
+	}
+
+	order, err := c.core.Orders.Get(timeoutErr.OrderURL)
+	if err != nil {
+		return nil, err
+	}
+
+	certRes := &Resource{
+		Domain:     timeoutErr.Domain,
+		PrivateKey: timeoutErr.PrivateKey,
+	}
+
+	ok, err := c.checkResponse(order, certRes, bundle, preferredChain)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("order %s is not valid yet: %s", timeoutErr.OrderURL, order.Status)
+	}
+
+	err = setValidity(certRes, order.NotAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.audited(timeoutErr.OrderURL, certRes)
 }
 
 // checkResponse checks to see if the certificate is ready and a link is contained in the response.
@@ -365,16 +893,22 @@ func (c *Certifier) checkResponse(order acme.ExtendedOrder, certRes *Resource, b
 		return valid, err
 	}
 
-	certs, err := c.core.Certificates.GetAll(order.Certificate, bundle)
+	certURL := order.Certificate
+	if certURL == "" {
+		// STAR order: the certificate is automatically renewed by the server.
+		certURL = order.StarCertificate
+	}
+
+	certs, err := c.core.Certificates.GetAll(certURL, bundle)
 	if err != nil {
 		return false, err
 	}
 
 	// Set the default certificate
-	certRes.IssuerCertificate = certs[order.Certificate].Issuer
-	certRes.Certificate = certs[order.Certificate].Cert
-	certRes.CertURL = order.Certificate
-	certRes.CertStableURL = order.Certificate
+	certRes.IssuerCertificate = certs[certURL].Issuer
+	certRes.Certificate = certs[certURL].Cert
+	certRes.CertURL = certURL
+	certRes.CertStableURL = certURL
 
 	if preferredChain == "" {
 		log.Infof("[%s] Server responded with a certificate.", certRes.Domain)
@@ -382,26 +916,20 @@ func (c *Certifier) checkResponse(order acme.ExtendedOrder, certRes *Resource, b
 		return true, nil
 	}
 
-	for link, cert := range certs {
-		ok, err := hasPreferredChain(cert.Issuer, preferredChain)
-		if err != nil {
-			return false, err
-		}
-
-		if ok {
-			log.Infof("[%s] Server responded with a certificate for the preferred certificate chains %q.", certRes.Domain, preferredChain)
+	link, err := selectPreferredChain(certURL, certs, preferredChain)
+	if err != nil {
+		return false, err
+	}
 
-			certRes.IssuerCertificate = cert.Issuer
-			certRes.Certificate = cert.Cert
-			certRes.CertURL = link
-			certRes.CertStableURL = link
+	if link != certURL {
+		log.Infof("[%s] Server responded with a certificate for the preferred certificate chains %q.", certRes.Domain, preferredChain)
 
-			return true, nil
-		}
+		certRes.IssuerCertificate = certs[link].Issuer
+		certRes.Certificate = certs[link].Cert
+		certRes.CertURL = link
+		certRes.CertStableURL = link
 	}
 
-	log.Infof("lego has been configured to prefer certificate chains with issuer %q, but no chain from the CA matched this issuer. Using the default certificate chain instead.", preferredChain)
-
 	return true, nil
 }
 
@@ -486,7 +1014,7 @@ func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*
 	}
 
 	// This is just meant to be informal for the user.
-	timeLeft := x509Cert.NotAfter.Sub(time.Now().UTC())
+	timeLeft := x509Cert.NotAfter.Sub(c.clock.Now().UTC())
 	log.Infof("[%s] acme: Trying renewal with %d hours remaining", certRes.Domain, int(timeLeft.Hours()))
 
 	// We always need to request a new certificate to renew.
@@ -628,6 +1156,100 @@ func (c *Certifier) Get(url string, bundle bool) (*Resource, error) {
 		return nil, err
 	}
 
+	return newResource(url, cert, issuer)
+}
+
+// GetOptions options used by Certifier.GetWithOptions.
+type GetOptions struct {
+	// Bundle includes the issuer certificate in the Certificate field of the Resource.
+	Bundle bool
+
+	// PreferredChain selects the chain (default or "alternate") with the given top certificate issuer common name.
+	// The default chain is used if no chain matches.
+	PreferredChain string
+}
+
+// GetWithOptions downloads a previously issued certificate from its URL (without re-issuing it),
+// the "alternate" chains are used to find the preferred chain.
+//
+// A CertificateNotFoundError is returned if the certificate is not available anymore (expired or purged).
+//
+// The returned Resource will not have the PrivateKey and CSR fields populated as these will not be available.
+func (c *Certifier) GetWithOptions(url string, options *GetOptions) (*Resource, error) {
+	if options == nil {
+		options = &GetOptions{}
+	}
+
+	certs, err := c.core.Certificates.GetAll(url, options.Bundle)
+	if err != nil {
+		var problem *acme.ProblemDetails
+		if errors.As(err, &problem) && problem.HTTPStatus == http.StatusNotFound {
+			return nil, &CertificateNotFoundError{URL: url, Err: err}
+		}
+
+		return nil, err
+	}
+
+	link := url
+
+	if options.PreferredChain != "" {
+		link, err = selectPreferredChain(url, certs, options.PreferredChain)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newResource(link, certs[link].Cert, certs[link].Issuer)
+}
+
+// selectPreferredChain returns the link of the chain matching the preferred chain, the default link otherwise.
+func selectPreferredChain(defaultLink string, certs map[string]*acme.RawCertificate, preferredChain string) (string, error) {
+	var alternates []string
+	for link := range certs {
+		if link != defaultLink {
+			alternates = append(alternates, link)
+		}
+	}
+
+	slices.Sort(alternates)
+
+	for _, link := range append([]string{defaultLink}, alternates...) {
+		if len(certs[link].Issuer) == 0 {
+			continue
+		}
+
+		ok, err := hasPreferredChain(certs[link].Issuer, preferredChain)
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			return link, nil
+		}
+	}
+
+	log.Infof("lego has been configured to prefer certificate chains with issuer %q, but no chain from the CA matched this issuer. Using the default certificate chain instead.", preferredChain)
+
+	return defaultLink, nil
+}
+
+// GetShortTerm returns the current short-term certificate of a STAR order, using the star-certificate URL.
+// If allowGet is true (the order has been created with AllowCertificateGet),
+// the certificate is fetched without authentication.
+//
+// The returned Resource will not have the PrivateKey and CSR fields populated as these will not be available.
+//
+// - https://www.rfc-editor.org/rfc/rfc8739.html#section-3.3
+func (c *Certifier) GetShortTerm(starCertURL string, bundle, allowGet bool) (*Resource, error) {
+	cert, issuer, err := c.core.Certificates.GetShortTerm(starCertURL, bundle, allowGet)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource(starCertURL, cert, issuer)
+}
+
+func newResource(url string, cert, issuer []byte) (*Resource, error) {
 	// Parse the returned cert bundle so that we can grab the domain from the common name.
 	x509Certs, err := certcrypto.ParsePEMBundle(cert)
 	if err != nil {
@@ -645,6 +1267,8 @@ func (c *Certifier) Get(url string, bundle bool) (*Resource, error) {
 		IssuerCertificate: issuer,
 		CertURL:           url,
 		CertStableURL:     url,
+		NotBefore:         x509Certs[0].NotBefore,
+		NotAfter:          x509Certs[0].NotAfter,
 	}, nil
 }
 
@@ -682,7 +1306,7 @@ func checkOrderStatus(order acme.ExtendedOrder) (bool, error) {
 func sanitizeDomain(domains []string) []string {
 	var sanitizedDomains []string
 	for _, domain := range domains {
-		sanitizedDomain, err := idna.ToASCII(domain)
+		sanitizedDomain, err := toASCII(domain)
 		if err != nil {
 			log.Infof("skip domain %q: unable to sanitize (punnycode): %v", domain, err)
 		} else {
@@ -691,3 +1315,19 @@ func sanitizeDomain(domains []string) []string {
 	}
 	return sanitizedDomains
 }
+
+// toASCII converts the domain to ASCII (punycode).
+// Only the domain part of an email address is converted, the local part is kept as-is.
+func toASCII(domain string) (string, error) {
+	i := strings.LastIndex(domain, "@")
+	if i < 0 {
+		return idna.ToASCII(domain)
+	}
+
+	host, err := idna.ToASCII(domain[i+1:])
+	if err != nil {
+		return "", err
+	}
+
+	return domain[:i+1] + host, nil
+}
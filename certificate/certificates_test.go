@@ -1,16 +1,25 @@
 package certificate
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -214,6 +223,47 @@ func Test_checkResponse(t *testing.T) {
 	assert.Equal(t, issuerMock, string(certRes.IssuerCertificate), "IssuerCertificate")
 }
 
+func Test_checkResponse_starCertificate(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("/star-certificate", func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(certResponseMock))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	order := acme.ExtendedOrder{
+		Order: acme.Order{
+			Status:          acme.StatusValid,
+			StarCertificate: apiURL + "/star-certificate",
+		},
+	}
+	certRes := &Resource{}
+
+	valid, err := certifier.checkResponse(order, certRes, true, "")
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, apiURL+"/star-certificate", certRes.CertURL)
+	assert.Equal(t, apiURL+"/star-certificate", certRes.CertStableURL)
+	assert.Equal(t, certResponseMock, string(certRes.Certificate), "Certificate")
+	assert.Equal(t, issuerMock, string(certRes.IssuerCertificate), "IssuerCertificate")
+
+	shortTerm, err := certifier.GetShortTerm(certRes.CertURL, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, "acme.wtf", shortTerm.Domain)
+	assert.Equal(t, certResponseMock, string(shortTerm.Certificate), "Certificate")
+}
+
 func Test_checkResponse_issuerRelUp(t *testing.T) {
 	mux, apiURL := tester.SetupFakeAPI(t)
 
@@ -389,6 +439,70 @@ func Test_Get(t *testing.T) {
 	assert.Equal(t, issuerMock, string(certRes.IssuerCertificate), "IssuerCertificate")
 }
 
+func TestCertifier_GetWithOptions(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("/certificate", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Add("Link", fmt.Sprintf(`<%s/certificate/1>;title="foo";rel="alternate"`, apiURL))
+
+		_, err := w.Write([]byte(certResponseMock))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	mux.HandleFunc("/certificate/1", func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(certResponseMock2))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	mux.HandleFunc("/expired", func(w http.ResponseWriter, _ *http.Request) {
+		writeProblem(w, &acme.ProblemDetails{
+			Type:       "urn:ietf:params:acme:error:malformed",
+			Detail:     "Certificate not found",
+			HTTPStatus: http.StatusNotFound,
+		})
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	certRes, err := certifier.GetWithOptions(apiURL+"/certificate", &GetOptions{Bundle: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, apiURL+"/certificate", certRes.CertURL)
+	assert.Equal(t, certResponseMock, string(certRes.Certificate), "Certificate")
+
+	certRes, err = certifier.GetWithOptions(apiURL+"/certificate", &GetOptions{Bundle: true, PreferredChain: "DST Root CA X3"})
+	require.NoError(t, err)
+
+	assert.Equal(t, apiURL+"/certificate/1", certRes.CertURL)
+	assert.Equal(t, apiURL+"/certificate/1", certRes.CertStableURL)
+	assert.Equal(t, certResponseMock2, string(certRes.Certificate), "Certificate")
+	assert.Equal(t, issuerMock2, string(certRes.IssuerCertificate), "IssuerCertificate")
+
+	certRes, err = certifier.GetWithOptions(apiURL+"/certificate", &GetOptions{Bundle: true, PreferredChain: "Unknown"})
+	require.NoError(t, err)
+
+	assert.Equal(t, apiURL+"/certificate", certRes.CertURL)
+
+	_, err = certifier.GetWithOptions(apiURL+"/expired", nil)
+	require.Error(t, err)
+
+	var notFound *CertificateNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, apiURL+"/expired", notFound.URL)
+}
+
 type resolverMock struct {
 	error error
 }
@@ -396,3 +510,524 @@ type resolverMock struct {
 func (r *resolverMock) Solve(_ []acme.Authorization) error {
 	return r.error
 }
+
+// reporterMock reports the challenge type of each authorization.
+type reporterMock struct {
+	resolverMock
+
+	types map[string]challenge.Type
+}
+
+func (r *reporterMock) SolveWithReport(_ []acme.Authorization) (map[string]challenge.Type, error) {
+	return r.types, r.error
+}
+
+func TestResource_SplitChain(t *testing.T) {
+	ca := newCAMock(t)
+
+	bundle := ca.issueForDomains([]string{"example.com"}, time.Now().Add(24*time.Hour))
+
+	block, _ := pem.Decode(bundle)
+	leafPEM := pem.EncodeToMemory(block)
+
+	testCases := []struct {
+		desc     string
+		resource Resource
+	}{
+		{
+			desc:     "bundle",
+			resource: Resource{Certificate: bundle, IssuerCertificate: ca.issuerPEM()},
+		},
+		{
+			desc:     "leaf only",
+			resource: Resource{Certificate: leafPEM, IssuerCertificate: ca.issuerPEM()},
+		},
+		{
+			desc:     "issuer certificate with the leaf",
+			resource: Resource{Certificate: leafPEM, IssuerCertificate: bundle},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			leaf, intermediates, err := test.resource.SplitChain()
+			require.NoError(t, err)
+
+			assert.Equal(t, string(leafPEM), string(leaf))
+			require.Len(t, intermediates, 1)
+			assert.Equal(t, string(ca.issuerPEM()), string(intermediates[0]))
+		})
+	}
+}
+
+func TestResource_SplitChain_errors(t *testing.T) {
+	ca := newCAMock(t)
+
+	_, _, err := (&Resource{Certificate: []byte("invalid")}).SplitChain()
+	require.Error(t, err)
+
+	_, _, err = (&Resource{Certificate: ca.issuerPEM()}).SplitChain()
+	require.EqualError(t, err, "certificate bundle starts with a CA certificate")
+}
+
+func TestResource_CertificateDER(t *testing.T) {
+	ca := newCAMock(t)
+
+	bundle := ca.issueForDomains([]string{"example.com"}, time.Now().Add(24*time.Hour))
+
+	expected, err := certcrypto.ParsePEMBundle(bundle)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(bundle)
+
+	testCases := []struct {
+		desc     string
+		resource Resource
+	}{
+		{
+			desc:     "bundle",
+			resource: Resource{Certificate: bundle},
+		},
+		{
+			desc:     "leaf only",
+			resource: Resource{Certificate: pem.EncodeToMemory(block), IssuerCertificate: ca.issuerPEM()},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			ders, err := test.resource.CertificateDER()
+			require.NoError(t, err)
+
+			// the leaf certificate first, then the issuer.
+			require.Len(t, ders, len(expected))
+
+			for i, der := range ders {
+				cert, err := x509.ParseCertificate(der)
+				require.NoError(t, err)
+
+				assert.True(t, cert.Equal(expected[i]), "certificate %d", i)
+			}
+		})
+	}
+}
+
+func TestResource_CertificateDER_errors(t *testing.T) {
+	ca := newCAMock(t)
+
+	_, err := (&Resource{Certificate: []byte("invalid")}).CertificateDER()
+	require.Error(t, err)
+
+	_, err = (&Resource{Certificate: ca.issuerPEM()}).CertificateDER()
+	require.EqualError(t, err, "certificate bundle starts with a CA certificate")
+}
+
+func TestResource_VerifyChain(t *testing.T) {
+	now := time.Now()
+
+	root := newTestCertificate(t, nil, "root", true, now.Add(time.Hour))
+	intermediate := newTestCertificate(t, root, "intermediate", true, now.Add(time.Hour))
+	leaf := newTestCertificate(t, intermediate, "example.com", false, now.Add(time.Hour))
+	expired := newTestCertificate(t, intermediate, "example.com", false, now.Add(-time.Minute))
+
+	otherRoot := newTestCertificate(t, nil, "other root", true, now.Add(time.Hour))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+
+	testCases := []struct {
+		desc     string
+		resource Resource
+		roots    *x509.CertPool
+		expected string
+	}{
+		{
+			desc:     "complete chain",
+			resource: Resource{Certificate: bundlePEM(leaf, intermediate)},
+			roots:    roots,
+		},
+		{
+			desc:     "chain in the issuer certificate",
+			resource: Resource{Certificate: bundlePEM(leaf), IssuerCertificate: bundlePEM(intermediate, root)},
+			roots:    roots,
+		},
+		{
+			desc:     "missing intermediate",
+			resource: Resource{Certificate: bundlePEM(leaf)},
+			roots:    roots,
+			expected: `invalid chain: the issuer "CN=intermediate" of "CN=example.com" is neither in the chain nor a trusted root (missing intermediate?): x509: certificate signed by unknown authority`,
+		},
+		{
+			desc:     "untrusted root",
+			resource: Resource{Certificate: bundlePEM(leaf, intermediate, root)},
+			roots:    newCertPool(otherRoot),
+			expected: `invalid chain: untrusted root "CN=root": x509: certificate signed by unknown authority`,
+		},
+		{
+			desc:     "expired",
+			resource: Resource{Certificate: bundlePEM(expired, intermediate)},
+			roots:    roots,
+			expected: `invalid chain: expired or not yet valid certificate "CN=example.com"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := test.resource.VerifyChain(test.roots)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expected)
+		})
+	}
+}
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCertificate creates a certificate signed by the parent (self-signed if the parent is nil).
+func newTestCertificate(t *testing.T, parent *testCertificate, cn string, isCA bool, notAfter time.Time) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+
+	if isCA {
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.DNSNames = []string{cn}
+	}
+
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), signer)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCertificate{cert: cert, key: key}
+}
+
+func bundlePEM(certs ...*testCertificate) []byte {
+	var bundle []byte
+	for _, c := range certs {
+		bundle = append(bundle, certcrypto.PEMEncode(certcrypto.DERCertificateBytes(c.cert.Raw))...)
+	}
+
+	return bundle
+}
+
+func newCertPool(certs ...*testCertificate) *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, c := range certs {
+		pool.AddCert(c.cert)
+	}
+
+	return pool
+}
+
+func TestObtainRequest_WithLifetime(t *testing.T) {
+	request := ObtainRequest{Domains: []string{"example.com"}}.WithLifetime(90 * 24 * time.Hour)
+
+	assert.WithinDuration(t, time.Now(), request.NotBefore, 2*time.Second)
+	assert.WithinDuration(t, time.Now().Add(90*24*time.Hour), request.NotAfter, 2*time.Second)
+	assert.Equal(t, 90*24*time.Hour, request.NotAfter.Sub(request.NotBefore))
+}
+
+func TestCertifier_Obtain_withLifetime(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		maxValidity time.Duration
+		expected    time.Duration
+	}{
+		{
+			desc:     "honored",
+			expected: 90 * 24 * time.Hour,
+		},
+		{
+			desc:        "clamped by the CA",
+			maxValidity: 7 * 24 * time.Hour,
+			expected:    7 * 24 * time.Hour,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			ca := newCAMock(t)
+			ca.maxValidity = test.maxValidity
+
+			var orders []acme.Order
+
+			ca.rejectOrder = func(order acme.Order) *acme.ProblemDetails {
+				orders = append(orders, order)
+				return nil
+			}
+
+			certifier := ca.newCertifier(CertifierOptions{})
+
+			request := ObtainRequest{Domains: []string{"example.com"}}.WithLifetime(90 * 24 * time.Hour)
+
+			res, err := certifier.Obtain(request)
+			require.NoError(t, err)
+
+			// the validity is sent in the order (RFC 3339).
+			require.Len(t, orders, 1)
+			assert.Equal(t, request.NotBefore.Format(time.RFC3339), orders[0].NotBefore)
+			assert.Equal(t, request.NotAfter.Format(time.RFC3339), orders[0].NotAfter)
+
+			notAfter, err := time.Parse(time.RFC3339, orders[0].NotAfter)
+			require.NoError(t, err)
+
+			assert.WithinDuration(t, time.Now().Add(90*24*time.Hour), notAfter, 5*time.Second)
+
+			// the resource contains the actual validity of the certificate.
+			leaf, err := certcrypto.ParsePEMCertificate(res.Certificate)
+			require.NoError(t, err)
+
+			assert.Equal(t, leaf.NotBefore, res.NotBefore)
+			assert.Equal(t, leaf.NotAfter, res.NotAfter)
+			assert.Equal(t, test.expected, res.NotAfter.Sub(res.NotBefore))
+		})
+	}
+}
+
+func TestCertifier_Obtain_finalizeTimeout(t *testing.T) {
+	ca := newCAMock(t)
+	ca.setProcessing(true)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	_, err := certifier.Obtain(ObtainRequest{
+		Domains:         []string{"example.com"},
+		Bundle:          true,
+		FinalizeTimeout: 300 * time.Millisecond,
+	})
+	require.Error(t, err)
+
+	var timeoutErr *FinalizeTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+
+	assert.Equal(t, ca.url+"/order/1", timeoutErr.OrderURL)
+	assert.Equal(t, "example.com", timeoutErr.Domain)
+	assert.NotEmpty(t, timeoutErr.PrivateKey)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the order is still processing.
+	_, err = certifier.ResumeOrder(timeoutErr, true, "")
+	require.Error(t, err)
+
+	// the order becomes valid after the timeout.
+	ca.setProcessing(false)
+
+	certRes, err := certifier.ResumeOrder(timeoutErr, true, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", certRes.Domain)
+	assert.Equal(t, timeoutErr.PrivateKey, certRes.PrivateKey)
+	assert.NotEmpty(t, certRes.Certificate)
+	assert.NotEmpty(t, certRes.IssuerCertificate)
+}
+
+func TestCertifier_ObtainWithContext_canceled(t *testing.T) {
+	ca := newCAMock(t)
+	ca.setProcessing(true)
+
+	certifier := ca.newCertifier(CertifierOptions{Timeout: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	_, err := certifier.ObtainWithContext(ctx, ObtainRequest{Domains: []string{"example.com"}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the cancellation of the context is not a finalize timeout.
+	var timeoutErr *FinalizeTimeoutError
+	assert.NotErrorAs(t, err, &timeoutErr)
+}
+
+func TestCertifier_Obtain_invalidOrder(t *testing.T) {
+	ca := newCAMock(t)
+	ca.setProcessing(true)
+	ca.invalidOrder = &acme.ProblemDetails{
+		Type:       "urn:ietf:params:acme:error:serverInternal",
+		Detail:     "issuance failed",
+		HTTPStatus: http.StatusOK,
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	start := time.Now()
+
+	_, err := certifier.Obtain(ObtainRequest{
+		Domains:         []string{"example.com"},
+		Bundle:          true,
+		FinalizeTimeout: time.Minute,
+	})
+
+	var problem *acme.ProblemDetails
+	require.ErrorAs(t, err, &problem)
+
+	assert.Equal(t, "issuance failed", problem.Detail)
+
+	// the invalid order is not a finalize timeout, and the wait stops immediately.
+	var timeoutErr *FinalizeTimeoutError
+	assert.NotErrorAs(t, err, &timeoutErr)
+	assert.Less(t, time.Since(start), 30*time.Second)
+}
+
+func Test_sanitizeDomain(t *testing.T) {
+	domains := sanitizeDomain([]string{"example.com", "bücher.example", "jöhn@bücher.example"})
+
+	assert.Equal(t, []string{"example.com", "xn--bcher-kva.example", "jöhn@xn--bcher-kva.example"}, domains)
+}
+
+func TestCertifier_Obtain_challengeTypes(t *testing.T) {
+	ca := newCAMock(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", ca.url+"/dir", "", key)
+	require.NoError(t, err)
+
+	types := map[string]challenge.Type{
+		"example.com":     challenge.HTTP01,
+		"www.example.com": challenge.DNS01,
+	}
+
+	certifier := NewCertifier(core, &reporterMock{types: types}, CertifierOptions{KeyType: certcrypto.EC256})
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com", "www.example.com"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, types, certRes.ChallengeTypes)
+
+	// the resolvers not reporting the challenge types.
+	certifier = NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.EC256})
+
+	certRes, err = certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	assert.Nil(t, certRes.ChallengeTypes)
+}
+
+func TestCertifier_Obtain_withoutCommonName(t *testing.T) {
+	ca := newCAMock(t)
+
+	var csrCN string
+	ca.rejectCSR = func(csr *x509.CertificateRequest) *acme.ProblemDetails {
+		csrCN = csr.Subject.CommonName
+		return nil
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	certRes, err := certifier.Obtain(ObtainRequest{
+		Domains:    []string{"example.com", "www.example.com"},
+		CSROptions: []certcrypto.CSROption{certcrypto.WithoutCommonName()},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, csrCN)
+	assert.Equal(t, "example.com", certRes.Domain)
+
+	cert, err := parseLeaf(certRes.Certificate)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"example.com", "www.example.com"}, cert.DNSNames)
+}
+
+func TestCertifier_Obtain_extensions(t *testing.T) {
+	ca := newCAMock(t)
+
+	var csrExtensions []pkix.Extension
+	ca.rejectCSR = func(csr *x509.CertificateRequest) *acme.ProblemDetails {
+		csrExtensions = csr.Extensions
+		return nil
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	policies, err := certcrypto.NewCertificatePoliciesExtension(asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 55555, 1})
+	require.NoError(t, err)
+
+	upn, err := certcrypto.NewUPN("user@example.com")
+	require.NoError(t, err)
+
+	_, err = certifier.Obtain(ObtainRequest{
+		Domains:    []string{"example.com"},
+		CSROptions: []certcrypto.CSROption{certcrypto.WithExtensions(policies), certcrypto.WithOtherNames(upn)},
+	})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, ext := range csrExtensions {
+		ids = append(ids, ext.Id.String())
+	}
+
+	// certificate policies, subjectAltName.
+	assert.ElementsMatch(t, []string{"2.5.29.32", "2.5.29.17"}, ids)
+}
+
+func TestCertifier_Obtain_withPrivateKey(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}}.WithPrivateKey(privateKey))
+	require.NoError(t, err)
+
+	assert.Equal(t, privateKey, certRes.Signer)
+	assert.Equal(t, certcrypto.PEMEncode(privateKey), certRes.PrivateKey)
+
+	cert, err := parseLeaf(certRes.Certificate)
+	require.NoError(t, err)
+
+	assert.True(t, privateKey.PublicKey.Equal(cert.PublicKey))
+}
+
+func TestCertifier_Obtain_withoutCommonName_rejected(t *testing.T) {
+	ca := newCAMock(t)
+
+	ca.rejectCSR = func(csr *x509.CertificateRequest) *acme.ProblemDetails {
+		if csr.Subject.CommonName != "" {
+			return nil
+		}
+
+		return &acme.ProblemDetails{Type: acme.BadCSRErr, Detail: "missing common name", HTTPStatus: http.StatusBadRequest}
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	_, err := certifier.Obtain(ObtainRequest{
+		Domains:    []string{"example.com"},
+		CSROptions: []certcrypto.CSROption{certcrypto.WithoutCommonName()},
+	})
+	require.ErrorContains(t, err, "the CA rejected the CSR without common name (it may require a common name)")
+
+	// with a common name.
+	_, err = certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,68 @@
+package certificate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_RenewBatch_clock(t *testing.T) {
+	ca := newCAMock(t)
+
+	now := time.Now()
+	fakeClock := clock.NewFake(now)
+
+	certifier := ca.newCertifier(CertifierOptions{Clock: fakeClock})
+
+	resources := []Resource{
+		{Domain: "example.com", Certificate: ca.issueForDomains([]string{"example.com"}, now.Add(40*24*time.Hour))},
+	}
+
+	options := &BatchRenewOptions{
+		RenewOptions: RenewOptions{Bundle: true},
+		RenewBefore:  30 * 24 * time.Hour,
+		DisableARI:   true,
+	}
+
+	results := certifier.RenewBatch(resources, options)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+
+	assert.False(t, results[0].Renewed)
+	assert.WithinDuration(t, now.Add(10*24*time.Hour), results[0].NextRenewal, time.Minute)
+
+	// fast-forward to the renewal time.
+	fakeClock.Advance(10 * 24 * time.Hour)
+
+	results = certifier.RenewBatch(resources, options)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+
+	assert.True(t, results[0].Renewed)
+}
+
+func TestCertifier_Obtain_clockFinalizeTimeout(t *testing.T) {
+	ca := newCAMock(t)
+	ca.setProcessing(true)
+
+	start := time.Now()
+	fakeClock := clock.NewFake(start)
+
+	certifier := ca.newCertifier(CertifierOptions{Clock: fakeClock})
+
+	_, err := certifier.Obtain(ObtainRequest{
+		Domains:         []string{"example.com"},
+		Bundle:          true,
+		FinalizeTimeout: time.Hour,
+	})
+
+	var timeoutErr *FinalizeTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.False(t, fakeClock.Now().Before(start.Add(time.Hour)))
+}
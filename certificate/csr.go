@@ -0,0 +1,123 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+)
+
+// minRSAKeySize is the minimum size of the RSA keys accepted by the CAs (CA/Browser Forum Baseline Requirements).
+const minRSAKeySize = 2048
+
+// ValidateCSR checks that a CSR can be used to obtain a certificate for the domains:
+//   - the names of the CSR (common name and SANs) must match exactly the domains (no missing name, no extra name),
+//   - the public key must be an RSA key of at least 2048 bits, or an ECDSA key on P-256, P-384 or P-521,
+//   - the signature of the CSR must be valid.
+//
+// The domains are compared case-insensitively, after the conversion of the IDNs to punycode.
+func ValidateCSR(csr *x509.CertificateRequest, domains []string) error {
+	if csr == nil {
+		return errors.New("invalid CSR: CSR is missing")
+	}
+
+	var errs []error
+
+	err := checkCSRNames(certcrypto.ExtractDomainsCSR(csr), domains)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = checkCSRKey(csr)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = csr.CheckSignature()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid signature: %w", err))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid CSR: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func checkCSRNames(names, domains []string) error {
+	names = normalizeNames(names)
+	domains = normalizeNames(domains)
+
+	var missing, extra []string
+
+	for _, domain := range domains {
+		if !slices.Contains(names, domain) {
+			missing = append(missing, domain)
+		}
+	}
+
+	for _, name := range names {
+		if !slices.Contains(domains, name) {
+			extra = append(extra, name)
+		}
+	}
+
+	var errs []error
+
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("missing names: %s", strings.Join(missing, ", ")))
+	}
+
+	if len(extra) > 0 {
+		errs = append(errs, fmt.Errorf("unexpected names: %s", strings.Join(extra, ", ")))
+	}
+
+	return errors.Join(errs...)
+}
+
+func normalizeNames(names []string) []string {
+	var normalized []string
+
+	for _, name := range sanitizeDomain(names) {
+		name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+		if !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+
+	return normalized
+}
+
+func checkCSRKey(csr *x509.CertificateRequest) error {
+	switch pub := csr.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeySize {
+			return fmt.Errorf("RSA key too small: %d bits (minimum %d bits)", pub.N.BitLen(), minRSAKeySize)
+		}
+
+		return nil
+
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+			return nil
+		default:
+			return fmt.Errorf("unsupported ECDSA curve: %s", pub.Curve.Params().Name)
+		}
+
+	case ed25519.PublicKey:
+		return errors.New("unsupported key type: Ed25519")
+
+	default:
+		return fmt.Errorf("unsupported key type: %s", csr.PublicKeyAlgorithm)
+	}
+}
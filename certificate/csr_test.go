@@ -0,0 +1,108 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCSR(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	csr := generateTestCSR(t, key, "example.com", "www.example.com", "xn--bcher-kva.example")
+
+	testCases := []struct {
+		desc     string
+		domains  []string
+		expected string
+	}{
+		{
+			desc:    "same domains",
+			domains: []string{"bücher.example", "WWW.example.com", "example.com"},
+		},
+		{
+			desc:     "missing SAN",
+			domains:  []string{"example.com", "www.example.com", "xn--bcher-kva.example", "api.example.com"},
+			expected: "invalid CSR: missing names: api.example.com",
+		},
+		{
+			desc:     "extra SAN",
+			domains:  []string{"example.com", "xn--bcher-kva.example"},
+			expected: "invalid CSR: unexpected names: www.example.com",
+		},
+		{
+			desc:     "mismatched SANs",
+			domains:  []string{"example.com", "example.org", "xn--bcher-kva.example"},
+			expected: "invalid CSR: missing names: example.org\nunexpected names: www.example.com",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateCSR(csr, test.domains)
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestValidateCSR_badSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	csr := generateTestCSR(t, key, "example.com")
+
+	csr.Signature[len(csr.Signature)-1] ^= 0xff
+
+	err = ValidateCSR(csr, []string{"example.com"})
+	require.ErrorContains(t, err, "invalid CSR: invalid signature: ")
+}
+
+func TestValidateCSR_weakKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	csr := generateTestCSR(t, key, "example.com")
+
+	err = ValidateCSR(csr, []string{"example.com"})
+	require.EqualError(t, err, "invalid CSR: RSA key too small: 1024 bits (minimum 2048 bits)")
+}
+
+func TestCertifier_ObtainForCSR_invalidCSR(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	csr := generateTestCSR(t, key, "example.com", "www.example.com")
+
+	res, err := certifier.ObtainForCSR(ObtainForCSRRequest{CSR: csr, Domains: []string{"example.com"}})
+	require.EqualError(t, err, "cannot obtain resource for CSR: invalid CSR: unexpected names: www.example.com")
+
+	assert.Nil(t, res)
+}
+
+func generateTestCSR(t *testing.T, key any, domain string, san ...string) *x509.CertificateRequest {
+	t.Helper()
+
+	raw, err := certcrypto.GenerateCSR(key, domain, san, false)
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+
+	return csr
+}
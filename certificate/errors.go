@@ -5,6 +5,42 @@ import (
 	"fmt"
 )
 
+// CertificateNotFoundError is returned when a certificate is not available anymore at its URL
+// (expired or purged by the CA).
+type CertificateNotFoundError struct {
+	URL string
+	Err error
+}
+
+func (e *CertificateNotFoundError) Error() string {
+	return fmt.Sprintf("certificate not found: %s: %v", e.URL, e.Err)
+}
+
+func (e *CertificateNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// FinalizeTimeoutError is returned when an order doesn't become valid before the finalize timeout.
+// It is not returned when the order is invalid, or when the context is canceled.
+//
+// The order may become valid later:
+// the certificate can be retrieved with Certifier.ResumeOrder.
+type FinalizeTimeoutError struct {
+	Domain   string
+	OrderURL string
+	// PrivateKey is the PEM encoded private key of the certificate, if generated by lego.
+	PrivateKey []byte
+	Err        error
+}
+
+func (e *FinalizeTimeoutError) Error() string {
+	return fmt.Sprintf("order %s not valid before the finalize timeout: %v", e.OrderURL, e.Err)
+}
+
+func (e *FinalizeTimeoutError) Unwrap() error {
+	return e.Err
+}
+
 type obtainError struct {
 	data map[string]error
 }
@@ -33,8 +69,3 @@ func (e *obtainError) Join() error {
 
 	return fmt.Errorf("error: one or more domains had a problem:\n%w", err)
 }
-
-type domainError struct {
-	Domain string
-	Error  error
-}
@@ -0,0 +1,121 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// orderNotReadyInterval is the initial interval between the retries of a finalization rejected with orderNotReady.
+const orderNotReadyInterval = time.Second
+
+// finalize sends the CSR to the finalize URL of the order.
+//
+// The finalization can be requested a beat too early (the CA rejects it with orderNotReady, even if all the authorizations are valid):
+// the status of the order is polled, and the finalization is retried with an exponential backoff (see CertifierOptions.OrderNotReadyRetries).
+// If the order is still not ready after the retries, the authorizations not valid are reported.
+func (c *Certifier) finalize(ctx context.Context, order acme.ExtendedOrder, csr []byte, opts []api.FinalizeOption) (acme.ExtendedOrder, error) {
+	retries := c.options.OrderNotReadyRetries
+	if retries == 0 {
+		retries = DefaultOrderNotReadyRetries
+	}
+
+	if retries < 0 || order.Location == "" {
+		return c.core.Orders.UpdateForCSR(order.Finalize, csr, opts...)
+	}
+
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = orderNotReadyInterval
+	bo.MaxElapsedTime = 0
+
+	var (
+		respOrder acme.ExtendedOrder
+		current   acme.ExtendedOrder
+	)
+
+	operation := func() error {
+		var err error
+
+		respOrder, err = c.core.Orders.UpdateForCSR(order.Finalize, csr, opts...)
+		if err == nil {
+			return nil
+		}
+
+		if !isOrderNotReady(err) {
+			return backoff.Permanent(err)
+		}
+
+		var errG error
+
+		current, errG = c.core.Orders.Get(order.Location)
+		if errG != nil {
+			return backoff.Permanent(errG)
+		}
+
+		switch current.Status {
+		case acme.StatusProcessing, acme.StatusValid:
+			// the order has been finalized in the meantime.
+			respOrder = current
+			return nil
+
+		case acme.StatusInvalid:
+			if current.Error != nil {
+				return backoff.Permanent(current.Error)
+			}
+
+			return backoff.Permanent(errors.New("the order is invalid"))
+
+		default:
+			return err
+		}
+	}
+
+	notify := func(err error, delay time.Duration) {
+		log.Infof("Retry finalization of the order %s in %s: %v", order.Location, delay, err)
+	}
+
+	err := backoff.RetryNotify(operation, backoff.WithContext(backoff.WithMaxRetries(bo, uint64(retries)), ctx), notify)
+	if err != nil {
+		if current.Status == acme.StatusPending {
+			return acme.ExtendedOrder{}, fmt.Errorf("order %s not ready: %w", order.Location, c.pendingAuthorizations(current, err))
+		}
+
+		return acme.ExtendedOrder{}, err
+	}
+
+	return respOrder, nil
+}
+
+// pendingAuthorizations describes the authorizations of the order which are not valid.
+func (c *Certifier) pendingAuthorizations(order acme.ExtendedOrder, cause error) error {
+	var pending []string
+
+	for _, authzURL := range order.Authorizations {
+		authz, err := c.core.Authorizations.Get(authzURL)
+		if err != nil {
+			return fmt.Errorf("%w: get authorization %s: %w", cause, authzURL, err)
+		}
+
+		if authz.Status != acme.StatusValid {
+			pending = append(pending, fmt.Sprintf("%s (%s)", authz.Identifier.Value, authz.Status))
+		}
+	}
+
+	if len(pending) == 0 {
+		return cause
+	}
+
+	return fmt.Errorf("authorizations not valid: %s: %w", strings.Join(pending, ", "), cause)
+}
+
+func isOrderNotReady(err error) bool {
+	var problem *acme.ProblemDetails
+	return errors.As(err, &problem) && problem.Type == acme.OrderNotReadyErr
+}
@@ -0,0 +1,75 @@
+package certificate
+
+import (
+	"crypto/x509"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_Obtain_orderNotReady(t *testing.T) {
+	ca := newCAMock(t)
+
+	var calls atomic.Int32
+
+	// the first finalization is requested a beat too early.
+	ca.rejectCSR = func(_ *x509.CertificateRequest) *acme.ProblemDetails {
+		if calls.Add(1) > 1 {
+			return nil
+		}
+
+		return &acme.ProblemDetails{
+			Type:       acme.OrderNotReadyErr,
+			Detail:     "Order's status (\"ready\") is not acceptable for finalization",
+			HTTPStatus: http.StatusForbidden,
+		}
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	res, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}, Bundle: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.NotEmpty(t, res.Certificate)
+}
+
+func TestCertifier_Obtain_orderNotReady_disabled(t *testing.T) {
+	ca := newCAMock(t)
+
+	var calls atomic.Int32
+
+	ca.rejectCSR = func(_ *x509.CertificateRequest) *acme.ProblemDetails {
+		calls.Add(1)
+
+		return &acme.ProblemDetails{
+			Type:       acme.OrderNotReadyErr,
+			Detail:     "Order's status (\"ready\") is not acceptable for finalization",
+			HTTPStatus: http.StatusForbidden,
+		}
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{OrderNotReadyRetries: -1})
+
+	_, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}, Bundle: true})
+	require.ErrorContains(t, err, acme.OrderNotReadyErr)
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCertifier_Obtain_orderNotReady_pendingAuthorization(t *testing.T) {
+	ca := newCAMock(t)
+	ca.pendingAuthzs = true
+
+	certifier := ca.newCertifier(CertifierOptions{OrderNotReadyRetries: 1})
+
+	_, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}, Bundle: true})
+	require.Error(t, err)
+
+	assert.ErrorContains(t, err, "not ready: authorizations not valid: example.com (pending): ")
+	assert.ErrorContains(t, err, acme.OrderNotReadyErr)
+}
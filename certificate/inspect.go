@@ -0,0 +1,76 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/hex"
+	"net"
+	"time"
+)
+
+// CertInfo contains the main information of a certificate.
+type CertInfo struct {
+	Subject      string
+	Issuer       string
+	SerialNumber string
+
+	DNSNames    []string
+	IPAddresses []net.IP
+
+	NotBefore time.Time
+	NotAfter  time.Time
+
+	// KeyType is the algorithm of the public key: "RSA", "ECDSA", or "Ed25519".
+	KeyType string
+	// KeySize is the size of the public key in bits (the size of the curve for ECDSA).
+	KeySize int
+
+	// SubjectKeyID and AuthorityKeyID are hex encoded.
+	SubjectKeyID   string
+	AuthorityKeyID string
+
+	OCSPServers           []string
+	CRLDistributionPoints []string
+	IssuingCertificateURL []string
+}
+
+// Inspect parses a PEM encoded certificate and returns its main information.
+// If certPEM is a bundle, the leaf certificate (the first one) is inspected.
+func Inspect(certPEM []byte) (*CertInfo, error) {
+	cert, err := parseLeaf(certPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CertInfo{
+		Subject:               cert.Subject.String(),
+		Issuer:                cert.Issuer.String(),
+		SerialNumber:          cert.SerialNumber.String(),
+		DNSNames:              cert.DNSNames,
+		IPAddresses:           cert.IPAddresses,
+		NotBefore:             cert.NotBefore,
+		NotAfter:              cert.NotAfter,
+		SubjectKeyID:          hex.EncodeToString(cert.SubjectKeyId),
+		AuthorityKeyID:        hex.EncodeToString(cert.AuthorityKeyId),
+		OCSPServers:           cert.OCSPServer,
+		CRLDistributionPoints: cert.CRLDistributionPoints,
+		IssuingCertificateURL: cert.IssuingCertificateURL,
+	}
+
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		info.KeyType = "RSA"
+		info.KeySize = pub.N.BitLen()
+	case *ecdsa.PublicKey:
+		info.KeyType = "ECDSA"
+		info.KeySize = pub.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		info.KeyType = "Ed25519"
+		info.KeySize = ed25519.PublicKeySize * 8
+	default:
+		info.KeyType = cert.PublicKeyAlgorithm.String()
+	}
+
+	return info, nil
+}
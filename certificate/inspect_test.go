@@ -0,0 +1,108 @@
+package certificate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc            string
+		key             crypto.Signer
+		expectedKeyType string
+		expectedKeySize int
+	}{
+		{
+			desc:            "RSA",
+			key:             rsaKey,
+			expectedKeyType: "RSA",
+			expectedKeySize: 2048,
+		},
+		{
+			desc:            "ECDSA",
+			key:             ecKey,
+			expectedKeyType: "ECDSA",
+			expectedKeySize: 384,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ca := newCAMock(t)
+
+			notBefore := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+			notAfter := notBefore.Add(90 * 24 * time.Hour)
+
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(42),
+				Subject:               pkix.Name{CommonName: "example.com"},
+				NotBefore:             notBefore,
+				NotAfter:              notAfter,
+				DNSNames:              []string{"example.com", "www.example.com"},
+				IPAddresses:           []net.IP{net.ParseIP("192.0.2.1")},
+				SubjectKeyId:          []byte{0xab, 0xcd},
+				OCSPServer:            []string{"http://ocsp.example.org"},
+				CRLDistributionPoints: []string{"http://crl.example.org/ca.crl"},
+				IssuingCertificateURL: []string{"http://ca.example.org/ca.crt"},
+			}
+
+			der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, test.key.Public(), ca.key)
+			require.NoError(t, err)
+
+			bundle := append(certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der)), ca.issuerPEM()...)
+
+			info, err := Inspect(bundle)
+			require.NoError(t, err)
+
+			expected := &CertInfo{
+				Subject:               "CN=example.com",
+				Issuer:                "CN=Mock Intermediate CA",
+				SerialNumber:          "42",
+				DNSNames:              []string{"example.com", "www.example.com"},
+				IPAddresses:           []net.IP{net.ParseIP("192.0.2.1").To4()},
+				NotBefore:             notBefore,
+				NotAfter:              notAfter,
+				KeyType:               test.expectedKeyType,
+				KeySize:               test.expectedKeySize,
+				SubjectKeyID:          "abcd",
+				AuthorityKeyID:        "01020304",
+				OCSPServers:           []string{"http://ocsp.example.org"},
+				CRLDistributionPoints: []string{"http://crl.example.org/ca.crl"},
+				IssuingCertificateURL: []string{"http://ca.example.org/ca.crt"},
+			}
+
+			assert.Equal(t, expected, info)
+		})
+	}
+}
+
+func TestInspect_errors(t *testing.T) {
+	ca := newCAMock(t)
+
+	_, err := Inspect([]byte("invalid"))
+	require.Error(t, err)
+
+	_, err = Inspect(ca.issuerPEM())
+	require.EqualError(t, err, "certificate bundle starts with a CA certificate")
+}
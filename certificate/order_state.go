@@ -0,0 +1,211 @@
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// orderStateVersion is the version of the serialized state of an OrderResource.
+const orderStateVersion = 1
+
+// Interface for the resolvers able to submit the challenges (ex: the dns-01 records)
+// and to validate them later, possibly in another process.
+type preSolver interface {
+	PreSolve(authorizations []acme.Authorization) error
+	SolvePresented(authorizations []acme.Authorization) error
+}
+
+// Interface for the preSolvers reporting the type of the challenge which validated each authorization.
+type presentedReporter interface {
+	SolvePresentedWithReport(authorizations []acme.Authorization) (map[string]challenge.Type, error)
+}
+
+// OrderResource represents an in-progress order, started by Certifier.StartOrder and finished by Certifier.FinishOrder.
+//
+// The state (see MarshalState) contains:
+//   - the domains, and the options of the request needed to finalize the order (MustStaple, Bundle, PreferredChain, etc.).
+//   - the order URL, the finalize URL, the identifiers, and the authorization URLs.
+//   - the authorizations, with their challenges (type, URL, token).
+//   - whether the challenges have been presented (ex: the dns-01 records created).
+//
+// The state doesn't contain the private key of the certificate, nor the account key:
+// the key authorizations (and so the values of the presented records) are computed from the tokens and the account key of the client.
+// The order must be finished by a client using the same account, with the same challenge configuration.
+type OrderResource struct {
+	Domains        []string             `json:"domains"`
+	OrderURL       string               `json:"orderUrl"`
+	Order          acme.Order           `json:"order"`
+	Authorizations []acme.Authorization `json:"authorizations"`
+
+	// Presented is true if the challenges have been submitted (see Certifier.StartOrder).
+	Presented bool `json:"presented"`
+
+	MustStaple                     bool          `json:"mustStaple,omitempty"`
+	Bundle                         bool          `json:"bundle,omitempty"`
+	PreferredChain                 string        `json:"preferredChain,omitempty"`
+	AlwaysDeactivateAuthorizations bool          `json:"alwaysDeactivateAuthorizations,omitempty"`
+	FinalizeTimeout                time.Duration `json:"finalizeTimeout,omitempty"`
+}
+
+type orderState struct {
+	Version int `json:"version"`
+	OrderResource
+}
+
+// MarshalState serializes the state of the order.
+func (o *OrderResource) MarshalState() ([]byte, error) {
+	return json.Marshal(orderState{Version: orderStateVersion, OrderResource: *o})
+}
+
+// UnmarshalState restores the state of the order serialized by MarshalState.
+func (o *OrderResource) UnmarshalState(data []byte) error {
+	var state orderState
+
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return fmt.Errorf("unmarshal order state: %w", err)
+	}
+
+	if state.Version != orderStateVersion {
+		return fmt.Errorf("unsupported order state version: %d", state.Version)
+	}
+
+	if state.OrderURL == "" || len(state.Domains) == 0 {
+		return errors.New("invalid order state: missing order URL or domains")
+	}
+
+	*o = state.OrderResource
+
+	return nil
+}
+
+func (o *OrderResource) extendedOrder() acme.ExtendedOrder {
+	return acme.ExtendedOrder{Order: o.Order, Location: o.OrderURL}
+}
+
+// StartOrder creates an order, retrieves its authorizations, and submits the challenges which can be prepared in advance
+// (ex: the records of the dns-01 challenges), without validating them.
+// The PrivateKey of the request is ignored: the private key is provided to FinishOrder.
+//
+// The returned OrderResource can be serialized (see OrderResource.MarshalState)
+// to finish the order in another process.
+func (c *Certifier) StartOrder(request ObtainRequest) (*OrderResource, error) {
+	if len(request.Domains) == 0 {
+		return nil, errors.New("no domains to obtain a certificate for")
+	}
+
+	domains := sanitizeDomain(request.Domains)
+
+	log.Infof("[%s] acme: Starting SAN certificate order", strings.Join(domains, ", "))
+
+	orderOpts := &api.OrderOptions{
+		NotBefore:      request.NotBefore,
+		NotAfter:       request.NotAfter,
+		ReplacesCertID: request.ReplacesCertID,
+		AutoRenewal:    request.AutoRenewal,
+
+		ExternalAccountBinding: request.ExternalAccountBinding,
+	}
+
+	order, err := c.core.Orders.NewWithOptions(domains, orderOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	authz, err := c.getAuthorizations(order)
+	if err != nil {
+		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
+		return nil, err
+	}
+
+	res := &OrderResource{
+		Domains:                        domains,
+		OrderURL:                       order.Location,
+		Order:                          order.Order,
+		Authorizations:                 authz,
+		MustStaple:                     request.MustStaple,
+		Bundle:                         request.Bundle,
+		PreferredChain:                 request.PreferredChain,
+		AlwaysDeactivateAuthorizations: request.AlwaysDeactivateAuthorizations,
+		FinalizeTimeout:                request.FinalizeTimeout,
+	}
+
+	if solvr, ok := c.resolver.(preSolver); ok {
+		err = solvr.PreSolve(authz)
+		if err != nil {
+			c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
+			return nil, err
+		}
+
+		res.Presented = true
+	}
+
+	return res, nil
+}
+
+// FinishOrder validates the challenges of an order started by StartOrder, then finalizes the order.
+// If privateKey is nil, a new private key is generated.
+//
+// This function will never return a partial certificate.
+// If one domain in the list fails, the whole certificate will fail.
+func (c *Certifier) FinishOrder(ctx context.Context, res *OrderResource, privateKey crypto.PrivateKey) (*Resource, error) {
+	if res == nil {
+		return nil, errors.New("missing order")
+	}
+
+	order := res.extendedOrder()
+
+	challengeTypes, err := c.solvePresented(res)
+
+	if err != nil {
+		// If any challenge fails, return. Do not generate partial SAN certificates.
+		c.deactivateAuthorizations(order, res.AlwaysDeactivateAuthorizations)
+		return nil, err
+	}
+
+	log.Infof("[%s] acme: Validations succeeded; requesting certificates", strings.Join(res.Domains, ", "))
+
+	failures := newObtainError()
+	opts := finalizeOptions{bundle: res.Bundle, preferredChain: res.PreferredChain, timeout: res.FinalizeTimeout}
+
+	cert, err := c.getForOrder(ctx, res.Domains, order, privateKey, res.MustStaple, opts)
+	if err != nil {
+		for _, auth := range res.Authorizations {
+			failures.Add(challenge.GetTargetedDomain(auth), err)
+		}
+	}
+
+	if cert != nil {
+		cert.ChallengeTypes = challengeTypes
+	}
+
+	if res.AlwaysDeactivateAuthorizations {
+		c.deactivateAuthorizations(order, true)
+	}
+
+	return cert, failures.Join()
+}
+
+// solvePresented validates the challenges of the order, without submitting them again if they have been presented.
+func (c *Certifier) solvePresented(res *OrderResource) (map[string]challenge.Type, error) {
+	solvr, ok := c.resolver.(preSolver)
+	if !ok || !res.Presented {
+		return c.solve(res.Authorizations)
+	}
+
+	if rep, ok := solvr.(presentedReporter); ok {
+		return rep.SolvePresentedWithReport(res.Authorizations)
+	}
+
+	return nil, solvr.SolvePresented(res.Authorizations)
+}
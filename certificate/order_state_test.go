@@ -0,0 +1,127 @@
+package certificate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type preSolverResolverMock struct {
+	preSolved       [][]acme.Authorization
+	solvedPresented [][]acme.Authorization
+	solved          int
+}
+
+func (r *preSolverResolverMock) Solve(_ []acme.Authorization) error {
+	r.solved++
+	return nil
+}
+
+func (r *preSolverResolverMock) PreSolve(authorizations []acme.Authorization) error {
+	r.preSolved = append(r.preSolved, authorizations)
+	return nil
+}
+
+func (r *preSolverResolverMock) SolvePresented(authorizations []acme.Authorization) error {
+	r.solvedPresented = append(r.solvedPresented, authorizations)
+	return nil
+}
+
+func TestCertifier_StartOrder_FinishOrder(t *testing.T) {
+	ca := newCAMock(t)
+
+	start := ca.newCertifier(CertifierOptions{})
+	startResolver := &preSolverResolverMock{}
+	start.resolver = startResolver
+
+	res, err := start.StartOrder(ObtainRequest{
+		Domains: []string{"example.com", "www.example.com"},
+		Bundle:  true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, startResolver.preSolved, 1)
+	assert.Len(t, startResolver.preSolved[0], 2)
+	assert.True(t, res.Presented)
+
+	state, err := res.MarshalState()
+	require.NoError(t, err)
+
+	// the order is finished by another certifier (ex: another process).
+	finishResolver := &preSolverResolverMock{}
+	finish := NewCertifier(start.core, finishResolver, start.options)
+
+	restored := &OrderResource{}
+	err = restored.UnmarshalState(state)
+	require.NoError(t, err)
+
+	assert.Equal(t, res, restored)
+
+	certRes, err := finish.FinishOrder(context.Background(), restored, nil)
+	require.NoError(t, err)
+
+	require.Len(t, finishResolver.solvedPresented, 1)
+	assert.Len(t, finishResolver.solvedPresented[0], 2)
+	assert.Zero(t, finishResolver.solved)
+
+	assert.Equal(t, "example.com", certRes.Domain)
+	assert.NotEmpty(t, certRes.PrivateKey)
+	assert.NotEmpty(t, certRes.Certificate)
+	assert.NotEmpty(t, certRes.IssuerCertificate)
+}
+
+func TestCertifier_FinishOrder_notPresented(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	res, err := certifier.StartOrder(ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	assert.False(t, res.Presented)
+
+	certifierResolver := &preSolverResolverMock{}
+	certifier.resolver = certifierResolver
+
+	_, err = certifier.FinishOrder(context.Background(), res, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, certifierResolver.solved)
+	assert.Empty(t, certifierResolver.solvedPresented)
+}
+
+func TestOrderResource_UnmarshalState_errors(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		data          string
+		expectedError string
+	}{
+		{
+			desc:          "invalid JSON",
+			data:          `{`,
+			expectedError: "unmarshal order state: unexpected end of JSON input",
+		},
+		{
+			desc:          "unsupported version",
+			data:          `{"version":42,"orderUrl":"https://ca.example/order/1","domains":["example.com"]}`,
+			expectedError: "unsupported order state version: 42",
+		},
+		{
+			desc:          "missing order URL",
+			data:          `{"version":1,"domains":["example.com"]}`,
+			expectedError: "invalid order state: missing order URL or domains",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := (&OrderResource{}).UnmarshalState([]byte(test.data))
+			require.EqualError(t, err, test.expectedError)
+		})
+	}
+}
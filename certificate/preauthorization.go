@@ -0,0 +1,76 @@
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// PreAuthorize creates and solves the authorizations of the domains outside of any order (pre-authorization).
+// The following orders for these domains reuse the valid authorizations (until they expire):
+// their challenges are not solved again.
+// The authorizations are relinquished by an order with AlwaysDeactivateAuthorizations.
+//
+// Returns api.ErrNoPreAuthorization if the CA doesn't support the pre-authorization.
+// The wildcard domains cannot be pre-authorized.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.4.1
+func (c *Certifier) PreAuthorize(domains []string) ([]acme.ExtendedAuthorization, error) {
+	if len(domains) == 0 {
+		return nil, errors.New("no domains to pre-authorize")
+	}
+
+	for _, domain := range domains {
+		if strings.HasPrefix(domain, "*.") {
+			return nil, fmt.Errorf("[%s] acme: the wildcard domains cannot be pre-authorized", domain)
+		}
+	}
+
+	var authzs []acme.ExtendedAuthorization
+
+	for _, domain := range domains {
+		authz, err := c.core.Authorizations.New(newIdentifier(domain))
+		if err != nil {
+			return nil, fmt.Errorf("[%s] acme: pre-authorization: %w", domain, err)
+		}
+
+		authzs = append(authzs, authz)
+	}
+
+	var toSolve []acme.Authorization
+
+	for _, authz := range authzs {
+		toSolve = append(toSolve, authz.Authorization)
+	}
+
+	err := c.resolver.Solve(toSolve)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, authz := range authzs {
+		authzs[i].Authorization, err = c.core.Authorizations.Get(authz.Location)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] acme: pre-authorization: get authorization %s: %w", authz.Identifier.Value, authz.Location, err)
+		}
+
+		log.Infof("[%s] acme: pre-authorization %s: %s", authz.Identifier.Value, authzs[i].Status, authz.Location)
+	}
+
+	return authzs, nil
+}
+
+func newIdentifier(domain string) acme.Identifier {
+	if net.ParseIP(domain) != nil {
+		return acme.Identifier{Type: "ip", Value: domain}
+	}
+
+	if strings.Contains(domain, "@") {
+		return acme.Identifier{Type: "email", Value: domain}
+	}
+
+	return acme.Identifier{Type: "dns", Value: domain}
+}
@@ -0,0 +1,113 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// preAuthzResolver solves the pending pre-authorizations of the CA mock, and records the authorizations to solve.
+type preAuthzResolver struct {
+	ca *caMock
+
+	calls [][]acme.Authorization
+}
+
+func (r *preAuthzResolver) Solve(authorizations []acme.Authorization) error {
+	r.calls = append(r.calls, authorizations)
+
+	for _, authz := range authorizations {
+		if authz.Status != acme.StatusValid {
+			r.ca.validatePreAuthz(authz.Identifier.Value)
+		}
+	}
+
+	return nil
+}
+
+func TestCertifier_PreAuthorize(t *testing.T) {
+	ca := newCAMock(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", ca.url+"/dir", "", key)
+	require.NoError(t, err)
+
+	solver := &preAuthzResolver{ca: ca}
+
+	certifier := NewCertifier(core, solver, CertifierOptions{KeyType: certcrypto.EC256})
+
+	authzs, err := certifier.PreAuthorize([]string{"example.com", "192.0.2.1"})
+	require.NoError(t, err)
+
+	require.Len(t, authzs, 2)
+
+	assert.Equal(t, ca.url+"/preauthz/example.com", authzs[0].Location)
+	assert.Equal(t, acme.Identifier{Type: "dns", Value: "example.com"}, authzs[0].Identifier)
+	assert.Equal(t, acme.StatusValid, authzs[0].Status)
+
+	assert.Equal(t, ca.url+"/preauthz/192.0.2.1", authzs[1].Location)
+	assert.Equal(t, acme.Identifier{Type: "ip", Value: "192.0.2.1"}, authzs[1].Identifier)
+	assert.Equal(t, acme.StatusValid, authzs[1].Status)
+
+	require.Len(t, solver.calls, 1)
+	assert.Equal(t, acme.StatusPending, solver.calls[0][0].Status)
+
+	// the following orders reuse the valid pre-authorizations: there is nothing left to solve.
+	for range 2 {
+		_, err = certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, solver.calls, 3)
+
+	for _, call := range solver.calls[1:] {
+		require.Len(t, call, 1)
+		assert.Equal(t, acme.StatusValid, call[0].Status)
+		assert.Equal(t, "example.com", call[0].Identifier.Value)
+	}
+}
+
+func TestCertifier_PreAuthorize_wildcard(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	_, err := certifier.PreAuthorize([]string{"example.com", "*.example.com"})
+	require.EqualError(t, err, "[*.example.com] acme: the wildcard domains cannot be pre-authorized")
+}
+
+func TestCertifier_PreAuthorize_unsupported(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /dir", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.Directory{
+			NewNonceURL:   server.URL + "/nonce",
+			NewAccountURL: server.URL + "/account",
+			NewOrderURL:   server.URL + "/newOrder",
+		})
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{})
+
+	_, err = certifier.PreAuthorize([]string{"example.com"})
+	require.ErrorIs(t, err, api.ErrNoPreAuthorization)
+}
@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
@@ -100,6 +102,81 @@ func (c *Certifier) GetRenewalInfo(req RenewalInfoRequest) (*RenewalInfoResponse
 	return &info, nil
 }
 
+// NextRenewal returns the recommended renewal time of a PEM encoded certificate (or bundle),
+// without an ACME client.
+//
+// If renewalInfoURL (the renewalInfo URL of the ACME directory) is defined,
+// the middle of the window suggested by the ACME server is returned.
+// If renewalInfoURL is empty, or the server has no renewal information for the certificate,
+// the expiration date minus DefaultRenewBefore is returned.
+//
+// https://datatracker.ietf.org/doc/draft-ietf-acme-ari
+func NextRenewal(certPEM []byte, renewalInfoURL string) (time.Time, error) {
+	cert, err := parseLeaf(certPEM)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if renewalInfoURL == "" || len(cert.AuthorityKeyId) == 0 {
+		return renewalTime(cert, nil, DefaultRenewBefore), nil
+	}
+
+	info, err := fetchRenewalInfo(cert, renewalInfoURL)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return renewalTime(cert, info, DefaultRenewBefore), nil
+}
+
+// fetchRenewalInfo gets the renewal information of a certificate without using the ACME client.
+// Returns nil if the server has no renewal information for the certificate.
+func fetchRenewalInfo(cert *x509.Certificate, renewalInfoURL string) (*RenewalInfoResponse, error) {
+	certID, err := MakeARICertID(cert)
+	if err != nil {
+		return nil, fmt.Errorf("error making certID: %w", err)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	resp, err := client.Get(strings.TrimSuffix(renewalInfoURL, "/") + "/" + certID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("renewal info: unexpected status code: %d", resp.StatusCode)
+	}
+
+	var info RenewalInfoResponse
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// ARICertID returns the ARI certificate identifier (see MakeARICertID) of the leaf certificate of a PEM bundle.
+// The identifier is used by the renewal information requests, and to replace a certificate (ObtainRequest.ReplacesCertID).
+func ARICertID(certPEM []byte) (string, error) {
+	leaf, err := parseLeaf(certPEM)
+	if err != nil {
+		return "", err
+	}
+
+	if len(leaf.AuthorityKeyId) == 0 {
+		return "", errors.New("the certificate has no authority key identifier extension: the ARI certificate identifier cannot be computed")
+	}
+
+	return MakeARICertID(leaf)
+}
+
 // MakeARICertID constructs a certificate identifier as described in draft-ietf-acme-ari-03, section 4.1.
 func MakeARICertID(leaf *x509.Certificate) (string, error) {
 	if leaf == nil {
@@ -0,0 +1,178 @@
+package certificate
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// DefaultRenewBefore is the default remaining validity under which a certificate is renewed.
+const DefaultRenewBefore = 30 * 24 * time.Hour
+
+// BatchRenewOptions options used by Certifier.RenewBatch.
+type BatchRenewOptions struct {
+	RenewOptions
+
+	// RenewBefore is the remaining validity under which a certificate is renewed,
+	// when the renewalInfo endpoint is not available (or disabled).
+	// Defaults to DefaultRenewBefore.
+	RenewBefore time.Duration
+
+	// DisableARI disables the use of the renewalInfo endpoint (draft-ietf-acme-ari).
+	DisableARI bool
+}
+
+// RenewResult is the outcome of the renewal of one certificate by Certifier.RenewBatch.
+type RenewResult struct {
+	Domain string
+
+	// Renewed is true if a new certificate has been obtained.
+	Renewed bool
+
+	// Resource is the new certificate, only defined when Renewed is true.
+	Resource *Resource
+
+	// NextRenewal is the time at which the certificate (the new one, if renewed) should be renewed.
+	NextRenewal time.Time
+
+	Err error
+}
+
+// RenewBatch renews the certificates which need to be renewed.
+//
+// A certificate is renewed when the renewal time (see RenewResult.NextRenewal) is reached:
+// the middle of the window suggested by the renewalInfo endpoint, if available,
+// otherwise the expiration date minus BatchRenewOptions.RenewBefore.
+//
+// A failure doesn't stop the batch: the error is reported in the result of the related certificate.
+// The results are in the same order as the resources.
+func (c *Certifier) RenewBatch(resources []Resource, options *BatchRenewOptions) []RenewResult {
+	if options == nil {
+		options = &BatchRenewOptions{}
+	}
+
+	results := make([]RenewResult, 0, len(resources))
+
+	for _, res := range resources {
+		result := c.renewIfNeeded(res, options)
+		if result.Err != nil {
+			log.Warnf("[%s] renewal failed: %v", result.Domain, result.Err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func (c *Certifier) renewIfNeeded(res Resource, options *BatchRenewOptions) RenewResult {
+	result := RenewResult{Domain: res.Domain}
+
+	cert, err := parseLeaf(res.Certificate)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if result.Domain == "" {
+		result.Domain, _ = certcrypto.GetCertificateMainDomain(cert)
+	}
+
+	result.NextRenewal = c.nextRenewal(cert, options)
+
+	if c.clock.Now().Before(result.NextRenewal) {
+		log.Infof("[%s] no renewal needed before %s", result.Domain, result.NextRenewal.Format(time.RFC3339))
+		return result
+	}
+
+	newRes, err := c.RenewWithOptions(res, &options.RenewOptions)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Renewed = true
+	result.Resource = newRes
+
+	newCert, err := parseLeaf(newRes.Certificate)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.NextRenewal = c.nextRenewal(newCert, options)
+
+	return result
+}
+
+// nextRenewal returns the renewal time of a certificate, using the renewalInfo endpoint if available.
+func (c *Certifier) nextRenewal(cert *x509.Certificate, options *BatchRenewOptions) time.Time {
+	var info *RenewalInfoResponse
+
+	if !options.DisableARI {
+		var err error
+
+		info, err = c.GetRenewalInfo(RenewalInfoRequest{Cert: cert})
+		if err != nil && !errors.Is(err, api.ErrNoARI) {
+			log.Warnf("acme: calling renewal info endpoint: %v", err)
+		}
+	}
+
+	return renewalTime(cert, info, options.RenewBefore)
+}
+
+// renewalTime returns the middle of the suggested window if the renewal information are available,
+// otherwise the expiration date minus renewBefore.
+func renewalTime(cert *x509.Certificate, info *RenewalInfoResponse, renewBefore time.Duration) time.Time {
+	if info != nil && !info.SuggestedWindow.Start.IsZero() && !info.SuggestedWindow.End.IsZero() {
+		start := info.SuggestedWindow.Start.UTC()
+
+		return start.Add(info.SuggestedWindow.End.UTC().Sub(start) / 2)
+	}
+
+	if renewBefore <= 0 {
+		renewBefore = DefaultRenewBefore
+	}
+
+	return cert.NotAfter.UTC().Add(-renewBefore)
+}
+
+// ShouldRenew reports whether the certificate expires within the threshold,
+// based only on the expiration date of the certificate itself (not on any stored metadata).
+// If certPEM is a bundle, the leaf certificate (the first one) is checked.
+// An expired certificate must always be renewed.
+func ShouldRenew(certPEM []byte, threshold time.Duration) (bool, error) {
+	if threshold < 0 {
+		return false, fmt.Errorf("invalid threshold: %s", threshold)
+	}
+
+	cert, err := parseLeaf(certPEM)
+	if err != nil {
+		return false, err
+	}
+
+	return shouldRenew(cert, threshold, time.Now()), nil
+}
+
+func shouldRenew(cert *x509.Certificate, threshold time.Duration, now time.Time) bool {
+	return !now.Add(threshold).Before(cert.NotAfter)
+}
+
+// parseLeaf parses a PEM encoded certificate (or bundle) and returns the leaf certificate.
+func parseLeaf(certPEM []byte) (*x509.Certificate, error) {
+	certificates, err := certcrypto.ParsePEMBundle(certPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	if certificates[0].IsCA {
+		return nil, errors.New("certificate bundle starts with a CA certificate")
+	}
+
+	return certificates[0], nil
+}
@@ -0,0 +1,166 @@
+package certificate
+
+import (
+	"crypto/x509"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_RenewBatch(t *testing.T) {
+	ca := newCAMock(t)
+
+	ca.rejectOrder = func(order acme.Order) *acme.ProblemDetails {
+		if slices.Contains(order.Identifiers, acme.Identifier{Type: "dns", Value: "fail.example.com"}) {
+			return &acme.ProblemDetails{
+				Type:       "urn:ietf:params:acme:error:rejectedIdentifier",
+				Detail:     "rejected",
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+
+		return nil
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	now := time.Now()
+
+	resources := []Resource{
+		{Domain: "renew.example.com", Certificate: ca.issueForDomains([]string{"renew.example.com"}, now.Add(5*24*time.Hour))},
+		{Domain: "skip.example.com", Certificate: ca.issueForDomains([]string{"skip.example.com"}, now.Add(80*24*time.Hour))},
+		{Domain: "fail.example.com", Certificate: ca.issueForDomains([]string{"fail.example.com"}, now.Add(24*time.Hour))},
+		{Domain: "invalid.example.com", Certificate: []byte("invalid")},
+	}
+
+	results := certifier.RenewBatch(resources, &BatchRenewOptions{
+		RenewOptions: RenewOptions{Bundle: true},
+		RenewBefore:  30 * 24 * time.Hour,
+	})
+
+	require.Len(t, results, 4)
+
+	renewed := results[0]
+	assert.Equal(t, "renew.example.com", renewed.Domain)
+	require.NoError(t, renewed.Err)
+	assert.True(t, renewed.Renewed)
+	require.NotNil(t, renewed.Resource)
+	assert.WithinDuration(t, now.Add(60*24*time.Hour), renewed.NextRenewal, time.Hour)
+
+	skipped := results[1]
+	assert.Equal(t, "skip.example.com", skipped.Domain)
+	require.NoError(t, skipped.Err)
+	assert.False(t, skipped.Renewed)
+	assert.Nil(t, skipped.Resource)
+	assert.WithinDuration(t, now.Add(50*24*time.Hour), skipped.NextRenewal, time.Minute)
+
+	failed := results[2]
+	assert.Equal(t, "fail.example.com", failed.Domain)
+	require.Error(t, failed.Err)
+	assert.False(t, failed.Renewed)
+	assert.WithinDuration(t, now.Add(-29*24*time.Hour), failed.NextRenewal, time.Minute)
+
+	invalid := results[3]
+	assert.Equal(t, "invalid.example.com", invalid.Domain)
+	require.Error(t, invalid.Err)
+	assert.False(t, invalid.Renewed)
+}
+
+func TestCertifier_RenewBatch_ari(t *testing.T) {
+	ca := newCAMock(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	ca.renewalInfo = func(_ string) *acme.RenewalInfoResponse {
+		return &acme.RenewalInfoResponse{
+			SuggestedWindow: acme.Window{
+				Start: now.Add(10 * 24 * time.Hour),
+				End:   now.Add(12 * 24 * time.Hour),
+			},
+		}
+	}
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	resources := []Resource{
+		// Without ARI, this certificate would be renewed.
+		{Domain: "example.com", Certificate: ca.issueForDomains([]string{"example.com"}, now.Add(5*24*time.Hour))},
+	}
+
+	results := certifier.RenewBatch(resources, nil)
+
+	require.Len(t, results, 1)
+
+	require.NoError(t, results[0].Err)
+	assert.False(t, results[0].Renewed)
+	assert.Equal(t, now.Add(11*24*time.Hour), results[0].NextRenewal)
+}
+
+func TestShouldRenew(t *testing.T) {
+	ca := newCAMock(t)
+
+	now := time.Now()
+
+	testCases := []struct {
+		desc     string
+		notAfter time.Time
+		expected bool
+	}{
+		{
+			desc:     "just inside the threshold",
+			notAfter: now.Add(30*24*time.Hour - time.Minute),
+			expected: true,
+		},
+		{
+			desc:     "just outside the threshold",
+			notAfter: now.Add(30*24*time.Hour + time.Minute),
+			expected: false,
+		},
+		{
+			desc:     "expired",
+			notAfter: now.Add(-time.Minute),
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// bundle: the leaf certificate is checked, not the issuer.
+			renew, err := ShouldRenew(ca.issueForDomains([]string{"example.com"}, test.notAfter), 30*24*time.Hour)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, renew)
+		})
+	}
+}
+
+func TestShouldRenew_errors(t *testing.T) {
+	ca := newCAMock(t)
+
+	_, err := ShouldRenew([]byte("invalid"), time.Hour)
+	require.Error(t, err)
+
+	_, err = ShouldRenew(ca.issuerPEM(), time.Hour)
+	require.EqualError(t, err, "certificate bundle starts with a CA certificate")
+
+	_, err = ShouldRenew(ca.issueForDomains([]string{"example.com"}, time.Now().Add(time.Hour)), -time.Hour)
+	require.EqualError(t, err, "invalid threshold: -1h0m0s")
+}
+
+func Test_shouldRenew_boundary(t *testing.T) {
+	notAfter := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	cert := &x509.Certificate{NotAfter: notAfter}
+
+	assert.True(t, shouldRenew(cert, 24*time.Hour, notAfter.Add(-24*time.Hour)))
+	assert.True(t, shouldRenew(cert, 24*time.Hour, notAfter.Add(-24*time.Hour+time.Second)))
+	assert.False(t, shouldRenew(cert, 24*time.Hour, notAfter.Add(-24*time.Hour-time.Second)))
+	assert.True(t, shouldRenew(cert, 0, notAfter))
+}
@@ -0,0 +1,83 @@
+package certificate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// RenewIfDue renews the certificate only if the renewal window suggested by the renewalInfo endpoint (draft-ietf-acme-ari) has opened.
+//
+// It returns the new certificate (nil if the certificate has not been renewed),
+// and the time at which RenewIfDue should be called again:
+//   - not renewed: the start of the suggested window, or earlier if the server asks to poll again before (Retry-After);
+//   - renewed: the end of the Retry-After delay if any, otherwise the fallback renewal time of the new certificate.
+//
+// If the renewalInfo endpoint is not available, the certificate is renewed when its remaining validity
+// is under CertifierOptions.RenewBefore (DefaultRenewBefore by default).
+func (c *Certifier) RenewIfDue(res *Resource) (*Resource, time.Time, error) {
+	if res == nil {
+		return nil, time.Time{}, errors.New("missing certificate resource")
+	}
+
+	certs, err := certcrypto.ParsePEMBundle(res.Certificate)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	cert := certs[0]
+	if cert.IsCA {
+		return nil, time.Time{}, errors.New("certificate bundle starts with a CA certificate")
+	}
+
+	now := c.clock.Now().UTC()
+
+	info, err := c.GetRenewalInfo(RenewalInfoRequest{Cert: cert})
+	if err != nil {
+		if !errors.Is(err, api.ErrNoARI) {
+			log.Warnf("[%s] acme: calling renewal info endpoint: %v", res.Domain, err)
+		}
+
+		info = nil
+	}
+
+	hasWindow := info != nil && !info.SuggestedWindow.Start.IsZero() && !info.SuggestedWindow.End.IsZero()
+
+	var due time.Time
+	if hasWindow {
+		due = info.SuggestedWindow.Start.UTC()
+	} else {
+		due = renewalTime(cert, nil, c.options.RenewBefore)
+	}
+
+	if now.Before(due) {
+		next := due
+		if hasWindow && info.RetryAfter > 0 && now.Add(info.RetryAfter).Before(due) {
+			next = now.Add(info.RetryAfter)
+		}
+
+		log.Infof("[%s] acme: no renewal needed before %s, next check at %s", res.Domain, due.Format(time.RFC3339), next.Format(time.RFC3339))
+
+		return nil, next, nil
+	}
+
+	// the new certificate is bundled like the current one.
+	newRes, err := c.RenewWithOptions(*res, &RenewOptions{Bundle: len(certs) > 1})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if hasWindow && info.RetryAfter > 0 {
+		return newRes, now.Add(info.RetryAfter), nil
+	}
+
+	newCert, err := parseLeaf(newRes.Certificate)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return newRes, renewalTime(newCert, nil, c.options.RenewBefore), nil
+}
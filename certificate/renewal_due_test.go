@@ -0,0 +1,142 @@
+package certificate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_RenewIfDue_ari(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		desc        string
+		window      acme.Window
+		retryAfter  time.Duration
+		expectRenew bool
+		expectNext  time.Time
+	}{
+		{
+			desc:       "window not opened",
+			window:     acme.Window{Start: now.Add(10 * 24 * time.Hour), End: now.Add(12 * 24 * time.Hour)},
+			expectNext: now.Add(10 * 24 * time.Hour),
+		},
+		{
+			desc:       "window not opened, retry after",
+			window:     acme.Window{Start: now.Add(10 * 24 * time.Hour), End: now.Add(12 * 24 * time.Hour)},
+			retryAfter: 6 * time.Hour,
+			expectNext: now.Add(6 * time.Hour),
+		},
+		{
+			desc:       "window not opened, retry after the start of the window",
+			window:     acme.Window{Start: now.Add(2 * time.Hour), End: now.Add(12 * 24 * time.Hour)},
+			retryAfter: 6 * time.Hour,
+			expectNext: now.Add(2 * time.Hour),
+		},
+		{
+			desc:        "window opened",
+			window:      acme.Window{Start: now.Add(-time.Hour), End: now.Add(24 * time.Hour)},
+			retryAfter:  6 * time.Hour,
+			expectRenew: true,
+			expectNext:  now.Add(6 * time.Hour),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			ca := newCAMock(t)
+
+			ca.renewalInfo = func(_ string) *acme.RenewalInfoResponse {
+				return &acme.RenewalInfoResponse{SuggestedWindow: test.window}
+			}
+			ca.renewalInfoRetryAfter = test.retryAfter
+
+			certifier := ca.newCertifier(CertifierOptions{})
+
+			// Without ARI, this certificate would not be renewed.
+			res := &Resource{Domain: "example.com", Certificate: ca.issueForDomains([]string{"example.com"}, now.Add(80*24*time.Hour))}
+
+			newRes, next, err := certifier.RenewIfDue(res)
+			require.NoError(t, err)
+
+			if test.expectRenew {
+				require.NotNil(t, newRes)
+				assert.NotEmpty(t, newRes.Certificate)
+			} else {
+				assert.Nil(t, newRes)
+			}
+
+			assert.WithinDuration(t, test.expectNext, next, 5*time.Second)
+		})
+	}
+}
+
+func TestCertifier_RenewIfDue_fallback(t *testing.T) {
+	now := time.Now().UTC()
+
+	testCases := []struct {
+		desc        string
+		renewBefore time.Duration
+		notAfter    time.Time
+		expectRenew bool
+		expectNext  time.Time
+	}{
+		{
+			desc:       "not due",
+			notAfter:   now.Add(80 * 24 * time.Hour),
+			expectNext: now.Add(50 * 24 * time.Hour),
+		},
+		{
+			desc:        "due",
+			notAfter:    now.Add(5 * 24 * time.Hour),
+			expectRenew: true,
+			// the CA mock issues certificates valid for 90 days.
+			expectNext: now.Add(60 * 24 * time.Hour),
+		},
+		{
+			desc:        "due with a custom threshold",
+			renewBefore: 85 * 24 * time.Hour,
+			notAfter:    now.Add(80 * 24 * time.Hour),
+			expectRenew: true,
+			expectNext:  now.Add(5 * 24 * time.Hour),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			// the renewalInfo endpoint is not available.
+			ca := newCAMock(t)
+
+			certifier := ca.newCertifier(CertifierOptions{RenewBefore: test.renewBefore})
+
+			res := &Resource{Domain: "example.com", Certificate: ca.issueForDomains([]string{"example.com"}, test.notAfter)}
+
+			newRes, next, err := certifier.RenewIfDue(res)
+			require.NoError(t, err)
+
+			if test.expectRenew {
+				require.NotNil(t, newRes)
+				assert.NotEmpty(t, newRes.Certificate)
+			} else {
+				assert.Nil(t, newRes)
+			}
+
+			assert.WithinDuration(t, test.expectNext, next, time.Minute)
+		})
+	}
+}
+
+func TestCertifier_RenewIfDue_errors(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	_, _, err := certifier.RenewIfDue(nil)
+	require.EqualError(t, err, "missing certificate resource")
+
+	_, _, err = certifier.RenewIfDue(&Resource{Certificate: ca.issuerPEM()})
+	require.EqualError(t, err, "certificate bundle starts with a CA certificate")
+}
@@ -1,8 +1,13 @@
 package certificate
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"net/http"
 	"testing"
 	"time"
@@ -37,6 +42,75 @@ func Test_makeCertID(t *testing.T) {
 	assert.Equal(t, ariLeafCertID, actual)
 }
 
+func TestARICertID(t *testing.T) {
+	ca := newCAMock(t)
+
+	// self-signed, without authority key identifier.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	withoutAKI := certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der))
+
+	testCases := []struct {
+		desc          string
+		certPEM       []byte
+		expected      string
+		expectedError string
+	}{
+		{
+			desc:     "leaf",
+			certPEM:  []byte(ariLeafPEM),
+			expected: ariLeafCertID,
+		},
+		{
+			desc:     "bundle",
+			certPEM:  append([]byte(ariLeafPEM+"\n"), ca.issuerPEM()...),
+			expected: ariLeafCertID,
+		},
+		{
+			desc:          "missing authority key identifier",
+			certPEM:       withoutAKI,
+			expectedError: "the certificate has no authority key identifier extension: the ARI certificate identifier cannot be computed",
+		},
+		{
+			desc:          "bundle starting with a CA",
+			certPEM:       ca.issuerPEM(),
+			expectedError: "certificate bundle starts with a CA certificate",
+		},
+		{
+			desc:          "invalid PEM",
+			certPEM:       []byte("not a certificate"),
+			expectedError: "no certificates were found while parsing the bundle",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			certID, err := ARICertID(test.certPEM)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, certID)
+		})
+	}
+}
+
 func TestCertifier_GetRenewalInfo(t *testing.T) {
 	leaf, err := certcrypto.ParsePEMCertificate([]byte(ariLeafPEM))
 	require.NoError(t, err)
@@ -201,3 +275,71 @@ func TestRenewalInfoResponse_ShouldRenew(t *testing.T) {
 		assert.Nil(t, rt)
 	})
 }
+
+func TestNextRenewal(t *testing.T) {
+	ca := newCAMock(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	ca.renewalInfo = func(_ string) *acme.RenewalInfoResponse {
+		return &acme.RenewalInfoResponse{
+			SuggestedWindow: acme.Window{
+				Start: now.Add(10 * 24 * time.Hour),
+				End:   now.Add(12 * 24 * time.Hour),
+			},
+		}
+	}
+
+	certPEM := ca.issueForDomains([]string{"example.com"}, now.Add(60*24*time.Hour))
+
+	next, err := NextRenewal(certPEM, ca.url+"/renewalInfo")
+	require.NoError(t, err)
+
+	assert.Equal(t, now.Add(11*24*time.Hour), next)
+}
+
+func TestNextRenewal_fallback(t *testing.T) {
+	ca := newCAMock(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	certPEM := ca.issueForDomains([]string{"example.com"}, now.Add(60*24*time.Hour))
+
+	testCases := []struct {
+		desc           string
+		renewalInfoURL string
+	}{
+		{
+			desc: "no renewal info URL",
+		},
+		{
+			desc:           "no renewal info for the certificate",
+			renewalInfoURL: ca.url + "/renewalInfo",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			next, err := NextRenewal(certPEM, test.renewalInfoURL)
+			require.NoError(t, err)
+
+			assert.Equal(t, now.Add(60*24*time.Hour-DefaultRenewBefore), next)
+		})
+	}
+}
+
+func TestNextRenewal_errors(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("GET /renewalInfo/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	ca := newCAMock(t)
+
+	_, err := NextRenewal([]byte("invalid"), "")
+	require.Error(t, err)
+
+	_, err = NextRenewal(ca.issueForDomains([]string{"example.com"}, time.Now().Add(time.Hour)), apiURL+"/renewalInfo")
+	require.EqualError(t, err, "renewal info: unexpected status code: 500")
+}
@@ -0,0 +1,100 @@
+package certificate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// opaqueSigner is a crypto.Signer which doesn't expose its private key (like an HSM).
+type opaqueSigner struct {
+	t   *testing.T
+	key *ecdsa.PrivateKey
+}
+
+func newOpaqueSigner(t *testing.T) *opaqueSigner {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return &opaqueSigner{t: t, key: key}
+}
+
+func (s *opaqueSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(rand, digest, opts)
+}
+
+// Bytes simulates the export of the raw private key.
+func (s *opaqueSigner) Bytes() ([]byte, error) {
+	s.t.Error("the private key must not be exported")
+
+	return nil, errors.New("the private key is not exportable")
+}
+
+func TestCertifier_ObtainForCSR_signer(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	signer := newOpaqueSigner(t)
+
+	csr, err := certcrypto.GenerateCSRFromSigner(signer, []string{"example.com", "www.example.com"}, false)
+	require.NoError(t, err)
+
+	res, err := certifier.ObtainForCSR(ObtainForCSRRequest{CSR: csr, Bundle: true})
+	require.NoError(t, err)
+
+	assert.Nil(t, res.PrivateKey)
+	assert.NotEmpty(t, res.CSR)
+
+	cert, err := certcrypto.ParsePEMCertificate(res.Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, signer.Public(), cert.PublicKey)
+	assert.ElementsMatch(t, []string{"example.com", "www.example.com"}, cert.DNSNames)
+
+	// The renewal uses the CSR, so the signer is not needed.
+	renewed, err := certifier.RenewWithOptions(*res, &RenewOptions{Bundle: true})
+	require.NoError(t, err)
+
+	cert, err = certcrypto.ParsePEMCertificate(renewed.Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, signer.Public(), cert.PublicKey)
+}
+
+func TestCertifier_Obtain_signer(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	signer := newOpaqueSigner(t)
+
+	res, err := certifier.Obtain(ObtainRequest{
+		Domains:    []string{"example.com"},
+		PrivateKey: signer,
+		NotAfter:   time.Now().Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	assert.Nil(t, res.PrivateKey)
+
+	cert, err := certcrypto.ParsePEMCertificate(res.Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, signer.Public(), cert.PublicKey)
+}
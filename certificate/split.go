@@ -0,0 +1,131 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SplitOption is an option of Certifier.ObtainSplit.
+type SplitOption func(*splitOptions) error
+
+type splitOptions struct {
+	maxSANs int
+	single  bool
+}
+
+// WithMaxSANsPerCert defines the maximum number of domains (SANs) per certificate.
+// The domains are split into several orders (and certificates) of at most n domains.
+func WithMaxSANsPerCert(n int) SplitOption {
+	return func(o *splitOptions) error {
+		if n < 2 {
+			return fmt.Errorf("invalid maximum number of SANs per certificate: %d (minimum 2)", n)
+		}
+
+		o.maxSANs = n
+		return nil
+	}
+}
+
+// WithSingleCert requires a single certificate:
+// ObtainSplit returns an error, instead of splitting the domains, if the maximum number of SANs is exceeded.
+func WithSingleCert() SplitOption {
+	return func(o *splitOptions) error {
+		o.single = true
+		return nil
+	}
+}
+
+// ObtainSplit is like Obtain, but splits the domains into several orders
+// when the number of domains exceeds the maximum number of SANs per certificate (see WithMaxSANsPerCert).
+// A wildcard domain and its apex (ex: `*.example.com` and `example.com`) are always in the same certificate.
+// The first domain of each group is used for the CommonName field of its certificate.
+//
+// The ReplacesCertID of the request is only used for the first order.
+//
+// The certificates are returned in the order of the groups.
+// On failure, the certificates already obtained are returned with the error.
+func (c *Certifier) ObtainSplit(ctx context.Context, request ObtainRequest, opts ...SplitOption) ([]*Resource, error) {
+	options := &splitOptions{}
+
+	for _, opt := range opts {
+		err := opt(options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(request.Domains) == 0 {
+		return nil, errors.New("no domains to obtain a certificate for")
+	}
+
+	chunks := [][]string{request.Domains}
+	if options.maxSANs > 0 && len(request.Domains) > options.maxSANs {
+		if options.single {
+			return nil, fmt.Errorf("a single certificate is required, but the number of domains (%d) exceeds the maximum number of SANs per certificate (%d)",
+				len(request.Domains), options.maxSANs)
+		}
+
+		chunks = splitDomains(request.Domains, options.maxSANs)
+	}
+
+	var resources []*Resource
+
+	for i, chunk := range chunks {
+		req := request
+		req.Domains = chunk
+
+		if i > 0 {
+			req.ReplacesCertID = ""
+		}
+
+		res, err := c.ObtainWithContext(ctx, req)
+		if err != nil {
+			return resources, fmt.Errorf("certificate %d/%d [%s]: %w", i+1, len(chunks), chunk[0], err)
+		}
+
+		resources = append(resources, res)
+	}
+
+	return resources, nil
+}
+
+// splitDomains splits the domains into chunks of at most maxSANs domains,
+// keeping the wildcard domains and their apex in the same chunk.
+// The order of the domains is preserved (a group is placed at the position of its first domain).
+func splitDomains(domains []string, maxSANs int) [][]string {
+	var groups [][]string
+
+	index := make(map[string]int)
+
+	for _, domain := range domains {
+		base := strings.TrimPrefix(domain, "*.")
+
+		if i, ok := index[base]; ok {
+			groups[i] = append(groups[i], domain)
+			continue
+		}
+
+		index[base] = len(groups)
+		groups = append(groups, []string{domain})
+	}
+
+	var chunks [][]string
+	var current []string
+
+	for _, group := range groups {
+		if len(current) > 0 && len(current)+len(group) > maxSANs {
+			chunks = append(chunks, current)
+			current = nil
+		}
+
+		current = append(current, group...)
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
@@ -0,0 +1,97 @@
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_ObtainSplit(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{OverallRequestLimit: 1000})
+
+	var domains []string
+	for i := range 250 {
+		domains = append(domains, fmt.Sprintf("d%03d.example.com", i))
+	}
+
+	resources, err := certifier.ObtainSplit(context.Background(), ObtainRequest{Domains: domains, Bundle: true}, WithMaxSANsPerCert(100))
+	require.NoError(t, err)
+
+	require.Len(t, resources, 3)
+
+	expected := []struct {
+		domain string
+		count  int
+	}{
+		{domain: "d000.example.com", count: 100},
+		{domain: "d100.example.com", count: 100},
+		{domain: "d200.example.com", count: 50},
+	}
+
+	for i, res := range resources {
+		assert.Equal(t, expected[i].domain, res.Domain)
+
+		cert, err := parseLeaf(res.Certificate)
+		require.NoError(t, err)
+
+		assert.Len(t, cert.DNSNames, expected[i].count)
+	}
+}
+
+func TestCertifier_ObtainSplit_singleCert(t *testing.T) {
+	ca := newCAMock(t)
+
+	certifier := ca.newCertifier(CertifierOptions{})
+
+	domains := []string{"a.example.com", "b.example.com", "c.example.com"}
+
+	_, err := certifier.ObtainSplit(context.Background(), ObtainRequest{Domains: domains}, WithMaxSANsPerCert(2), WithSingleCert())
+	require.EqualError(t, err, "a single certificate is required, but the number of domains (3) exceeds the maximum number of SANs per certificate (2)")
+}
+
+func Test_splitDomains(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		domains  []string
+		maxSANs  int
+		expected [][]string
+	}{
+		{
+			desc:     "no split",
+			domains:  []string{"a.com", "b.com"},
+			maxSANs:  2,
+			expected: [][]string{{"a.com", "b.com"}},
+		},
+		{
+			desc:     "split",
+			domains:  []string{"a.com", "b.com", "c.com"},
+			maxSANs:  2,
+			expected: [][]string{{"a.com", "b.com"}, {"c.com"}},
+		},
+		{
+			desc:     "wildcard and apex kept together",
+			domains:  []string{"a.com", "b.com", "*.b.com", "c.com"},
+			maxSANs:  2,
+			expected: [][]string{{"a.com"}, {"b.com", "*.b.com"}, {"c.com"}},
+		},
+		{
+			desc:     "wildcard before apex",
+			domains:  []string{"*.a.com", "b.com", "a.com"},
+			maxSANs:  2,
+			expected: [][]string{{"*.a.com", "a.com"}, {"b.com"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, splitDomains(test.domains, test.maxSANs))
+		})
+	}
+}
@@ -20,6 +20,9 @@ const (
 
 	// TLSALPN01 is the "tls-alpn-01" ACME challenge https://www.rfc-editor.org/rfc/rfc8737.html
 	TLSALPN01 = Type("tls-alpn-01")
+
+	// EMAILREPLY00 is the "email-reply-00" ACME challenge (S/MIME) https://www.rfc-editor.org/rfc/rfc8823.html
+	EMAILREPLY00 = Type("email-reply-00")
 )
 
 func (t Type) String() string {
@@ -0,0 +1,95 @@
+package dns01
+
+import (
+	"strings"
+	"sync"
+)
+
+// activeRecords are the records being created or removed by the DNS providers on behalf of a challenge
+// (by FQDN, and by domain and value),
+// so the lookups done by the providers use the settings of the challenge
+// (see GetChallengeInfo and WithResolver, FindZoneByFqdn and WithZoneForDomain).
+var activeRecords = &recordRegistry{entries: make(map[string]*recordEntry)}
+
+type recordRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]*recordEntry
+}
+
+type recordEntry struct {
+	chlg  *Challenge
+	count int
+}
+
+func (r *recordRegistry) add(chlg *Challenge, keys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		entry, ok := r.entries[key]
+		if !ok || entry.chlg != chlg {
+			entry = &recordEntry{chlg: chlg}
+			r.entries[key] = entry
+		}
+
+		entry.count++
+	}
+}
+
+func (r *recordRegistry) remove(chlg *Challenge, keys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		entry, ok := r.entries[key]
+		if !ok || entry.chlg != chlg {
+			continue
+		}
+
+		entry.count--
+		if entry.count <= 0 {
+			delete(r.entries, key)
+		}
+	}
+}
+
+func (r *recordRegistry) get(key string) (*Challenge, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	return entry.chlg, true
+}
+
+// fqdnKey is the key of a record in the registry.
+func fqdnKey(fqdn string) string {
+	return strings.ToLower(ToFqdn(fqdn))
+}
+
+// infoKey is the key of the challenge information of a record in the registry.
+func infoKey(domain, value string) string {
+	return strings.ToLower(domain) + " " + value
+}
+
+// withRecords calls fn with the records registered as handled by the challenge.
+func (c *Challenge) withRecords(records []Record, fn func() error) error {
+	keys := make([]string, 0, 3*len(records))
+
+	for _, record := range records {
+		keys = append(keys, fqdnKey(record.FQDN), fqdnKey(record.Domain), infoKey(record.Domain, record.Value))
+	}
+
+	activeRecords.add(c, keys)
+	defer activeRecords.remove(c, keys)
+
+	return fn()
+}
+
+// withRecord is like withRecords for the record of a single domain.
+func (c *Challenge) withRecord(domain string, info ChallengeInfo, fn func() error) error {
+	return c.withRecords([]Record{{Domain: domain, FQDN: info.EffectiveFQDN, Value: info.Value}}, fn)
+}
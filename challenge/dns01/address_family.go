@@ -0,0 +1,112 @@
+package dns01
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/miekg/dns"
+)
+
+// AddressFamily is the IP address family used to reach the nameservers.
+type AddressFamily int
+
+const (
+	// AddressFamilyAny uses both IPv4 and IPv6 (default).
+	AddressFamilyAny AddressFamily = iota
+	// AddressFamilyIPv4 uses only IPv4.
+	AddressFamilyIPv4
+	// AddressFamilyIPv6 uses only IPv6 (e.g. on an IPv6-only network).
+	AddressFamilyIPv6
+)
+
+func (f AddressFamily) String() string {
+	switch f {
+	case AddressFamilyAny:
+		return "any"
+	case AddressFamilyIPv4:
+		return "ipv4"
+	case AddressFamilyIPv6:
+		return "ipv6"
+	default:
+		return fmt.Sprintf("AddressFamily(%d)", int(f))
+	}
+}
+
+// network returns the network of the DNS client for the address family ("udp", "udp4", "udp6", "tcp", ...).
+func (f AddressFamily) network(base string) string {
+	switch f {
+	case AddressFamilyIPv4:
+		return base + "4"
+	case AddressFamilyIPv6:
+		return base + "6"
+	default:
+		return base
+	}
+}
+
+// accepts returns false if the nameserver is an IP address of another address family.
+// The nameservers defined by a hostname are always accepted: the hostname is resolved for the address family.
+func (f AddressFamily) accepts(nameserver string) bool {
+	host, _, err := net.SplitHostPort(nameserver)
+	if err != nil {
+		host = nameserver
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return true
+	}
+
+	switch f {
+	case AddressFamilyIPv4:
+		return ip.To4() != nil
+	case AddressFamilyIPv6:
+		return ip.To4() == nil
+	default:
+		return true
+	}
+}
+
+// WithAddressFamily forces the IP address family used by the challenge to reach the nameservers
+// (the recursive and the authoritative ones):
+// the nameservers of the other family are ignored, and the hostnames are resolved only for this family.
+func WithAddressFamily(family AddressFamily) ChallengeOption {
+	return func(chlg *Challenge) error {
+		switch family {
+		case AddressFamilyAny, AddressFamilyIPv4, AddressFamilyIPv6:
+			chlg.preCheck.family = family
+			return nil
+		default:
+			return fmt.Errorf("unsupported address family: %s", family)
+		}
+	}
+}
+
+// rtypes returns the types of the address records of the address family.
+func (f AddressFamily) rtypes() []uint16 {
+	switch f {
+	case AddressFamilyIPv4:
+		return []uint16{dns.TypeA}
+	case AddressFamilyIPv6:
+		return []uint16{dns.TypeAAAA}
+	default:
+		return []uint16{dns.TypeA, dns.TypeAAAA}
+	}
+}
+
+// filterNameservers returns the nameservers reachable with the address family.
+func filterNameservers(nameservers []string, family AddressFamily) []string {
+	if family == AddressFamilyAny {
+		return nameservers
+	}
+
+	var filtered []string
+
+	for _, ns := range nameservers {
+		if family.accepts(ns) {
+			filtered = append(filtered, ns)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,97 @@
+package dns01
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAddressFamily_ipv6Only(t *testing.T) {
+	pc, err := net.ListenPacket("udp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 is not available: %v", err)
+	}
+	_ = pc.Close()
+
+	records := map[string][]string{"_acme-challenge.example.com.": {"value"}}
+
+	var v4Queries atomic.Int32
+
+	handler := txtHandler(records)
+
+	v4 := startDNSServerOn(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		v4Queries.Add(1)
+		handler(w, req)
+	})
+	v6 := startDNSServerOn(t, "[::1]:0", handler)
+
+	r, err := dnsQueryFamily("_acme-challenge.example.com.", dns.TypeTXT, []string{v4, v6}, true, AddressFamilyIPv6)
+	require.NoError(t, err)
+
+	require.Len(t, r.Answer, 1)
+	assert.Equal(t, []string{"value"}, r.Answer[0].(*dns.TXT).Txt)
+
+	assert.Zero(t, v4Queries.Load())
+}
+
+func TestWithAddressFamily_noNameserver(t *testing.T) {
+	_, err := dnsQueryFamily("_acme-challenge.example.com.", dns.TypeTXT, []string{"[::1]:53"}, true, AddressFamilyIPv4)
+	require.EqualError(t, err, "no nameserver for the address family ipv4")
+}
+
+func TestWithAddressFamily(t *testing.T) {
+	chlg := NewChallenge(nil, nil, &providerMock{}, WithAddressFamily(AddressFamilyIPv6))
+	assert.Equal(t, AddressFamilyIPv6, chlg.preCheck.family)
+
+	// the option is local to the challenge.
+	other := NewChallenge(nil, nil, &providerMock{})
+	assert.Equal(t, AddressFamilyAny, other.preCheck.family)
+}
+
+func TestWithAddressFamily_propagation(t *testing.T) {
+	records := map[string][]string{"_acme-challenge.example.com.": {"value"}}
+
+	v4 := startDNSServerOn(t, "127.0.0.1:0", txtHandler(records))
+
+	preCheck := newPreCheck()
+	preCheck.requireRecursiveNssPropagation = true
+	preCheck.requireAuthoritativeNssPropagation = false
+	preCheck.resolver = []string{v4}
+
+	found, err := preCheck.checkDNSPropagation("_acme-challenge.example.com.", "value")
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	preCheck.family = AddressFamilyIPv6
+
+	_, err = preCheck.checkDNSPropagation("_acme-challenge.example.com.", "value")
+	require.EqualError(t, err, "initial recursive nameserver: no nameserver for the address family ipv6")
+}
+
+func TestWithAddressFamily_unsupported(t *testing.T) {
+	err := WithAddressFamily(AddressFamily(42))(&Challenge{})
+	require.EqualError(t, err, "unsupported address family: AddressFamily(42)")
+}
+
+func Test_filterNameservers(t *testing.T) {
+	nameservers := []string{"192.0.2.1:53", "[2001:db8::1]:53", "ns.example.com:53"}
+
+	testCases := []struct {
+		family   AddressFamily
+		expected []string
+	}{
+		{family: AddressFamilyAny, expected: nameservers},
+		{family: AddressFamilyIPv4, expected: []string{"192.0.2.1:53", "ns.example.com:53"}},
+		{family: AddressFamilyIPv6, expected: []string{"[2001:db8::1]:53", "ns.example.com:53"}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.family.String(), func(t *testing.T) {
+			assert.Equal(t, test.expected, filterNameservers(nameservers, test.family))
+		})
+	}
+}
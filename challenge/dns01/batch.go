@@ -0,0 +1,196 @@
+package dns01
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// Record is a TXT record required by a dns-01 challenge.
+type Record struct {
+	// Domain is the domain of the authorization.
+	Domain string
+	// FQDN is the fully qualified name of the record (after CNAME resolution, if any).
+	FQDN string
+	// Value is the value of the TXT record.
+	Value string
+	// Comment is the comment to attach to the record, empty if not defined (see WithRecordComment).
+	Comment string
+}
+
+// BatchProvider is a provider able to create and remove the records of all the authorizations of an order at once.
+// When a provider implements this interface, PresentBatch and CleanUpBatch are called once per order
+// instead of calling Present and CleanUp for each authorization.
+// The zone check (see WithZoneCheck) is done for each record before the call to PresentBatch,
+// and the whole batch is retried on failure (see WithPresentRetry).
+type BatchProvider interface {
+	challenge.Provider
+	PresentBatch(records []Record) error
+	CleanUpBatch(records []Record) error
+}
+
+// Batch returns true if the provider supports the creation of the records in batch.
+// The records are not created in batch when there are providers by domain (see WithDNSProviderForDomain),
+// or when the provider also implements AuthzProvider.
+func (c *Challenge) Batch() bool {
+	if len(c.domainProviders) > 0 {
+		return false
+	}
+
+	if _, ok := c.provider.(AuthzProvider); ok {
+		return false
+	}
+
+	_, ok := c.provider.(BatchProvider)
+	return ok
+}
+
+// PreSolveBatch submits the TXT records of all the authorizations to the DNS provider at once.
+// The provider must implement BatchProvider (see Batch).
+func (c *Challenge) PreSolveBatch(authzs []acme.Authorization) error {
+	provider, ok := c.provider.(BatchProvider)
+	if !ok || !c.Batch() {
+		return fmt.Errorf("acme: the DNS provider %T doesn't support batch", c.provider)
+	}
+
+	for _, authz := range authzs {
+		domain := challenge.GetTargetedDomain(authz)
+		log.Infof("[%s] acme: Preparing to solve DNS-01", domain)
+
+		err := c.checkCAA(authz)
+		if err != nil {
+			return fmt.Errorf("[%s] acme: %w", domain, err)
+		}
+	}
+
+	records, err := c.getRecords(authzs)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		err = c.checkRecordZone(record.Domain, record.FQDN)
+		if err != nil {
+			return fmt.Errorf("[%s] acme: error presenting token: %w", record.Domain, err)
+		}
+	}
+
+	err = c.presentBatch(provider, records)
+	if err != nil {
+		return fmt.Errorf("acme: error presenting tokens: %w", err)
+	}
+
+	for i, record := range records {
+		c.events.emit(c.clock.Now(), EventChallengePresented, challenge.GetTargetedDomain(authzs[i]), record.FQDN, nil)
+	}
+
+	return nil
+}
+
+func (c *Challenge) presentBatch(provider BatchProvider, records []Record) error {
+	for attempt := 1; ; attempt++ {
+		err := c.withRecords(records, func() error { return provider.PresentBatch(records) })
+		if err == nil {
+			err = c.verifyBatch(records)
+		}
+
+		if err == nil || attempt >= c.presentRetry.attempts {
+			return err
+		}
+
+		log.Infof("acme: error presenting tokens (attempt %d/%d): %v", attempt, c.presentRetry.attempts, err)
+
+		c.clock.Sleep(c.presentRetry.interval)
+	}
+}
+
+// verifyBatch checks that the provider reports the records as created, if the provider implements ProviderVerify.
+func (c *Challenge) verifyBatch(records []Record) error {
+	verifier, ok := c.provider.(ProviderVerify)
+	if !ok {
+		return nil
+	}
+
+	for _, record := range records {
+		found, err := verifier.Verify(record.FQDN, record.Value)
+		if err != nil {
+			return fmt.Errorf("verify: %w", err)
+		}
+
+		if !found {
+			return fmt.Errorf("verify: the DNS provider doesn't report the record as created [fqdn: %s]", record.FQDN)
+		}
+	}
+
+	return nil
+}
+
+// CleanUpBatch removes the TXT records of all the authorizations at once.
+// The provider must implement BatchProvider.
+func (c *Challenge) CleanUpBatch(authzs []acme.Authorization) error {
+	provider, ok := c.provider.(BatchProvider)
+	if !ok || !c.Batch() {
+		return fmt.Errorf("acme: the DNS provider %T doesn't support batch", c.provider)
+	}
+
+	for _, authz := range authzs {
+		log.Infof("[%s] acme: Cleaning DNS-01 challenge", challenge.GetTargetedDomain(authz))
+	}
+
+	records, err := c.getRecords(authzs)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		for _, record := range records {
+			c.forgetChallengeInfo(record.Domain, record.Value)
+		}
+	}()
+
+	if !supportsCleanUp(provider) {
+		for i, record := range records {
+			log.Warnf("[%s] acme: the DNS provider doesn't remove the records, the TXT record %s may linger", challenge.GetTargetedDomain(authzs[i]), record.FQDN)
+		}
+	}
+
+	err = c.withRecords(records, func() error { return provider.CleanUpBatch(records) })
+	if err != nil {
+		return err
+	}
+
+	for i, record := range records {
+		c.events.emit(c.clock.Now(), EventCleanupDone, challenge.GetTargetedDomain(authzs[i]), record.FQDN, nil)
+	}
+
+	return nil
+}
+
+func (c *Challenge) getRecords(authzs []acme.Authorization) ([]Record, error) {
+	records := make([]Record, 0, len(authzs))
+
+	for _, authz := range authzs {
+		chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
+		if err != nil {
+			return nil, err
+		}
+
+		keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		info := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+
+		records = append(records, Record{
+			Domain:  authz.Identifier.Value,
+			FQDN:    info.EffectiveFQDN,
+			Value:   info.Value,
+			Comment: c.getRecordComment(authz.Identifier.Value, keyAuth),
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,182 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchProviderMock struct {
+	providerTimeoutMock
+
+	// failures is the number of calls to PresentBatch to fail.
+	failures int
+
+	presented [][]Record
+	cleaned   [][]Record
+}
+
+func (p *batchProviderMock) PresentBatch(records []Record) error {
+	p.presented = append(p.presented, records)
+
+	if len(p.presented) <= p.failures {
+		return errors.New("rate limited")
+	}
+
+	return p.present
+}
+
+func (p *batchProviderMock) CleanUpBatch(records []Record) error {
+	p.cleaned = append(p.cleaned, records)
+	return p.cleanUp
+}
+
+func TestChallenge_Batch(t *testing.T) {
+	assert.False(t, NewChallenge(nil, nil, &providerMock{}).Batch())
+	assert.True(t, NewChallenge(nil, nil, &batchProviderMock{}).Batch())
+
+	// the records are created through PresentAuthz.
+	assert.False(t, NewChallenge(nil, nil, &optionalProviderMock{}).Batch())
+}
+
+func TestChallenge_PreSolveBatch(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &batchProviderMock{}
+
+	chlg := NewChallenge(core, nil, provider)
+
+	var authzs []acme.Authorization
+	for _, domain := range []string{"example.com", "a.example.com", "b.example.com"} {
+		authzs = append(authzs, acme.Authorization{
+			Identifier: acme.Identifier{Value: domain},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token-" + domain}},
+		})
+	}
+
+	err = chlg.PreSolveBatch(authzs)
+	require.NoError(t, err)
+
+	err = chlg.CleanUpBatch(authzs)
+	require.NoError(t, err)
+
+	require.Len(t, provider.presented, 1)
+	require.Len(t, provider.cleaned, 1)
+
+	records := provider.presented[0]
+	require.Len(t, records, 3)
+
+	for i, record := range records {
+		keyAuth, err := core.GetKeyAuthorization(authzs[i].Challenges[0].Token)
+		require.NoError(t, err)
+
+		info := GetChallengeInfo(authzs[i].Identifier.Value, keyAuth)
+
+		assert.Equal(t, Record{Domain: authzs[i].Identifier.Value, FQDN: info.EffectiveFQDN, Value: info.Value}, record)
+	}
+
+	assert.Equal(t, records, provider.cleaned[0])
+}
+
+func TestChallenge_PreSolveBatch_notSupported(t *testing.T) {
+	chlg := NewChallenge(nil, nil, &providerMock{})
+
+	err := chlg.PreSolveBatch(nil)
+	require.Error(t, err)
+
+	err = chlg.CleanUpBatch(nil)
+	require.Error(t, err)
+}
+
+func TestChallenge_PreSolveBatch_options(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authzs := []acme.Authorization{
+		{
+			Identifier: acme.Identifier{Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token-a"}},
+		},
+		{
+			Identifier: acme.Identifier{Value: "example.org"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token-b"}},
+		},
+	}
+
+	provider := &batchProviderMock{failures: 1}
+
+	chlg := NewChallenge(core, nil, provider,
+		WithPresentRetry(2, 0),
+		WithRecordComment(func(domain, _ string) string { return "lego " + domain }),
+	)
+
+	err = chlg.PreSolveBatch(authzs)
+	require.NoError(t, err)
+
+	// the whole batch is retried.
+	require.Len(t, provider.presented, 2)
+	assert.Equal(t, provider.presented[0], provider.presented[1])
+
+	var comments []string
+	for _, record := range provider.presented[1] {
+		comments = append(comments, record.Comment)
+	}
+
+	assert.Equal(t, []string{"lego example.com", "lego example.org"}, comments)
+}
+
+func TestChallenge_PreSolveBatch_zoneNotFound(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	ClearFqdnCache()
+	t.Cleanup(ClearFqdnCache)
+
+	setRecursiveNameservers(t, startDNSServer(t, soaHandler("example.org.")))
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authzs := []acme.Authorization{{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}}
+
+	provider := &batchProviderMock{}
+
+	chlg := NewChallenge(core, nil, provider, WithZoneCheck())
+
+	err = chlg.PreSolveBatch(authzs)
+	require.Error(t, err)
+
+	var zoneErr *ZoneNotFoundError
+	require.ErrorAs(t, err, &zoneErr)
+
+	assert.Empty(t, provider.presented)
+}
@@ -0,0 +1,88 @@
+package dns01
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	stdlog "log"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capabilitiesProviderMock struct {
+	providerMock
+
+	supportsCleanUp bool
+}
+
+func (p *capabilitiesProviderMock) SupportsCleanUp() bool {
+	return p.supportsCleanUp
+}
+
+func TestChallenge_CleanUp_noOp(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: "dns-01", Token: "token"}},
+	}
+
+	const warning = "[WARN] [example.com] acme: the DNS provider doesn't remove the records, the TXT record _acme-challenge.example.com. may linger"
+
+	testCases := []struct {
+		desc     string
+		provider challenge.Provider
+		noOp     bool
+	}{
+		{
+			desc:     "no-op CleanUp",
+			provider: &capabilitiesProviderMock{supportsCleanUp: false},
+			noOp:     true,
+		},
+		{
+			desc:     "supported CleanUp",
+			provider: &capabilitiesProviderMock{supportsCleanUp: true},
+		},
+		{
+			desc:     "without capabilities",
+			provider: &providerMock{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			backupLogger := log.Logger
+			t.Cleanup(func() { log.Logger = backupLogger })
+
+			buf := &bytes.Buffer{}
+			log.Logger = stdlog.New(buf, "", 0)
+
+			chlg := NewChallenge(core, nil, test.provider)
+
+			err := chlg.CleanUp(authz)
+			require.NoError(t, err)
+
+			if test.noOp {
+				assert.Contains(t, buf.String(), warning)
+			} else {
+				assert.NotContains(t, buf.String(), warning)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package dns01
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/wait"
+	"github.com/miekg/dns"
+)
+
+type cleanupVerification struct {
+	gracePeriod time.Duration
+	strict      bool
+}
+
+// WithVerifyCleanup checks, after the CleanUp of the provider, that the TXT record is gone:
+// the authoritative nameservers (or the propagation nameservers, see WithPropagationNameservers) are queried
+// until none of them returns the value of the challenge, up to gracePeriod.
+// This detects the providers reporting a successful CleanUp without removing the record.
+//
+// If the record still resolves after the grace period, a warning is logged,
+// or, if strict is true, CleanUp returns an error.
+func WithVerifyCleanup(gracePeriod time.Duration, strict bool) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if gracePeriod <= 0 {
+			return fmt.Errorf("invalid cleanup verification grace period: %s", gracePeriod)
+		}
+
+		chlg.cleanupVerification = &cleanupVerification{gracePeriod: gracePeriod, strict: strict}
+		return nil
+	}
+}
+
+// verifyCleanup checks that the TXT record has been removed, if enabled (see WithVerifyCleanup).
+func (c *Challenge) verifyCleanup(domain string, info ChallengeInfo) error {
+	_, interval := c.getTimeouts(domain)
+	interval = min(interval, c.cleanupVerification.gracePeriod)
+
+	err := wait.For("cleanup verification", c.cleanupVerification.gracePeriod, interval, func() (bool, error) {
+		errC := c.preCheck.checkRecordRemoved(info.EffectiveFQDN, info.Value)
+		return errC == nil, errC
+	})
+	if err == nil {
+		return nil
+	}
+
+	if c.cleanupVerification.strict {
+		return fmt.Errorf("the TXT record has not been removed [fqdn: %s]: %w", info.EffectiveFQDN, err)
+	}
+
+	log.Warnf("[%s] acme: the TXT record has not been removed [fqdn: %s]: %v", domain, info.EffectiveFQDN, err)
+
+	return nil
+}
+
+// checkRecordRemoved checks that none of the nameservers returns the TXT record with the given value.
+func (p preCheck) checkRecordRemoved(fqdn, value string) error {
+	nameservers := p.propagationNameservers
+
+	if len(nameservers) == 0 {
+		authoritativeNss, err := lookupNameserversCustom(fqdn, p.recursiveNameservers(), p.family)
+		if err != nil {
+			return err
+		}
+
+		for _, ns := range authoritativeNss {
+			nameservers = append(nameservers, net.JoinHostPort(ns, "53"))
+		}
+	}
+
+	var remaining []string
+
+	for _, ns := range nameservers {
+		r, err := dnsQueryFamily(fqdn, dns.TypeTXT, []string{ns}, false, p.family)
+		if err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(r.Answer, func(rr dns.RR) bool {
+			txt, ok := rr.(*dns.TXT)
+			return ok && strings.Join(txt.Txt, "") == value
+		}) {
+			remaining = append(remaining, ns)
+		}
+	}
+
+	if len(remaining) > 0 {
+		return fmt.Errorf("NS %s still returned the TXT record [fqdn: %s, value: %s]", strings.Join(remaining, ", "), fqdn, value)
+	}
+
+	return nil
+}
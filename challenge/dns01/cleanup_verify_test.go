@@ -0,0 +1,114 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// cleanupProviderMock reports a successful CleanUp, and removes the record only if remove is true.
+type cleanupProviderMock struct {
+	remove  bool
+	removed atomic.Bool
+}
+
+func (p *cleanupProviderMock) Present(domain, token, keyAuth string) error { return nil }
+
+func (p *cleanupProviderMock) CleanUp(domain, token, keyAuth string) error {
+	p.removed.Store(p.remove)
+	return nil
+}
+
+func (p *cleanupProviderMock) Timeout() (time.Duration, time.Duration) {
+	return time.Second, 10 * time.Millisecond
+}
+
+func TestWithVerifyCleanup(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	keyAuth, err := core.GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	setRecursiveNameservers(t, startDNSServer(t, txtHandler(nil)))
+
+	records := map[string][]string{"_acme-challenge.example.com.": {getChallengeValue(keyAuth)}}
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: "dns-01", Token: "token"}},
+	}
+
+	testCases := []struct {
+		desc          string
+		remove        bool
+		strict        bool
+		expectedError string
+	}{
+		{
+			desc:   "record removed",
+			remove: true,
+			strict: true,
+		},
+		{
+			desc: "record persists",
+		},
+		{
+			desc:          "record persists (strict)",
+			strict:        true,
+			expectedError: "[example.com] acme: the TXT record has not been removed [fqdn: _acme-challenge.example.com.]: cleanup verification: time limit exceeded: last error: NS ",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider := &cleanupProviderMock{remove: test.remove}
+
+			ns := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+				if provider.removed.Load() {
+					txtHandler(nil)(w, req)
+					return
+				}
+
+				txtHandler(records)(w, req)
+			})
+
+			chlg := NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error { return nil }, provider,
+				WithPropagationNameservers([]string{ns}),
+				WithVerifyCleanup(100*time.Millisecond, test.strict),
+			)
+
+			err := chlg.CleanUp(authz)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				require.ErrorContains(t, err, "still returned the TXT record")
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWithVerifyCleanup_invalid(t *testing.T) {
+	chlg := &Challenge{}
+
+	err := WithVerifyCleanup(0, false)(chlg)
+	require.EqualError(t, err, "invalid cleanup verification grace period: 0s")
+}
@@ -0,0 +1,21 @@
+package dns01
+
+import (
+	"errors"
+
+	"github.com/go-acme/lego/v4/platform/clock"
+)
+
+// WithClock replaces the clock used by the challenge (the waits before and between the propagation checks,
+// the retries of Present, and the elapsed times), e.g. a clock.Fake to solve the challenge without wall-clock delay in tests.
+func WithClock(clk clock.Clock) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if clk == nil {
+			return errors.New("the clock is nil")
+		}
+
+		chlg.clock = clk
+
+		return nil
+	}
+}
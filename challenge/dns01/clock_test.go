@@ -0,0 +1,99 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/clock"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithClock(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFake(start)
+
+	var progress []PropagationProgress
+
+	// the record is propagated after 5 minutes.
+	chlg := NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		&providerTimeoutMock{timeout: time.Hour, interval: time.Minute},
+		WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+			return !fakeClock.Now().Before(start.Add(5 * time.Minute)), nil
+		}),
+		WithPropagationProgress(func(p PropagationProgress) { progress = append(progress, p) }),
+		WithClock(fakeClock),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	wallStart := time.Now()
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(wallStart), 5*time.Second)
+
+	assert.Equal(t, start.Add(5*time.Minute), fakeClock.Now())
+
+	require.Len(t, progress, 5)
+	assert.Equal(t, time.Minute, progress[0].Elapsed)
+	assert.Equal(t, 5*time.Minute, progress[4].Elapsed)
+	assert.True(t, progress[4].Propagated)
+}
+
+func TestWithClock_timeout(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFake(start)
+
+	chlg := NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		&providerTimeoutMock{timeout: time.Hour, interval: time.Minute},
+		WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) { return false, nil }),
+		WithClock(fakeClock),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.Solve(authz)
+	require.EqualError(t, err, "propagation: time limit exceeded")
+
+	assert.Equal(t, start.Add(time.Hour+time.Minute), fakeClock.Now())
+}
+
+func TestWithClock_nil(t *testing.T) {
+	err := WithClock(nil)(&Challenge{})
+	require.EqualError(t, err, "the clock is nil")
+}
@@ -3,9 +3,31 @@ package dns01
 import (
 	"strings"
 
+	"github.com/go-acme/lego/v4/log"
 	"github.com/miekg/dns"
 )
 
+// WithCNAMEFollowPredicate limits the CNAME following (e.g. to the target zones provisioned for the challenges):
+// the predicate is called at each hop, with the current FQDN and the target of its CNAME,
+// and the following stops at the current FQDN when it returns false.
+func WithCNAMEFollowPredicate(predicate func(from, to string) bool) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.cnameFollow = predicate
+		return nil
+	}
+}
+
+// acceptCNAME returns true if the CNAME from the FQDN to the target must be followed (a nil predicate follows all the CNAMEs).
+func acceptCNAME(predicate func(from, to string) bool, from, to string) bool {
+	if predicate == nil || predicate(from, to) {
+		return true
+	}
+
+	log.Infof("Not following the CNAME entry for %q: %q (stopped by the predicate)", from, to)
+
+	return false
+}
+
 // Update FQDN with CNAME if any.
 func updateDomainWithCName(r *dns.Msg, fqdn string) string {
 	for _, rr := range r.Answer {
@@ -1,11 +1,20 @@
 package dns01
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
 	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_updateDomainWithCName_caseInsensitive(t *testing.T) {
@@ -33,3 +42,157 @@ func Test_updateDomainWithCName_caseInsensitive(t *testing.T) {
 
 	assert.Equal(t, cnameTarget, fqdn)
 }
+
+// cnameHandler answers to the CNAME queries with the given chain (FQDN -> target).
+func cnameHandler(chain map[string]string) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		for _, q := range req.Question {
+			target, ok := chain[q.Name]
+			if !ok || q.Qtype != dns.TypeCNAME {
+				continue
+			}
+
+			m.Answer = append(m.Answer, &dns.CNAME{
+				Hdr:    dns.RR_Header{Name: q.Name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: 60},
+				Target: target,
+			})
+		}
+
+		_ = w.WriteMsg(m)
+	}
+}
+
+func TestWithCNAMEFollowPredicate(t *testing.T) {
+	chain := map[string]string{
+		"_acme-challenge.example.com.":              "_acme-challenge.example.com.acme.test.",
+		"_acme-challenge.example.com.acme.test.":    "_acme-challenge.example.com.external.net.",
+		"_acme-challenge.example.com.external.net.": "_acme-challenge.example.com.other.org.",
+	}
+
+	nameservers := []string{startDNSServer(t, cnameHandler(chain))}
+
+	testCases := []struct {
+		desc      string
+		predicate func(from, to string) bool
+		expected  string
+	}{
+		{
+			desc:     "no predicate",
+			expected: "_acme-challenge.example.com.other.org.",
+		},
+		{
+			desc: "stop at the zone boundary",
+			predicate: func(_, to string) bool {
+				return strings.HasSuffix(to, ".acme.test.")
+			},
+			expected: "_acme-challenge.example.com.acme.test.",
+		},
+		{
+			desc:      "never follow",
+			predicate: func(_, _ string) bool { return false },
+			expected:  "_acme-challenge.example.com.",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var hops [][2]string
+
+			predicate := test.predicate
+			if predicate != nil {
+				predicate = func(from, to string) bool {
+					hops = append(hops, [2]string{from, to})
+					return test.predicate(from, to)
+				}
+			}
+
+			chlg := NewChallenge(nil, nil, nil, WithResolver(nameservers), WithCNAMEFollowPredicate(predicate))
+
+			info := chlg.getChallengeInfo("example.com", "keyAuth")
+
+			assert.Equal(t, "_acme-challenge.example.com.", info.FQDN)
+			assert.Equal(t, test.expected, info.EffectiveFQDN)
+
+			if test.predicate != nil {
+				// the predicate is consulted at each hop, up to the first refusal.
+				last := hops[len(hops)-1]
+				assert.Equal(t, test.expected, last[0])
+				assert.Equal(t, chain[test.expected], last[1])
+			}
+		})
+	}
+}
+
+func TestWithCNAMEFollowPredicate_perChallenge(t *testing.T) {
+	chain := map[string]string{
+		"_acme-challenge.example.com.":           "_acme-challenge.example.com.acme.test.",
+		"_acme-challenge.example.com.acme.test.": "_acme-challenge.example.com.external.net.",
+	}
+
+	nameservers := []string{startDNSServer(t, cnameHandler(chain))}
+	setRecursiveNameservers(t, nameservers...)
+
+	stopped := NewChallenge(nil, nil, nil, WithResolver(nameservers),
+		WithCNAMEFollowPredicate(func(_, _ string) bool { return false }))
+
+	other := NewChallenge(nil, nil, nil, WithResolver(nameservers))
+
+	assert.Equal(t, "_acme-challenge.example.com.", stopped.getChallengeInfo("example.com", "keyAuth").EffectiveFQDN)
+
+	// the predicate of a challenge doesn't apply to the other challenges.
+	assert.Equal(t, "_acme-challenge.example.com.external.net.", other.getChallengeInfo("example.com", "keyAuth").EffectiveFQDN)
+	assert.Equal(t, "_acme-challenge.example.com.external.net.", GetChallengeInfo("example.com", "keyAuth").EffectiveFQDN)
+}
+
+func TestChallenge_getChallengeInfo_once(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "false")
+
+	chain := map[string]string{
+		"_acme-challenge.example.com.": "_acme-challenge.example.com.acme.test.",
+	}
+
+	var queries atomic.Int32
+
+	handler := cnameHandler(chain)
+
+	nameservers := []string{startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		for _, q := range req.Question {
+			if q.Qtype == dns.TypeCNAME && q.Name == "_acme-challenge.example.com." {
+				queries.Add(1)
+			}
+		}
+
+		handler(w, req)
+	})}
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	provider := &fqdnProviderMock{}
+
+	chlg := NewChallenge(core, nil, provider, WithResolver(nameservers),
+		WithRecordComment(func(domain, _ string) string { return domain }))
+
+	require.NoError(t, chlg.PreSolve(authz))
+	require.NoError(t, chlg.CleanUp(authz))
+
+	assert.Equal(t, int32(1), queries.Load())
+	assert.Equal(t, []string{"_acme-challenge.example.com.acme.test."}, provider.presented)
+	assert.Equal(t, []string{"_acme-challenge.example.com.acme.test."}, provider.cleaned)
+
+	// the information is dropped by the clean up.
+	assert.Empty(t, chlg.infos)
+}
@@ -0,0 +1,113 @@
+package dns01
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WithDelegatedFQDN defines, per domain, the FQDN of the TXT record to create,
+// instead of the FQDN derived from the domain (`_acme-challenge.<domain>.` and the CNAMEs resolution).
+//
+// This is useful when `_acme-challenge.<domain>` is delegated (through a CNAME) to a dedicated zone
+// with a fixed record name.
+// The keys are the domains of the authorizations (without the wildcard prefix),
+// the values are the FQDNs of the records.
+//
+// The records of the delegated domains are created through BatchProvider.PresentBatch (and removed through BatchProvider.CleanUpBatch),
+// if the provider implements BatchProvider, otherwise through Present (and CleanUp):
+// the provider gets the delegated FQDN as EffectiveFQDN from GetChallengeInfo.
+// The propagation checks are done against the delegated FQDNs.
+func WithDelegatedFQDN(mapping map[string]string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if len(mapping) == 0 {
+			return errors.New("empty delegated FQDN mapping")
+		}
+
+		delegated := make(map[string]string, len(mapping))
+
+		for domain, fqdn := range mapping {
+			if fqdn == "" {
+				return fmt.Errorf("empty delegated FQDN for %s", domain)
+			}
+
+			delegated[strings.ToLower(UnFqdn(domain))] = ToFqdn(strings.ToLower(fqdn))
+		}
+
+		chlg.delegatedFQDNs = delegated
+
+		return nil
+	}
+}
+
+// getChallengeInfo is like GetChallengeInfo but uses the delegated FQDN of the domain, if any, as EffectiveFQDN.
+// The CNAMEs are followed with the resolver of the challenge (see WithResolver), and its predicate (see WithCNAMEFollowPredicate).
+//
+// The CNAMEs are resolved once per authorization:
+// the information is kept from the first use to the clean up of the record (see forgetChallengeInfo),
+// so the record is removed where it was created.
+func (c *Challenge) getChallengeInfo(domain, keyAuth string) ChallengeInfo {
+	if record, ok := c.getDelegatedRecord(domain, keyAuth); ok {
+		return ChallengeInfo{
+			Value:         record.Value,
+			FQDN:          getChallengeFQDN(domain, false, nil, AddressFamilyAny, nil),
+			EffectiveFQDN: record.FQDN,
+		}
+	}
+
+	key := infoKey(domain, getChallengeValue(keyAuth))
+
+	c.infosMu.Lock()
+	info, ok := c.infos[key]
+	c.infosMu.Unlock()
+
+	if ok {
+		return info
+	}
+
+	info = getChallengeInfoCustom(domain, keyAuth, c.preCheck.recursiveNameservers(), c.preCheck.family, c.cnameFollow)
+
+	c.infosMu.Lock()
+	defer c.infosMu.Unlock()
+
+	if c.infos == nil {
+		c.infos = make(map[string]ChallengeInfo)
+	}
+
+	c.infos[key] = info
+
+	return info
+}
+
+// forgetChallengeInfo removes the challenge information of the record from the cache of getChallengeInfo.
+func (c *Challenge) forgetChallengeInfo(domain, value string) {
+	c.infosMu.Lock()
+	defer c.infosMu.Unlock()
+
+	delete(c.infos, infoKey(domain, value))
+}
+
+func (c *Challenge) getDelegatedRecord(domain, keyAuth string) (Record, bool) {
+	fqdn, ok := c.delegatedFQDNs[strings.ToLower(domain)]
+	if !ok {
+		return Record{}, false
+	}
+
+	return Record{Domain: domain, FQDN: fqdn, Value: getChallengeValue(keyAuth)}, true
+}
+
+// getDelegatedBatchProvider returns the record of the delegated domain, and the provider of the domain,
+// if the domain is delegated and the provider implements BatchProvider.
+func (c *Challenge) getDelegatedBatchProvider(domain, keyAuth string) (Record, BatchProvider, bool) {
+	record, ok := c.getDelegatedRecord(domain, keyAuth)
+	if !ok {
+		return Record{}, nil, false
+	}
+
+	provider, ok := c.getProvider(domain).(BatchProvider)
+	if !ok {
+		return Record{}, nil, false
+	}
+
+	return record, provider, true
+}
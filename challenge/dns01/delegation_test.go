@@ -0,0 +1,103 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDelegatedFQDN(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &batchProviderMock{
+		providerTimeoutMock: providerTimeoutMock{timeout: time.Second, interval: time.Millisecond},
+	}
+
+	var checkedFQDN string
+
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		provider,
+		WithDelegatedFQDN(map[string]string{"Example.com": "example-com.acme.example.org"}),
+		WrapPreCheck(func(_, fqdn, _ string, _ PreCheckFunc) (bool, error) {
+			checkedFQDN = fqdn
+			return true, nil
+		}),
+	)
+
+	assert.Equal(t, map[string]string{"example.com": "example-com.acme.example.org."}, chlg.delegatedFQDNs)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	keyAuth, err := core.GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	expected := Record{
+		Domain: "example.com",
+		FQDN:   "example-com.acme.example.org.",
+		Value:  GetChallengeInfo("example.com", keyAuth).Value,
+	}
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+
+	require.Equal(t, [][]Record{{expected}}, provider.presented)
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected.FQDN, checkedFQDN)
+
+	err = chlg.CleanUp(authz)
+	require.NoError(t, err)
+
+	require.Equal(t, [][]Record{{expected}}, provider.cleaned)
+}
+
+func TestWithDelegatedFQDN_notBatchProvider(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &fqdnProviderMock{}
+
+	chlg := NewChallenge(core, nil, provider,
+		WithDelegatedFQDN(map[string]string{"example.com": "example-com.acme.example.org"}))
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+
+	err = chlg.CleanUp(authz)
+	require.NoError(t, err)
+
+	// the provider creates and removes the record at the delegated FQDN.
+	assert.Equal(t, []string{"example-com.acme.example.org."}, provider.presented)
+	assert.Equal(t, []string{"example-com.acme.example.org."}, provider.cleaned)
+}
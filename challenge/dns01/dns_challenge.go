@@ -1,18 +1,22 @@
 package dns01
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/clock"
 	"github.com/go-acme/lego/v4/platform/wait"
 	"github.com/miekg/dns"
 )
@@ -32,6 +36,32 @@ type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) erro
 
 type ChallengeOption func(*Challenge) error
 
+// WithValidateFunc replaces the function used to validate the challenge with the ACME server.
+// The function receives the live *api.Core of the client.
+func WithValidateFunc(validate ValidateFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if validate == nil {
+			return errors.New("validate function is nil")
+		}
+
+		chlg.validate = validate
+		return nil
+	}
+}
+
+// WrapValidateFunc wraps the function used to validate the challenge with the ACME server
+// (by default, the standard ACME validation), in order to add telemetry, retries, etc.
+func WrapValidateFunc(wrap func(next ValidateFunc) ValidateFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if wrap == nil {
+			return errors.New("validate wrapper is nil")
+		}
+
+		chlg.validate = wrap(chlg.validate)
+		return nil
+	}
+}
+
 // CondOption Conditional challenge option.
 func CondOption(condition bool, opt ChallengeOption) ChallengeOption {
 	if !condition {
@@ -50,6 +80,41 @@ type Challenge struct {
 	provider   challenge.Provider
 	preCheck   preCheck
 	dnsTimeout time.Duration
+
+	propagationProfile *PropagationProfile
+
+	events *eventWriter
+
+	delegatedFQDNs map[string]string
+
+	presentRetry presentRetry
+
+	caaAccountURICheck bool
+
+	domainProviders map[string]challenge.Provider
+
+	propagationProgress func(p PropagationProgress)
+
+	propagationBackoff wait.Backoff
+
+	cleanupVerification *cleanupVerification
+
+	recordComment func(domain, fqdn string) string
+
+	ttlReduction *ttlReduction
+
+	validationGate ValidationGateFunc
+
+	zoneCheck bool
+
+	zoneOverrides map[string]string
+
+	cnameFollow func(from, to string) bool
+
+	infosMu sync.Mutex
+	infos   map[string]ChallengeInfo
+
+	clock clock.Clock
 }
 
 func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Provider, opts ...ChallengeOption) *Challenge {
@@ -59,6 +124,7 @@ func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Prov
 		provider:   provider,
 		preCheck:   newPreCheck(),
 		dnsTimeout: 10 * time.Second,
+		clock:      clock.Real,
 	}
 
 	for _, opt := range opts {
@@ -68,6 +134,8 @@ func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Prov
 		}
 	}
 
+	chlg.useProviderNameservers()
+
 	return chlg
 }
 
@@ -82,25 +150,117 @@ func (c *Challenge) PreSolve(authz acme.Authorization) error {
 		return err
 	}
 
-	if c.provider == nil {
+	if c.getProvider(authz.Identifier.Value) == nil {
 		return fmt.Errorf("[%s] acme: no DNS Provider configured", domain)
 	}
 
+	err = c.checkCAA(authz)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", domain, err)
+	}
+
 	// Generate the Key Authorization for the challenge
 	keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
 	if err != nil {
 		return err
 	}
 
-	err = c.provider.Present(authz.Identifier.Value, chlng.Token, keyAuth)
+	err = c.present(authz, chlng.Token, keyAuth)
 	if err != nil {
 		return fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)
 	}
 
+	info := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+
+	if _, delegated := c.getDelegatedRecord(authz.Identifier.Value, keyAuth); !delegated {
+		logRecordInfo(domain, c.getProvider(authz.Identifier.Value), info)
+	}
+
+	c.events.emit(c.clock.Now(), EventChallengePresented, domain, info.EffectiveFQDN, nil)
+
+	return nil
+}
+
+func (c *Challenge) present(authz acme.Authorization, token, keyAuth string) error {
+	for attempt := 1; ; attempt++ {
+		err := c.presentAttempt(attempt, authz, token, keyAuth)
+		if err == nil {
+			err = c.verify(authz, keyAuth)
+		}
+
+		var zoneErr *ZoneNotFoundError
+		if err == nil || attempt >= c.presentRetry.attempts || errors.As(err, &zoneErr) {
+			return err
+		}
+
+		log.Infof("[%s] acme: error presenting token (attempt %d/%d): %v",
+			challenge.GetTargetedDomain(authz), attempt, c.presentRetry.attempts, err)
+
+		c.clock.Sleep(c.presentRetry.interval)
+	}
+}
+
+// verify checks that the provider reports the record as created, if the provider implements ProviderVerify.
+func (c *Challenge) verify(authz acme.Authorization, keyAuth string) error {
+	provider, ok := c.getProvider(authz.Identifier.Value).(ProviderVerify)
+	if !ok {
+		return nil
+	}
+
+	info := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+
+	found, err := provider.Verify(info.EffectiveFQDN, info.Value)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	if !found {
+		return fmt.Errorf("verify: the DNS provider doesn't report the record as created [fqdn: %s]", info.EffectiveFQDN)
+	}
+
 	return nil
 }
 
+func (c *Challenge) presentAttempt(attempt int, authz acme.Authorization, token, keyAuth string) error {
+	if record, provider, ok := c.getDelegatedBatchProvider(authz.Identifier.Value, keyAuth); ok {
+		record.Comment = c.getRecordComment(authz.Identifier.Value, keyAuth)
+
+		return c.withRecords([]Record{record}, func() error { return provider.PresentBatch([]Record{record}) })
+	}
+
+	err := c.checkZone(authz.Identifier.Value, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	provider := c.getProvider(authz.Identifier.Value)
+
+	info := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+
+	return c.withRecord(authz.Identifier.Value, info, func() error {
+		return c.presentProvider(provider, attempt, authz, token, keyAuth, info)
+	})
+}
+
+func (c *Challenge) presentProvider(provider challenge.Provider, attempt int, authz acme.Authorization, token, keyAuth string, info ChallengeInfo) error {
+	if provider, ok := provider.(AuthzProvider); ok {
+		return provider.PresentAuthz(authz, info)
+	}
+
+	return presentWithOptions(provider, authz.Identifier.Value, token, keyAuth, PresentOptions{
+		Attempt: attempt,
+		Comment: c.getRecordComment(authz.Identifier.Value, keyAuth),
+	})
+}
+
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveBefore(authz, time.Time{})
+}
+
+// SolveBefore is like Solve, but the propagation wait is bounded by the deadline (no deadline if zero),
+// and the validation is not requested once the deadline is exceeded.
+// When the deadline is exceeded, the returned error wraps context.DeadlineExceeded.
+func (c *Challenge) SolveBefore(authz acme.Authorization, deadline time.Time) error {
 	domain := challenge.GetTargetedDomain(authz)
 	log.Infof("[%s] acme: Trying to solve DNS-01", domain)
 
@@ -115,33 +275,123 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 		return err
 	}
 
-	info := GetChallengeInfo(authz.Identifier.Value, keyAuth)
+	info := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+
+	timeout, interval := c.getTimeouts(authz.Identifier.Value)
+
+	if isExceeded(c.clock, deadline) {
+		return fmt.Errorf("[%s] acme: %w", domain, context.DeadlineExceeded)
+	}
 
-	var timeout, interval time.Duration
-	switch provider := c.provider.(type) {
-	case challenge.ProviderTimeout:
-		timeout, interval = provider.Timeout()
-	default:
-		timeout, interval = DefaultPropagationTimeout, DefaultPollingInterval
+	if !deadline.IsZero() {
+		interval = min(interval, deadline.Sub(c.clock.Now()))
 	}
 
-	log.Infof("[%s] acme: Checking DNS record propagation. [nameservers=%s]", domain, strings.Join(recursiveNameservers, ","))
+	log.Infof("[%s] acme: Checking DNS record propagation. [nameservers=%s]", domain, strings.Join(c.preCheck.checkedNameservers(), ","))
+
+	c.events.emit(c.clock.Now(), EventPropagationStarted, domain, info.EffectiveFQDN, nil)
+
+	start := c.clock.Now()
 
-	time.Sleep(interval)
+	c.initialSleep(authz.Identifier.Value, interval)
+
+	if !deadline.IsZero() {
+		timeout = min(timeout, max(deadline.Sub(c.clock.Now()), 0))
+	}
 
-	err = wait.For("propagation", timeout, interval, func() (bool, error) {
-		stop, errP := c.preCheck.call(domain, info.EffectiveFQDN, info.Value)
-		if !stop || errP != nil {
+	var successes, attempt int
+	var ttlReduced bool
+
+	err = c.waitForPropagation(timeout, interval, func() (bool, error) {
+		attempt++
+
+		stop, errP := c.checkPropagation(domain, info, start, attempt)
+		if !stop {
+			successes = 0
 			log.Infof("[%s] acme: Waiting for DNS record propagation.", domain)
+
+			if !ttlReduced {
+				ttlReduced = c.reduceTTL(authz.Identifier.Value, chlng.Token, keyAuth, c.clock.Now().Sub(start), timeout)
+			}
+
+			return false, errP
+		}
+
+		successes++
+		if successes < c.preCheck.stableChecks {
+			log.Infof("[%s] acme: DNS record propagation observed (%d/%d).", domain, successes, c.preCheck.stableChecks)
+			return false, nil
 		}
-		return stop, errP
+
+		return true, nil
 	})
+	if err != nil {
+		if isExceeded(c.clock, deadline) {
+			err = fmt.Errorf("[%s] acme: %w: %w", domain, context.DeadlineExceeded, err)
+		}
+
+		c.events.emit(c.clock.Now(), EventPropagationFailed, domain, info.EffectiveFQDN, err)
+		return err
+	}
+
+	c.events.emit(c.clock.Now(), EventPropagationComplete, domain, info.EffectiveFQDN, nil)
+
+	err = c.waitValidationGate(domain, info)
 	if err != nil {
 		return err
 	}
 
+	// the validation of the challenge is not requested after the deadline: the record will be cleaned up.
+	if isExceeded(c.clock, deadline) {
+		return fmt.Errorf("[%s] acme: the validation is not requested: %w", domain, context.DeadlineExceeded)
+	}
+
 	chlng.KeyAuthorization = keyAuth
-	return c.validate(c.core, domain, chlng)
+
+	c.events.emit(c.clock.Now(), EventValidationRequested, domain, info.EffectiveFQDN, nil)
+
+	err = c.validate(c.core, domain, chlng)
+	if err != nil {
+		c.events.emit(c.clock.Now(), EventValidationFailed, domain, info.EffectiveFQDN, err)
+		return err
+	}
+
+	c.events.emit(c.clock.Now(), EventValidationSucceeded, domain, info.EffectiveFQDN, nil)
+
+	return nil
+}
+
+// isExceeded returns true if the deadline is defined and exceeded.
+func isExceeded(clk clock.Clock, deadline time.Time) bool {
+	return !deadline.IsZero() && !clk.Now().Before(deadline)
+}
+
+// checkPropagation checks the propagation of the record, and reports the progress (see WithPropagationProgress).
+func (c *Challenge) checkPropagation(domain string, info ChallengeInfo, start time.Time, attempt int) (bool, error) {
+	if c.propagationProgress == nil {
+		return c.preCheck.call(domain, info.EffectiveFQDN, info.Value)
+	}
+
+	var statuses []NameserverStatus
+
+	preCheck := c.preCheck
+	preCheck.observe = func(status NameserverStatus) {
+		statuses = append(statuses, status)
+	}
+
+	stop, err := preCheck.call(domain, info.EffectiveFQDN, info.Value)
+
+	c.propagationProgress(PropagationProgress{
+		Domain:      domain,
+		FQDN:        info.EffectiveFQDN,
+		Elapsed:     c.clock.Now().Sub(start),
+		Attempt:     attempt,
+		Propagated:  stop,
+		Err:         err,
+		Nameservers: statuses,
+	})
+
+	return stop, err
 }
 
 // CleanUp cleans the challenge.
@@ -158,20 +408,101 @@ func (c *Challenge) CleanUp(authz acme.Authorization) error {
 		return err
 	}
 
-	return c.provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
+	info := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+	defer c.forgetChallengeInfo(authz.Identifier.Value, info.Value)
+
+	if record, provider, ok := c.getDelegatedBatchProvider(authz.Identifier.Value, keyAuth); ok {
+		err = c.withRecords([]Record{record}, func() error { return provider.CleanUpBatch([]Record{record}) })
+	} else if provider := c.getProvider(authz.Identifier.Value); provider != nil {
+		if !supportsCleanUp(provider) {
+			log.Warnf("[%s] acme: the DNS provider doesn't remove the records, the TXT record %s may linger",
+				challenge.GetTargetedDomain(authz), info.EffectiveFQDN)
+		}
+
+		err = c.withRecord(authz.Identifier.Value, info, func() error {
+			return provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.cleanupVerification != nil {
+		err = c.verifyCleanup(challenge.GetTargetedDomain(authz), info)
+		if err != nil {
+			return fmt.Errorf("[%s] acme: %w", challenge.GetTargetedDomain(authz), err)
+		}
+	}
+
+	c.events.emit(c.clock.Now(), EventCleanupDone, challenge.GetTargetedDomain(authz), info.EffectiveFQDN, nil)
+
+	return nil
 }
 
+// supportsCleanUp returns false if the provider declares its CleanUp as a no-op (see challenge.ProviderCapabilities).
+func supportsCleanUp(provider challenge.Provider) bool {
+	capabilities, ok := provider.(challenge.ProviderCapabilities)
+
+	return !ok || capabilities.SupportsCleanUp()
+}
+
+// getTimeouts returns the propagation timeout and polling interval:
+// the ones of the provider of the domain, if defined, otherwise the ones of the propagation profile or the defaults.
+func (c *Challenge) getTimeouts(domain string) (timeout, interval time.Duration) {
+	if provider, ok := c.getProvider(domain).(challenge.ProviderTimeout); ok {
+		return provider.Timeout()
+	}
+
+	if c.propagationProfile != nil {
+		return c.propagationProfile.Timeout, c.propagationProfile.Interval
+	}
+
+	return DefaultPropagationTimeout, DefaultPollingInterval
+}
+
+// Sequential returns true if the default provider, or one of the providers by domain (see WithDNSProviderForDomain),
+// requires the challenges to be solved one by one, with the longest interval of these providers.
 func (c *Challenge) Sequential() (bool, time.Duration) {
-	if p, ok := c.provider.(sequential); ok {
-		return ok, p.Sequential()
+	var isSequential bool
+	var interval time.Duration
+
+	providers := []challenge.Provider{c.provider}
+	for _, provider := range c.domainProviders {
+		providers = append(providers, provider)
 	}
-	return false, 0
+
+	for _, provider := range providers {
+		if p, ok := provider.(sequential); ok {
+			isSequential = true
+			interval = max(interval, p.Sequential())
+		}
+	}
+
+	return isSequential, interval
 }
 
 type sequential interface {
 	Sequential() time.Duration
 }
 
+// AuthzProvider is a provider which needs the whole authorization to create the record
+// (e.g. to distinguish the wildcard authorizations).
+// When a provider implements this interface, PresentAuthz is called instead of Present.
+// The record is still removed through CleanUp.
+type AuthzProvider interface {
+	challenge.Provider
+	PresentAuthz(authz acme.Authorization, info ChallengeInfo) error
+}
+
+// ProviderVerify is a provider able to check, through its own API, that a record has been created.
+// When a provider implements this interface, Verify is called right after the creation of the record,
+// before the propagation check:
+// a record accepted but not created by the provider is detected early (and can be retried, see WithPresentRetry).
+type ProviderVerify interface {
+	challenge.Provider
+	Verify(fqdn, value string) (bool, error)
+}
+
 // GetRecord returns a DNS record which will fulfill the `dns-01` challenge.
 // Deprecated: use GetChallengeInfo instead.
 func GetRecord(domain, keyAuth string) (fqdn, value string) {
@@ -192,22 +523,43 @@ type ChallengeInfo struct {
 	Value string
 }
 
+// FindZone determines the zone apex of the EffectiveFQDN (see FindZoneByFqdn):
+// when the challenge FQDN is a CNAME, the record is created in the zone of the CNAME target.
+func (i ChallengeInfo) FindZone() (string, error) {
+	return FindZoneByFqdn(i.EffectiveFQDN)
+}
+
 // GetChallengeInfo returns information used to create a DNS record which will fulfill the `dns-01` challenge.
+// When called by a DNS provider creating or removing the record of a challenge,
+// the information is the one of the challenge (e.g. the CNAMEs are followed with its resolver, see WithResolver),
+// so the provider creates the record where the challenge checks it.
 func GetChallengeInfo(domain, keyAuth string) ChallengeInfo {
-	keyAuthShaBytes := sha256.Sum256([]byte(keyAuth))
-	// base64URL encoding without padding
-	value := base64.RawURLEncoding.EncodeToString(keyAuthShaBytes[:sha256.Size])
+	if chlg, ok := activeRecords.get(infoKey(domain, getChallengeValue(keyAuth))); ok {
+		return chlg.getChallengeInfo(domain, keyAuth)
+	}
+
+	return getChallengeInfoCustom(domain, keyAuth, recursiveNameservers, AddressFamilyAny, nil)
+}
 
+// getChallengeInfoCustom is like GetChallengeInfo but follows the CNAMEs with the given nameservers of the address family,
+// as long as the predicate accepts them (see WithCNAMEFollowPredicate).
+func getChallengeInfoCustom(domain, keyAuth string, nameservers []string, family AddressFamily, predicate func(from, to string) bool) ChallengeInfo {
 	ok, _ := strconv.ParseBool(os.Getenv("LEGO_DISABLE_CNAME_SUPPORT"))
 
 	return ChallengeInfo{
-		Value:         value,
-		FQDN:          getChallengeFQDN(domain, false),
-		EffectiveFQDN: getChallengeFQDN(domain, !ok),
+		Value:         getChallengeValue(keyAuth),
+		FQDN:          getChallengeFQDN(domain, false, nameservers, family, nil),
+		EffectiveFQDN: getChallengeFQDN(domain, !ok, nameservers, family, predicate),
 	}
 }
 
-func getChallengeFQDN(domain string, followCNAME bool) string {
+func getChallengeValue(keyAuth string) string {
+	keyAuthShaBytes := sha256.Sum256([]byte(keyAuth))
+	// base64URL encoding without padding
+	return base64.RawURLEncoding.EncodeToString(keyAuthShaBytes[:sha256.Size])
+}
+
+func getChallengeFQDN(domain string, followCNAME bool, nameservers []string, family AddressFamily, predicate func(from, to string) bool) string {
 	fqdn := fmt.Sprintf("_acme-challenge.%s.", domain)
 
 	if !followCNAME {
@@ -217,7 +569,7 @@ func getChallengeFQDN(domain string, followCNAME bool) string {
 	// recursion counter so it doesn't spin out of control
 	for range 50 {
 		// Keep following CNAMEs
-		r, err := dnsQuery(fqdn, dns.TypeCNAME, recursiveNameservers, true)
+		r, err := dnsQueryFamily(fqdn, dns.TypeCNAME, nameservers, true, family)
 
 		if err != nil || r.Rcode != dns.RcodeSuccess {
 			// No more CNAME records to follow, exit
@@ -226,7 +578,7 @@ func getChallengeFQDN(domain string, followCNAME bool) string {
 
 		// Check if the domain has CNAME then use that
 		cname := updateDomainWithCName(r, fqdn)
-		if cname == fqdn {
+		if cname == fqdn || !acceptCNAME(predicate, fqdn, cname) {
 			break
 		}
 
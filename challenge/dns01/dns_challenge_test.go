@@ -1,6 +1,7 @@
 package dns01
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"errors"
@@ -11,7 +12,9 @@ import (
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/clock"
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -281,3 +284,317 @@ func TestChallenge_CleanUp(t *testing.T) {
 		})
 	}
 }
+
+func TestWithValidateFunc(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	defaultValidate := func(_ *api.Core, _ string, _ acme.Challenge) error { return errors.New("default") }
+
+	var receivedCore *api.Core
+
+	chlg := NewChallenge(core, defaultValidate, &providerMock{},
+		WithValidateFunc(func(core *api.Core, _ string, _ acme.Challenge) error {
+			receivedCore = core
+			return nil
+		}))
+
+	require.NoError(t, chlg.validate(core, "example.com", acme.Challenge{}))
+	assert.Same(t, core, receivedCore)
+}
+
+func TestWrapValidateFunc(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	var calls int
+
+	defaultValidate := func(_ *api.Core, _ string, _ acme.Challenge) error {
+		calls++
+
+		if calls < 3 {
+			return &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:serverInternal"}
+		}
+
+		return nil
+	}
+
+	// retries the validation on serverInternal errors.
+	retry := func(next ValidateFunc) ValidateFunc {
+		return func(core *api.Core, domain string, chlng acme.Challenge) error {
+			for {
+				err := next(core, domain, chlng)
+
+				var problem *acme.ProblemDetails
+				if !errors.As(err, &problem) || problem.Type != "urn:ietf:params:acme:error:serverInternal" {
+					return err
+				}
+			}
+		}
+	}
+
+	chlg := NewChallenge(core, defaultValidate, &providerMock{}, WrapValidateFunc(retry))
+
+	require.NoError(t, chlg.validate(core, "example.com", acme.Challenge{}))
+	assert.Equal(t, 3, calls)
+}
+
+type authzProviderMock struct {
+	providerMock
+
+	authz []acme.Authorization
+	infos []ChallengeInfo
+}
+
+func (p *authzProviderMock) Present(_, _, _ string) error {
+	return errors.New("present must not be called")
+}
+
+func (p *authzProviderMock) PresentAuthz(authz acme.Authorization, info ChallengeInfo) error {
+	p.authz = append(p.authz, authz)
+	p.infos = append(p.infos, info)
+
+	return nil
+}
+
+func TestChallenge_PreSolve_authzProvider(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &authzProviderMock{}
+
+	chlg := NewChallenge(core, nil, provider)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Wildcard:   true,
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+
+	keyAuth, err := core.GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	assert.Equal(t, []acme.Authorization{authz}, provider.authz)
+	assert.Equal(t, []ChallengeInfo{GetChallengeInfo("example.com", keyAuth)}, provider.infos)
+}
+
+// verifyProviderMock accepts the records but creates them only after the given number of attempts.
+type verifyProviderMock struct {
+	providerMock
+
+	createdAfter int
+	presents     int
+
+	verified []string
+}
+
+func (p *verifyProviderMock) Present(_, _, _ string) error {
+	p.presents++
+	return nil
+}
+
+func (p *verifyProviderMock) Verify(fqdn, _ string) (bool, error) {
+	p.verified = append(p.verified, fqdn)
+
+	return p.createdAfter > 0 && p.presents >= p.createdAfter, nil
+}
+
+func TestChallenge_PreSolve_providerVerify(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	testCases := []struct {
+		desc             string
+		createdAfter     int
+		opts             []ChallengeOption
+		expectedPresents int
+		expectedErr      string
+	}{
+		{
+			desc:             "created",
+			createdAfter:     1,
+			expectedPresents: 1,
+		},
+		{
+			desc:             "silently dropped",
+			expectedPresents: 1,
+			expectedErr:      "[example.com] acme: error presenting token: verify: the DNS provider doesn't report the record as created [fqdn: _acme-challenge.example.com.]",
+		},
+		{
+			desc:             "created on retry",
+			createdAfter:     2,
+			opts:             []ChallengeOption{WithPresentRetry(3, 0)},
+			expectedPresents: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider := &verifyProviderMock{createdAfter: test.createdAfter}
+
+			chlg := NewChallenge(core, nil, provider, test.opts...)
+
+			err := chlg.PreSolve(authz)
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedPresents, provider.presents)
+			assert.Contains(t, provider.verified, "_acme-challenge.example.com.")
+		})
+	}
+}
+
+func TestChallenge_SolveBefore(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	testCases := []struct {
+		desc          string
+		propagation   time.Duration
+		deadline      time.Time
+		expectedError string
+		validated     bool
+		expectedNow   time.Time
+	}{
+		{
+			desc:        "propagated before the deadline",
+			propagation: 5 * time.Minute,
+			deadline:    start.Add(10 * time.Minute),
+			validated:   true,
+			expectedNow: start.Add(5 * time.Minute),
+		},
+		{
+			desc:          "propagation wait bounded by the deadline",
+			propagation:   30 * time.Minute,
+			deadline:      start.Add(10 * time.Minute),
+			expectedError: "[example.com] acme: context deadline exceeded: propagation: time limit exceeded",
+			expectedNow:   start.Add(10 * time.Minute),
+		},
+		{
+			desc:          "deadline already exceeded",
+			deadline:      start,
+			expectedError: "[example.com] acme: context deadline exceeded",
+			expectedNow:   start,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			fakeClock := clock.NewFake(start)
+
+			var validated bool
+
+			chlg := NewChallenge(core,
+				func(_ *api.Core, _ string, _ acme.Challenge) error {
+					validated = true
+					return nil
+				},
+				&providerTimeoutMock{timeout: time.Hour, interval: time.Minute},
+				WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+					return !fakeClock.Now().Before(start.Add(test.propagation)), nil
+				}),
+				WithClock(fakeClock),
+			)
+
+			err := chlg.SolveBefore(authz, test.deadline)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			}
+
+			assert.Equal(t, test.validated, validated)
+			assert.Equal(t, test.expectedNow, fakeClock.Now())
+		})
+	}
+}
+
+func TestChallenge_SolveBefore_validationSkipped(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFake(start)
+
+	var validated bool
+
+	// the record is propagated, but the validation gate holds the challenge until after the deadline.
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error {
+			validated = true
+			return nil
+		},
+		&providerTimeoutMock{timeout: time.Hour, interval: time.Minute},
+		WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) { return true, nil }),
+		WithValidationGate(func(_, _, _ string) error {
+			fakeClock.Advance(time.Hour)
+			return nil
+		}),
+		WithClock(fakeClock),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.SolveBefore(authz, start.Add(10*time.Minute))
+	require.EqualError(t, err, "[example.com] acme: the validation is not requested: context deadline exceeded")
+
+	assert.False(t, validated)
+}
@@ -0,0 +1,101 @@
+package dns01
+
+import (
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// startDNSServer starts a local DNS server (UDP) and returns its address.
+func startDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	return startDNSServerOn(t, "127.0.0.1:0", handler)
+}
+
+// startDNSServerOn starts a local DNS server (UDP) on the given address and returns its address.
+func startDNSServerOn(t *testing.T, addr string, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", addr)
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+
+	server := &dns.Server{
+		PacketConn:        pc,
+		Handler:           handler,
+		NotifyStartedFunc: func() { close(started) },
+	}
+
+	go func() { _ = server.ActivateAndServe() }()
+
+	<-started
+
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
+
+// txtHandler answers to the TXT queries with the given records (FQDN -> values).
+func txtHandler(records map[string][]string) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		for _, q := range req.Question {
+			if q.Qtype != dns.TypeTXT {
+				continue
+			}
+
+			for _, value := range records[q.Name] {
+				m.Answer = append(m.Answer, &dns.TXT{
+					Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+					Txt: []string{value},
+				})
+			}
+		}
+
+		_ = w.WriteMsg(m)
+	}
+}
+
+// setRecursiveNameservers overrides the recursive nameservers for the duration of the test.
+func setRecursiveNameservers(t *testing.T, nameservers ...string) {
+	t.Helper()
+
+	original := recursiveNameservers
+	t.Cleanup(func() { recursiveNameservers = original })
+
+	recursiveNameservers = nameservers
+}
+
+// soaHandler answers to the SOA queries of the given zones.
+func soaHandler(zones ...string) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		for _, q := range req.Question {
+			if q.Qtype != dns.TypeSOA || !slices.Contains(zones, q.Name) {
+				continue
+			}
+
+			m.Answer = append(m.Answer, &dns.SOA{
+				Hdr:     dns.RR_Header{Name: q.Name, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 60},
+				Ns:      "ns1." + q.Name,
+				Mbox:    "admin." + q.Name,
+				Refresh: 60,
+			})
+		}
+
+		if len(m.Answer) == 0 {
+			m.Rcode = dns.RcodeNameError
+		}
+
+		_ = w.WriteMsg(m)
+	}
+}
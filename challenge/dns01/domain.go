@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/miekg/dns"
+	"golang.org/x/net/publicsuffix"
 )
 
 // ExtractSubDomain extracts the subdomain part from a domain and a zone.
@@ -22,3 +23,24 @@ func ExtractSubDomain(domain, zone string) (string, error) {
 
 	return strings.TrimSuffix(canonDomain, "."+canonZone), nil
 }
+
+// RegisteredDomain returns the registered domain (the public suffix plus one label) of a domain.
+// For example, the registered domain of `_acme-challenge.www.example.co.uk.` is `example.co.uk.`.
+func RegisteredDomain(fqdn string) (string, error) {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(UnFqdn(fqdn)))
+	if err != nil {
+		return "", fmt.Errorf("could not determine the registered domain of %s: %w", fqdn, err)
+	}
+
+	return ToFqdn(domain), nil
+}
+
+// isPublicSuffix returns true if the domain is a public suffix managed by ICANN (e.g. `com.`, `co.uk.`).
+// The private suffixes (e.g. `github.io.`) are ignored because they can be zones.
+func isPublicSuffix(fqdn string) bool {
+	domain := strings.ToLower(UnFqdn(fqdn))
+
+	suffix, icann := publicsuffix.PublicSuffix(domain)
+
+	return icann && suffix == domain
+}
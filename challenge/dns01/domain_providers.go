@@ -0,0 +1,63 @@
+package dns01
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// WithDNSProviderForDomain defines the DNS providers of some domains (or zones),
+// in order to solve, in a single order, the challenges of domains hosted by different DNS providers.
+//
+// A domain matches a key if it's the key itself or a subdomain of the key (ex: `www.example.com` matches `example.com`),
+// the longest matching key is used.
+// The unmapped domains use the default provider of the challenge.
+//
+// The challenges of an order with mapped domains are never created in batch (see BatchProvider).
+func WithDNSProviderForDomain(providers map[string]challenge.Provider) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if len(providers) == 0 {
+			return errors.New("no domain providers")
+		}
+
+		chlg.domainProviders = make(map[string]challenge.Provider, len(providers))
+
+		for domain, provider := range providers {
+			if provider == nil {
+				return errors.New("domain providers: nil provider for " + domain)
+			}
+
+			chlg.domainProviders[normalizeProviderDomain(domain)] = provider
+		}
+
+		return nil
+	}
+}
+
+// getProvider returns the provider of the domain: the provider of the longest matching domain (see WithDNSProviderForDomain),
+// or the default provider.
+func (c *Challenge) getProvider(domain string) challenge.Provider {
+	if len(c.domainProviders) == 0 {
+		return c.provider
+	}
+
+	domain = normalizeProviderDomain(domain)
+
+	for {
+		if provider, ok := c.domainProviders[domain]; ok {
+			return provider
+		}
+
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			return c.provider
+		}
+
+		domain = parent
+	}
+}
+
+func normalizeProviderDomain(domain string) string {
+	return strings.ToLower(strings.TrimPrefix(UnFqdn(domain), "*."))
+}
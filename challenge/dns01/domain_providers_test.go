@@ -0,0 +1,121 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingProvider struct {
+	presented []string
+	cleaned   []string
+}
+
+func (p *recordingProvider) Present(domain, _, _ string) error {
+	p.presented = append(p.presented, domain)
+	return nil
+}
+
+func (p *recordingProvider) CleanUp(domain, _, _ string) error {
+	p.cleaned = append(p.cleaned, domain)
+	return nil
+}
+
+func TestWithDNSProviderForDomain(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	route53 := &recordingProvider{}
+	cloudflare := &recordingProvider{}
+	fallback := &recordingProvider{}
+
+	chlg := NewChallenge(core, nil, fallback,
+		WithDNSProviderForDomain(map[string]challenge.Provider{
+			"example.com":     route53,
+			"example.org":     cloudflare,
+			"sub.example.org": route53,
+		}),
+	)
+
+	assert.False(t, chlg.Batch())
+
+	for _, domain := range []string{"www.example.com", "example.org", "a.sub.example.org", "example.net"} {
+		authz := acme.Authorization{
+			Identifier: acme.Identifier{Type: "dns", Value: domain},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+		}
+
+		require.NoError(t, chlg.PreSolve(authz))
+		require.NoError(t, chlg.CleanUp(authz))
+	}
+
+	assert.Equal(t, []string{"www.example.com", "a.sub.example.org"}, route53.presented)
+	assert.Equal(t, []string{"www.example.com", "a.sub.example.org"}, route53.cleaned)
+	assert.Equal(t, []string{"example.org"}, cloudflare.presented)
+	assert.Equal(t, []string{"example.org"}, cloudflare.cleaned)
+	assert.Equal(t, []string{"example.net"}, fallback.presented)
+	assert.Equal(t, []string{"example.net"}, fallback.cleaned)
+}
+
+func TestWithDNSProviderForDomain_sequential(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		provider         challenge.Provider
+		domainProviders  map[string]challenge.Provider
+		expected         bool
+		expectedInterval time.Duration
+	}{
+		{
+			desc:     "no sequential provider",
+			provider: &providerMock{},
+			domainProviders: map[string]challenge.Provider{
+				"example.com": &providerMock{},
+			},
+		},
+		{
+			desc:     "sequential domain provider",
+			provider: &providerMock{},
+			domainProviders: map[string]challenge.Provider{
+				"example.com": &sequentialProviderMock{interval: 3 * time.Second},
+				"example.org": &providerMock{},
+			},
+			expected:         true,
+			expectedInterval: 3 * time.Second,
+		},
+		{
+			desc:     "longest interval",
+			provider: &sequentialProviderMock{interval: 5 * time.Second},
+			domainProviders: map[string]challenge.Provider{
+				"example.com": &sequentialProviderMock{interval: 3 * time.Second},
+				"example.org": &sequentialProviderMock{interval: 10 * time.Second},
+			},
+			expected:         true,
+			expectedInterval: 10 * time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			chlg := NewChallenge(nil, nil, test.provider, WithDNSProviderForDomain(test.domainProviders))
+
+			isSequential, interval := chlg.Sequential()
+
+			assert.Equal(t, test.expected, isSequential)
+			assert.Equal(t, test.expectedInterval, interval)
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package dns01
 
 import (
+	"slices"
+	"strconv"
+	"sync/atomic"
 	"testing"
 
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -100,3 +104,227 @@ func TestExtractSubDomain_errors(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisteredDomain(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		fqdn     string
+		expected string
+	}{
+		{
+			desc:     "subdomain",
+			fqdn:     "_acme-challenge.www.example.com.",
+			expected: "example.com.",
+		},
+		{
+			desc:     "registered domain itself",
+			fqdn:     "example.com.",
+			expected: "example.com.",
+		},
+		{
+			desc:     "multi-level public suffix",
+			fqdn:     "_acme-challenge.www.example.co.uk.",
+			expected: "example.co.uk.",
+		},
+		{
+			desc:     "multi-level public suffix, registered domain itself",
+			fqdn:     "example.co.uk",
+			expected: "example.co.uk.",
+		},
+		{
+			desc:     "uppercase",
+			fqdn:     "WWW.Example.CO.UK.",
+			expected: "example.co.uk.",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			domain, err := RegisteredDomain(test.fqdn)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, domain)
+		})
+	}
+}
+
+func TestRegisteredDomain_publicSuffix(t *testing.T) {
+	_, err := RegisteredDomain("co.uk.")
+	require.Error(t, err)
+}
+
+func TestFindZoneByFqdnCustom_publicSuffix(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		fqdn          string
+		zones         []string
+		expected      string
+		expectedError string
+	}{
+		{
+			desc:     "zone is the registered domain",
+			fqdn:     "_acme-challenge.www.example.co.uk.",
+			zones:    []string{"example.co.uk.", "co.uk."},
+			expected: "example.co.uk.",
+		},
+		{
+			desc:     "zone is a subdomain of the registered domain",
+			fqdn:     "_acme-challenge.www.example.co.uk.",
+			zones:    []string{"www.example.co.uk.", "example.co.uk."},
+			expected: "www.example.co.uk.",
+		},
+		{
+			desc:          "only the public suffix has a SOA",
+			fqdn:          "_acme-challenge.example.co.uk.",
+			zones:         []string{"co.uk.", "uk."},
+			expectedError: "could not find the start of authority for '_acme-challenge.example.co.uk.'",
+		},
+		{
+			desc:     "private suffix",
+			fqdn:     "_acme-challenge.example.github.io.",
+			zones:    []string{"github.io."},
+			expected: "github.io.",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			ClearFqdnCache()
+			t.Cleanup(ClearFqdnCache)
+
+			addr := startDNSServer(t, soaHandler(test.zones...))
+
+			zone, err := FindZoneByFqdnCustom(test.fqdn, []string{addr})
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, zone)
+		})
+	}
+}
+
+// nestedZonesHandler answers like a recursive resolver for the zones example.com. and sub.example.com. (delegated),
+// and other.net. (target of the CNAME at _acme-challenge.example.com.).
+func nestedZonesHandler(queries *atomic.Int32) dns.HandlerFunc {
+	zones := []string{"example.com.", "sub.example.com.", "other.net."}
+	cnames := map[string]string{"_acme-challenge.example.com.": "_acme-challenge.other.net."}
+
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		queries.Add(1)
+
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		for _, q := range req.Question {
+			if target, ok := cnames[q.Name]; ok {
+				m.Answer = append(m.Answer, &dns.CNAME{
+					Hdr:    dns.RR_Header{Name: q.Name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: 60},
+					Target: target,
+				})
+
+				continue
+			}
+
+			if q.Qtype != dns.TypeSOA || !slices.Contains(zones, q.Name) {
+				continue
+			}
+
+			m.Answer = append(m.Answer, &dns.SOA{
+				Hdr:     dns.RR_Header{Name: q.Name, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 60},
+				Ns:      "ns1." + q.Name,
+				Mbox:    "admin." + q.Name,
+				Refresh: 60,
+			})
+		}
+
+		_ = w.WriteMsg(m)
+	}
+}
+
+func TestFindZoneByFqdn_nestedZones(t *testing.T) {
+	ClearFqdnCache()
+	t.Cleanup(ClearFqdnCache)
+
+	var queries atomic.Int32
+
+	setRecursiveNameservers(t, startDNSServer(t, nestedZonesHandler(&queries)))
+
+	testCases := []struct {
+		fqdn     string
+		expected string
+	}{
+		{fqdn: "example.com.", expected: "example.com."},
+		{fqdn: "_acme-challenge.www.example.com.", expected: "example.com."},
+		{fqdn: "_acme-challenge.sub.example.com.", expected: "sub.example.com."},
+		{fqdn: "_acme-challenge.a.b.sub.example.com.", expected: "sub.example.com."},
+		// the CNAME is skipped: the zone of the CNAME itself.
+		{fqdn: "_acme-challenge.example.com.", expected: "example.com."},
+	}
+
+	for _, test := range testCases {
+		zone, err := FindZoneByFqdn(test.fqdn)
+		require.NoError(t, err)
+
+		assert.Equal(t, test.expected, zone, test.fqdn)
+	}
+
+	// cached.
+	count := queries.Load()
+
+	for _, test := range testCases {
+		_, err := FindZoneByFqdn(test.fqdn)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, count, queries.Load())
+}
+
+func TestChallengeInfo_FindZone(t *testing.T) {
+	ClearFqdnCache()
+	t.Cleanup(ClearFqdnCache)
+
+	var queries atomic.Int32
+
+	setRecursiveNameservers(t, startDNSServer(t, nestedZonesHandler(&queries)))
+
+	testCases := []struct {
+		desc         string
+		domain       string
+		disableCNAME bool
+		expected     string
+	}{
+		{
+			desc:     "CNAME at the challenge FQDN",
+			domain:   "example.com",
+			expected: "other.net.",
+		},
+		{
+			desc:         "CNAME support disabled",
+			domain:       "example.com",
+			disableCNAME: true,
+			expected:     "example.com.",
+		},
+		{
+			desc:     "nested zone",
+			domain:   "www.sub.example.com",
+			expected: "sub.example.com.",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", strconv.FormatBool(test.disableCNAME))
+
+			zone, err := GetChallengeInfo(test.domain, "keyAuth").FindZone()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, zone)
+		})
+	}
+}
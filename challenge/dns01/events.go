@@ -0,0 +1,73 @@
+package dns01
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// Types of the events of the solve lifecycle.
+const (
+	EventChallengePresented  = "challenge_presented"
+	EventPropagationStarted  = "propagation_started"
+	EventPropagationComplete = "propagation_complete"
+	EventPropagationFailed   = "propagation_failed"
+	EventValidationRequested = "validation_requested"
+	EventValidationSucceeded = "validation_succeeded"
+	EventValidationFailed    = "validation_failed"
+	EventCleanupDone         = "cleanup_done"
+)
+
+// Event is an event of the solve lifecycle.
+type Event struct {
+	Time          time.Time `json:"time"`
+	Type          string    `json:"type"`
+	Domain        string    `json:"domain"`
+	ChallengeType string    `json:"challengeType"`
+	FQDN          string    `json:"fqdn"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// WithEventWriter writes the events of the solve lifecycle to w, as JSON lines.
+// The writes are synchronized.
+func WithEventWriter(w io.Writer) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.events = &eventWriter{enc: json.NewEncoder(w)}
+		return nil
+	}
+}
+
+type eventWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// emit writes an event occurred at the given time (no-op if the writer is not defined).
+func (e *eventWriter) emit(at time.Time, eventType, domain, fqdn string, err error) {
+	if e == nil {
+		return
+	}
+
+	event := Event{
+		Time:          at.UTC(),
+		Type:          eventType,
+		Domain:        domain,
+		ChallengeType: challenge.DNS01.String(),
+		FQDN:          fqdn,
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if errW := e.enc.Encode(event); errW != nil {
+		log.Warnf("[%s] acme: unable to write the event %s: %v", domain, eventType, errW)
+	}
+}
@@ -0,0 +1,137 @@
+package dns01
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/clock"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEventWriter(t *testing.T) {
+	setRecursiveNameservers(t, startDNSServer(t, txtHandler(nil)))
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		validate ValidateFunc
+		expected []string
+	}{
+		{
+			desc:     "success",
+			validate: func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+			expected: []string{
+				EventChallengePresented,
+				EventPropagationStarted,
+				EventPropagationComplete,
+				EventValidationRequested,
+				EventValidationSucceeded,
+				EventCleanupDone,
+			},
+		},
+		{
+			desc:     "validation failed",
+			validate: func(_ *api.Core, _ string, _ acme.Challenge) error { return errors.New("OOPS") },
+			expected: []string{
+				EventChallengePresented,
+				EventPropagationStarted,
+				EventPropagationComplete,
+				EventValidationRequested,
+				EventValidationFailed,
+				EventCleanupDone,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+
+			provider := &providerTimeoutMock{timeout: time.Second, interval: time.Millisecond}
+
+			chlg := NewChallenge(core, test.validate, provider,
+				WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) { return true, nil }),
+				WithEventWriter(buf))
+
+			authz := acme.Authorization{
+				Identifier: acme.Identifier{Value: "example.com"},
+				Challenges: []acme.Challenge{{Type: challenge.DNS01.String()}},
+			}
+
+			require.NoError(t, chlg.PreSolve(authz))
+			_ = chlg.Solve(authz)
+			require.NoError(t, chlg.CleanUp(authz))
+
+			var types []string
+
+			scanner := bufio.NewScanner(buf)
+			for scanner.Scan() {
+				var event Event
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+
+				assert.Equal(t, "example.com", event.Domain)
+				assert.Equal(t, "dns-01", event.ChallengeType)
+				assert.Equal(t, "_acme-challenge.example.com.", event.FQDN)
+				assert.False(t, event.Time.IsZero())
+
+				if event.Type == EventValidationFailed {
+					assert.Equal(t, "OOPS", event.Error)
+				}
+
+				types = append(types, event.Type)
+			}
+
+			assert.Equal(t, test.expected, types)
+		})
+	}
+}
+
+func TestWithEventWriter_clock(t *testing.T) {
+	setRecursiveNameservers(t, startDNSServer(t, txtHandler(nil)))
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	chlg := NewChallenge(core, nil, &providerMock{}, WithClock(clock.NewFake(start)), WithEventWriter(buf))
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String()}},
+	}
+
+	require.NoError(t, chlg.PreSolve(authz))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &event))
+
+	assert.Equal(t, EventChallengePresented, event.Type)
+	assert.Equal(t, start, event.Time)
+}
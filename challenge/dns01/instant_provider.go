@@ -0,0 +1,25 @@
+package dns01
+
+import (
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// InstantProvider is a provider whose records are visible as soon as Present returns
+// (e.g. an in-memory or an etcd-backed DNS server).
+// When the provider of a domain implements this interface, and Instant returns true,
+// the propagation is checked right away, without the initial sleep of one polling interval.
+type InstantProvider interface {
+	challenge.Provider
+	Instant() bool
+}
+
+// initialSleep waits one polling interval before the first propagation check, unless the provider of the domain is instant.
+func (c *Challenge) initialSleep(domain string, interval time.Duration) {
+	if provider, ok := c.getProvider(domain).(InstantProvider); ok && provider.Instant() {
+		return
+	}
+
+	c.clock.Sleep(interval)
+}
@@ -0,0 +1,86 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/clock"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type instantProviderMock struct {
+	providerTimeoutMock
+	instant bool
+}
+
+func (p *instantProviderMock) Instant() bool { return p.instant }
+
+func TestChallenge_Solve_instantProvider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		provider challenge.Provider
+		expected time.Duration
+	}{
+		{
+			desc:     "instant provider",
+			provider: &instantProviderMock{providerTimeoutMock: providerTimeoutMock{timeout: time.Minute, interval: 10 * time.Second}, instant: true},
+		},
+		{
+			desc:     "not instant",
+			provider: &instantProviderMock{providerTimeoutMock: providerTimeoutMock{timeout: time.Minute, interval: 10 * time.Second}},
+			expected: 10 * time.Second,
+		},
+		{
+			desc:     "other provider",
+			provider: &providerTimeoutMock{timeout: time.Minute, interval: 10 * time.Second},
+			expected: 10 * time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var checks int
+
+			start := time.Now()
+			fakeClock := clock.NewFake(start)
+
+			chlg := NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+				test.provider,
+				WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+					checks++
+					return true, nil
+				}),
+				WithClock(fakeClock),
+			)
+
+			authz := acme.Authorization{
+				Identifier: acme.Identifier{Value: "example.com"},
+				Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+			}
+
+			err := chlg.Solve(authz)
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, checks)
+			assert.Equal(t, test.expected, fakeClock.Now().Sub(start))
+		})
+	}
+}
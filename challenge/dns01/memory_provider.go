@@ -0,0 +1,151 @@
+package dns01
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// MemoryProvider is a DNS provider keeping the TXT records in memory,
+// and serving them with an embedded authoritative DNS server (UDP) run by lego itself.
+//
+// FOR TESTS AND DEMOS ONLY (e.g. against Pebble started with `-dnsserver <address of the provider>`):
+// the CA must be able to query the embedded server, which is never the case of a public CA.
+//
+// The propagation check queries the embedded server (see NameserversProvider), without configuration of the challenge.
+type MemoryProvider struct {
+	server *dns.Server
+	addr   string
+
+	mu      sync.RWMutex
+	records map[string][]string
+}
+
+// NewMemoryProvider creates a MemoryProvider listening on a random port of the loopback interface (see Addr).
+// FOR TESTS AND DEMOS ONLY.
+func NewMemoryProvider() (*MemoryProvider, error) {
+	return NewMemoryProviderOn("127.0.0.1:0")
+}
+
+// NewMemoryProviderOn creates a MemoryProvider listening on the address (host:port).
+// FOR TESTS AND DEMOS ONLY.
+func NewMemoryProviderOn(addr string) (*MemoryProvider, error) {
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("memory: %w", err)
+	}
+
+	p := &MemoryProvider{
+		addr:    pc.LocalAddr().String(),
+		records: make(map[string][]string),
+	}
+
+	started := make(chan struct{})
+
+	p.server = &dns.Server{
+		PacketConn:        pc,
+		Handler:           dns.HandlerFunc(p.serveDNS),
+		NotifyStartedFunc: func() { close(started) },
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- p.server.ActivateAndServe() }()
+
+	select {
+	case <-started:
+		return p, nil
+	case err = <-errCh:
+		return nil, fmt.Errorf("memory: %w", err)
+	}
+}
+
+// Addr returns the address (host:port) of the embedded DNS server.
+func (p *MemoryProvider) Addr() string {
+	return p.addr
+}
+
+// Nameservers returns the embedded DNS server, queried to check the propagation.
+func (p *MemoryProvider) Nameservers() []string {
+	return []string{p.addr}
+}
+
+// Close stops the embedded DNS server.
+func (p *MemoryProvider) Close() error {
+	return p.server.Shutdown()
+}
+
+// Present adds the TXT record to the embedded DNS server.
+func (p *MemoryProvider) Present(domain, _, keyAuth string) error {
+	fqdn, value := memoryRecord(domain, keyAuth)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !slices.Contains(p.records[fqdn], value) {
+		p.records[fqdn] = append(p.records[fqdn], value)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record from the embedded DNS server.
+func (p *MemoryProvider) CleanUp(domain, _, keyAuth string) error {
+	fqdn, value := memoryRecord(domain, keyAuth)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.records[fqdn] = slices.DeleteFunc(p.records[fqdn], func(v string) bool { return v == value })
+
+	if len(p.records[fqdn]) == 0 {
+		delete(p.records, fqdn)
+	}
+
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *MemoryProvider) Timeout() (timeout, interval time.Duration) {
+	return 10 * time.Second, 100 * time.Millisecond
+}
+
+// Instant returns true: the records are served as soon as they are created.
+func (p *MemoryProvider) Instant() bool {
+	return true
+}
+
+func (p *MemoryProvider) serveDNS(w dns.ResponseWriter, req *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(req)
+	m.Authoritative = true
+
+	p.mu.RLock()
+
+	for _, q := range req.Question {
+		if q.Qtype != dns.TypeTXT {
+			continue
+		}
+
+		for _, value := range p.records[strings.ToLower(q.Name)] {
+			m.Answer = append(m.Answer, &dns.TXT{
+				Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 0},
+				Txt: []string{value},
+			})
+		}
+	}
+
+	p.mu.RUnlock()
+
+	_ = w.WriteMsg(m)
+}
+
+// memoryRecord returns the FQDN (without following the CNAMEs) and the value of the TXT record.
+func memoryRecord(domain, keyAuth string) (fqdn, value string) {
+	return strings.ToLower(getChallengeFQDN(domain, false, nil, AddressFamilyAny, nil)), getChallengeValue(keyAuth)
+}
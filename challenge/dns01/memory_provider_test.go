@@ -0,0 +1,113 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queryTXT(t *testing.T, addr, fqdn string) []string {
+	t.Helper()
+
+	r, err := dnsQuery(fqdn, dns.TypeTXT, []string{addr}, false)
+	require.NoError(t, err)
+
+	var values []string
+	for _, rr := range r.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			values = append(values, txt.Txt...)
+		}
+	}
+
+	return values
+}
+
+func TestMemoryProvider(t *testing.T) {
+	provider, err := NewMemoryProvider()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	err = provider.Present("Example.com", "token", "keyAuthA")
+	require.NoError(t, err)
+
+	err = provider.Present("example.com", "token", "keyAuthB")
+	require.NoError(t, err)
+
+	// idempotent.
+	err = provider.Present("example.com", "token", "keyAuthB")
+	require.NoError(t, err)
+
+	values := queryTXT(t, provider.Addr(), "_acme-challenge.example.com.")
+	assert.ElementsMatch(t, []string{getChallengeValue("keyAuthA"), getChallengeValue("keyAuthB")}, values)
+
+	err = provider.CleanUp("example.com", "token", "keyAuthA")
+	require.NoError(t, err)
+
+	values = queryTXT(t, provider.Addr(), "_acme-challenge.example.com.")
+	assert.Equal(t, []string{getChallengeValue("keyAuthB")}, values)
+
+	err = provider.CleanUp("example.com", "token", "keyAuthB")
+	require.NoError(t, err)
+
+	assert.Empty(t, queryTXT(t, provider.Addr(), "_acme-challenge.example.com."))
+}
+
+func TestMemoryProvider_solve(t *testing.T) {
+	provider, err := NewMemoryProvider()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	// the validation by the CA queries the embedded DNS server.
+	validate := func(_ *api.Core, domain string, chlng acme.Challenge) error {
+		keyAuth, errK := core.GetKeyAuthorization(chlng.Token)
+		if errK != nil {
+			return errK
+		}
+
+		for _, value := range queryTXT(t, provider.Addr(), "_acme-challenge."+domain+".") {
+			if value == getChallengeValue(keyAuth) {
+				return nil
+			}
+		}
+
+		return errors.New("TXT record not found")
+	}
+
+	chlg := NewChallenge(core, validate, provider)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	err = chlg.CleanUp(authz)
+	require.NoError(t, err)
+
+	assert.Empty(t, queryTXT(t, provider.Addr(), "_acme-challenge.example.com."))
+}
@@ -21,6 +21,9 @@ var fqdnSoaCache = &sync.Map{}
 var defaultNameservers = []string{
 	"google-public-dns-a.google.com:53",
 	"google-public-dns-b.google.com:53",
+	// IPv6 addresses of the same servers, for the IPv6-only networks.
+	"[2001:4860:4860::8888]:53",
+	"[2001:4860:4860::8844]:53",
 }
 
 // recursiveNameservers are used to pre-check DNS propagation.
@@ -94,14 +97,20 @@ func ParseNameservers(servers []string) []string {
 
 // lookupNameservers returns the authoritative nameservers for the given fqdn.
 func lookupNameservers(fqdn string) ([]string, error) {
+	return lookupNameserversCustom(fqdn, recursiveNameservers, AddressFamilyAny)
+}
+
+// lookupNameserversCustom returns the authoritative nameservers for the given fqdn,
+// using the given recursive nameservers of the address family.
+func lookupNameserversCustom(fqdn string, nameservers []string, family AddressFamily) ([]string, error) {
 	var authoritativeNss []string
 
-	zone, err := FindZoneByFqdn(fqdn)
+	zone, err := FindZoneByFqdnCustom(fqdn, filterNameservers(nameservers, family))
 	if err != nil {
 		return nil, fmt.Errorf("could not find zone: %w", err)
 	}
 
-	r, err := dnsQuery(zone, dns.TypeNS, recursiveNameservers, true)
+	r, err := dnsQueryFamily(zone, dns.TypeNS, nameservers, true, family)
 	if err != nil {
 		return nil, fmt.Errorf("NS call failed: %w", err)
 	}
@@ -137,13 +146,23 @@ func FindPrimaryNsByFqdnCustom(fqdn string, nameservers []string) (string, error
 
 // FindZoneByFqdn determines the zone apex for the given fqdn
 // by recursing up the domain labels until the nameserver returns a SOA record in the answer section.
+// The search stops at the public suffix boundary, and the results are cached (until the SOA refresh delay).
+//
+// The fqdn itself is not resolved (a CNAME is skipped, as it cannot exist at a zone apex):
+// the DNS providers must use the EffectiveFQDN of the challenge (see ChallengeInfo.FindZone)
+// to get the zone of the CNAME target.
 func FindZoneByFqdn(fqdn string) (string, error) {
 	return FindZoneByFqdnCustom(fqdn, recursiveNameservers)
 }
 
 // FindZoneByFqdnCustom determines the zone apex for the given fqdn
 // by recursing up the domain labels until the nameserver returns a SOA record in the answer section.
+// The zone defined by WithZoneForDomain, for the challenge creating or removing the record of the FQDN, is returned without lookup.
 func FindZoneByFqdnCustom(fqdn string, nameservers []string) (string, error) {
+	if zone, ok := zoneOverride(fqdn); ok {
+		return zone, nil
+	}
+
 	soa, err := lookupSoaByFqdn(fqdn, nameservers)
 	if err != nil {
 		return "", fmt.Errorf("[fqdn=%s] %w", fqdn, err)
@@ -179,6 +198,11 @@ func fetchSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error) {
 	for _, index := range labelIndexes {
 		domain := fqdn[index:]
 
+		// A zone cannot be above a registered domain: don't cross the public suffix boundary.
+		if isPublicSuffix(domain) {
+			break
+		}
+
 		r, err = dnsQuery(domain, dns.TypeSOA, nameservers, true)
 		if err != nil {
 			continue
@@ -214,7 +238,13 @@ func fetchSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error) {
 		}
 	}
 
-	return nil, &DNSError{Message: fmt.Sprintf("could not find the start of authority for '%s'", fqdn), MsgOut: r, Err: err}
+	dnsErr := &DNSError{Message: fmt.Sprintf("could not find the start of authority for '%s'", fqdn), MsgOut: r, Err: err}
+	if err != nil {
+		// the lookup failed (e.g. unreachable nameservers): the zone may exist.
+		return nil, dnsErr
+	}
+
+	return nil, &ZoneNotFoundError{FQDN: fqdn, Err: dnsErr}
 }
 
 // dnsMsgContainsCNAME checks for a CNAME answer in msg.
@@ -226,18 +256,28 @@ func dnsMsgContainsCNAME(msg *dns.Msg) bool {
 }
 
 func dnsQuery(fqdn string, rtype uint16, nameservers []string, recursive bool) (*dns.Msg, error) {
+	return dnsQueryFamily(fqdn, rtype, nameservers, recursive, AddressFamilyAny)
+}
+
+// dnsQueryFamily is like dnsQuery, but only queries the nameservers of the address family (see WithAddressFamily).
+func dnsQueryFamily(fqdn string, rtype uint16, nameservers []string, recursive bool, family AddressFamily) (*dns.Msg, error) {
 	m := createDNSMsg(fqdn, rtype, recursive)
 
 	if len(nameservers) == 0 {
 		return nil, &DNSError{Message: "empty list of nameservers"}
 	}
 
+	nameservers = filterNameservers(nameservers, family)
+	if len(nameservers) == 0 {
+		return nil, &DNSError{Message: fmt.Sprintf("no nameserver for the address family %s", family)}
+	}
+
 	var r *dns.Msg
 	var err error
 	var errAll error
 
 	for _, ns := range nameservers {
-		r, err = sendDNSQuery(m, ns)
+		r, err = sendDNSQuery(m, ns, family)
 		if err == nil && len(r.Answer) > 0 {
 			break
 		}
@@ -264,9 +304,9 @@ func createDNSMsg(fqdn string, rtype uint16, recursive bool) *dns.Msg {
 	return m
 }
 
-func sendDNSQuery(m *dns.Msg, ns string) (*dns.Msg, error) {
+func sendDNSQuery(m *dns.Msg, ns string, family AddressFamily) (*dns.Msg, error) {
 	if ok, _ := strconv.ParseBool(os.Getenv("LEGO_EXPERIMENTAL_DNS_TCP_ONLY")); ok {
-		tcp := &dns.Client{Net: "tcp", Timeout: dnsTimeout}
+		tcp := &dns.Client{Net: family.network("tcp"), Timeout: dnsTimeout}
 		r, _, err := tcp.Exchange(m, ns)
 		if err != nil {
 			return r, &DNSError{Message: "DNS call error", MsgIn: m, NS: ns, Err: err}
@@ -275,11 +315,11 @@ func sendDNSQuery(m *dns.Msg, ns string) (*dns.Msg, error) {
 		return r, nil
 	}
 
-	udp := &dns.Client{Net: "udp", Timeout: dnsTimeout}
+	udp := &dns.Client{Net: family.network("udp"), Timeout: dnsTimeout}
 	r, _, err := udp.Exchange(m, ns)
 
 	if r != nil && r.Truncated {
-		tcp := &dns.Client{Net: "tcp", Timeout: dnsTimeout}
+		tcp := &dns.Client{Net: family.network("tcp"), Timeout: dnsTimeout}
 		// If the TCP request succeeds, the "err" will reset to nil
 		r, _, err = tcp.Exchange(m, ns)
 	}
@@ -338,6 +378,22 @@ func (d *DNSError) Unwrap() error {
 	return d.Err
 }
 
+// ZoneNotFoundError is returned by the zone-finding helpers (see FindZoneByFqdn)
+// when the nameservers answer that there is no zone for the FQDN.
+// This error is definitive: the creation of the record is not retried (see WithPresentRetry).
+type ZoneNotFoundError struct {
+	FQDN string
+	Err  error
+}
+
+func (z *ZoneNotFoundError) Error() string {
+	return z.Err.Error()
+}
+
+func (z *ZoneNotFoundError) Unwrap() error {
+	return z.Err
+}
+
 func formatQuestions(questions []dns.Question) string {
 	var parts []string
 	for _, question := range questions {
@@ -91,11 +91,10 @@ var findXByFqdnTestCases = []struct {
 		nameservers: recursiveNameservers,
 	},
 	{
-		desc:        "domain is a eTLD",
-		fqdn:        "example.com.ac.",
-		zone:        "ac.",
-		primaryNs:   "a0.nic.ac.",
-		nameservers: recursiveNameservers,
+		desc:          "domain is under a multi-level public suffix",
+		fqdn:          "example.com.ac.",
+		nameservers:   recursiveNameservers,
+		expectedError: "[fqdn=example.com.ac.] could not find the start of authority for 'example.com.ac.'",
 	},
 	{
 		desc:        "domain is a cross-zone CNAME",
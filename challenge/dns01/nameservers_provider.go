@@ -0,0 +1,34 @@
+package dns01
+
+import (
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// NameserversProvider is a provider serving its records with its own nameservers (e.g. an embedded DNS server).
+// When the provider of the challenge implements this interface, the CNAMEs are followed and the propagation is checked
+// with these nameservers, unless the challenge defines its own (see WithResolver and WithPropagationNameservers).
+type NameserversProvider interface {
+	challenge.Provider
+	Nameservers() []string
+}
+
+// useProviderNameservers checks the propagation with the nameservers of the provider, if it defines them.
+func (c *Challenge) useProviderNameservers() {
+	provider, ok := c.provider.(NameserversProvider)
+	if !ok {
+		return
+	}
+
+	nameservers := ParseNameservers(provider.Nameservers())
+	if len(nameservers) == 0 {
+		return
+	}
+
+	if len(c.preCheck.resolver) == 0 {
+		c.preCheck.resolver = nameservers
+	}
+
+	if len(c.preCheck.propagationNameservers) == 0 {
+		c.preCheck.propagationNameservers = nameservers
+	}
+}
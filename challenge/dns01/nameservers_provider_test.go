@@ -0,0 +1,56 @@
+package dns01
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nameserversProviderMock struct {
+	providerMock
+
+	nameservers []string
+}
+
+func (p *nameserversProviderMock) Nameservers() []string { return p.nameservers }
+
+func TestNameserversProvider(t *testing.T) {
+	provider := &nameserversProviderMock{nameservers: []string{"127.0.0.1:5353"}}
+
+	testCases := []struct {
+		desc                   string
+		provider               *nameserversProviderMock
+		options                []ChallengeOption
+		expectedResolver       []string
+		expectedPropagationNss []string
+	}{
+		{
+			desc:                   "nameservers of the provider",
+			provider:               provider,
+			expectedResolver:       []string{"127.0.0.1:5353"},
+			expectedPropagationNss: []string{"127.0.0.1:5353"},
+		},
+		{
+			desc:                   "no nameservers",
+			provider:               &nameserversProviderMock{},
+			expectedResolver:       nil,
+			expectedPropagationNss: nil,
+		},
+		{
+			desc:                   "nameservers of the challenge",
+			provider:               provider,
+			options:                []ChallengeOption{WithResolver([]string{"192.0.2.1"}), WithPropagationNameservers([]string{"192.0.2.2"})},
+			expectedResolver:       []string{"192.0.2.1:53"},
+			expectedPropagationNss: []string{"192.0.2.2:53"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			chlg := NewChallenge(nil, nil, test.provider, test.options...)
+
+			assert.Equal(t, test.expectedResolver, chlg.preCheck.resolver)
+			assert.Equal(t, test.expectedPropagationNss, chlg.preCheck.propagationNameservers)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package dns01
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -44,6 +45,68 @@ func RecursiveNSsPropagationRequirement() ChallengeOption {
 	}
 }
 
+// WithPropagationStableChecks requires the propagation check to succeed n times in a row
+// before notifying ACME that the DNS challenge is ready.
+// Any failed check resets the count.
+// This mitigates the flapping of resolvers during propagation.
+// The default is 1 (the first successful check stops the wait).
+func WithPropagationStableChecks(n int) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if n < 1 {
+			return fmt.Errorf("invalid number of stable checks: %d", n)
+		}
+
+		chlg.preCheck.stableChecks = n
+		return nil
+	}
+}
+
+// WithPropagationNameservers defines the nameservers used to check the propagation of the TXT record,
+// instead of the authoritative nameservers of the zone.
+// The nameservers where the record is created (by the provider) and the checked nameservers are decoupled:
+// ex: the record is created on a hidden primary, and the propagation is checked on the public secondaries.
+//
+// The replication from a hidden primary (NOTIFY then AXFR/IXFR) adds a lag:
+// without NOTIFY, the secondaries only refresh the zone after the SOA refresh interval (or retry, on failure).
+// The propagation timeout of the provider should be greater than this lag.
+func WithPropagationNameservers(nameservers []string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if len(nameservers) == 0 {
+			return errors.New("no propagation nameservers")
+		}
+
+		chlg.preCheck.propagationNameservers = ParseNameservers(nameservers)
+		return nil
+	}
+}
+
+// WithAuthoritativeShadowCheck adds a check mimicking the validation by the CA:
+// the authoritative nameservers of the zone are discovered,
+// and each address (IPv4 and IPv6) of each nameserver is queried directly.
+// The ACME validation is only triggered when all the addresses return the expected TXT record.
+// This detects the authoritative nameservers lagging behind the (cached) recursive nameservers.
+func WithAuthoritativeShadowCheck() ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.preCheck.authoritativeShadowCheck = true
+		return nil
+	}
+}
+
+// WithResolver defines the recursive nameservers of the challenge, instead of the global recursive nameservers (see AddRecursiveNameservers).
+// The same nameservers are used to follow the CNAMEs (effective FQDN) and to check the propagation,
+// so both are computed against the same view of the DNS (e.g. with a split-horizon DNS).
+// The DNS providers calling GetChallengeInfo, while creating or removing the record, get the same effective FQDN.
+func WithResolver(nameservers []string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if len(nameservers) == 0 {
+			return errors.New("no resolver nameservers")
+		}
+
+		chlg.preCheck.resolver = ParseNameservers(nameservers)
+		return nil
+	}
+}
+
 func PropagationWait(wait time.Duration, skipCheck bool) ChallengeOption {
 	return WrapPreCheck(func(domain, fqdn, value string, check PreCheckFunc) (bool, error) {
 		time.Sleep(wait)
@@ -65,14 +128,54 @@ type preCheck struct {
 
 	// require the TXT record to be propagated to all recursive name servers
 	requireRecursiveNssPropagation bool
+
+	// number of consecutive successful checks required
+	stableChecks int
+
+	// the nameservers to check instead of the authoritative nameservers
+	propagationNameservers []string
+
+	// query all the addresses of the authoritative nameservers, like a CA
+	authoritativeShadowCheck bool
+
+	// the recursive nameservers of the challenge (CNAME resolution and propagation check)
+	resolver []string
+
+	// the address family used to reach the nameservers (see WithAddressFamily)
+	family AddressFamily
+
+	// receives the status of each checked nameserver (see WithPropagationProgress)
+	observe func(status NameserverStatus)
 }
 
 func newPreCheck() preCheck {
 	return preCheck{
 		requireAuthoritativeNssPropagation: true,
+		stableChecks:                       1,
 	}
 }
 
+// recursiveNameservers returns the recursive nameservers of the challenge,
+// or the global recursive nameservers (see AddRecursiveNameservers).
+func (p preCheck) recursiveNameservers() []string {
+	if len(p.resolver) > 0 {
+		return p.resolver
+	}
+
+	return recursiveNameservers
+}
+
+// checkedNameservers returns the nameservers checked for the propagation:
+// the propagation nameservers if defined, otherwise the recursive nameservers of the challenge
+// (the authoritative nameservers are discovered through them).
+func (p preCheck) checkedNameservers() []string {
+	if len(p.propagationNameservers) > 0 {
+		return p.propagationNameservers
+	}
+
+	return p.recursiveNameservers()
+}
+
 func (p preCheck) call(domain, fqdn, value string) (bool, error) {
 	if p.checkFunc == nil {
 		return p.checkDNSPropagation(fqdn, value)
@@ -84,7 +187,7 @@ func (p preCheck) call(domain, fqdn, value string) (bool, error) {
 // checkDNSPropagation checks if the expected TXT record has been propagated to all authoritative nameservers.
 func (p preCheck) checkDNSPropagation(fqdn, value string) (bool, error) {
 	// Initial attempt to resolve at the recursive NS (require to get CNAME)
-	r, err := dnsQuery(fqdn, dns.TypeTXT, recursiveNameservers, true)
+	r, err := dnsQueryFamily(fqdn, dns.TypeTXT, p.recursiveNameservers(), true, p.family)
 	if err != nil {
 		return false, fmt.Errorf("initial recursive nameserver: %w", err)
 	}
@@ -93,23 +196,50 @@ func (p preCheck) checkDNSPropagation(fqdn, value string) (bool, error) {
 		fqdn = updateDomainWithCName(r, fqdn)
 	}
 
+	found, err := p.checkNameservers(fqdn, value)
+	if !found || err != nil || !p.authoritativeShadowCheck {
+		return found, err
+	}
+
+	found, err = checkAuthoritativeShadow(fqdn, value, p.recursiveNameservers(), p.family)
+	if err != nil {
+		return found, fmt.Errorf("authoritative shadow check: %w", err)
+	}
+
+	return found, nil
+}
+
+// checkNameservers checks the TXT record on the recursive nameservers, the propagation nameservers, and the authoritative nameservers,
+// depending on the configuration.
+func (p preCheck) checkNameservers(fqdn, value string) (bool, error) {
+	var err error
+
 	if p.requireRecursiveNssPropagation {
-		_, err = checkNameserversPropagation(fqdn, value, recursiveNameservers, false)
+		_, err = checkNameserversPropagationObserved(fqdn, value, p.recursiveNameservers(), false, p.family, p.observe)
 		if err != nil {
 			return false, fmt.Errorf("recursive nameservers: %w", err)
 		}
 	}
 
+	if len(p.propagationNameservers) > 0 {
+		found, errP := checkNameserversPropagationObserved(fqdn, value, p.propagationNameservers, false, p.family, p.observe)
+		if errP != nil {
+			return found, fmt.Errorf("propagation nameservers: %w", errP)
+		}
+
+		return found, nil
+	}
+
 	if !p.requireAuthoritativeNssPropagation {
 		return true, nil
 	}
 
-	authoritativeNss, err := lookupNameservers(fqdn)
+	authoritativeNss, err := lookupNameserversCustom(fqdn, p.recursiveNameservers(), p.family)
 	if err != nil {
 		return false, err
 	}
 
-	found, err := checkNameserversPropagation(fqdn, value, authoritativeNss, true)
+	found, err := checkNameserversPropagationObserved(fqdn, value, authoritativeNss, true, p.family, p.observe)
 	if err != nil {
 		return found, fmt.Errorf("authoritative nameservers: %w", err)
 	}
@@ -119,38 +249,68 @@ func (p preCheck) checkDNSPropagation(fqdn, value string) (bool, error) {
 
 // checkNameserversPropagation queries each of the given nameservers for the expected TXT record.
 func checkNameserversPropagation(fqdn, value string, nameservers []string, addPort bool) (bool, error) {
+	return checkNameserversPropagationObserved(fqdn, value, nameservers, addPort, AddressFamilyAny, nil)
+}
+
+// checkNameserversPropagationObserved is like checkNameserversPropagation,
+// but reports the status of each nameserver to observe (if not nil).
+// With an observer, all the nameservers are queried, even after a failure, and the first error is returned.
+func checkNameserversPropagationObserved(fqdn, value string, nameservers []string, addPort bool, family AddressFamily, observe func(NameserverStatus)) (bool, error) {
+	var firstErr error
+
 	for _, ns := range nameservers {
 		if addPort {
 			ns = net.JoinHostPort(ns, "53")
 		}
 
-		r, err := dnsQuery(fqdn, dns.TypeTXT, []string{ns}, false)
-		if err != nil {
-			return false, err
+		err := checkNameserverPropagation(fqdn, value, ns, family)
+
+		if observe != nil {
+			observe(NameserverStatus{Nameserver: ns, Found: err == nil, Err: err})
 		}
 
-		if r.Rcode != dns.RcodeSuccess {
-			return false, fmt.Errorf("NS %s returned %s for %s", ns, dns.RcodeToString[r.Rcode], fqdn)
+		if err == nil {
+			continue
 		}
 
-		var records []string
-
-		var found bool
-		for _, rr := range r.Answer {
-			if txt, ok := rr.(*dns.TXT); ok {
-				record := strings.Join(txt.Txt, "")
-				records = append(records, record)
-				if record == value {
-					found = true
-					break
-				}
-			}
+		if observe == nil {
+			return false, err
 		}
 
-		if !found {
-			return false, fmt.Errorf("NS %s did not return the expected TXT record [fqdn: %s, value: %s]: %s", ns, fqdn, value, strings.Join(records, " ,"))
+		if firstErr == nil {
+			firstErr = err
 		}
 	}
 
+	if firstErr != nil {
+		return false, firstErr
+	}
+
 	return true, nil
 }
+
+// checkNameserverPropagation queries a nameserver for the expected TXT record.
+func checkNameserverPropagation(fqdn, value, ns string, family AddressFamily) error {
+	r, err := dnsQueryFamily(fqdn, dns.TypeTXT, []string{ns}, false, family)
+	if err != nil {
+		return err
+	}
+
+	if r.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("NS %s returned %s for %s", ns, dns.RcodeToString[r.Rcode], fqdn)
+	}
+
+	var records []string
+
+	for _, rr := range r.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			record := strings.Join(txt.Txt, "")
+			records = append(records, record)
+			if record == value {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("NS %s did not return the expected TXT record [fqdn: %s, value: %s]: %s", ns, fqdn, value, strings.Join(records, " ,"))
+}
@@ -0,0 +1,127 @@
+package dns01
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/miekg/dns"
+)
+
+// WithCAAAccountURICheck checks, before creating the record,
+// that the CAA records of the domain (RFC 8659) allow the account of the client to issue (`accounturi` parameter, RFC 8657).
+// The check fails fast, instead of waiting for the CA to refuse the issuance.
+//
+// A domain without CAA records, or with CAA records without `accounturi` parameter, is allowed.
+// The account URI can be retrieved with lego.Client.AccountURI.
+func WithCAAAccountURICheck() ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.caaAccountURICheck = true
+		return nil
+	}
+}
+
+// checkCAA checks the CAA records of the domain of the authorization, if enabled.
+func (c *Challenge) checkCAA(authz acme.Authorization) error {
+	if !c.caaAccountURICheck {
+		return nil
+	}
+
+	return checkCAAAccountURI(authz.Identifier.Value, authz.Wildcard, c.core.GetAccountURI(), filterNameservers(c.preCheck.recursiveNameservers(), c.preCheck.family))
+}
+
+// checkCAAAccountURI checks that the relevant CAA records of the domain allow the account URI.
+func checkCAAAccountURI(domain string, wildcard bool, accountURI string, nameservers []string) error {
+	records, err := lookupCAA(strings.TrimPrefix(domain, "*."), nameservers)
+	if err != nil {
+		return fmt.Errorf("CAA lookup: %w", err)
+	}
+
+	var accountURIs []string
+
+	for _, record := range filterCAA(records, wildcard) {
+		if uri, ok := parseCAAParameters(record.Value)["accounturi"]; ok {
+			accountURIs = append(accountURIs, uri)
+		}
+	}
+
+	if len(accountURIs) == 0 {
+		return nil
+	}
+
+	for _, uri := range accountURIs {
+		if uri == accountURI {
+			return nil
+		}
+	}
+
+	if accountURI == "" {
+		return fmt.Errorf("CAA records of %s require an account URI (%s), but the account is not registered",
+			domain, strings.Join(accountURIs, ", "))
+	}
+
+	return fmt.Errorf("CAA records of %s don't allow the account %s (accounturi: %s)",
+		domain, accountURI, strings.Join(accountURIs, ", "))
+}
+
+// lookupCAA returns the relevant CAA RRset of the domain:
+// the first non-empty CAA RRset, climbing the DNS tree from the domain to the TLD (RFC 8659, section 3).
+func lookupCAA(domain string, nameservers []string) ([]*dns.CAA, error) {
+	labels := dns.SplitDomainName(domain)
+
+	for i := range labels {
+		r, err := dnsQuery(dns.Fqdn(strings.Join(labels[i:], ".")), dns.TypeCAA, nameservers, true)
+		if err != nil {
+			return nil, err
+		}
+
+		var records []*dns.CAA
+
+		for _, rr := range r.Answer {
+			if caa, ok := rr.(*dns.CAA); ok {
+				records = append(records, caa)
+			}
+		}
+
+		if len(records) > 0 {
+			return records, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// filterCAA returns the records applicable to the issuance:
+// the `issuewild` records for a wildcard (if any), otherwise the `issue` records.
+func filterCAA(records []*dns.CAA, wildcard bool) []*dns.CAA {
+	byTag := map[string][]*dns.CAA{}
+
+	for _, record := range records {
+		tag := strings.ToLower(record.Tag)
+		byTag[tag] = append(byTag[tag], record)
+	}
+
+	if wildcard && len(byTag["issuewild"]) > 0 {
+		return byTag["issuewild"]
+	}
+
+	return byTag["issue"]
+}
+
+// parseCAAParameters parses the parameters of an `issue` value (ex: `ca.example; accounturi=https://ca.example/acct/1`).
+func parseCAAParameters(value string) map[string]string {
+	parameters := map[string]string{}
+
+	parts := strings.Split(value, ";")
+
+	for _, part := range parts[1:] {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+
+		parameters[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(val)
+	}
+
+	return parameters
+}
@@ -0,0 +1,151 @@
+package dns01
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// caaHandler answers to the CAA queries with the given records (FQDN -> records).
+func caaHandler(records map[string][]dns.CAA) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		for _, q := range req.Question {
+			if q.Qtype != dns.TypeCAA {
+				continue
+			}
+
+			for _, record := range records[q.Name] {
+				record.Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeCAA, Class: dns.ClassINET, Ttl: 60}
+				m.Answer = append(m.Answer, &record)
+			}
+		}
+
+		_ = w.WriteMsg(m)
+	}
+}
+
+func Test_checkCAAAccountURI(t *testing.T) {
+	const accountURI = "https://ca.example/acct/1"
+
+	testCases := []struct {
+		desc          string
+		domain        string
+		wildcard      bool
+		accountURI    string
+		records       map[string][]dns.CAA
+		expectedError string
+	}{
+		{
+			desc:       "no CAA records",
+			domain:     "www.example.com",
+			accountURI: accountURI,
+		},
+		{
+			desc:       "CAA records without accounturi",
+			domain:     "www.example.com",
+			accountURI: accountURI,
+			records: map[string][]dns.CAA{
+				"example.com.": {{Tag: "issue", Value: "ca.example"}},
+			},
+		},
+		{
+			desc:       "matching accounturi",
+			domain:     "www.example.com",
+			accountURI: accountURI,
+			records: map[string][]dns.CAA{
+				"example.com.": {{Tag: "issue", Value: "ca.example; accounturi=https://ca.example/acct/1"}},
+			},
+		},
+		{
+			desc:       "matching accounturi among several records",
+			domain:     "example.com",
+			accountURI: accountURI,
+			records: map[string][]dns.CAA{
+				"example.com.": {
+					{Tag: "issue", Value: "ca.example; accounturi=https://ca.example/acct/2"},
+					{Tag: "issue", Value: "ca.example; validationmethods=dns-01; accounturi=https://ca.example/acct/1"},
+				},
+			},
+		},
+		{
+			desc:       "mismatching accounturi",
+			domain:     "www.example.com",
+			accountURI: accountURI,
+			records: map[string][]dns.CAA{
+				"example.com.": {{Tag: "issue", Value: "ca.example; accounturi=https://ca.example/acct/2"}},
+			},
+			expectedError: "CAA records of www.example.com don't allow the account https://ca.example/acct/1 (accounturi: https://ca.example/acct/2)",
+		},
+		{
+			desc:   "accounturi without registered account",
+			domain: "example.com",
+			records: map[string][]dns.CAA{
+				"example.com.": {{Tag: "issue", Value: "ca.example; accounturi=https://ca.example/acct/2"}},
+			},
+			expectedError: "CAA records of example.com require an account URI (https://ca.example/acct/2), but the account is not registered",
+		},
+		{
+			desc:       "closest RRset takes precedence",
+			domain:     "www.example.com",
+			accountURI: accountURI,
+			records: map[string][]dns.CAA{
+				"www.example.com.": {{Tag: "issue", Value: "ca.example; accounturi=https://ca.example/acct/1"}},
+				"example.com.":     {{Tag: "issue", Value: "ca.example; accounturi=https://ca.example/acct/2"}},
+			},
+		},
+		{
+			desc:       "wildcard uses issuewild",
+			domain:     "*.example.com",
+			wildcard:   true,
+			accountURI: accountURI,
+			records: map[string][]dns.CAA{
+				"example.com.": {
+					{Tag: "issue", Value: "ca.example; accounturi=https://ca.example/acct/1"},
+					{Tag: "issuewild", Value: "ca.example; accounturi=https://ca.example/acct/2"},
+				},
+			},
+			expectedError: "CAA records of *.example.com don't allow the account https://ca.example/acct/1 (accounturi: https://ca.example/acct/2)",
+		},
+		{
+			desc:       "wildcard without issuewild uses issue",
+			domain:     "*.example.com",
+			wildcard:   true,
+			accountURI: accountURI,
+			records: map[string][]dns.CAA{
+				"example.com.": {{Tag: "issue", Value: "ca.example; accounturi=https://ca.example/acct/1"}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addr := startDNSServer(t, caaHandler(test.records))
+
+			err := checkCAAAccountURI(test.domain, test.wildcard, test.accountURI, []string{addr})
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_parseCAAParameters(t *testing.T) {
+	parameters := parseCAAParameters("ca.example; accounturi = https://ca.example/acct/1 ;validationmethods=dns-01")
+
+	expected := map[string]string{
+		"accounturi":        "https://ca.example/acct/1",
+		"validationmethods": "dns-01",
+	}
+
+	assert.Equal(t, expected, parameters)
+}
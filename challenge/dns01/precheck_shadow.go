@@ -0,0 +1,62 @@
+package dns01
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/miekg/dns"
+)
+
+// authoritativePort is the port used to query the addresses of the authoritative nameservers.
+var authoritativePort = "53"
+
+// checkAuthoritativeShadow queries each address of each authoritative nameserver of the zone for the expected TXT record.
+func checkAuthoritativeShadow(fqdn, value string, nameservers []string, family AddressFamily) (bool, error) {
+	authoritativeNss, err := lookupNameserversCustom(fqdn, nameservers, family)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ns := range authoritativeNss {
+		addresses, err := lookupAddresses(ns, nameservers, family)
+		if err != nil {
+			return false, err
+		}
+
+		for _, addr := range addresses {
+			found, err := checkNameserversPropagationObserved(fqdn, value, []string{net.JoinHostPort(addr, authoritativePort)}, false, family, nil)
+			if err != nil {
+				return found, fmt.Errorf("%s: %w", ns, err)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// lookupAddresses returns the addresses of a host in the address family (IPv4 and IPv6 by default), using the recursive nameservers.
+func lookupAddresses(host string, nameservers []string, family AddressFamily) ([]string, error) {
+	var addresses []string
+
+	for _, rtype := range family.rtypes() {
+		r, err := dnsQueryFamily(dns.Fqdn(host), rtype, nameservers, true, family)
+		if err != nil {
+			continue
+		}
+
+		for _, rr := range r.Answer {
+			switch record := rr.(type) {
+			case *dns.A:
+				addresses = append(addresses, record.A.String())
+			case *dns.AAAA:
+				addresses = append(addresses, record.AAAA.String())
+			}
+		}
+	}
+
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("could not resolve the addresses of the nameserver %s", host)
+	}
+
+	return addresses, nil
+}
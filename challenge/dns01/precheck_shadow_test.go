@@ -0,0 +1,101 @@
+package dns01
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recursiveHandler answers like a recursive resolver for the zone example.com.
+// The zone has 2 nameservers: ns1.example.com. (127.0.0.1) and ns2.example.com. (127.0.0.2).
+func recursiveHandler(txt map[string][]string) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		for _, q := range req.Question {
+			hdr := dns.RR_Header{Name: q.Name, Rrtype: q.Qtype, Class: dns.ClassINET, Ttl: 60}
+
+			switch {
+			case q.Qtype == dns.TypeSOA && q.Name == "example.com.":
+				m.Answer = append(m.Answer, &dns.SOA{Hdr: hdr, Ns: "ns1.example.com.", Mbox: "admin.example.com."})
+			case q.Qtype == dns.TypeNS && q.Name == "example.com.":
+				m.Answer = append(m.Answer,
+					&dns.NS{Hdr: hdr, Ns: "ns1.example.com."},
+					&dns.NS{Hdr: hdr, Ns: "ns2.example.com."},
+				)
+			case q.Qtype == dns.TypeA && q.Name == "ns1.example.com.":
+				m.Answer = append(m.Answer, &dns.A{Hdr: hdr, A: net.ParseIP("127.0.0.1")})
+			case q.Qtype == dns.TypeA && q.Name == "ns2.example.com.":
+				m.Answer = append(m.Answer, &dns.A{Hdr: hdr, A: net.ParseIP("127.0.0.2")})
+			case q.Qtype == dns.TypeTXT:
+				for _, value := range txt[q.Name] {
+					m.Answer = append(m.Answer, &dns.TXT{Hdr: hdr, Txt: []string{value}})
+				}
+			}
+		}
+
+		_ = w.WriteMsg(m)
+	}
+}
+
+func TestWithAuthoritativeShadowCheck(t *testing.T) {
+	const fqdn = "_acme-challenge.example.com."
+
+	testCases := []struct {
+		desc          string
+		ns2Records    map[string][]string
+		expected      bool
+		expectedError string
+	}{
+		{
+			desc:       "all the authoritative nameservers are up to date",
+			ns2Records: map[string][]string{fqdn: {"value"}},
+			expected:   true,
+		},
+		{
+			desc:          "an authoritative nameserver lags",
+			ns2Records:    map[string][]string{fqdn: {"old"}},
+			expectedError: "authoritative shadow check: ns2.example.com.: NS 127.0.0.2:",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			ClearFqdnCache()
+			t.Cleanup(ClearFqdnCache)
+
+			// The recursive resolver already has the new value.
+			recursive := startDNSServer(t, recursiveHandler(map[string][]string{fqdn: {"value"}}))
+			setRecursiveNameservers(t, recursive)
+
+			ns1 := startDNSServer(t, txtHandler(map[string][]string{fqdn: {"value"}}))
+
+			_, port, err := net.SplitHostPort(ns1)
+			require.NoError(t, err)
+
+			startDNSServerOn(t, net.JoinHostPort("127.0.0.2", port), txtHandler(test.ns2Records))
+
+			original := authoritativePort
+			t.Cleanup(func() { authoritativePort = original })
+
+			authoritativePort = port
+
+			chlg := NewChallenge(nil, nil, &providerMock{},
+				DisableAuthoritativeNssPropagationRequirement(),
+				WithAuthoritativeShadowCheck())
+
+			found, err := chlg.preCheck.call("example.com", fqdn, "value")
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, found)
+		})
+	}
+}
@@ -1,8 +1,17 @@
 package dns01
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -112,3 +121,264 @@ func TestCheckAuthoritativeNssErr(t *testing.T) {
 		})
 	}
 }
+
+func TestWithPropagationStableChecks(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		stableChecks  int
+		results       []bool
+		expectedCalls int
+	}{
+		{
+			desc:          "default",
+			results:       []bool{false, true},
+			expectedCalls: 2,
+		},
+		{
+			desc:          "consecutive successes",
+			stableChecks:  3,
+			results:       []bool{true, true, true},
+			expectedCalls: 3,
+		},
+		{
+			desc:          "reset on miss",
+			stableChecks:  2,
+			results:       []bool{true, false, true, true},
+			expectedCalls: 4,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var calls int
+
+			preCheck := func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+				calls++
+
+				if calls > len(test.results) {
+					return true, nil
+				}
+
+				return test.results[calls-1], nil
+			}
+
+			provider := &providerTimeoutMock{timeout: 5 * time.Second, interval: 10 * time.Millisecond}
+
+			validate := func(_ *api.Core, _ string, _ acme.Challenge) error { return nil }
+
+			chlg := NewChallenge(core, validate, provider,
+				WrapPreCheck(preCheck),
+				CondOption(test.stableChecks > 0, WithPropagationStableChecks(test.stableChecks)))
+
+			authz := acme.Authorization{
+				Identifier: acme.Identifier{Value: "example.com"},
+				Challenges: []acme.Challenge{{Type: challenge.DNS01.String()}},
+			}
+
+			err = chlg.Solve(authz)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedCalls, calls)
+		})
+	}
+}
+
+func TestWithPropagationNameservers(t *testing.T) {
+	fqdn := "_acme-challenge.example.com."
+
+	setRecursiveNameservers(t, startDNSServer(t, txtHandler(nil)))
+
+	propagated := startDNSServer(t, txtHandler(map[string][]string{fqdn: {"value"}}))
+	lagging := startDNSServer(t, txtHandler(nil))
+
+	testCases := []struct {
+		desc        string
+		nameservers []string
+		expectedErr string
+	}{
+		{
+			desc:        "propagated",
+			nameservers: []string{propagated},
+		},
+		{
+			desc:        "not yet replicated",
+			nameservers: []string{propagated, lagging},
+			expectedErr: "propagation nameservers: NS " + lagging + " did not return the expected TXT record [fqdn: _acme-challenge.example.com., value: value]: ",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			chlg := NewChallenge(nil, nil, &providerMock{}, WithPropagationNameservers(test.nameservers))
+
+			found, err := chlg.preCheck.call("example.com", fqdn, "value")
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				assert.False(t, found)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, found)
+		})
+	}
+}
+
+func TestWithResolver(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "false")
+
+	alias := "_acme-challenge.example.com."
+	target := "_acme-challenge.internal.example.net."
+
+	// The public view doesn't know the records.
+	setRecursiveNameservers(t, startDNSServer(t, txtHandler(nil)))
+
+	// The internal view (split-horizon) delegates the challenge to an internal zone.
+	internal := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		for _, q := range req.Question {
+			switch {
+			case q.Name == alias && (q.Qtype == dns.TypeCNAME || q.Qtype == dns.TypeTXT):
+				m.Answer = append(m.Answer, &dns.CNAME{
+					Hdr:    dns.RR_Header{Name: alias, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: 60},
+					Target: target,
+				})
+
+			case q.Name == target && q.Qtype == dns.TypeTXT:
+				m.Answer = append(m.Answer, &dns.TXT{
+					Hdr: dns.RR_Header{Name: target, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+					Txt: []string{getChallengeValue("keyAuth")},
+				})
+			}
+		}
+
+		_ = w.WriteMsg(m)
+	})
+
+	opts := []ChallengeOption{
+		DisableAuthoritativeNssPropagationRequirement(),
+		RecursiveNSsPropagationRequirement(),
+	}
+
+	chlg := NewChallenge(nil, nil, &providerMock{}, append(opts, WithResolver([]string{internal}))...)
+
+	info := chlg.getChallengeInfo("example.com", "keyAuth")
+	assert.Equal(t, target, info.EffectiveFQDN)
+
+	found, err := chlg.preCheck.call("example.com", info.EffectiveFQDN, info.Value)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	// Without the option, the public view is used for both.
+	chlg = NewChallenge(nil, nil, &providerMock{}, opts...)
+
+	info = chlg.getChallengeInfo("example.com", "keyAuth")
+	assert.Equal(t, alias, info.EffectiveFQDN)
+
+	_, err = chlg.preCheck.call("example.com", info.EffectiveFQDN, info.Value)
+	require.Error(t, err)
+}
+
+// fqdnProviderMock is a provider computing the FQDN of the record with GetChallengeInfo, like most of the DNS providers.
+type fqdnProviderMock struct {
+	presented []string
+	cleaned   []string
+}
+
+func (p *fqdnProviderMock) Present(domain, _, keyAuth string) error {
+	p.presented = append(p.presented, GetChallengeInfo(domain, keyAuth).EffectiveFQDN)
+	return nil
+}
+
+func (p *fqdnProviderMock) CleanUp(domain, _, keyAuth string) error {
+	p.cleaned = append(p.cleaned, GetChallengeInfo(domain, keyAuth).EffectiveFQDN)
+	return nil
+}
+
+func TestWithResolver_provider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "false")
+
+	alias := "_acme-challenge.example.com."
+	target := "_acme-challenge.internal.example.net."
+
+	// The public view doesn't know the records.
+	setRecursiveNameservers(t, startDNSServer(t, txtHandler(nil)))
+
+	// The internal view (split-horizon) delegates the challenge to an internal zone.
+	internal := startDNSServer(t, cnameHandler(map[string]string{alias: target}))
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &fqdnProviderMock{}
+
+	chlg := NewChallenge(core, nil, provider, WithResolver([]string{internal}))
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	require.NoError(t, chlg.PreSolve(authz))
+	require.NoError(t, chlg.CleanUp(authz))
+
+	keyAuth, err := core.GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	// the provider creates the record where the challenge checks it.
+	assert.Equal(t, target, chlg.getChallengeInfo("example.com", keyAuth).EffectiveFQDN)
+	assert.Equal(t, []string{target}, provider.presented)
+	assert.Equal(t, []string{target}, provider.cleaned)
+
+	// outside the challenge, the global recursive nameservers are used.
+	assert.Equal(t, alias, GetChallengeInfo("example.com", keyAuth).EffectiveFQDN)
+}
+
+func Test_preCheck_checkedNameservers(t *testing.T) {
+	setRecursiveNameservers(t, "192.0.2.1:53")
+
+	testCases := []struct {
+		desc     string
+		options  []ChallengeOption
+		expected []string
+	}{
+		{
+			desc:     "global recursive nameservers",
+			expected: []string{"192.0.2.1:53"},
+		},
+		{
+			desc:     "resolver of the challenge",
+			options:  []ChallengeOption{WithResolver([]string{"192.0.2.2"})},
+			expected: []string{"192.0.2.2:53"},
+		},
+		{
+			desc:     "propagation nameservers",
+			options:  []ChallengeOption{WithResolver([]string{"192.0.2.2"}), WithPropagationNameservers([]string{"192.0.2.3"})},
+			expected: []string{"192.0.2.3:53"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			chlg := NewChallenge(nil, nil, &providerMock{}, test.options...)
+
+			assert.Equal(t, test.expected, chlg.preCheck.checkedNameservers())
+		})
+	}
+}
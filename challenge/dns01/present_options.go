@@ -0,0 +1,31 @@
+package dns01
+
+import "github.com/go-acme/lego/v4/challenge"
+
+// PresentOptions are the details of the creation of a record.
+type PresentOptions struct {
+	// Attempt is the attempt number of the creation of the record, starting at 1 (see WithPresentRetry).
+	Attempt int
+	// Comment is the comment to attach to the record, empty if not defined (see WithRecordComment).
+	Comment string
+}
+
+// PresentOptionsProvider is a provider using the details of the creation of the record:
+// the attempt number (e.g. to vary the TTL between the attempts, to work around a caching bug of the DNS provider),
+// or the comment (or tag) to attach to the record, for the providers whose API supports it.
+// When a provider implements this interface, PresentWithOptions is called instead of Present.
+// The record is still removed through CleanUp.
+type PresentOptionsProvider interface {
+	challenge.Provider
+	PresentWithOptions(domain, token, keyAuth string, opts PresentOptions) error
+}
+
+// presentWithOptions creates the record through PresentWithOptions if the provider implements PresentOptionsProvider,
+// otherwise through Present (the options are ignored).
+func presentWithOptions(provider challenge.Provider, domain, token, keyAuth string, opts PresentOptions) error {
+	if p, ok := provider.(PresentOptionsProvider); ok {
+		return p.PresentWithOptions(domain, token, keyAuth, opts)
+	}
+
+	return provider.Present(domain, token, keyAuth)
+}
@@ -0,0 +1,25 @@
+package dns01
+
+import (
+	"errors"
+	"time"
+)
+
+type presentRetry struct {
+	attempts int
+	interval time.Duration
+}
+
+// WithPresentRetry retries the creation of the record when the provider fails, up to attempts times (including the first one).
+// The providers implementing PresentOptionsProvider receive the attempt number.
+func WithPresentRetry(attempts int, interval time.Duration) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if attempts < 1 {
+			return errors.New("the number of present attempts must be greater than 0")
+		}
+
+		chlg.presentRetry = presentRetry{attempts: attempts, interval: interval}
+
+		return nil
+	}
+}
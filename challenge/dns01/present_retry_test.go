@@ -0,0 +1,93 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type attemptProviderMock struct {
+	providerMock
+
+	// failures is the number of attempts to fail.
+	failures int
+	attempts []int
+	options  []PresentOptions
+}
+
+func (p *attemptProviderMock) PresentWithOptions(_, _, _ string, opts PresentOptions) error {
+	p.attempts = append(p.attempts, opts.Attempt)
+	p.options = append(p.options, opts)
+
+	if len(p.attempts) <= p.failures {
+		return errors.New("duplicated TTL")
+	}
+
+	return nil
+}
+
+func TestChallenge_PreSolve_presentRetry(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	testCases := []struct {
+		desc             string
+		opts             []ChallengeOption
+		failures         int
+		expectedAttempts []int
+		requireErr       require.ErrorAssertionFunc
+	}{
+		{
+			desc:             "success after retries",
+			opts:             []ChallengeOption{WithPresentRetry(3, 0)},
+			failures:         2,
+			expectedAttempts: []int{1, 2, 3},
+			requireErr:       require.NoError,
+		},
+		{
+			desc:             "too many failures",
+			opts:             []ChallengeOption{WithPresentRetry(2, 0)},
+			failures:         2,
+			expectedAttempts: []int{1, 2},
+			requireErr:       require.Error,
+		},
+		{
+			desc:             "no retry",
+			failures:         1,
+			expectedAttempts: []int{1},
+			requireErr:       require.Error,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider := &attemptProviderMock{failures: test.failures}
+
+			chlg := NewChallenge(core, nil, provider, test.opts...)
+
+			err := chlg.PreSolve(authz)
+			test.requireErr(t, err)
+
+			assert.Equal(t, test.expectedAttempts, provider.attempts)
+		})
+	}
+}
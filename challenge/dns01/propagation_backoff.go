@@ -0,0 +1,33 @@
+package dns01
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/wait"
+)
+
+// WithPropagationBackoff defines the strategy computing the interval between the propagation checks
+// (e.g. wait.Exponential for the long propagation windows).
+// By default, the checks are spaced by the polling interval (wait.Linear).
+func WithPropagationBackoff(backoff wait.Backoff) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if backoff == nil {
+			return errors.New("the propagation backoff is nil")
+		}
+
+		chlg.propagationBackoff = backoff
+
+		return nil
+	}
+}
+
+// waitForPropagation polls the propagation check, with the backoff strategy if defined.
+func (c *Challenge) waitForPropagation(timeout, interval time.Duration, f func() (bool, error)) error {
+	backoff := c.propagationBackoff
+	if backoff == nil {
+		backoff = wait.Linear(interval)
+	}
+
+	return wait.ForWithClock(c.clock, "propagation", timeout, backoff, f)
+}
@@ -0,0 +1,74 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPropagationBackoff(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var attempts []int
+
+	backoff := func(attempt int) time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts = append(attempts, attempt)
+
+		return time.Millisecond
+	}
+
+	var checks int
+
+	preCheck := func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+		checks++
+		return checks == 3, nil
+	}
+
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		&providerTimeoutMock{timeout: time.Second, interval: time.Millisecond},
+		WrapPreCheck(preCheck),
+		WithPropagationBackoff(backoff),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// the first interval is logged, then one interval after each unsuccessful check.
+	assert.Equal(t, []int{1, 1, 2}, attempts)
+}
+
+func TestWithPropagationBackoff_nil(t *testing.T) {
+	err := WithPropagationBackoff(nil)(&Challenge{})
+	require.EqualError(t, err, "the propagation backoff is nil")
+}
@@ -0,0 +1,44 @@
+package dns01
+
+import (
+	"fmt"
+	"time"
+)
+
+// PropagationProfile contains the recommended propagation timeout and polling interval for a DNS provider.
+type PropagationProfile struct {
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// PropagationProfiles is a registry of the recommended propagation profiles, indexed by provider name.
+// The values are seeded from the defaults known to work for the related providers,
+// the registry can be modified (before creating the challenges) to add or override entries.
+var PropagationProfiles = map[string]PropagationProfile{
+	"azuredns":     {Timeout: 2 * time.Minute, Interval: 2 * time.Second},
+	"cloudflare":   {Timeout: 2 * time.Minute, Interval: 2 * time.Second},
+	"digitalocean": {Timeout: 60 * time.Second, Interval: 5 * time.Second},
+	"gandiv5":      {Timeout: 20 * time.Minute, Interval: 20 * time.Second},
+	"gcloud":       {Timeout: 3 * time.Minute, Interval: 5 * time.Second},
+	"godaddy":      {Timeout: 2 * time.Minute, Interval: 2 * time.Second},
+	"hetzner":      {Timeout: 2 * time.Minute, Interval: 2 * time.Second},
+	"namecheap":    {Timeout: 60 * time.Minute, Interval: 15 * time.Second},
+	"ovh":          {Timeout: DefaultPropagationTimeout, Interval: DefaultPollingInterval},
+	"rfc2136":      {Timeout: 60 * time.Second, Interval: 2 * time.Second},
+	"route53":      {Timeout: 2 * time.Minute, Interval: 4 * time.Second},
+}
+
+// WithPropagationProfile uses the propagation profile registered (in PropagationProfiles) for the named provider.
+// The profile is only used when the provider doesn't define its own timeout (challenge.ProviderTimeout).
+func WithPropagationProfile(name string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		profile, ok := PropagationProfiles[name]
+		if !ok {
+			return fmt.Errorf("unknown propagation profile: %s", name)
+		}
+
+		chlg.propagationProfile = &profile
+
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package dns01_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/clock"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/providers/dns/httpreq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPropagationProfile_providerTimeout(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	dns01.PropagationProfiles["slow-secondaries"] = dns01.PropagationProfile{Timeout: 30 * time.Minute, Interval: time.Minute}
+	t.Cleanup(func() { delete(dns01.PropagationProfiles, "slow-secondaries") })
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	config := httpreq.NewDefaultConfig()
+	config.Endpoint, _ = url.Parse("http://localhost:8090")
+	config.PropagationTimeout = 2 * time.Minute
+	config.PollingInterval = 2 * time.Second
+
+	provider, err := httpreq.NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	testCases := []struct {
+		desc            string
+		options         []dns01.ChallengeOption
+		expectedElapsed time.Duration
+	}{
+		{
+			desc: "timeout of the provider",
+			// the initial wait, then the timeout.
+			expectedElapsed: 2*time.Second + 2*time.Minute,
+		},
+		{
+			desc:    "provider timeout overrides the profile",
+			options: []dns01.ChallengeOption{dns01.WithPropagationProfile("slow-secondaries")},
+			// the initial wait, then the timeout.
+			expectedElapsed: 2*time.Second + 2*time.Minute,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+			fakeClock := clock.NewFake(start)
+
+			options := append([]dns01.ChallengeOption{
+				dns01.WrapPreCheck(func(_, _, _ string, _ dns01.PreCheckFunc) (bool, error) { return false, nil }),
+				dns01.WithClock(fakeClock),
+			}, test.options...)
+
+			chlg := dns01.NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error { return nil }, provider, options...)
+
+			err := chlg.Solve(authz)
+			require.EqualError(t, err, "propagation: time limit exceeded")
+
+			assert.Equal(t, test.expectedElapsed, fakeClock.Now().Sub(start))
+		})
+	}
+}
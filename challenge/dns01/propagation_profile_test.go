@@ -0,0 +1,69 @@
+package dns01
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPropagationProfile(t *testing.T) {
+	PropagationProfiles["custom"] = PropagationProfile{Timeout: 5 * time.Minute, Interval: 10 * time.Second}
+	t.Cleanup(func() { delete(PropagationProfiles, "custom") })
+
+	testCases := []struct {
+		desc             string
+		provider         challenge.Provider
+		profile          string
+		expectedTimeout  time.Duration
+		expectedInterval time.Duration
+	}{
+		{
+			desc:             "no profile",
+			provider:         &providerMock{},
+			expectedTimeout:  DefaultPropagationTimeout,
+			expectedInterval: DefaultPollingInterval,
+		},
+		{
+			desc:             "built-in profile",
+			provider:         &providerMock{},
+			profile:          "route53",
+			expectedTimeout:  2 * time.Minute,
+			expectedInterval: 4 * time.Second,
+		},
+		{
+			desc:             "custom profile",
+			provider:         &providerMock{},
+			profile:          "custom",
+			expectedTimeout:  5 * time.Minute,
+			expectedInterval: 10 * time.Second,
+		},
+		{
+			desc:             "unknown profile",
+			provider:         &providerMock{},
+			profile:          "unknown",
+			expectedTimeout:  DefaultPropagationTimeout,
+			expectedInterval: DefaultPollingInterval,
+		},
+		{
+			desc:             "provider timeout overrides the profile",
+			provider:         &providerTimeoutMock{timeout: 30 * time.Second, interval: time.Second},
+			profile:          "custom",
+			expectedTimeout:  30 * time.Second,
+			expectedInterval: time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			chlg := NewChallenge(nil, nil, test.provider,
+				CondOption(test.profile != "", WithPropagationProfile(test.profile)))
+
+			timeout, interval := chlg.getTimeouts("example.com")
+
+			assert.Equal(t, test.expectedTimeout, timeout)
+			assert.Equal(t, test.expectedInterval, interval)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package dns01
+
+import (
+	"errors"
+	"time"
+)
+
+// NameserverStatus is the status of the TXT record on a nameserver, during a propagation check.
+type NameserverStatus struct {
+	// Nameserver is the address of the nameserver (host:port).
+	Nameserver string
+	// Found is true if the nameserver returned the expected TXT record.
+	Found bool
+	// Err is the reason why the record was not found.
+	Err error
+}
+
+// PropagationProgress is the state of the propagation of a TXT record, after a propagation check (see WithPropagationProgress).
+type PropagationProgress struct {
+	Domain string
+	FQDN   string
+
+	// Elapsed is the time elapsed since the start of the propagation wait.
+	Elapsed time.Duration
+	// Attempt is the number of the check, starting at 1.
+	Attempt int
+
+	// Propagated is true if the check succeeded (see also WithPropagationStableChecks).
+	Propagated bool
+	// Err is the error of the check, if any.
+	Err error
+
+	// Nameservers are the statuses of the checked nameservers (recursive, propagation, or authoritative nameservers, depending on the configuration).
+	// Empty if the check doesn't query nameservers (e.g. custom check, see WrapPreCheck).
+	Nameservers []NameserverStatus
+}
+
+// WithPropagationProgress calls fn after each propagation check (in the wait loop), with the intermediate state of the propagation.
+// The function is called synchronously, it must not block.
+func WithPropagationProgress(fn func(p PropagationProgress)) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if fn == nil {
+			return errors.New("propagation progress function is nil")
+		}
+
+		chlg.propagationProgress = fn
+		return nil
+	}
+}
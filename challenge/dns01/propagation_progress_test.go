@@ -0,0 +1,97 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPropagationProgress(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	keyAuth, err := core.GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	fqdn := "_acme-challenge.example.com."
+	records := map[string][]string{fqdn: {getChallengeValue(keyAuth)}}
+
+	setRecursiveNameservers(t, startDNSServer(t, txtHandler(nil)))
+
+	propagated := startDNSServer(t, txtHandler(records))
+
+	// the lagging nameserver returns the record from the third query.
+	var queries atomic.Int32
+	lagging := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		if queries.Add(1) < 3 {
+			txtHandler(nil)(w, req)
+			return
+		}
+
+		txtHandler(records)(w, req)
+	})
+
+	var progress []PropagationProgress
+
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		&providerTimeoutMock{timeout: 5 * time.Second, interval: 10 * time.Millisecond},
+		WithPropagationNameservers([]string{lagging, propagated}),
+		WithPropagationProgress(func(p PropagationProgress) {
+			progress = append(progress, p)
+		}),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	require.Len(t, progress, 3)
+
+	for i, p := range progress {
+		assert.Equal(t, "example.com", p.Domain)
+		assert.Equal(t, fqdn, p.FQDN)
+		assert.Equal(t, i+1, p.Attempt)
+		assert.Positive(t, p.Elapsed)
+
+		require.Len(t, p.Nameservers, 2)
+		assert.Equal(t, lagging, p.Nameservers[0].Nameserver)
+		assert.Equal(t, propagated, p.Nameservers[1].Nameserver)
+
+		// all the nameservers are queried, even after a failure.
+		assert.True(t, p.Nameservers[1].Found)
+	}
+
+	assert.False(t, progress[0].Propagated)
+	assert.False(t, progress[0].Nameservers[0].Found)
+	require.Error(t, progress[0].Nameservers[0].Err)
+	require.Error(t, progress[0].Err)
+
+	assert.False(t, progress[1].Propagated)
+
+	assert.True(t, progress[2].Propagated)
+	assert.True(t, progress[2].Nameservers[0].Found)
+	require.NoError(t, progress[2].Err)
+}
@@ -0,0 +1,121 @@
+package dns01
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/clock"
+)
+
+// Actions of the audit entries.
+const (
+	AuditActionPresent = "present"
+	AuditActionCleanUp = "cleanup"
+)
+
+// AuditEntry is a line of the audit log written by AuditProvider.
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Domain  string    `json:"domain"`
+	FQDN    string    `json:"fqdn"`
+	Value   string    `json:"value"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error,omitempty"`
+	// Prev is the SHA-256 (hex) of the previous line of the log (empty for the first line):
+	// the lines are chained, a modified or removed line breaks the chain.
+	Prev string `json:"prev,omitempty"`
+}
+
+type auditProvider struct {
+	forwarder
+
+	provider challenge.Provider
+	clock    clock.Clock
+
+	mu   sync.Mutex
+	w    io.Writer
+	prev string
+}
+
+// AuditProvider wraps a provider to write an audit entry (see AuditEntry), as a JSON line,
+// for each call to Present and CleanUp: the record (FQDN and value) and the outcome of the call.
+// The entries are chained by their hash (tamper-evident), and the writes are synchronized.
+// A failure to write the entry is returned by Present and CleanUp, joined with the error of the wrapped provider.
+// The options of the records (see PresentOptionsProvider) and the optional interfaces of the wrapped provider
+// are forwarded, except AuthzProvider, BatchProvider and TTLUpdater (see wrapProvider).
+func AuditProvider(p challenge.Provider, w io.Writer) challenge.Provider {
+	return AuditProviderWithClock(p, w, clock.Real)
+}
+
+// AuditProviderWithClock is like AuditProvider, with the clock used for the time of the entries
+// (e.g. a clock.Fake for a reproducible log in tests).
+func AuditProviderWithClock(p challenge.Provider, w io.Writer, clk clock.Clock) challenge.Provider {
+	if clk == nil {
+		clk = clock.Real
+	}
+
+	return wrapProvider(&auditProvider{forwarder: forwarder{origin: p}, provider: p, clock: clk, w: w}, p)
+}
+
+func (a *auditProvider) Present(domain, token, keyAuth string) error {
+	return a.PresentWithOptions(domain, token, keyAuth, PresentOptions{})
+}
+
+func (a *auditProvider) PresentWithOptions(domain, token, keyAuth string, opts PresentOptions) error {
+	err := presentWithOptions(a.provider, domain, token, keyAuth, opts)
+
+	return errors.Join(err, a.write(AuditActionPresent, domain, keyAuth, err))
+}
+
+func (a *auditProvider) CleanUp(domain, token, keyAuth string) error {
+	err := a.provider.CleanUp(domain, token, keyAuth)
+
+	return errors.Join(err, a.write(AuditActionCleanUp, domain, keyAuth, err))
+}
+
+func (a *auditProvider) write(action, domain, keyAuth string, err error) error {
+	info := GetChallengeInfo(domain, keyAuth)
+
+	entry := AuditEntry{
+		Time:    a.clock.Now().UTC(),
+		Action:  action,
+		Domain:  domain,
+		FQDN:    info.EffectiveFQDN,
+		Value:   info.Value,
+		Success: err == nil,
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry.Prev = a.prev
+
+	line, errM := json.Marshal(entry)
+	if errM != nil {
+		return fmt.Errorf("[%s] unable to encode the audit entry: %w", domain, errM)
+	}
+
+	line = append(line, '\n')
+
+	_, errW := a.w.Write(line)
+	if errW != nil {
+		return fmt.Errorf("[%s] unable to write the audit entry: %w", domain, errW)
+	}
+
+	digest := sha256.Sum256(line)
+	a.prev = hex.EncodeToString(digest[:])
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package dns01
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditProvider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	buf := &bytes.Buffer{}
+
+	now := time.Date(2024, time.December, 1, 10, 0, 0, 0, time.UTC)
+
+	provider := AuditProviderWithClock(&providerMock{cleanUp: errors.New("OOPS")}, buf, clock.NewFake(now))
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.EqualError(t, err, "OOPS")
+
+	info := GetChallengeInfo("example.com", "keyAuth")
+
+	var entries []AuditEntry
+	var lines [][]byte
+
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var entry AuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+
+		entries = append(entries, entry)
+		lines = append(lines, append(bytes.Clone(scanner.Bytes()), '\n'))
+	}
+
+	require.NoError(t, scanner.Err())
+	require.Len(t, entries, 2)
+
+	digest := sha256.Sum256(lines[0])
+
+	expected := []AuditEntry{
+		{
+			Time:    now,
+			Action:  AuditActionPresent,
+			Domain:  "example.com",
+			FQDN:    "_acme-challenge.example.com.",
+			Value:   info.Value,
+			Success: true,
+		},
+		{
+			Time:    now,
+			Action:  AuditActionCleanUp,
+			Domain:  "example.com",
+			FQDN:    "_acme-challenge.example.com.",
+			Value:   info.Value,
+			Success: false,
+			Error:   "OOPS",
+			Prev:    hex.EncodeToString(digest[:]),
+		},
+	}
+
+	assert.Equal(t, expected, entries)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestAuditProvider_writeError(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider := AuditProvider(&providerMock{cleanUp: errors.New("OOPS")}, failingWriter{})
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.EqualError(t, err, "[example.com] unable to write the audit entry: disk full")
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.EqualError(t, err, "OOPS\n[example.com] unable to write the audit entry: disk full")
+}
+
+func TestAuditProvider_interfaces(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		provider   challenge.Provider
+		timeout    bool
+		sequential bool
+	}{
+		{
+			desc:     "simple provider",
+			provider: &providerMock{},
+		},
+		{
+			desc:     "provider with timeout",
+			provider: &providerTimeoutMock{timeout: 10 * time.Second, interval: time.Second},
+			timeout:  true,
+		},
+		{
+			desc:       "sequential provider",
+			provider:   &sequentialProviderMock{interval: 3 * time.Second},
+			sequential: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := AuditProvider(test.provider, &bytes.Buffer{})
+
+			pt, ok := provider.(challenge.ProviderTimeout)
+			require.Equal(t, test.timeout, ok)
+
+			if ok {
+				timeout, interval := pt.Timeout()
+				assert.Equal(t, 10*time.Second, timeout)
+				assert.Equal(t, time.Second, interval)
+			}
+
+			ps, ok := provider.(sequential)
+			require.Equal(t, test.sequential, ok)
+
+			if ok {
+				assert.Equal(t, 3*time.Second, ps.Sequential())
+			}
+		})
+	}
+}
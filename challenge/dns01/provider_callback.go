@@ -0,0 +1,44 @@
+package dns01
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// CallbackFunc creates or removes the TXT record of a dns-01 challenge.
+// fqdn is the effective FQDN of the record (after CNAMEs resolution), value is the value of the TXT record.
+type CallbackFunc func(ctx context.Context, domain, fqdn, value string) error
+
+type callbackProvider struct {
+	present CallbackFunc
+	cleanUp CallbackFunc
+}
+
+// CallbackProvider returns a provider delegating the management of the records to functions
+// (e.g. to call an external service able to update the DNS).
+// cleanUp is optional.
+func CallbackProvider(present, cleanUp CallbackFunc) challenge.Provider {
+	return &callbackProvider{present: present, cleanUp: cleanUp}
+}
+
+func (c *callbackProvider) Present(domain, _, keyAuth string) error {
+	if c.present == nil {
+		return errors.New("callback: missing present function")
+	}
+
+	info := GetChallengeInfo(domain, keyAuth)
+
+	return c.present(context.Background(), domain, info.EffectiveFQDN, info.Value)
+}
+
+func (c *callbackProvider) CleanUp(domain, _, keyAuth string) error {
+	if c.cleanUp == nil {
+		return nil
+	}
+
+	info := GetChallengeInfo(domain, keyAuth)
+
+	return c.cleanUp(context.Background(), domain, info.EffectiveFQDN, info.Value)
+}
@@ -0,0 +1,53 @@
+package dns01
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackProvider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	var presented, cleaned []string
+
+	provider := CallbackProvider(
+		func(_ context.Context, domain, fqdn, value string) error {
+			presented = append(presented, domain, fqdn, value)
+			return nil
+		},
+		func(_ context.Context, domain, fqdn, value string) error {
+			cleaned = append(cleaned, domain, fqdn, value)
+			return nil
+		},
+	)
+
+	info := GetChallengeInfo("example.com", "keyAuth")
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	expected := []string{"example.com", "_acme-challenge.example.com.", info.Value}
+
+	assert.Equal(t, expected, presented)
+	assert.Equal(t, expected, cleaned)
+}
+
+func TestCallbackProvider_errors(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider := CallbackProvider(
+		func(_ context.Context, _, _, _ string) error { return errors.New("present") },
+		nil,
+	)
+
+	require.EqualError(t, provider.Present("example.com", "token", "keyAuth"), "present")
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	provider = CallbackProvider(nil, nil)
+
+	require.Error(t, provider.Present("example.com", "token", "keyAuth"))
+}
@@ -0,0 +1,110 @@
+package dns01
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// failoverRecord identifies a record: its effective FQDN and its value.
+type failoverRecord struct {
+	fqdn  string
+	value string
+}
+
+type failoverProvider struct {
+	forwarder
+
+	primary   challenge.Provider
+	secondary challenge.Provider
+
+	mu sync.Mutex
+	// presenters is the provider which has presented each record.
+	presenters map[failoverRecord]challenge.Provider
+}
+
+// FailoverProvider returns a provider creating the records on the primary provider,
+// or on the secondary provider only if the primary fails (e.g. a flaky API).
+// Each record is removed by the provider which has created it.
+// The options of the records (see PresentOptionsProvider) are passed to the provider creating the record,
+// and the record is verified (see ProviderVerify) by this provider.
+// The other optional interfaces are the ones of the primary provider,
+// except AuthzProvider, BatchProvider and TTLUpdater (see wrapProvider).
+func FailoverProvider(primary, secondary challenge.Provider) challenge.Provider {
+	return wrapProvider(&failoverProvider{
+		forwarder:  forwarder{origin: primary},
+		primary:    primary,
+		secondary:  secondary,
+		presenters: make(map[failoverRecord]challenge.Provider),
+	}, primary)
+}
+
+func (f *failoverProvider) Present(domain, token, keyAuth string) error {
+	return f.PresentWithOptions(domain, token, keyAuth, PresentOptions{})
+}
+
+func (f *failoverProvider) PresentWithOptions(domain, token, keyAuth string, opts PresentOptions) error {
+	key := newFailoverRecord(domain, keyAuth)
+
+	errP := presentWithOptions(f.primary, domain, token, keyAuth, opts)
+	if errP == nil {
+		f.setPresenter(key, f.primary)
+		return nil
+	}
+
+	log.Warnf("[%s] failover: primary provider: presenting token: %v, trying the secondary provider", domain, errP)
+
+	errS := presentWithOptions(f.secondary, domain, token, keyAuth, opts)
+	if errS != nil {
+		return errors.Join(fmt.Errorf("primary provider: %w", errP), fmt.Errorf("secondary provider: %w", errS))
+	}
+
+	f.setPresenter(key, f.secondary)
+
+	return nil
+}
+
+func (f *failoverProvider) CleanUp(domain, token, keyAuth string) error {
+	key := newFailoverRecord(domain, keyAuth)
+
+	f.mu.Lock()
+	provider, ok := f.presenters[key]
+	delete(f.presenters, key)
+	f.mu.Unlock()
+
+	if !ok {
+		// unknown record (e.g. presented by another process): the primary provider is the default one.
+		provider = f.primary
+	}
+
+	return provider.CleanUp(domain, token, keyAuth)
+}
+
+// Verify verifies the record with the provider which has created it.
+func (f *failoverProvider) Verify(fqdn, value string) (bool, error) {
+	f.mu.Lock()
+	provider, ok := f.presenters[failoverRecord{fqdn: fqdn, value: value}]
+	f.mu.Unlock()
+
+	if !ok {
+		provider = f.primary
+	}
+
+	return forwarder{origin: provider}.Verify(fqdn, value)
+}
+
+func (f *failoverProvider) setPresenter(key failoverRecord, provider challenge.Provider) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.presenters[key] = provider
+}
+
+func newFailoverRecord(domain, keyAuth string) failoverRecord {
+	info := GetChallengeInfo(domain, keyAuth)
+
+	return failoverRecord{fqdn: info.EffectiveFQDN, value: info.Value}
+}
@@ -0,0 +1,84 @@
+package dns01
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailoverProvider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	primary := &mirrorProviderMock{}
+	secondary := &mirrorProviderMock{}
+
+	provider := FailoverProvider(primary, secondary)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	assert.Equal(t, 1, primary.presented)
+	assert.Equal(t, 1, primary.cleaned)
+	assert.Zero(t, secondary.presented)
+	assert.Zero(t, secondary.cleaned)
+}
+
+func TestFailoverProvider_primaryFailure(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	primary := &mirrorProviderMock{present: errors.New("present")}
+	secondary := &mirrorProviderMock{}
+
+	provider := FailoverProvider(primary, secondary)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.NoError(t, provider.Present("example.org", "token", "keyAuth"))
+
+	// only the records presented by the secondary provider are removed by the secondary provider.
+	primary.present = nil
+	require.NoError(t, provider.Present("example.net", "token", "keyAuth"))
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("example.org", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("example.net", "token", "keyAuth"))
+
+	assert.Equal(t, 3, primary.presented)
+	assert.Equal(t, 1, primary.cleaned)
+	assert.Equal(t, 2, secondary.presented)
+	assert.Equal(t, 2, secondary.cleaned)
+}
+
+func TestFailoverProvider_bothFailures(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	primary := &mirrorProviderMock{present: errors.New("primary present")}
+	secondary := &mirrorProviderMock{present: errors.New("secondary present")}
+
+	provider := FailoverProvider(primary, secondary)
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.EqualError(t, err, "primary provider: primary present\nsecondary provider: secondary present")
+
+	// the record is unknown: the primary provider is used.
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	assert.Equal(t, 1, primary.cleaned)
+	assert.Zero(t, secondary.cleaned)
+}
+
+func TestFailoverProvider_timeout(t *testing.T) {
+	primary := &providerTimeoutMock{timeout: time.Minute, interval: time.Second}
+
+	provider := FailoverProvider(primary, &mirrorProviderMock{})
+
+	pt, ok := provider.(challenge.ProviderTimeout)
+	require.True(t, ok)
+
+	timeout, interval := pt.Timeout()
+	assert.Equal(t, time.Minute, timeout)
+	assert.Equal(t, time.Second, interval)
+}
@@ -0,0 +1,50 @@
+package dns01
+
+import (
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+type mirrorProvider struct {
+	forwarder
+
+	primary   challenge.Provider
+	secondary challenge.Provider
+}
+
+// MirrorProvider wraps a provider to also create the records on a secondary provider (e.g. during a DNS provider migration).
+// Present and CleanUp must succeed on the primary provider,
+// the secondary provider is best-effort: its failures are only logged.
+// The options of the records (see PresentOptionsProvider) are passed to both providers.
+// The other optional interfaces are the ones of the primary provider,
+// except AuthzProvider, BatchProvider and TTLUpdater (see wrapProvider).
+func MirrorProvider(primary, secondary challenge.Provider) challenge.Provider {
+	return wrapProvider(&mirrorProvider{forwarder: forwarder{origin: primary}, primary: primary, secondary: secondary}, primary)
+}
+
+func (m *mirrorProvider) Present(domain, token, keyAuth string) error {
+	return m.PresentWithOptions(domain, token, keyAuth, PresentOptions{})
+}
+
+func (m *mirrorProvider) PresentWithOptions(domain, token, keyAuth string, opts PresentOptions) error {
+	err := presentWithOptions(m.primary, domain, token, keyAuth, opts)
+	if err != nil {
+		return err
+	}
+
+	err = presentWithOptions(m.secondary, domain, token, keyAuth, opts)
+	if err != nil {
+		log.Warnf("[%s] mirror: secondary provider: presenting token: %v", domain, err)
+	}
+
+	return nil
+}
+
+func (m *mirrorProvider) CleanUp(domain, token, keyAuth string) error {
+	err := m.secondary.CleanUp(domain, token, keyAuth)
+	if err != nil {
+		log.Warnf("[%s] mirror: secondary provider: cleaning up: %v", domain, err)
+	}
+
+	return m.primary.CleanUp(domain, token, keyAuth)
+}
@@ -0,0 +1,83 @@
+package dns01
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mirrorProviderMock struct {
+	present, cleanUp error
+
+	presented, cleaned int
+}
+
+func (p *mirrorProviderMock) Present(_, _, _ string) error {
+	p.presented++
+	return p.present
+}
+
+func (p *mirrorProviderMock) CleanUp(_, _, _ string) error {
+	p.cleaned++
+	return p.cleanUp
+}
+
+func TestMirrorProvider(t *testing.T) {
+	primary := &mirrorProviderMock{}
+	secondary := &mirrorProviderMock{}
+
+	provider := MirrorProvider(primary, secondary)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	assert.Equal(t, 1, primary.presented)
+	assert.Equal(t, 1, primary.cleaned)
+	assert.Equal(t, 1, secondary.presented)
+	assert.Equal(t, 1, secondary.cleaned)
+}
+
+func TestMirrorProvider_secondaryFailure(t *testing.T) {
+	primary := &mirrorProviderMock{}
+	secondary := &mirrorProviderMock{present: errors.New("present"), cleanUp: errors.New("cleanUp")}
+
+	provider := MirrorProvider(primary, secondary)
+
+	require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+	require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+	assert.Equal(t, 1, primary.presented)
+	assert.Equal(t, 1, primary.cleaned)
+	assert.Equal(t, 1, secondary.presented)
+	assert.Equal(t, 1, secondary.cleaned)
+}
+
+func TestMirrorProvider_primaryFailure(t *testing.T) {
+	primary := &mirrorProviderMock{present: errors.New("present"), cleanUp: errors.New("cleanUp")}
+	secondary := &mirrorProviderMock{}
+
+	provider := MirrorProvider(primary, secondary)
+
+	require.EqualError(t, provider.Present("example.com", "token", "keyAuth"), "present")
+	require.EqualError(t, provider.CleanUp("example.com", "token", "keyAuth"), "cleanUp")
+
+	assert.Zero(t, secondary.presented)
+	assert.Equal(t, 1, secondary.cleaned)
+}
+
+func TestMirrorProvider_timeout(t *testing.T) {
+	primary := &providerTimeoutMock{timeout: time.Minute, interval: time.Second}
+
+	provider := MirrorProvider(primary, &mirrorProviderMock{})
+
+	pt, ok := provider.(challenge.ProviderTimeout)
+	require.True(t, ok)
+
+	timeout, interval := pt.Timeout()
+	assert.Equal(t, time.Minute, timeout)
+	assert.Equal(t, time.Second, interval)
+}
@@ -0,0 +1,59 @@
+package dns01
+
+import (
+	"context"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"golang.org/x/time/rate"
+)
+
+type rateLimitProvider struct {
+	forwarder
+
+	provider challenge.Provider
+	limiter  *rate.Limiter
+}
+
+// RateLimitProvider wraps a provider to limit the rate of the calls to Present and CleanUp.
+// The limiter is a token bucket (rps tokens per second, up to burst tokens) shared by all the goroutines using the provider.
+// A rps lower than or equal to 0 disables the limit.
+// The options of the records (see PresentOptionsProvider) and the optional interfaces of the wrapped provider
+// are forwarded, except AuthzProvider, BatchProvider and TTLUpdater (see wrapProvider).
+func RateLimitProvider(p challenge.Provider, rps float64, burst int) challenge.Provider {
+	if burst < 1 {
+		burst = 1
+	}
+
+	limit := rate.Limit(rps)
+	if rps <= 0 {
+		limit = rate.Inf
+	}
+
+	return wrapProvider(&rateLimitProvider{
+		forwarder: forwarder{origin: p},
+		provider:  p,
+		limiter:   rate.NewLimiter(limit, burst),
+	}, p)
+}
+
+func (r *rateLimitProvider) Present(domain, token, keyAuth string) error {
+	return r.PresentWithOptions(domain, token, keyAuth, PresentOptions{})
+}
+
+func (r *rateLimitProvider) PresentWithOptions(domain, token, keyAuth string, opts PresentOptions) error {
+	err := r.limiter.Wait(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return presentWithOptions(r.provider, domain, token, keyAuth, opts)
+}
+
+func (r *rateLimitProvider) CleanUp(domain, token, keyAuth string) error {
+	err := r.limiter.Wait(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return r.provider.CleanUp(domain, token, keyAuth)
+}
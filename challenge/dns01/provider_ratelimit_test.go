@@ -0,0 +1,129 @@
+package dns01
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingProvider struct {
+	present atomic.Int32
+	cleanUp atomic.Int32
+}
+
+func (p *countingProvider) Present(_, _, _ string) error {
+	p.present.Add(1)
+	return nil
+}
+
+func (p *countingProvider) CleanUp(_, _, _ string) error {
+	p.cleanUp.Add(1)
+	return nil
+}
+
+type sequentialProviderMock struct {
+	providerMock
+	interval time.Duration
+}
+
+func (p *sequentialProviderMock) Sequential() time.Duration { return p.interval }
+
+func TestRateLimitProvider(t *testing.T) {
+	counter := &countingProvider{}
+
+	provider := RateLimitProvider(counter, 20, 1)
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+		}()
+
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+		}()
+	}
+
+	wg.Wait()
+
+	// 10 calls at 20 calls per second with a burst of 1: at least 9 intervals of 50ms.
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	assert.EqualValues(t, 5, counter.present.Load())
+	assert.EqualValues(t, 5, counter.cleanUp.Load())
+}
+
+func TestRateLimitProvider_unlimited(t *testing.T) {
+	for _, rps := range []float64{0, -1} {
+		counter := &countingProvider{}
+
+		provider := RateLimitProvider(counter, rps, 1)
+
+		for range 10 {
+			require.NoError(t, provider.Present("example.com", "token", "keyAuth"))
+			require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+		}
+
+		assert.EqualValues(t, 10, counter.present.Load())
+		assert.EqualValues(t, 10, counter.cleanUp.Load())
+	}
+}
+
+func TestRateLimitProvider_interfaces(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		provider   challenge.Provider
+		timeout    bool
+		sequential bool
+	}{
+		{
+			desc:     "simple provider",
+			provider: &providerMock{},
+		},
+		{
+			desc:     "provider with timeout",
+			provider: &providerTimeoutMock{timeout: 10 * time.Second, interval: time.Second},
+			timeout:  true,
+		},
+		{
+			desc:       "sequential provider",
+			provider:   &sequentialProviderMock{interval: 3 * time.Second},
+			sequential: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := RateLimitProvider(test.provider, 10, 1)
+
+			pt, ok := provider.(challenge.ProviderTimeout)
+			require.Equal(t, test.timeout, ok)
+
+			if ok {
+				timeout, interval := pt.Timeout()
+				assert.Equal(t, 10*time.Second, timeout)
+				assert.Equal(t, time.Second, interval)
+			}
+
+			ps, ok := provider.(sequential)
+			require.Equal(t, test.sequential, ok)
+
+			if ok {
+				assert.Equal(t, 3*time.Second, ps.Sequential())
+			}
+		})
+	}
+}
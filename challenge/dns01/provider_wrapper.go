@@ -0,0 +1,98 @@
+package dns01
+
+import (
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// forwardingProvider is a provider wrapper forwarding the optional interfaces to the wrapped provider:
+// PresentWithOptions goes through the logic of the wrapper,
+// the other methods are answered by the wrapped provider (see forwarder).
+type forwardingProvider interface {
+	PresentOptionsProvider
+	ProviderVerify
+	challenge.ProviderCapabilities
+	NameserversProvider
+}
+
+// wrapProvider returns a provider delegating to wrapper,
+// and exposing the optional interfaces (challenge.ProviderTimeout, sequential) implemented by origin.
+//
+// AuthzProvider, BatchProvider and TTLUpdater are not forwarded:
+// the records are created one by one through PresentWithOptions, and their TTL is not updated.
+func wrapProvider(wrapper forwardingProvider, origin challenge.Provider) challenge.Provider {
+	pt, hasTimeout := origin.(challenge.ProviderTimeout)
+	ps, isSequential := origin.(sequential)
+
+	switch {
+	case hasTimeout && isSequential:
+		return &timeoutSequentialWrapper{forwardingProvider: wrapper, timeout: pt, sequential: ps}
+	case hasTimeout:
+		return &timeoutWrapper{forwardingProvider: wrapper, timeout: pt}
+	case isSequential:
+		return &sequentialWrapper{forwardingProvider: wrapper, sequential: ps}
+	default:
+		return wrapper
+	}
+}
+
+// forwarder answers the optional interfaces of a wrapper with the ones of the wrapped provider,
+// or with neutral values if the wrapped provider doesn't implement them.
+type forwarder struct {
+	origin challenge.Provider
+}
+
+// Verify reports the record as created if the wrapped provider doesn't implement ProviderVerify.
+func (f forwarder) Verify(fqdn, value string) (bool, error) {
+	if p, ok := f.origin.(ProviderVerify); ok {
+		return p.Verify(fqdn, value)
+	}
+
+	return true, nil
+}
+
+func (f forwarder) SupportsCleanUp() bool {
+	return supportsCleanUp(f.origin)
+}
+
+// Nameservers returns nil if the wrapped provider doesn't implement NameserversProvider.
+func (f forwarder) Nameservers() []string {
+	if p, ok := f.origin.(NameserversProvider); ok {
+		return p.Nameservers()
+	}
+
+	return nil
+}
+
+type timeoutWrapper struct {
+	forwardingProvider
+	timeout challenge.ProviderTimeout
+}
+
+func (w *timeoutWrapper) Timeout() (timeout, interval time.Duration) {
+	return w.timeout.Timeout()
+}
+
+type sequentialWrapper struct {
+	forwardingProvider
+	sequential sequential
+}
+
+func (w *sequentialWrapper) Sequential() time.Duration {
+	return w.sequential.Sequential()
+}
+
+type timeoutSequentialWrapper struct {
+	forwardingProvider
+	timeout    challenge.ProviderTimeout
+	sequential sequential
+}
+
+func (w *timeoutSequentialWrapper) Timeout() (timeout, interval time.Duration) {
+	return w.timeout.Timeout()
+}
+
+func (w *timeoutSequentialWrapper) Sequential() time.Duration {
+	return w.sequential.Sequential()
+}
@@ -0,0 +1,128 @@
+package dns01
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// optionalProviderMock is a provider implementing all the optional interfaces.
+type optionalProviderMock struct {
+	providerTimeoutMock
+
+	options  []PresentOptions
+	verified []string
+}
+
+func (p *optionalProviderMock) PresentWithOptions(_, _, _ string, opts PresentOptions) error {
+	p.options = append(p.options, opts)
+	return nil
+}
+
+func (p *optionalProviderMock) Verify(fqdn, _ string) (bool, error) {
+	p.verified = append(p.verified, fqdn)
+	return false, nil
+}
+
+func (p *optionalProviderMock) SupportsCleanUp() bool { return false }
+
+func (p *optionalProviderMock) Nameservers() []string { return []string{"192.0.2.1:53"} }
+
+func (p *optionalProviderMock) PresentAuthz(_ acme.Authorization, _ ChallengeInfo) error { return nil }
+
+func (p *optionalProviderMock) PresentBatch(_ []Record) error { return nil }
+
+func (p *optionalProviderMock) CleanUpBatch(_ []Record) error { return nil }
+
+func Test_wrapProvider_optionalInterfaces(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	testCases := []struct {
+		desc string
+		wrap func(p challenge.Provider) challenge.Provider
+	}{
+		{
+			desc: "rate limit",
+			wrap: func(p challenge.Provider) challenge.Provider { return RateLimitProvider(p, 0, 1) },
+		},
+		{
+			desc: "mirror",
+			wrap: func(p challenge.Provider) challenge.Provider { return MirrorProvider(p, &providerMock{}) },
+		},
+		{
+			desc: "failover",
+			wrap: func(p challenge.Provider) challenge.Provider { return FailoverProvider(p, &providerMock{}) },
+		},
+		{
+			desc: "audit",
+			wrap: func(p challenge.Provider) challenge.Provider { return AuditProvider(p, io.Discard) },
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			origin := &optionalProviderMock{providerTimeoutMock: providerTimeoutMock{timeout: time.Minute, interval: time.Second}}
+
+			provider := test.wrap(origin)
+
+			// forwarded.
+			optionsProvider, ok := provider.(PresentOptionsProvider)
+			require.True(t, ok)
+
+			opts := PresentOptions{Attempt: 2, Comment: "lego"}
+
+			require.NoError(t, optionsProvider.PresentWithOptions("example.com", "token", "keyAuth", opts))
+			assert.Equal(t, []PresentOptions{opts}, origin.options)
+
+			verifier, ok := provider.(ProviderVerify)
+			require.True(t, ok)
+
+			info := GetChallengeInfo("example.com", "keyAuth")
+
+			found, err := verifier.Verify(info.EffectiveFQDN, info.Value)
+			require.NoError(t, err)
+
+			assert.False(t, found)
+			assert.Equal(t, []string{info.EffectiveFQDN}, origin.verified)
+
+			assert.False(t, supportsCleanUp(provider))
+
+			nameservers, ok := provider.(NameserversProvider)
+			require.True(t, ok)
+			assert.Equal(t, []string{"192.0.2.1:53"}, nameservers.Nameservers())
+
+			timeout, ok := provider.(challenge.ProviderTimeout)
+			require.True(t, ok)
+
+			actualTimeout, actualInterval := timeout.Timeout()
+			assert.Equal(t, time.Minute, actualTimeout)
+			assert.Equal(t, time.Second, actualInterval)
+
+			// not forwarded: the records are created one by one through PresentWithOptions.
+			_, ok = provider.(AuthzProvider)
+			assert.False(t, ok)
+
+			_, ok = provider.(BatchProvider)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func Test_wrapProvider_neutral(t *testing.T) {
+	provider := RateLimitProvider(&providerMock{}, 0, 1)
+
+	found, err := provider.(ProviderVerify).Verify("_acme-challenge.example.com.", "value")
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	assert.True(t, supportsCleanUp(provider))
+	assert.Nil(t, provider.(NameserversProvider).Nameservers())
+
+	_, ok := provider.(challenge.ProviderTimeout)
+	assert.False(t, ok)
+}
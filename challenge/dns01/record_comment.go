@@ -0,0 +1,28 @@
+package dns01
+
+import "errors"
+
+// WithRecordComment defines the comment attached to the TXT records (e.g. a label and the order),
+// to identify the records created by lego in a shared zone.
+// The function receives the domain and the effective FQDN of the record.
+// The comment is passed to the providers implementing PresentOptionsProvider,
+// and to the providers implementing BatchProvider (see Record), the other providers ignore it.
+func WithRecordComment(comment func(domain, fqdn string) string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if comment == nil {
+			return errors.New("record comment function is nil")
+		}
+
+		chlg.recordComment = comment
+		return nil
+	}
+}
+
+// getRecordComment returns the comment of the record, or an empty string if no comment is defined.
+func (c *Challenge) getRecordComment(domain, keyAuth string) string {
+	if c.recordComment == nil {
+		return ""
+	}
+
+	return c.recordComment(domain, c.getChallengeInfo(domain, keyAuth).EffectiveFQDN)
+}
@@ -0,0 +1,135 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type optionsProviderMock struct {
+	providerMock
+
+	presented bool
+	options   []PresentOptions
+}
+
+func (p *optionsProviderMock) Present(_, _, _ string) error {
+	p.presented = true
+	return nil
+}
+
+func (p *optionsProviderMock) PresentWithOptions(_, _, _ string, opts PresentOptions) error {
+	p.options = append(p.options, opts)
+	return nil
+}
+
+func TestWithRecordComment(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	comment := func(domain, fqdn string) string {
+		return "lego order-42 " + domain + " " + fqdn
+	}
+
+	testCases := []struct {
+		desc     string
+		opts     []ChallengeOption
+		expected []PresentOptions
+	}{
+		{
+			desc:     "with comment",
+			opts:     []ChallengeOption{WithRecordComment(comment)},
+			expected: []PresentOptions{{Attempt: 1, Comment: "lego order-42 example.com _acme-challenge.example.com."}},
+		},
+		{
+			desc:     "without comment",
+			expected: []PresentOptions{{Attempt: 1}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider := &optionsProviderMock{}
+
+			chlg := NewChallenge(core, nil, provider, test.opts...)
+
+			err := chlg.PreSolve(authz)
+			require.NoError(t, err)
+
+			assert.False(t, provider.presented)
+			assert.Equal(t, test.expected, provider.options)
+		})
+	}
+}
+
+func TestWithRecordComment_unsupported(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	// the comment is ignored by the providers without comment support.
+	chlg := NewChallenge(core, nil, &providerMock{}, WithRecordComment(func(_, _ string) string { return "lego" }))
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+}
+
+func TestWithRecordComment_presentRetry(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	provider := &attemptProviderMock{failures: 1}
+
+	chlg := NewChallenge(core, nil, provider,
+		WithRecordComment(func(_, _ string) string { return "lego" }),
+		WithPresentRetry(2, 0))
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+
+	// each attempt receives both the attempt number and the comment.
+	expected := []PresentOptions{{Attempt: 1, Comment: "lego"}, {Attempt: 2, Comment: "lego"}}
+
+	assert.Equal(t, expected, provider.options)
+}
@@ -0,0 +1,48 @@
+package dns01
+
+import (
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// RecordInfo describes a record as created by a DNS provider.
+type RecordInfo struct {
+	// Name is the FQDN of the record.
+	Name  string
+	Type  string
+	Value string
+	// ID is the identifier of the record in the API of the DNS provider (if any).
+	ID string
+}
+
+// ProviderRecordInfo is a provider able to report the record it has just created.
+// Unlike ProviderVerify, the record is the provider's own view of what it wrote (not a lookup through its API):
+// it helps to diagnose a mismatch between the FQDN expected by lego and the one derived by the provider.
+type ProviderRecordInfo interface {
+	challenge.Provider
+	// LastRecord returns the record created by the last call to Present, false if there is none.
+	LastRecord() (RecordInfo, bool)
+}
+
+// logRecordInfo logs the record reported by the provider, if it implements ProviderRecordInfo,
+// and warns when the record doesn't match the challenge.
+func logRecordInfo(domain string, provider challenge.Provider, info ChallengeInfo) {
+	reporter, ok := provider.(ProviderRecordInfo)
+	if !ok {
+		return
+	}
+
+	record, ok := reporter.LastRecord()
+	if !ok {
+		log.Infof("[%s] acme: the DNS provider doesn't report any created record", domain)
+		return
+	}
+
+	log.Infof("[%s] acme: the DNS provider has created the record [name: %s, type: %s, value: %s, id: %s]",
+		domain, record.Name, record.Type, record.Value, record.ID)
+
+	if ToFqdn(record.Name) != info.EffectiveFQDN {
+		log.Warnf("[%s] acme: the record created by the DNS provider (%s) doesn't match the challenge FQDN (%s)",
+			domain, record.Name, info.EffectiveFQDN)
+	}
+}
@@ -0,0 +1,112 @@
+package dns01
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	stdlog "log"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordInfoProviderMock creates the record on a name derived by the provider itself.
+type recordInfoProviderMock struct {
+	providerMock
+
+	name func(domain string) string
+
+	last *RecordInfo
+}
+
+func (p *recordInfoProviderMock) Present(domain, _, keyAuth string) error {
+	info := GetChallengeInfo(domain, keyAuth)
+
+	p.last = &RecordInfo{Name: p.name(domain), Type: "TXT", Value: info.Value, ID: "42"}
+
+	return nil
+}
+
+func (p *recordInfoProviderMock) LastRecord() (RecordInfo, bool) {
+	if p.last == nil {
+		return RecordInfo{}, false
+	}
+
+	return *p.last, true
+}
+
+func TestChallenge_PreSolve_recordInfo(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	keyAuth, err := core.GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	info := GetChallengeInfo("example.com", keyAuth)
+
+	testCases := []struct {
+		desc     string
+		name     func(domain string) string
+		mismatch bool
+	}{
+		{
+			desc: "matching record",
+			name: func(domain string) string { return "_acme-challenge." + domain },
+		},
+		{
+			desc:     "FQDN mismatch",
+			name:     func(domain string) string { return "_acme-challenge." + domain + ".example.com" },
+			mismatch: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			backupLogger := log.Logger
+			t.Cleanup(func() { log.Logger = backupLogger })
+
+			buf := &bytes.Buffer{}
+			log.Logger = stdlog.New(buf, "", 0)
+
+			provider := &recordInfoProviderMock{name: test.name}
+
+			chlg := NewChallenge(core, nil, provider)
+
+			err := chlg.PreSolve(authz)
+			require.NoError(t, err)
+
+			record, ok := provider.LastRecord()
+			require.True(t, ok)
+
+			assert.Equal(t, info.Value, record.Value)
+			assert.Contains(t, buf.String(), "[example.com] acme: the DNS provider has created the record [name: "+record.Name+", type: TXT, value: "+info.Value+", id: 42]")
+
+			if test.mismatch {
+				assert.NotEqual(t, info.EffectiveFQDN, ToFqdn(record.Name))
+				assert.Contains(t, buf.String(), "doesn't match the challenge FQDN (_acme-challenge.example.com.)")
+			} else {
+				assert.Equal(t, info.EffectiveFQDN, ToFqdn(record.Name))
+				assert.NotContains(t, buf.String(), "doesn't match the challenge FQDN")
+			}
+		})
+	}
+}
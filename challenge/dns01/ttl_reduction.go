@@ -0,0 +1,74 @@
+package dns01
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// TTLUpdater is a provider able to update the TTL of the record it created (see WithTTLReduction).
+type TTLUpdater interface {
+	challenge.Provider
+	UpdateTTL(domain, token, keyAuth string, ttl int) error
+}
+
+type ttlReduction struct {
+	// fraction of the propagation timeout after which the TTL is reduced.
+	fraction float64
+	ttl      int
+}
+
+// WithTTLReduction lowers the TTL of the record (to ttl seconds) when the propagation is not complete
+// after a fraction (between 0 and 1) of the propagation timeout,
+// to encourage the resolvers (and the validators) to refresh a stale cached answer.
+// The TTL is reduced only once, and only by the providers implementing TTLUpdater: the other providers just wait.
+func WithTTLReduction(fraction float64, ttl int) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if fraction <= 0 || fraction >= 1 {
+			return fmt.Errorf("invalid TTL reduction threshold: %v (must be between 0 and 1)", fraction)
+		}
+
+		if ttl <= 0 {
+			return fmt.Errorf("invalid reduced TTL: %d", ttl)
+		}
+
+		chlg.ttlReduction = &ttlReduction{fraction: fraction, ttl: ttl}
+
+		return nil
+	}
+}
+
+// reduceTTL lowers the TTL of the record if the threshold is reached.
+// It returns true when no reduction is (or will be) attempted anymore.
+func (c *Challenge) reduceTTL(domain, token, keyAuth string, elapsed, timeout time.Duration) bool {
+	if c.ttlReduction == nil {
+		return true
+	}
+
+	if elapsed < time.Duration(c.ttlReduction.fraction*float64(timeout)) {
+		return false
+	}
+
+	provider, ok := c.getProvider(domain).(TTLUpdater)
+	if !ok {
+		return true
+	}
+
+	if _, delegated := c.getDelegatedRecord(domain, keyAuth); delegated {
+		return true
+	}
+
+	log.Infof("[%s] acme: DNS record propagation not complete after %s, reducing the TTL of the record to %d.",
+		domain, elapsed.Round(time.Second), c.ttlReduction.ttl)
+
+	err := c.withRecord(domain, c.getChallengeInfo(domain, keyAuth), func() error {
+		return provider.UpdateTTL(domain, token, keyAuth, c.ttlReduction.ttl)
+	})
+	if err != nil {
+		log.Warnf("[%s] acme: failed to reduce the TTL of the record: %v", domain, err)
+	}
+
+	return true
+}
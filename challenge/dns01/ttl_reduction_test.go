@@ -0,0 +1,143 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ttlUpdaterMock struct {
+	providerTimeoutMock
+
+	mu      sync.Mutex
+	updates []int
+	at      []time.Time
+}
+
+func (p *ttlUpdaterMock) UpdateTTL(_, _, _ string, ttl int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.updates = append(p.updates, ttl)
+	p.at = append(p.at, time.Now())
+
+	return nil
+}
+
+func (p *ttlUpdaterMock) updated() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.updates) > 0
+}
+
+func TestWithTTLReduction(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &ttlUpdaterMock{
+		providerTimeoutMock: providerTimeoutMock{timeout: 2 * time.Second, interval: 10 * time.Millisecond},
+	}
+
+	// the record propagates only once the TTL has been reduced.
+	preCheck := func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+		return provider.updated(), nil
+	}
+
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		provider,
+		WrapPreCheck(preCheck),
+		WithTTLReduction(0.25, 60),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	start := time.Now()
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	assert.Equal(t, []int{60}, provider.updates)
+	require.Len(t, provider.at, 1)
+	assert.GreaterOrEqual(t, provider.at[0].Sub(start), 500*time.Millisecond)
+}
+
+func TestWithTTLReduction_notTTLUpdater(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	var checks int
+
+	preCheck := func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+		checks++
+		return checks == 5, nil
+	}
+
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		&providerTimeoutMock{timeout: time.Second, interval: 10 * time.Millisecond},
+		WrapPreCheck(preCheck),
+		WithTTLReduction(0.01, 60),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, checks)
+}
+
+func TestWithTTLReduction_invalid(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		fraction float64
+		ttl      int
+		expected string
+	}{
+		{desc: "zero fraction", fraction: 0, ttl: 60, expected: "invalid TTL reduction threshold: 0 (must be between 0 and 1)"},
+		{desc: "whole timeout", fraction: 1, ttl: 60, expected: "invalid TTL reduction threshold: 1 (must be between 0 and 1)"},
+		{desc: "zero TTL", fraction: 0.5, ttl: 0, expected: "invalid reduced TTL: 0"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := WithTTLReduction(test.fraction, test.ttl)(&Challenge{})
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
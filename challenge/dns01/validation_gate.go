@@ -0,0 +1,45 @@
+package dns01
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// ValidationGateFunc blocks until the validation of the challenge is approved, or returns an error to abort it.
+// fqdn is the effective FQDN of the record (after CNAMEs resolution), value is the value of the TXT record.
+type ValidationGateFunc func(domain, fqdn, value string) error
+
+// WithValidationGate defines an approval gate (e.g. a change-management hold),
+// called after the propagation of the record and before requesting the validation to the ACME server.
+// The validation is requested only when the gate returns without error.
+func WithValidationGate(gate ValidationGateFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if gate == nil {
+			return errors.New("validation gate is nil")
+		}
+
+		chlg.validationGate = gate
+
+		return nil
+	}
+}
+
+// waitValidationGate waits for the approval of the validation, if a gate is defined.
+func (c *Challenge) waitValidationGate(domain string, info ChallengeInfo) error {
+	if c.validationGate == nil {
+		return nil
+	}
+
+	log.Infof("[%s] acme: Waiting for the approval of the validation.", domain)
+
+	err := c.validationGate(domain, info.EffectiveFQDN, info.Value)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: validation not approved: %w", domain, err)
+	}
+
+	log.Infof("[%s] acme: Validation approved.", domain)
+
+	return nil
+}
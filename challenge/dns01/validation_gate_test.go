@@ -0,0 +1,108 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithValidationGate(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	var approvedAt, validatedAt time.Time
+	var gateArgs []string
+
+	gate := func(domain, fqdn, value string) error {
+		gateArgs = []string{domain, fqdn, value}
+
+		time.Sleep(200 * time.Millisecond)
+		approvedAt = time.Now()
+
+		return nil
+	}
+
+	validate := func(_ *api.Core, _ string, _ acme.Challenge) error {
+		validatedAt = time.Now()
+		return nil
+	}
+
+	chlg := NewChallenge(core, validate,
+		&providerTimeoutMock{timeout: time.Second, interval: time.Millisecond},
+		WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) { return true, nil }),
+		WithValidationGate(gate),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	require.Len(t, gateArgs, 3)
+	assert.Equal(t, "example.com", gateArgs[0])
+	assert.Equal(t, "_acme-challenge.example.com.", gateArgs[1])
+	assert.NotEmpty(t, gateArgs[2])
+
+	require.False(t, validatedAt.IsZero())
+	assert.False(t, validatedAt.Before(approvedAt), "the validation has been requested before the approval")
+}
+
+func TestWithValidationGate_rejected(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	var validated bool
+
+	validate := func(_ *api.Core, _ string, _ acme.Challenge) error {
+		validated = true
+		return nil
+	}
+
+	chlg := NewChallenge(core, validate,
+		&providerTimeoutMock{timeout: time.Second, interval: time.Millisecond},
+		WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) { return true, nil }),
+		WithValidationGate(func(_, _, _ string) error { return errors.New("change request CR-42 rejected") }),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.Solve(authz)
+	require.EqualError(t, err, "[example.com] acme: validation not approved: change request CR-42 rejected")
+
+	assert.False(t, validated)
+}
+
+func TestWithValidationGate_nil(t *testing.T) {
+	err := WithValidationGate(nil)(&Challenge{})
+	require.EqualError(t, err, "validation gate is nil")
+}
@@ -0,0 +1,120 @@
+package dns01
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// WithZoneForDomain defines, per domain, the zone to use instead of the zone found by the SOA lookup
+// (see FindZoneByFqdn), for the DNS providers unable to discover the zone, or when the zone resolution is ambiguous.
+// The keys are the domains (without the wildcard prefix), the values are the zones: the domains themselves or one of their parents.
+// The zone is used for the domain and its subdomains (e.g. `_acme-challenge.<domain>`),
+// the other domains (and the CNAME targets outside the domain) use the SOA lookup.
+// The zone is also returned by FindZoneByFqdn to the DNS provider creating or removing the record of the challenge.
+func WithZoneForDomain(mapping map[string]string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if len(mapping) == 0 {
+			return errors.New("empty zone mapping")
+		}
+
+		overrides := make(map[string]string, len(mapping))
+
+		for domain, zone := range mapping {
+			domain = strings.ToLower(UnFqdn(strings.TrimPrefix(domain, "*.")))
+			zone = strings.ToLower(UnFqdn(zone))
+
+			if domain == "" || zone == "" {
+				return fmt.Errorf("invalid zone mapping: %q: %q", domain, zone)
+			}
+
+			if domain != zone && !strings.HasSuffix(domain, "."+zone) {
+				return fmt.Errorf("the zone %s is not a parent of %s", zone, domain)
+			}
+
+			overrides[domain] = ToFqdn(zone)
+		}
+
+		chlg.zoneOverrides = overrides
+
+		return nil
+	}
+}
+
+// zoneOverride returns the zone defined by WithZoneForDomain for the FQDN,
+// if the record of the FQDN is being created or removed by a challenge.
+func zoneOverride(fqdn string) (string, bool) {
+	chlg, ok := activeRecords.get(fqdnKey(fqdn))
+	if !ok {
+		return "", false
+	}
+
+	return chlg.zoneOverride(fqdn)
+}
+
+// zoneOverride returns the zone defined by WithZoneForDomain for the FQDN (the zone of the most specific domain).
+func (c *Challenge) zoneOverride(fqdn string) (string, bool) {
+	name := strings.ToLower(UnFqdn(fqdn))
+
+	var match, zone string
+
+	for domain, z := range c.zoneOverrides {
+		if name != domain && !strings.HasSuffix(name, "."+domain) {
+			continue
+		}
+
+		if len(domain) > len(match) {
+			match, zone = domain, z
+		}
+	}
+
+	return zone, zone != ""
+}
+
+// WithZoneCheck checks that the zone of the record exists (see FindZoneByFqdn) before the creation of the record,
+// and fails immediately with a ZoneNotFoundError if it doesn't,
+// instead of relying on the provider (some providers create the record at the wrong place, or do nothing)
+// and reaching the propagation timeout.
+// The zones not visible to the recursive nameservers (e.g. private zones) must be defined with WithZoneForDomain.
+func WithZoneCheck() ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.zoneCheck = true
+		return nil
+	}
+}
+
+// checkZone checks that the zone of the record exists, if enabled.
+func (c *Challenge) checkZone(domain, keyAuth string) error {
+	if !c.zoneCheck {
+		return nil
+	}
+
+	return c.checkRecordZone(domain, c.getChallengeInfo(domain, keyAuth).EffectiveFQDN)
+}
+
+// checkRecordZone is like checkZone for the FQDN of a record.
+func (c *Challenge) checkRecordZone(domain, fqdn string) error {
+	if !c.zoneCheck {
+		return nil
+	}
+
+	if _, ok := c.zoneOverride(fqdn); ok {
+		return nil
+	}
+
+	_, err := FindZoneByFqdnCustom(fqdn, filterNameservers(c.preCheck.recursiveNameservers(), c.preCheck.family))
+
+	var zoneErr *ZoneNotFoundError
+	if errors.As(err, &zoneErr) {
+		return fmt.Errorf("zone check: %w", err)
+	}
+
+	if err != nil {
+		// the lookup failed (e.g. unreachable nameservers): the provider is called anyway.
+		log.Warnf("[%s] acme: zone check: %v", domain, err)
+	}
+
+	return nil
+}
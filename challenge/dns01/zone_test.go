@@ -0,0 +1,265 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zoneProviderMock is a provider which finds the zone of the record, like most of the DNS providers.
+type zoneProviderMock struct {
+	zones map[string]string
+	calls int
+}
+
+func (p *zoneProviderMock) Present(domain, _, keyAuth string) error {
+	p.calls++
+
+	info := GetChallengeInfo(domain, keyAuth)
+
+	zone, err := FindZoneByFqdn(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("could not find zone: %w", err)
+	}
+
+	p.zones[domain] = zone
+
+	return nil
+}
+
+func (p *zoneProviderMock) CleanUp(_, _, _ string) error { return nil }
+
+func TestWithZoneForDomain_provider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	ClearFqdnCache()
+	t.Cleanup(ClearFqdnCache)
+
+	// the SOA lookup finds the zone "sub.example.com." for the unlisted domains.
+	setRecursiveNameservers(t, startDNSServer(t, soaHandler("sub.example.com.")))
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &zoneProviderMock{zones: map[string]string{}}
+
+	chlg := NewChallenge(core, nil, provider, WithZoneForDomain(map[string]string{"a.sub.example.com": "example.com"}))
+
+	// another challenge, with another mapping, doesn't interfere.
+	_ = NewChallenge(core, nil, provider, WithZoneForDomain(map[string]string{"a.sub.example.com": "sub.example.com"}))
+
+	for _, domain := range []string{"a.sub.example.com", "b.sub.example.com"} {
+		authz := acme.Authorization{
+			Identifier: acme.Identifier{Value: domain},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+		}
+
+		err = chlg.PreSolve(authz)
+		require.NoError(t, err)
+	}
+
+	expected := map[string]string{
+		"a.sub.example.com": "example.com.",
+		"b.sub.example.com": "sub.example.com.",
+	}
+
+	assert.Equal(t, expected, provider.zones)
+
+	// outside the creation of the record, the zone is found by the SOA lookup.
+	zone, err := FindZoneByFqdn("_acme-challenge.a.sub.example.com.")
+	require.NoError(t, err)
+
+	assert.Equal(t, "sub.example.com.", zone)
+}
+
+func TestFindZoneByFqdn_zoneForDomain(t *testing.T) {
+	ClearFqdnCache()
+	t.Cleanup(ClearFqdnCache)
+
+	var queries atomic.Int32
+
+	handler := soaHandler("example.com.", "example.org.")
+
+	setRecursiveNameservers(t, startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		queries.Add(1)
+		handler(w, req)
+	}))
+
+	chlg := NewChallenge(nil, nil, nil, WithZoneForDomain(map[string]string{
+		"*.example.com":     "example.com.",
+		"a.sub.example.com": "SUB.example.com",
+	}))
+
+	testCases := []struct {
+		fqdn     string
+		expected string
+	}{
+		{fqdn: "_acme-challenge.example.com.", expected: "example.com."},
+		{fqdn: "_acme-challenge.www.example.com.", expected: "example.com."},
+		{fqdn: "_acme-challenge.a.sub.example.com.", expected: "sub.example.com."},
+		{fqdn: "_Acme-Challenge.A.Sub.Example.com.", expected: "sub.example.com."},
+	}
+
+	for _, test := range testCases {
+		err := chlg.withRecords([]Record{{Domain: "example.com", FQDN: test.fqdn}}, func() error {
+			zone, err := FindZoneByFqdn(test.fqdn)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, zone, test.fqdn)
+
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Zero(t, queries.Load())
+
+	// unlisted domain: SOA lookup.
+	err := chlg.withRecords([]Record{{Domain: "example.org", FQDN: "_acme-challenge.example.org."}}, func() error {
+		zone, err := FindZoneByFqdn("_acme-challenge.example.org.")
+		require.NoError(t, err)
+
+		assert.Equal(t, "example.org.", zone)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.NotZero(t, queries.Load())
+}
+
+func TestWithZoneForDomain_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		mapping  map[string]string
+		expected string
+	}{
+		{
+			desc:     "empty",
+			mapping:  map[string]string{},
+			expected: "empty zone mapping",
+		},
+		{
+			desc:     "empty zone",
+			mapping:  map[string]string{"example.com": ""},
+			expected: `invalid zone mapping: "example.com": ""`,
+		},
+		{
+			desc:     "not a parent",
+			mapping:  map[string]string{"a.example.com": "example.org"},
+			expected: "the zone example.org is not a parent of a.example.com",
+		},
+		{
+			desc:     "suffix but not a parent",
+			mapping:  map[string]string{"myexample.com": "example.com"},
+			expected: "the zone example.com is not a parent of myexample.com",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := WithZoneForDomain(test.mapping)(&Challenge{})
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestFindZoneByFqdn_zoneNotFound(t *testing.T) {
+	ClearFqdnCache()
+	t.Cleanup(ClearFqdnCache)
+
+	setRecursiveNameservers(t, startDNSServer(t, soaHandler("example.org.")))
+
+	_, err := FindZoneByFqdn("_acme-challenge.example.com.")
+	require.Error(t, err)
+
+	var zoneErr *ZoneNotFoundError
+	require.ErrorAs(t, err, &zoneErr)
+
+	assert.Equal(t, "_acme-challenge.example.com.", zoneErr.FQDN)
+	assert.Contains(t, err.Error(), "could not find the start of authority for '_acme-challenge.example.com.'")
+}
+
+func TestFindZoneByFqdn_lookupError(t *testing.T) {
+	ClearFqdnCache()
+	t.Cleanup(ClearFqdnCache)
+
+	setRecursiveNameservers(t)
+
+	_, err := FindZoneByFqdn("_acme-challenge.example.com.")
+	require.Error(t, err)
+
+	var zoneErr *ZoneNotFoundError
+	assert.NotErrorAs(t, err, &zoneErr)
+}
+
+func TestChallenge_PreSolve_zoneNotFound(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	ClearFqdnCache()
+	t.Cleanup(ClearFqdnCache)
+
+	setRecursiveNameservers(t, startDNSServer(t, soaHandler("example.org.")))
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	t.Run("provider using FindZoneByFqdn", func(t *testing.T) {
+		provider := &zoneProviderMock{zones: map[string]string{}}
+
+		// the retries would take 1 minute.
+		chlg := NewChallenge(core, nil, provider, WithPresentRetry(4, 20*time.Second))
+
+		start := time.Now()
+
+		err := chlg.PreSolve(authz)
+		require.Error(t, err)
+
+		var zoneErr *ZoneNotFoundError
+		require.ErrorAs(t, err, &zoneErr)
+
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Equal(t, 1, provider.calls)
+	})
+
+	t.Run("zone check", func(t *testing.T) {
+		provider := &countingProvider{}
+
+		chlg := NewChallenge(core, nil, provider, WithZoneCheck())
+
+		err := chlg.PreSolve(authz)
+		require.Error(t, err)
+
+		var zoneErr *ZoneNotFoundError
+		require.ErrorAs(t, err, &zoneErr)
+
+		assert.Zero(t, provider.present.Load())
+	})
+}
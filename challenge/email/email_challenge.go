@@ -0,0 +1,199 @@
+// Package email implements the email-reply-00 challenge (S/MIME certificates).
+// https://www.rfc-editor.org/rfc/rfc8823.html
+package email
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// DefaultTimeout is the default maximum time to wait for the challenge email.
+const DefaultTimeout = 10 * time.Minute
+
+const (
+	subjectPrefix = "ACME:"
+
+	responseBegin = "-----BEGIN ACME RESPONSE-----"
+	responseEnd   = "-----END ACME RESPONSE-----"
+)
+
+type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
+
+// Message is an email of the challenge: the challenge email sent by the CA, or the response email.
+type Message struct {
+	From      string
+	To        string
+	Subject   string
+	MessageID string
+	InReplyTo string
+	Body      string
+}
+
+// Provider is the mailbox handler of the email addresses to validate.
+type Provider interface {
+	// Present surfaces the challenge to the mailbox:
+	// the provider waits for the challenge email sent by from (the CA) to address, and returns it.
+	// The subject of the challenge email contains the first part of the token.
+	Present(ctx context.Context, address, from string) (*Message, error)
+
+	// Reply sends the response email (the reply to the challenge email, to the CA).
+	Reply(ctx context.Context, reply *Message) error
+}
+
+// ProviderTimeout allows for a provider to specify the maximum time to wait for the challenge email (DefaultTimeout by default).
+type ProviderTimeout interface {
+	Provider
+	Timeout() time.Duration
+}
+
+type Challenge struct {
+	core     *api.Core
+	validate ValidateFunc
+	provider Provider
+}
+
+func NewChallenge(core *api.Core, validate ValidateFunc, provider Provider) *Challenge {
+	return &Challenge{
+		core:     core,
+		validate: validate,
+		provider: provider,
+	}
+}
+
+func (c *Challenge) SetProvider(provider Provider) {
+	c.provider = provider
+}
+
+// Solve waits for the challenge email, replies with the response, then waits for the validation of the challenge.
+func (c *Challenge) Solve(authz acme.Authorization) error {
+	address := authz.Identifier.Value
+	log.Infof("[%s] acme: Trying to solve email-reply-00", address)
+
+	chlng, err := challenge.FindChallenge(challenge.EMAILREPLY00, authz)
+	if err != nil {
+		return err
+	}
+
+	if chlng.From == "" {
+		return fmt.Errorf("[%s] acme: missing the sender of the challenge email", address)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout())
+	defer cancel()
+
+	log.Infof("[%s] acme: Waiting for the challenge email from %s", address, chlng.From)
+
+	msg, err := c.provider.Present(ctx, address, chlng.From)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: error receiving the challenge email: %w", address, err)
+	}
+
+	err = checkSender(msg, chlng.From)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", address, err)
+	}
+
+	tokenPart1, err := GetTokenPart1(msg.Subject)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", address, err)
+	}
+
+	// The token is the concatenation of the token-part1 (challenge email) and the token-part2 (challenge object).
+	keyAuth, err := c.core.GetKeyAuthorization(tokenPart1 + chlng.Token)
+	if err != nil {
+		return err
+	}
+
+	err = c.provider.Reply(ctx, NewReply(msg, address, keyAuth))
+	if err != nil {
+		return fmt.Errorf("[%s] acme: error sending the response email: %w", address, err)
+	}
+
+	chlng.KeyAuthorization = keyAuth
+
+	return c.validate(c.core, address, chlng)
+}
+
+func (c *Challenge) timeout() time.Duration {
+	if provider, ok := c.provider.(ProviderTimeout); ok && provider.Timeout() > 0 {
+		return provider.Timeout()
+	}
+
+	return DefaultTimeout
+}
+
+// GetTokenPart1 extracts the first part of the token from the subject of the challenge email ("ACME: <token-part1>").
+func GetTokenPart1(subject string) (string, error) {
+	// the reply prefixes (e.g. "Re:") are not expected, but harmless.
+	i := strings.Index(subject, subjectPrefix)
+	if i < 0 {
+		return "", fmt.Errorf("invalid challenge email subject: %q", subject)
+	}
+
+	token := strings.TrimSpace(subject[i+len(subjectPrefix):])
+
+	_, err := base64.RawURLEncoding.DecodeString(token)
+	if token == "" || err != nil {
+		return "", fmt.Errorf("invalid token in the challenge email subject: %q", subject)
+	}
+
+	return token, nil
+}
+
+// GetChallengeResponse returns the response of the challenge: the base64url encoded SHA-256 digest of the key authorization.
+func GetChallengeResponse(keyAuth string) string {
+	digest := sha256.Sum256([]byte(keyAuth))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// NewReply creates the response email to the challenge email.
+func NewReply(msg *Message, address, keyAuth string) *Message {
+	subject := strings.TrimSpace(msg.Subject)
+	if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = "Re: " + subject
+	}
+
+	body := fmt.Sprintf("%s\r\n%s\r\n%s\r\n", responseBegin, GetChallengeResponse(keyAuth), responseEnd)
+
+	return &Message{
+		From:      address,
+		To:        msg.From,
+		Subject:   subject,
+		InReplyTo: msg.MessageID,
+		Body:      body,
+	}
+}
+
+// checkSender checks that the challenge email has been sent by the CA.
+func checkSender(msg *Message, from string) error {
+	if msg == nil {
+		return errors.New("no challenge email")
+	}
+
+	sender, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender of the challenge email: %q: %w", msg.From, err)
+	}
+
+	expected, err := mail.ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("invalid sender of the challenge: %q: %w", from, err)
+	}
+
+	if !strings.EqualFold(sender.Address, expected.Address) {
+		return fmt.Errorf("unexpected sender of the challenge email: %s (expected %s)", sender.Address, expected.Address)
+	}
+
+	return nil
+}
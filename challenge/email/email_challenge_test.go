@@ -0,0 +1,195 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	caAddress  = "acme-challenge@ca.example"
+	tokenPart1 = "LgYemJLy3F1LDkiJrdIGbEzyFJyOyf6vBdyZ1TG3sME"
+	tokenPart2 = "DGyRejmCefe7v4NfDGDKfA"
+)
+
+// mailboxMock delivers the challenge email sent by the CA, and records the replies.
+type mailboxMock struct {
+	inbox   chan *Message
+	replies []*Message
+
+	presented []string
+	timeout   time.Duration
+}
+
+func (m *mailboxMock) Present(ctx context.Context, address, from string) (*Message, error) {
+	m.presented = append(m.presented, address, from)
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case msg := <-m.inbox:
+		return msg, nil
+	}
+}
+
+func (m *mailboxMock) Reply(_ context.Context, reply *Message) error {
+	m.replies = append(m.replies, reply)
+	return nil
+}
+
+func (m *mailboxMock) Timeout() time.Duration {
+	return m.timeout
+}
+
+func newCore(t *testing.T) *api.Core {
+	t.Helper()
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	return core
+}
+
+func newAuthorization(from string) acme.Authorization {
+	return acme.Authorization{
+		Identifier: acme.Identifier{Type: "email", Value: "alice@example.com"},
+		Challenges: []acme.Challenge{
+			{Type: challenge.EMAILREPLY00.String(), Token: tokenPart2, From: from},
+		},
+	}
+}
+
+func TestChallenge_Solve(t *testing.T) {
+	core := newCore(t)
+
+	mailbox := &mailboxMock{inbox: make(chan *Message, 1)}
+
+	// the CA sends the challenge email after a while.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		mailbox.inbox <- &Message{
+			From:      "ACME CA <" + caAddress + ">",
+			To:        "alice@example.com",
+			Subject:   "ACME: " + tokenPart1,
+			MessageID: "<A2299BB.FF7788@ca.example>",
+		}
+	}()
+
+	var validated acme.Challenge
+
+	validate := func(_ *api.Core, domain string, chlng acme.Challenge) error {
+		assert.Equal(t, "alice@example.com", domain)
+		validated = chlng
+		return nil
+	}
+
+	err := NewChallenge(core, validate, mailbox).Solve(newAuthorization(caAddress))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"alice@example.com", caAddress}, mailbox.presented)
+
+	keyAuth, err := core.GetKeyAuthorization(tokenPart1 + tokenPart2)
+	require.NoError(t, err)
+
+	assert.Equal(t, keyAuth, validated.KeyAuthorization)
+
+	require.Len(t, mailbox.replies, 1)
+
+	reply := mailbox.replies[0]
+	assert.Equal(t, "alice@example.com", reply.From)
+	assert.Equal(t, "ACME CA <"+caAddress+">", reply.To)
+	assert.Equal(t, "Re: ACME: "+tokenPart1, reply.Subject)
+	assert.Equal(t, "<A2299BB.FF7788@ca.example>", reply.InReplyTo)
+
+	expectedBody := "-----BEGIN ACME RESPONSE-----\r\n" + GetChallengeResponse(keyAuth) + "\r\n-----END ACME RESPONSE-----\r\n"
+	assert.Equal(t, expectedBody, reply.Body)
+}
+
+func TestChallenge_Solve_errors(t *testing.T) {
+	core := newCore(t)
+
+	testCases := []struct {
+		desc     string
+		from     string
+		msg      *Message
+		expected string
+	}{
+		{
+			desc:     "missing sender",
+			expected: "[alice@example.com] acme: missing the sender of the challenge email",
+		},
+		{
+			desc:     "unexpected sender",
+			from:     caAddress,
+			msg:      &Message{From: "mallory@evil.example", Subject: "ACME: " + tokenPart1},
+			expected: "[alice@example.com] acme: unexpected sender of the challenge email: mallory@evil.example (expected acme-challenge@ca.example)",
+		},
+		{
+			desc:     "invalid subject",
+			from:     caAddress,
+			msg:      &Message{From: caAddress, Subject: "Hello"},
+			expected: `[alice@example.com] acme: invalid challenge email subject: "Hello"`,
+		},
+		{
+			desc:     "invalid token",
+			from:     caAddress,
+			msg:      &Message{From: caAddress, Subject: "ACME: not a token!"},
+			expected: `[alice@example.com] acme: invalid token in the challenge email subject: "ACME: not a token!"`,
+		},
+		{
+			desc:     "no challenge email",
+			from:     caAddress,
+			expected: "[alice@example.com] acme: error receiving the challenge email: context deadline exceeded",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			mailbox := &mailboxMock{inbox: make(chan *Message, 1), timeout: 100 * time.Millisecond}
+			if test.msg != nil {
+				mailbox.inbox <- test.msg
+			}
+
+			validate := func(_ *api.Core, _ string, _ acme.Challenge) error {
+				return errors.New("unexpected validation")
+			}
+
+			err := NewChallenge(core, validate, mailbox).Solve(newAuthorization(test.from))
+			require.EqualError(t, err, test.expected)
+
+			assert.Empty(t, mailbox.replies)
+		})
+	}
+}
+
+func TestGetTokenPart1(t *testing.T) {
+	token, err := GetTokenPart1("Re: ACME:  " + tokenPart1 + " ")
+	require.NoError(t, err)
+
+	assert.Equal(t, tokenPart1, token)
+}
+
+func TestGetChallengeResponse(t *testing.T) {
+	response := GetChallengeResponse("keyAuth")
+
+	assert.Len(t, response, 43)
+	assert.False(t, strings.ContainsAny(response, "+/="))
+}
@@ -1,6 +1,7 @@
 package http01
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"net"
@@ -24,21 +25,64 @@ type ProviderServer struct {
 	matcher  domainMatcher
 	done     chan bool
 	listener net.Listener
+
+	healthPath string
+}
+
+// ProviderServerOption is an option of the ProviderServer.
+type ProviderServerOption func(*ProviderServer) error
+
+// WithHealthPath enables a health endpoint (e.g. "/healthz") which returns 200 while the server is serving.
+// It can be used as a readiness or liveness probe.
+// The path must not be inside the `.well-known/acme-challenge/` directory.
+func WithHealthPath(path string) ProviderServerOption {
+	return func(s *ProviderServer) error {
+		if path == "" {
+			return errors.New("empty health path")
+		}
+
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		if strings.HasPrefix(path, ChallengePath("")) {
+			return fmt.Errorf("the health path %q conflicts with the challenge path", path)
+		}
+
+		s.healthPath = path
+
+		return nil
+	}
 }
 
 // NewProviderServer creates a new ProviderServer on the selected interface and port.
 // Setting iface and / or port to an empty string will make the server fall back to
 // the "any" interface and port 80 respectively.
-func NewProviderServer(iface, port string) *ProviderServer {
+func NewProviderServer(iface, port string, opts ...ProviderServerOption) *ProviderServer {
 	if port == "" {
 		port = "80"
 	}
 
-	return &ProviderServer{network: "tcp", address: net.JoinHostPort(iface, port), matcher: &hostMatcher{}}
+	s := &ProviderServer{network: "tcp", address: net.JoinHostPort(iface, port), matcher: &hostMatcher{}}
+
+	return s.apply(opts)
 }
 
-func NewUnixProviderServer(socketPath string, mode fs.FileMode) *ProviderServer {
-	return &ProviderServer{network: "unix", address: socketPath, socketMode: mode, matcher: &hostMatcher{}}
+func NewUnixProviderServer(socketPath string, mode fs.FileMode, opts ...ProviderServerOption) *ProviderServer {
+	s := &ProviderServer{network: "unix", address: socketPath, socketMode: mode, matcher: &hostMatcher{}}
+
+	return s.apply(opts)
+}
+
+func (s *ProviderServer) apply(opts []ProviderServerOption) *ProviderServer {
+	for _, opt := range opts {
+		err := opt(s)
+		if err != nil {
+			log.Infof("server option error: %v", err)
+		}
+	}
+
+	return s
 }
 
 // Present starts a web server and makes the token available at `ChallengePath(token)` for web requests.
@@ -132,7 +176,17 @@ func (s *ProviderServer) serve(domain, token, keyAuth string) {
 		}
 	})
 
-	httpServer := &http.Server{Handler: mux}
+	if s.healthPath != "" {
+		mux.HandleFunc(s.healthPath, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("OK"))
+		})
+	}
+
+	// Every response, including the 404 of the unknown tokens, must not be cached:
+	// a CDN or a caching proxy in front of the server could otherwise serve a stale key authorization
+	// or a negatively cached 404 to the ACME server.
+	httpServer := &http.Server{Handler: noCache(mux)}
 
 	// Once httpServer is shut down
 	// we don't want any lingering connections, so disable KeepAlives.
@@ -145,3 +199,13 @@ func (s *ProviderServer) serve(domain, token, keyAuth string) {
 
 	s.done <- true
 }
+
+// noCache sets the headers preventing the caching of the responses by the intermediaries.
+func noCache(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -435,3 +435,125 @@ func testServeWithProxy(t *testing.T, header, extra *testProxyHeader, expectErro
 		require.NoError(t, err)
 	}
 }
+
+func TestProviderServer_healthPath(t *testing.T) {
+	providerServer := NewProviderServer("localhost", "23459", WithHealthPath("/healthz"))
+
+	err := providerServer.Present("localhost", "token", "keyAuth")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = providerServer.CleanUp("localhost", "token", "keyAuth") })
+
+	baseURL := "http://" + providerServer.GetAddress()
+
+	resp, err := http.Get(baseURL + "/healthz")
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(baseURL + ChallengePath("token"))
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "keyAuth", string(body))
+}
+
+func TestWithHealthPath(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		path     string
+		expected string
+	}{
+		{
+			desc:     "absolute path",
+			path:     "/healthz",
+			expected: "/healthz",
+		},
+		{
+			desc:     "relative path",
+			path:     "healthz",
+			expected: "/healthz",
+		},
+		{
+			desc: "empty",
+			path: "",
+		},
+		{
+			desc: "challenge path",
+			path: "/.well-known/acme-challenge/healthz",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			providerServer := NewProviderServer("", "", WithHealthPath(test.path))
+
+			assert.Equal(t, test.expected, providerServer.healthPath)
+		})
+	}
+}
+
+func TestProviderServer_healthPath_disabled(t *testing.T) {
+	providerServer := NewProviderServer("localhost", "23460")
+
+	err := providerServer.Present("localhost", "token", "keyAuth")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = providerServer.CleanUp("localhost", "token", "keyAuth") })
+
+	resp, err := http.Get("http://" + providerServer.GetAddress() + "/healthz")
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestProviderServer_noCache(t *testing.T) {
+	providerServer := NewProviderServer("localhost", "23461")
+
+	err := providerServer.Present("localhost", "token", "keyAuth")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = providerServer.CleanUp("localhost", "token", "keyAuth") })
+
+	baseURL := "http://" + providerServer.GetAddress()
+
+	testCases := []struct {
+		desc           string
+		path           string
+		expectedStatus int
+	}{
+		{
+			desc:           "known token",
+			path:           ChallengePath("token"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "unknown token",
+			path:           ChallengePath("unknown"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			resp, err := http.Get(baseURL + test.path)
+			require.NoError(t, err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+			assert.Equal(t, "no-cache", resp.Header.Get("Pragma"))
+		})
+	}
+}
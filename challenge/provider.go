@@ -26,3 +26,11 @@ type ProviderTimeout interface {
 	Provider
 	Timeout() (timeout, interval time.Duration)
 }
+
+// ProviderCapabilities allows a Provider to declare the operations it really implements.
+// A Provider which doesn't implement this interface is assumed to support all the operations.
+type ProviderCapabilities interface {
+	Provider
+	// SupportsCleanUp returns false if CleanUp is a no-op (e.g. the provider relies on the TTL of the records).
+	SupportsCleanUp() bool
+}
@@ -0,0 +1,25 @@
+package resolver
+
+import (
+	"errors"
+
+	"github.com/go-acme/lego/v4/platform/clock"
+)
+
+// WithClock replaces the clock used by the solver manager (the order deadline and the waits between the sequential solves),
+// e.g. a clock.Fake to solve the challenges without wall-clock delay in tests.
+func WithClock(clk clock.Clock) Option {
+	return func(c *SolverManager) error {
+		if clk == nil {
+			return errors.New("the clock is nil")
+		}
+
+		c.clock = clk
+
+		return nil
+	}
+}
+
+func (c *SolverManager) getClock() clock.Clock {
+	return clock.OrReal(c.clock)
+}
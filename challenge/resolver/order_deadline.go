@@ -0,0 +1,170 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/clock"
+)
+
+// errDeadlineExceeded is recorded for the authorizations not solved before the order deadline.
+var errDeadlineExceeded = errors.New("the order deadline is exceeded")
+
+// WithOrderDeadline defines a deadline for the solving of all the challenges of the orders (e.g. a reconcile budget).
+// When the deadline is exceeded, the remaining solves are skipped, and a DeadlineExceededError is returned.
+// The records (or the challenge servers) already presented are still cleaned up.
+//
+// The solvers aware of the deadline (e.g. dns01) bound their propagation wait by the deadline,
+// and don't request the validation once it is exceeded.
+// The other solvers are not interrupted: the deadline is checked before each solve.
+//
+// This gives an upper bound to the time spent in the resolution of the challenges,
+// whatever the number of domains and the propagation time of the DNS provider.
+func WithOrderDeadline(deadline time.Time) Option {
+	return func(c *SolverManager) error {
+		if deadline.IsZero() {
+			return errors.New("invalid order deadline: zero time")
+		}
+
+		c.orderDeadlineAt = deadline
+		return nil
+	}
+}
+
+// WithOrderTimeout is like WithOrderDeadline, but with a time budget for each order:
+// the deadline of an order is the start of the solving of its challenges plus the timeout.
+// When both are defined, the earliest deadline applies.
+func WithOrderTimeout(timeout time.Duration) Option {
+	return func(c *SolverManager) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid order timeout: %s", timeout)
+		}
+
+		c.orderTimeout = timeout
+		return nil
+	}
+}
+
+// DeadlineExceededError is returned when the challenges of an order are not all solved before the order deadline (see WithOrderDeadline).
+type DeadlineExceededError struct {
+	Deadline time.Time
+	// Solved are the domains solved before the deadline.
+	Solved []string
+	// Unsolved are the domains not solved: abandoned because of the deadline, or failed.
+	Unsolved []string
+
+	// Err contains the errors of the domains which failed before the deadline, if any.
+	Err error
+}
+
+func (e *DeadlineExceededError) Error() string {
+	msg := fmt.Sprintf("acme: the order deadline (%s) is exceeded: solved [%s], unsolved [%s]",
+		e.Deadline.Format(time.RFC3339), strings.Join(e.Solved, ", "), strings.Join(e.Unsolved, ", "))
+
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *DeadlineExceededError) Unwrap() error {
+	return e.Err
+}
+
+// newDeadlineExceededError returns a DeadlineExceededError if some authorizations have not been solved because of the deadline.
+func newDeadlineExceededError(deadline time.Time, authorizations []acme.Authorization, failures obtainError) *DeadlineExceededError {
+	exceeded := false
+
+	others := make(obtainError)
+
+	for domain, err := range failures {
+		if errors.Is(err, errDeadlineExceeded) {
+			exceeded = true
+			continue
+		}
+
+		others[domain] = err
+	}
+
+	if !exceeded {
+		return nil
+	}
+
+	deadlineErr := &DeadlineExceededError{Deadline: deadline}
+
+	for _, authz := range authorizations {
+		domain := challenge.GetTargetedDomain(authz)
+
+		if _, ok := failures[domain]; ok {
+			deadlineErr.Unsolved = append(deadlineErr.Unsolved, domain)
+		} else {
+			deadlineErr.Solved = append(deadlineErr.Solved, domain)
+		}
+	}
+
+	sort.Strings(deadlineErr.Solved)
+	sort.Strings(deadlineErr.Unsolved)
+
+	if len(others) > 0 {
+		deadlineErr.Err = others
+	}
+
+	return deadlineErr
+}
+
+// Interface for the solvers able to bound the solving of a challenge by a deadline (e.g. dns01).
+// When the deadline is exceeded, the returned error must wrap context.DeadlineExceeded.
+type deadlineSolver interface {
+	SolveBefore(authorization acme.Authorization, deadline time.Time) error
+}
+
+// orderDeadline returns the deadline of an order starting now, or the zero time if there is no order deadline:
+// the earliest of the order deadline (see WithOrderDeadline) and the end of the order timeout (see WithOrderTimeout).
+func (c *SolverManager) orderDeadline() time.Time {
+	if c.orderTimeout <= 0 {
+		return c.orderDeadlineAt
+	}
+
+	deadline := c.getClock().Now().Add(c.orderTimeout)
+	if !c.orderDeadlineAt.IsZero() && c.orderDeadlineAt.Before(deadline) {
+		return c.orderDeadlineAt
+	}
+
+	return deadline
+}
+
+// solveBefore solves the authorization if the deadline is not exceeded.
+// The deadline is passed to the solvers aware of it, the other solvers are not bounded.
+func solveBefore(clk clock.Clock, solvr solver, authz acme.Authorization, deadline time.Time) error {
+	if deadline.IsZero() {
+		return solvr.Solve(authz)
+	}
+
+	if isExceeded(clk, deadline) {
+		return errDeadlineExceeded
+	}
+
+	s, ok := solvr.(deadlineSolver)
+	if !ok {
+		return solvr.Solve(authz)
+	}
+
+	err := s.SolveBefore(authz, deadline)
+	if err != nil && errors.Is(err, context.DeadlineExceeded) && isExceeded(clk, deadline) {
+		return fmt.Errorf("%w: %w", errDeadlineExceeded, err)
+	}
+
+	return err
+}
+
+// isExceeded returns true if the deadline is defined and exceeded.
+func isExceeded(clk clock.Clock, deadline time.Time) bool {
+	return !deadline.IsZero() && !clk.Now().Before(deadline)
+}
@@ -0,0 +1,278 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowSolverMock simulates a solver waiting for the propagation of each record, bounded by the deadline (like dns01).
+type slowSolverMock struct {
+	preSolverMock
+
+	clock       *clock.Fake
+	propagation time.Duration
+	sequential  bool
+
+	mu        sync.Mutex
+	presented []string
+	validated []string
+	cleaned   []string
+}
+
+func (s *slowSolverMock) PreSolve(authorization acme.Authorization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.presented = append(s.presented, authorization.Identifier.Value)
+
+	return s.preSolverMock.PreSolve(authorization)
+}
+
+func (s *slowSolverMock) Solve(authorization acme.Authorization) error {
+	return s.SolveBefore(authorization, time.Time{})
+}
+
+func (s *slowSolverMock) SolveBefore(authorization acme.Authorization, deadline time.Time) error {
+	if !deadline.IsZero() && s.clock.Now().Add(s.propagation).After(deadline) {
+		s.clock.Advance(deadline.Sub(s.clock.Now()))
+		return fmt.Errorf("propagation: %w", context.DeadlineExceeded)
+	}
+
+	s.clock.Advance(s.propagation)
+
+	s.mu.Lock()
+	s.validated = append(s.validated, authorization.Identifier.Value)
+	s.mu.Unlock()
+
+	return s.preSolverMock.Solve(authorization)
+}
+
+func (s *slowSolverMock) CleanUp(authorization acme.Authorization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleaned = append(s.cleaned, authorization.Identifier.Value)
+
+	return s.preSolverMock.CleanUp(authorization)
+}
+
+func (s *slowSolverMock) Sequential() (bool, time.Duration) {
+	return s.sequential, 0
+}
+
+// plainSolverMock is a solver unaware of the deadline.
+type plainSolverMock struct {
+	preSolverMock
+
+	clock       *clock.Fake
+	propagation time.Duration
+
+	solved []string
+}
+
+func (s *plainSolverMock) Solve(authorization acme.Authorization) error {
+	s.clock.Advance(s.propagation)
+	s.solved = append(s.solved, authorization.Identifier.Value)
+
+	return s.preSolverMock.Solve(authorization)
+}
+
+func TestWithOrderDeadline(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		sequential        bool
+		solve             map[string]error
+		expectedPresented []string
+		expectedError     string
+	}{
+		{
+			desc:              "parallel",
+			expectedPresented: []string{"a.example.com", "b.example.com", "c.example.com"},
+			expectedError:     "acme: the order deadline (2024-12-01T10:00:00Z) is exceeded: solved [a.example.com, b.example.com], unsolved [c.example.com]",
+		},
+		{
+			desc:              "sequential",
+			sequential:        true,
+			expectedPresented: []string{"a.example.com", "b.example.com", "c.example.com"},
+			expectedError:     "acme: the order deadline (2024-12-01T10:00:00Z) is exceeded: solved [a.example.com, b.example.com], unsolved [c.example.com]",
+		},
+		{
+			desc:              "with a failure",
+			solve:             map[string]error{"a.example.com": errors.New("solve error a.example.com")},
+			expectedPresented: []string{"a.example.com", "b.example.com", "c.example.com"},
+			expectedError: "acme: the order deadline (2024-12-01T10:00:00Z) is exceeded: solved [b.example.com], unsolved [a.example.com, c.example.com]: " +
+				"error: one or more domains had a problem:\n[a.example.com] solve error a.example.com\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			start := time.Date(2024, time.December, 1, 9, 50, 0, 0, time.UTC)
+			fakeClock := clock.NewFake(start)
+
+			solvr := &slowSolverMock{
+				preSolverMock: preSolverMock{solve: test.solve},
+				clock:         fakeClock,
+				propagation:   4 * time.Minute,
+				sequential:    test.sequential,
+			}
+
+			// the deadline is exceeded during the propagation of the third record.
+			prober := &Prober{
+				solverManager: &SolverManager{
+					solvers:         map[challenge.Type]solver{challenge.HTTP01: solvr},
+					orderDeadlineAt: start.Add(10 * time.Minute),
+					clock:           fakeClock,
+				},
+			}
+
+			authz := []acme.Authorization{
+				createStubAuthorizationHTTP01("a.example.com", acme.StatusProcessing),
+				createStubAuthorizationHTTP01("b.example.com", acme.StatusProcessing),
+				createStubAuthorizationHTTP01("c.example.com", acme.StatusProcessing),
+			}
+
+			err := prober.Solve(authz)
+
+			var deadlineErr *DeadlineExceededError
+			require.ErrorAs(t, err, &deadlineErr)
+			require.EqualError(t, err, test.expectedError)
+
+			// the solve of the third record stopped at the deadline.
+			assert.Equal(t, start.Add(10*time.Minute), fakeClock.Now())
+
+			solvr.mu.Lock()
+			defer solvr.mu.Unlock()
+
+			// the validation of the third record has not been requested.
+			assert.Equal(t, []string{"a.example.com", "b.example.com"}, solvr.validated)
+
+			// the presented records are cleaned up, including the one not solved.
+			assert.Equal(t, test.expectedPresented, solvr.presented)
+			assert.ElementsMatch(t, solvr.presented, solvr.cleaned)
+		})
+	}
+}
+
+func TestWithOrderTimeout_perOrder(t *testing.T) {
+	start := time.Date(2024, time.December, 1, 9, 50, 0, 0, time.UTC)
+	fakeClock := clock.NewFake(start)
+
+	solvr := &slowSolverMock{clock: fakeClock, propagation: 4 * time.Minute}
+
+	prober := &Prober{
+		solverManager: &SolverManager{
+			solvers:      map[challenge.Type]solver{challenge.HTTP01: solvr},
+			orderTimeout: 10 * time.Minute,
+			clock:        fakeClock,
+		},
+	}
+
+	// each order has its own deadline: the later orders are not affected by the time spent in the previous ones.
+	for i := range 3 {
+		authz := []acme.Authorization{
+			createStubAuthorizationHTTP01(fmt.Sprintf("a%d.example.com", i), acme.StatusProcessing),
+			createStubAuthorizationHTTP01(fmt.Sprintf("b%d.example.com", i), acme.StatusProcessing),
+		}
+
+		err := prober.Solve(authz)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, start.Add(24*time.Minute), fakeClock.Now())
+	assert.Len(t, solvr.validated, 6)
+}
+
+func TestWithOrderDeadline_solverUnaware(t *testing.T) {
+	start := time.Date(2024, time.December, 1, 9, 50, 0, 0, time.UTC)
+	fakeClock := clock.NewFake(start)
+
+	solvr := &plainSolverMock{clock: fakeClock, propagation: 4 * time.Minute}
+
+	prober := &Prober{
+		solverManager: &SolverManager{
+			solvers:         map[challenge.Type]solver{challenge.HTTP01: solvr},
+			orderDeadlineAt: start.Add(6 * time.Minute),
+			clock:           fakeClock,
+		},
+	}
+
+	authz := []acme.Authorization{
+		createStubAuthorizationHTTP01("a.example.com", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("b.example.com", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("c.example.com", acme.StatusProcessing),
+	}
+
+	err := prober.Solve(authz)
+	require.EqualError(t, err, "acme: the order deadline (2024-12-01T09:56:00Z) is exceeded: solved [a.example.com, b.example.com], unsolved [c.example.com]")
+
+	// the solve in progress at the deadline is not interrupted, the next ones are skipped.
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, solvr.solved)
+	assert.Equal(t, start.Add(8*time.Minute), fakeClock.Now())
+}
+
+func TestSolverManager_orderDeadline(t *testing.T) {
+	start := time.Date(2024, time.December, 1, 9, 50, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		options  []Option
+		expected time.Time
+	}{
+		{
+			desc: "none",
+		},
+		{
+			desc:     "deadline",
+			options:  []Option{WithOrderDeadline(start.Add(time.Hour))},
+			expected: start.Add(time.Hour),
+		},
+		{
+			desc:     "timeout",
+			options:  []Option{WithOrderTimeout(10 * time.Minute)},
+			expected: start.Add(10 * time.Minute),
+		},
+		{
+			desc:     "timeout before the deadline",
+			options:  []Option{WithOrderDeadline(start.Add(time.Hour)), WithOrderTimeout(10 * time.Minute)},
+			expected: start.Add(10 * time.Minute),
+		},
+		{
+			desc:     "deadline before the end of the timeout",
+			options:  []Option{WithOrderDeadline(start.Add(5 * time.Minute)), WithOrderTimeout(10 * time.Minute)},
+			expected: start.Add(5 * time.Minute),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			manager := &SolverManager{clock: clock.NewFake(start)}
+
+			for _, opt := range test.options {
+				require.NoError(t, opt(manager))
+			}
+
+			assert.Equal(t, test.expected, manager.orderDeadline())
+		})
+	}
+}
+
+func TestWithOrderDeadline_invalid(t *testing.T) {
+	err := WithOrderDeadline(time.Time{})(&SolverManager{})
+	require.EqualError(t, err, "invalid order deadline: zero time")
+
+	err = WithOrderTimeout(0)(&SolverManager{})
+	require.EqualError(t, err, "invalid order timeout: 0s")
+}
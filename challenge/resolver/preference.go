@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// Option configures the SolverManager.
+type Option func(*SolverManager) error
+
+// WithChallengePreference defines the challenge types allowed for all the authorizations, by order of preference.
+// The first type offered by the CA, and with a configured solver, is used.
+// The types not in the list are never used.
+// If none of the preferred types is available, the authorization fails with an error naming the offered types.
+//
+// Without preference, the solver is chosen among the configured solvers, in a deterministic order (tls-alpn-01, http-01, dns-01).
+func WithChallengePreference(types []challenge.Type) Option {
+	return func(c *SolverManager) error {
+		if len(types) == 0 {
+			return errors.New("empty challenge preference")
+		}
+
+		c.preference = types
+		return nil
+	}
+}
+
+// WithDomainChallengePreference is like WithChallengePreference, but only for the authorizations of a domain.
+// It overrides WithChallengePreference for this domain.
+// The domain of a wildcard authorization is the wildcard domain (ex: `*.example.com`).
+func WithDomainChallengePreference(domain string, types []challenge.Type) Option {
+	return func(c *SolverManager) error {
+		if len(types) == 0 {
+			return fmt.Errorf("empty challenge preference for %s", domain)
+		}
+
+		if c.domainPreferences == nil {
+			c.domainPreferences = map[string][]challenge.Type{}
+		}
+
+		c.domainPreferences[domain] = types
+		return nil
+	}
+}
+
+// getPreference returns the preferred challenge types of an authorization, if any.
+func (c *SolverManager) getPreference(authz acme.Authorization) []challenge.Type {
+	if types, ok := c.domainPreferences[challenge.GetTargetedDomain(authz)]; ok {
+		return types
+	}
+
+	return c.preference
+}
+
+// choosePreferredSolver returns the solver of the first preferred challenge type offered by the CA, and this type.
+func (c *SolverManager) choosePreferredSolver(authz acme.Authorization, types []challenge.Type) (solver, challenge.Type, error) {
+	offered := make(map[challenge.Type]struct{})
+
+	var offeredTypes []string
+	for _, chlg := range authz.Challenges {
+		offered[challenge.Type(chlg.Type)] = struct{}{}
+		offeredTypes = append(offeredTypes, chlg.Type)
+	}
+
+	for _, chlgType := range types {
+		if _, ok := offered[chlgType]; !ok {
+			continue
+		}
+
+		if solvr, ok := c.solvers[chlgType]; ok {
+			log.Infof("[%s] acme: use %s solver", challenge.GetTargetedDomain(authz), chlgType)
+			return solvr, chlgType, nil
+		}
+	}
+
+	preferred := make([]string, len(types))
+	for i, chlgType := range types {
+		preferred[i] = chlgType.String()
+	}
+
+	return nil, "", fmt.Errorf("[%s] acme: none of the preferred challenges (%s) is available (offered: %s)",
+		challenge.GetTargetedDomain(authz), strings.Join(preferred, ", "), strings.Join(offeredTypes, ", "))
+}
@@ -0,0 +1,98 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolverManager_chooseSolver_preference(t *testing.T) {
+	httpSolver := &preSolverMock{}
+	tlsSolver := &preSolverMock{}
+	dnsSolver := &preSolverMock{}
+
+	solvers := map[challenge.Type]solver{
+		challenge.HTTP01:    httpSolver,
+		challenge.TLSALPN01: tlsSolver,
+		challenge.DNS01:     dnsSolver,
+	}
+
+	newAuthz := func(domain string, types ...challenge.Type) acme.Authorization {
+		authz := acme.Authorization{Identifier: acme.Identifier{Type: "dns", Value: domain}}
+		for _, chlgType := range types {
+			authz.Challenges = append(authz.Challenges, acme.Challenge{Type: chlgType.String()})
+		}
+		return authz
+	}
+
+	testCases := []struct {
+		desc          string
+		options       []Option
+		authz         acme.Authorization
+		expected      solver
+		expectedError string
+	}{
+		{
+			desc:     "no preference",
+			authz:    newAuthz("example.com", challenge.DNS01, challenge.HTTP01, challenge.TLSALPN01),
+			expected: tlsSolver,
+		},
+		{
+			desc:     "first preferred type",
+			options:  []Option{WithChallengePreference([]challenge.Type{challenge.HTTP01, challenge.TLSALPN01})},
+			authz:    newAuthz("example.com", challenge.DNS01, challenge.HTTP01, challenge.TLSALPN01),
+			expected: httpSolver,
+		},
+		{
+			desc:     "fallback to the next preferred type",
+			options:  []Option{WithChallengePreference([]challenge.Type{challenge.TLSALPN01, challenge.HTTP01})},
+			authz:    newAuthz("example.com", challenge.DNS01, challenge.HTTP01),
+			expected: httpSolver,
+		},
+		{
+			desc: "domain preference",
+			options: []Option{
+				WithChallengePreference([]challenge.Type{challenge.DNS01}),
+				WithDomainChallengePreference("www.example.com", []challenge.Type{challenge.TLSALPN01, challenge.HTTP01}),
+			},
+			authz:    newAuthz("www.example.com", challenge.DNS01, challenge.HTTP01, challenge.TLSALPN01),
+			expected: tlsSolver,
+		},
+		{
+			desc: "other domain",
+			options: []Option{
+				WithChallengePreference([]challenge.Type{challenge.DNS01}),
+				WithDomainChallengePreference("www.example.com", []challenge.Type{challenge.TLSALPN01, challenge.HTTP01}),
+			},
+			authz:    newAuthz("example.com", challenge.DNS01, challenge.HTTP01, challenge.TLSALPN01),
+			expected: dnsSolver,
+		},
+		{
+			desc:          "none of the preferred types is offered",
+			options:       []Option{WithChallengePreference([]challenge.Type{challenge.TLSALPN01, challenge.HTTP01})},
+			authz:         newAuthz("example.com", challenge.DNS01),
+			expectedError: "[example.com] acme: none of the preferred challenges (tls-alpn-01, http-01) is available (offered: dns-01)",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			manager := NewSolversManager(nil, test.options...)
+			manager.solvers = solvers
+
+			solvr, _, err := manager.chooseSolver(test.authz)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Same(t, test.expected, solvr)
+		})
+	}
+}
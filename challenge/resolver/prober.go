@@ -1,12 +1,12 @@
 package resolver
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/clock"
 )
 
 // Interface for all challenge solvers to implement.
@@ -25,14 +25,23 @@ type cleanup interface {
 	CleanUp(authorization acme.Authorization) error
 }
 
+// Interface for challenges like dns, where the records of all the challenges of an order can be created
+// (and deleted) at once, instead of one by one.
+type batchSolver interface {
+	Batch() bool
+	PreSolveBatch(authorizations []acme.Authorization) error
+	CleanUpBatch(authorizations []acme.Authorization) error
+}
+
 type sequential interface {
 	Sequential() (bool, time.Duration)
 }
 
-// an authz with the solver we have chosen and the index of the challenge associated with it.
+// an authz with the solver we have chosen and the type of the challenge associated with it.
 type selectedAuthSolver struct {
-	authz  acme.Authorization
-	solver solver
+	authz    acme.Authorization
+	solver   solver
+	chlgType challenge.Type
 }
 
 type Prober struct {
@@ -48,10 +57,99 @@ func NewProber(solverManager *SolverManager) *Prober {
 // Solve Looks through the challenge combinations to find a solvable match.
 // Then solves the challenges in series and returns.
 func (p *Prober) Solve(authorizations []acme.Authorization) error {
+	_, err := p.solve(authorizations, false)
+	return err
+}
+
+// SolveWithReport is like Solve, and reports the type of the challenge which validated each authorization,
+// by domain (see challenge.GetTargetedDomain).
+// The type of the authorizations already valid is the type of their valid challenge, if the server reports it.
+func (p *Prober) SolveWithReport(authorizations []acme.Authorization) (map[string]challenge.Type, error) {
+	return p.solve(authorizations, false)
+}
+
+// PreSolve submits the challenges which can be prepared in advance (ex: the records of the dns-01 challenges),
+// without validating them.
+// The challenges are validated later with SolvePresented, possibly in another process
+// (the solvers must be configured identically).
+// The challenges of the sequential solvers are not submitted: they are handled entirely by SolvePresented.
+func (p *Prober) PreSolve(authorizations []acme.Authorization) error {
+	failures := make(obtainError)
+
+	authSolvers, _ := p.selectSolvers(authorizations, failures)
+
+	preSolve(authSolvers, failures)
+
+	if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
+
+// SolvePresented is like Solve, but the challenges already submitted by PreSolve are not submitted again.
+func (p *Prober) SolvePresented(authorizations []acme.Authorization) error {
+	_, err := p.solve(authorizations, true)
+	return err
+}
+
+// SolvePresentedWithReport is like SolvePresented, and reports the type of the challenge which validated each authorization
+// (see SolveWithReport).
+func (p *Prober) SolvePresentedWithReport(authorizations []acme.Authorization) (map[string]challenge.Type, error) {
+	return p.solve(authorizations, true)
+}
+
+func (p *Prober) solve(authorizations []acme.Authorization, presented bool) (map[string]challenge.Type, error) {
 	failures := make(obtainError)
 
-	var authSolvers []*selectedAuthSolver
-	var authSolversSequential []*selectedAuthSolver
+	authSolvers, authSolversSequential := p.selectSolvers(authorizations, failures)
+
+	clk := p.solverManager.getClock()
+	deadline := p.solverManager.orderDeadline()
+
+	parallelSolve(clk, authSolvers, failures, presented, deadline)
+
+	sequentialSolve(clk, authSolversSequential, failures, deadline)
+
+	if err := newDeadlineExceededError(deadline, authorizations, failures); err != nil {
+		return nil, err
+	}
+
+	// Be careful not to return an empty failures map,
+	// for even an empty obtainError is a non-nil error value
+	if len(failures) > 0 {
+		return nil, failures
+	}
+
+	return report(authorizations, append(authSolvers, authSolversSequential...)), nil
+}
+
+// report returns the type of the challenge which validated each authorization.
+func report(authorizations []acme.Authorization, authSolvers []*selectedAuthSolver) map[string]challenge.Type {
+	types := make(map[string]challenge.Type)
+
+	for _, authz := range authorizations {
+		if authz.Status != acme.StatusValid {
+			continue
+		}
+
+		// The challenges of a valid authorization are restricted to the validated challenge.
+		for _, chlg := range authz.Challenges {
+			if chlg.Status == acme.StatusValid || len(authz.Challenges) == 1 {
+				types[challenge.GetTargetedDomain(authz)] = challenge.Type(chlg.Type)
+				break
+			}
+		}
+	}
+
+	for _, authSolver := range authSolvers {
+		types[challenge.GetTargetedDomain(authSolver.authz)] = authSolver.chlgType
+	}
+
+	return types
+}
+
+// selectSolvers selects a solver for each authz, and splits them between parallel and sequential solvers.
+func (p *Prober) selectSolvers(authorizations []acme.Authorization, failures obtainError) (authSolvers, authSolversSequential []*selectedAuthSolver) {
 
 	// Loop through the resources, basically through the domains.
 	// First pass just selects a solver for each authz.
@@ -63,8 +161,9 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 			continue
 		}
 
-		if solvr := p.solverManager.chooseSolver(authz); solvr != nil {
-			authSolver := &selectedAuthSolver{authz: authz, solver: solvr}
+		solvr, chlgType, err := p.solverManager.chooseSolver(authz)
+		if err == nil {
+			authSolver := &selectedAuthSolver{authz: authz, solver: solvr, chlgType: chlgType}
 
 			switch s := solvr.(type) {
 			case sequential:
@@ -77,27 +176,23 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 				authSolvers = append(authSolvers, authSolver)
 			}
 		} else {
-			failures[domain] = fmt.Errorf("[%s] acme: could not determine solvers", domain)
+			failures[domain] = err
 		}
 	}
 
-	parallelSolve(authSolvers, failures)
-
-	sequentialSolve(authSolversSequential, failures)
-
-	// Be careful not to return an empty failures map,
-	// for even an empty obtainError is a non-nil error value
-	if len(failures) > 0 {
-		return failures
-	}
-	return nil
+	return authSolvers, authSolversSequential
 }
 
-func sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
+func sequentialSolve(clk clock.Clock, authSolvers []*selectedAuthSolver, failures obtainError, deadline time.Time) {
 	for i, authSolver := range authSolvers {
 		// Submit the challenge
 		domain := challenge.GetTargetedDomain(authSolver.authz)
 
+		if isExceeded(clk, deadline) {
+			failures[domain] = errDeadlineExceeded
+			continue
+		}
+
 		if solvr, ok := authSolver.solver.(preSolver); ok {
 			err := solvr.PreSolve(authSolver.authz)
 			if err != nil {
@@ -108,7 +203,7 @@ func sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
 		}
 
 		// Solve challenge
-		err := authSolver.solver.Solve(authSolver.authz)
+		err := solveBefore(clk, authSolver.solver, authSolver.authz, deadline)
 		if err != nil {
 			failures[domain] = err
 			cleanUp(authSolver.solver, authSolver.authz)
@@ -121,29 +216,40 @@ func sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
 		if len(authSolvers)-1 > i {
 			solvr := authSolver.solver.(sequential)
 			_, interval := solvr.Sequential()
+			if !deadline.IsZero() {
+				interval = max(min(interval, deadline.Sub(clk.Now())), 0)
+			}
+
 			log.Infof("sequence: wait for %s", interval)
-			time.Sleep(interval)
+			clk.Sleep(interval)
 		}
 	}
 }
 
-func parallelSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
+func parallelSolve(clk clock.Clock, authSolvers []*selectedAuthSolver, failures obtainError, presented bool, deadline time.Time) {
 	// For all valid preSolvers, first submit the challenges, so they have max time to propagate
-	for _, authSolver := range authSolvers {
-		authz := authSolver.authz
-		if solvr, ok := authSolver.solver.(preSolver); ok {
-			err := solvr.PreSolve(authz)
-			if err != nil {
-				failures[challenge.GetTargetedDomain(authz)] = err
-			}
-		}
+	if !presented {
+		preSolve(authSolvers, failures)
 	}
 
+	batches := groupBatches(authSolvers)
+
 	defer func() {
 		// Clean all created TXT records
 		for _, authSolver := range authSolvers {
+			if solvr, ok := authSolver.solver.(batchSolver); ok && solvr.Batch() {
+				continue
+			}
+
 			cleanUp(authSolver.solver, authSolver.authz)
 		}
+
+		for solvr, authzs := range batches {
+			err := solvr.CleanUpBatch(authzs)
+			if err != nil {
+				log.Warnf("acme: cleaning up failed: %v ", err)
+			}
+		}
 	}()
 
 	// Finally solve all challenges for real
@@ -155,13 +261,51 @@ func parallelSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
 			continue
 		}
 
-		err := authSolver.solver.Solve(authz)
+		err := solveBefore(clk, authSolver.solver, authz, deadline)
 		if err != nil {
 			failures[domain] = err
 		}
 	}
 }
 
+// preSolve submits the challenges of the preSolvers and of the batch solvers.
+func preSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
+	for _, authSolver := range authSolvers {
+		if solvr, ok := authSolver.solver.(batchSolver); ok && solvr.Batch() {
+			continue
+		}
+
+		if solvr, ok := authSolver.solver.(preSolver); ok {
+			err := solvr.PreSolve(authSolver.authz)
+			if err != nil {
+				failures[challenge.GetTargetedDomain(authSolver.authz)] = err
+			}
+		}
+	}
+
+	for solvr, authzs := range groupBatches(authSolvers) {
+		err := solvr.PreSolveBatch(authzs)
+		if err != nil {
+			for _, authz := range authzs {
+				failures[challenge.GetTargetedDomain(authz)] = err
+			}
+		}
+	}
+}
+
+// groupBatches groups the authorizations by batch solver.
+func groupBatches(authSolvers []*selectedAuthSolver) map[batchSolver][]acme.Authorization {
+	batches := make(map[batchSolver][]acme.Authorization)
+
+	for _, authSolver := range authSolvers {
+		if solvr, ok := authSolver.solver.(batchSolver); ok && solvr.Batch() {
+			batches[solvr] = append(batches[solvr], authSolver.authz)
+		}
+	}
+
+	return batches
+}
+
 func cleanUp(solvr solver, authz acme.Authorization) {
 	if solvr, ok := solvr.(cleanup); ok {
 		domain := challenge.GetTargetedDomain(authz)
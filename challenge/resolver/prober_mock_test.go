@@ -42,3 +42,41 @@ func createStubAuthorizationHTTP01(domain, status string) acme.Authorization {
 		},
 	}
 }
+
+type batchSolverMock struct {
+	preSolverMock
+
+	preSolveBatchCalls [][]acme.Authorization
+	cleanUpBatchCalls  [][]acme.Authorization
+	preSolveCalls      int
+	cleanUpCalls       int
+}
+
+func (s *batchSolverMock) Batch() bool { return true }
+
+func (s *batchSolverMock) PreSolve(authorization acme.Authorization) error {
+	s.preSolveCalls++
+	return s.preSolverMock.PreSolve(authorization)
+}
+
+func (s *batchSolverMock) CleanUp(authorization acme.Authorization) error {
+	s.cleanUpCalls++
+	return s.preSolverMock.CleanUp(authorization)
+}
+
+func (s *batchSolverMock) PreSolveBatch(authorizations []acme.Authorization) error {
+	s.preSolveBatchCalls = append(s.preSolveBatchCalls, authorizations)
+
+	for _, authz := range authorizations {
+		if err := s.preSolve[authz.Identifier.Value]; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *batchSolverMock) CleanUpBatch(authorizations []acme.Authorization) error {
+	s.cleanUpBatchCalls = append(s.cleanUpBatchCalls, authorizations)
+	return nil
+}
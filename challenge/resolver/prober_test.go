@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -115,3 +116,153 @@ func TestProber_Solve(t *testing.T) {
 		})
 	}
 }
+
+func TestProber_Solve_batch(t *testing.T) {
+	solvr := &batchSolverMock{
+		preSolverMock: preSolverMock{
+			preSolve: map[string]error{},
+			solve:    map[string]error{},
+			cleanUp:  map[string]error{},
+		},
+	}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	authz := []acme.Authorization{
+		createStubAuthorizationHTTP01("acme.wtf", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("lego.wtf", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("mydomain.wtf", acme.StatusProcessing),
+	}
+
+	err := prober.Solve(authz)
+	require.NoError(t, err)
+
+	assert.Zero(t, solvr.preSolveCalls)
+	assert.Zero(t, solvr.cleanUpCalls)
+
+	require.Len(t, solvr.preSolveBatchCalls, 1)
+	assert.Len(t, solvr.preSolveBatchCalls[0], 3)
+
+	require.Len(t, solvr.cleanUpBatchCalls, 1)
+	assert.Len(t, solvr.cleanUpBatchCalls[0], 3)
+}
+
+func TestProber_Solve_batchError(t *testing.T) {
+	solvr := &batchSolverMock{
+		preSolverMock: preSolverMock{
+			preSolve: map[string]error{
+				"acme.wtf": errors.New("preSolve error"),
+			},
+			solve:   map[string]error{},
+			cleanUp: map[string]error{},
+		},
+	}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	authz := []acme.Authorization{
+		createStubAuthorizationHTTP01("acme.wtf", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("lego.wtf", acme.StatusProcessing),
+	}
+
+	err := prober.Solve(authz)
+	require.EqualError(t, err, `error: one or more domains had a problem:
+[acme.wtf] preSolve error
+[lego.wtf] preSolve error
+`)
+
+	require.Len(t, solvr.cleanUpBatchCalls, 1)
+}
+
+func TestProber_PreSolve_SolvePresented(t *testing.T) {
+	solvr := &batchSolverMock{
+		preSolverMock: preSolverMock{
+			preSolve: map[string]error{},
+			solve:    map[string]error{},
+			cleanUp:  map[string]error{},
+		},
+	}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	authz := []acme.Authorization{
+		createStubAuthorizationHTTP01("acme.wtf", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("lego.wtf", acme.StatusProcessing),
+	}
+
+	err := prober.PreSolve(authz)
+	require.NoError(t, err)
+
+	require.Len(t, solvr.preSolveBatchCalls, 1)
+	assert.Empty(t, solvr.cleanUpBatchCalls)
+
+	err = prober.SolvePresented(authz)
+	require.NoError(t, err)
+
+	// the challenges are not submitted again.
+	require.Len(t, solvr.preSolveBatchCalls, 1)
+	require.Len(t, solvr.cleanUpBatchCalls, 1)
+	assert.Len(t, solvr.cleanUpBatchCalls[0], 2)
+}
+
+func TestProber_SolveWithReport(t *testing.T) {
+	manager := &SolverManager{
+		solvers: map[challenge.Type]solver{
+			challenge.HTTP01: &preSolverMock{},
+			challenge.DNS01:  &preSolverMock{},
+		},
+	}
+
+	prober := &Prober{solverManager: manager}
+
+	wildcard := createStubAuthorizationHTTP01("example.com", acme.StatusPending)
+	wildcard.Wildcard = true
+	wildcard.Challenges = []acme.Challenge{{Type: challenge.DNS01.String()}}
+
+	both := createStubAuthorizationHTTP01("www.example.com", acme.StatusPending)
+	both.Challenges = append(both.Challenges, acme.Challenge{Type: challenge.DNS01.String()})
+
+	reused := createStubAuthorizationHTTP01("api.example.com", acme.StatusValid)
+	reused.Challenges = []acme.Challenge{{Type: challenge.DNS01.String(), Status: acme.StatusValid}}
+
+	authz := []acme.Authorization{
+		createStubAuthorizationHTTP01("example.com", acme.StatusPending),
+		wildcard,
+		both,
+		reused,
+	}
+
+	types, err := prober.SolveWithReport(authz)
+	require.NoError(t, err)
+
+	expected := map[string]challenge.Type{
+		"example.com":   challenge.HTTP01,
+		"*.example.com": challenge.DNS01,
+		// the challenges are sorted by type (the first solver available is used).
+		"www.example.com": challenge.HTTP01,
+		"api.example.com": challenge.DNS01,
+	}
+
+	assert.Equal(t, expected, types)
+}
+
+func TestProber_SolveWithReport_failure(t *testing.T) {
+	manager := &SolverManager{
+		solvers: map[challenge.Type]solver{
+			challenge.HTTP01: &preSolverMock{solve: map[string]error{"example.com": errors.New("solve error")}},
+		},
+	}
+
+	prober := &Prober{solverManager: manager}
+
+	types, err := prober.SolveWithReport([]acme.Authorization{createStubAuthorizationHTTP01("example.com", acme.StatusPending)})
+	require.Error(t, err)
+
+	assert.Nil(t, types)
+}
@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -12,9 +13,11 @@ import (
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/challenge/email"
 	"github.com/go-acme/lego/v4/challenge/http01"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/clock"
 )
 
 type byType []acme.Challenge
@@ -26,30 +29,55 @@ func (a byType) Less(i, j int) bool { return a[i].Type > a[j].Type }
 type SolverManager struct {
 	core    *api.Core
 	solvers map[challenge.Type]solver
+
+	preference        []challenge.Type
+	domainPreferences map[string][]challenge.Type
+
+	validationTimeout time.Duration
+	orderDeadlineAt   time.Time
+	orderTimeout      time.Duration
+
+	clock clock.Clock
 }
 
-func NewSolversManager(core *api.Core) *SolverManager {
-	return &SolverManager{
+func NewSolversManager(core *api.Core, opts ...Option) *SolverManager {
+	c := &SolverManager{
 		solvers: map[challenge.Type]solver{},
 		core:    core,
+		clock:   clock.Real,
+	}
+
+	for _, opt := range opts {
+		err := opt(c)
+		if err != nil {
+			log.Infof("solver manager option error: %v", err)
+		}
 	}
+
+	return c
 }
 
 // SetHTTP01Provider specifies a custom provider p that can solve the given HTTP-01 challenge.
 func (c *SolverManager) SetHTTP01Provider(p challenge.Provider) error {
-	c.solvers[challenge.HTTP01] = http01.NewChallenge(c.core, validate, p)
+	c.solvers[challenge.HTTP01] = http01.NewChallenge(c.core, c.validate, p)
 	return nil
 }
 
 // SetTLSALPN01Provider specifies a custom provider p that can solve the given TLS-ALPN-01 challenge.
 func (c *SolverManager) SetTLSALPN01Provider(p challenge.Provider) error {
-	c.solvers[challenge.TLSALPN01] = tlsalpn01.NewChallenge(c.core, validate, p)
+	c.solvers[challenge.TLSALPN01] = tlsalpn01.NewChallenge(c.core, c.validate, p)
 	return nil
 }
 
 // SetDNS01Provider specifies a custom provider p that can solve the given DNS-01 challenge.
 func (c *SolverManager) SetDNS01Provider(p challenge.Provider, opts ...dns01.ChallengeOption) error {
-	c.solvers[challenge.DNS01] = dns01.NewChallenge(c.core, validate, p, opts...)
+	c.solvers[challenge.DNS01] = dns01.NewChallenge(c.core, c.validate, p, opts...)
+	return nil
+}
+
+// SetEmailReplyProvider specifies a mailbox handler p that can solve the given email-reply-00 challenge (S/MIME).
+func (c *SolverManager) SetEmailReplyProvider(p email.Provider) error {
+	c.solvers[challenge.EMAILREPLY00] = email.NewChallenge(c.core, c.validate, p)
 	return nil
 }
 
@@ -58,24 +86,41 @@ func (c *SolverManager) Remove(chlgType challenge.Type) {
 	delete(c.solvers, chlgType)
 }
 
-// Checks all challenges from the server in order and returns the first matching solver.
-func (c *SolverManager) chooseSolver(authz acme.Authorization) solver {
+// Checks all challenges from the server in order and returns the first matching solver, and the type of its challenge.
+// If a challenge preference is defined (see WithChallengePreference), the first preferred challenge offered by the server is used.
+func (c *SolverManager) chooseSolver(authz acme.Authorization) (solver, challenge.Type, error) {
 	// Allow to have a deterministic challenge order
 	sort.Sort(byType(authz.Challenges))
 
 	domain := challenge.GetTargetedDomain(authz)
+
+	if types := c.getPreference(authz); len(types) > 0 {
+		return c.choosePreferredSolver(authz, types)
+	}
+
 	for _, chlg := range authz.Challenges {
 		if solvr, ok := c.solvers[challenge.Type(chlg.Type)]; ok {
 			log.Infof("[%s] acme: use %s solver", domain, chlg.Type)
-			return solvr
+			return solvr, challenge.Type(chlg.Type), nil
 		}
 		log.Infof("[%s] acme: Could not find solver for: %s", domain, chlg.Type)
 	}
 
-	return nil
+	return nil, "", fmt.Errorf("[%s] acme: could not determine solvers", domain)
 }
 
 func validate(core *api.Core, domain string, chlg acme.Challenge) error {
+	return validateWithTimeout(core, domain, chlg, 0)
+}
+
+// validate validates the challenge with the ACME server, within the validation timeout (see WithValidationTimeout).
+func (c *SolverManager) validate(core *api.Core, domain string, chlg acme.Challenge) error {
+	return validateWithTimeout(core, domain, chlg, c.validationTimeout)
+}
+
+// validateWithTimeout triggers the validation of the challenge, and polls the authorization until it is valid or invalid.
+// If timeout is 0, the polling stops after 100 times the Retry-After delay.
+func validateWithTimeout(core *api.Core, domain string, chlg acme.Challenge, timeout time.Duration) error {
 	chlng, err := core.Challenges.New(chlg.URL)
 	if err != nil {
 		return fmt.Errorf("failed to initiate challenge: %w", err)
@@ -106,6 +151,19 @@ func validate(core *api.Core, domain string, chlg acme.Challenge) error {
 	bo.MaxInterval = 10 * initialInterval
 	bo.MaxElapsedTime = 100 * initialInterval
 
+	var b backoff.BackOff = bo
+
+	if timeout > 0 {
+		bo.MaxElapsedTime = 0
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		b = backoff.WithContext(bo, ctx)
+	}
+
+	status := chlng.Status
+
 	// After the path is sent, the ACME server will access our server.
 	// Repeatedly check the server for an updated status on our request.
 	operation := func() error {
@@ -114,6 +172,8 @@ func validate(core *api.Core, domain string, chlg acme.Challenge) error {
 			return backoff.Permanent(err)
 		}
 
+		status = authz.Status
+
 		valid, err := checkAuthorizationStatus(authz)
 		if err != nil {
 			return backoff.Permanent(err)
@@ -127,7 +187,12 @@ func validate(core *api.Core, domain string, chlg acme.Challenge) error {
 		return errors.New("the server didn't respond to our request")
 	}
 
-	return backoff.Retry(operation, bo)
+	err = backoff.Retry(operation, b)
+	if timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+		return &ValidationTimeoutError{Domain: domain, AuthorizationURL: chlng.AuthorizationURL, Status: status, Timeout: timeout}
+	}
+
+	return err
 }
 
 func checkChallengeStatus(chlng acme.ExtendedChallenge) (bool, error) {
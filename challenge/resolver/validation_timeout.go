@@ -0,0 +1,36 @@
+package resolver
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithValidationTimeout bounds the polling of the authorization after the validation of a challenge is triggered.
+// If the authorization is still pending (or processing) after the timeout, the validation fails with a ValidationTimeoutError,
+// instead of waiting for a server which never completes the validation.
+// An invalid authorization still fails immediately, with the problem reported by the server.
+//
+// Without timeout, the polling stops after 100 times the Retry-After delay of the challenge.
+func WithValidationTimeout(timeout time.Duration) Option {
+	return func(c *SolverManager) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid validation timeout: %s", timeout)
+		}
+
+		c.validationTimeout = timeout
+		return nil
+	}
+}
+
+// ValidationTimeoutError is returned when the authorization is still not valid after the validation timeout (see WithValidationTimeout).
+type ValidationTimeoutError struct {
+	Domain           string
+	AuthorizationURL string
+	// Status is the last status of the authorization.
+	Status  string
+	Timeout time.Duration
+}
+
+func (e *ValidationTimeoutError) Error() string {
+	return fmt.Sprintf("[%s] acme: the authorization is still %s after %s (%s)", e.Domain, e.Status, e.Timeout, e.AuthorizationURL)
+}
@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithValidationTimeout(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		retryAfter    string
+		authzStatus   string
+		expectedError string
+		timeout       bool
+	}{
+		{
+			desc:          "stuck pending",
+			retryAfter:    "1",
+			authzStatus:   acme.StatusPending,
+			expectedError: "[example.com] acme: the authorization is still pending after 200ms",
+			timeout:       true,
+		},
+		{
+			desc:          "invalid",
+			retryAfter:    "0",
+			authzStatus:   acme.StatusInvalid,
+			expectedError: "acme: error: 403 :: urn:ietf:params:acme:error:unauthorized :: the TXT record is missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			mux, apiURL := tester.SetupFakeAPI(t)
+
+			privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+			require.NoError(t, err)
+
+			mux.HandleFunc("POST /chlg", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Link", "<"+apiURL+`/authz>; rel="up"`)
+				w.Header().Set("Retry-After", test.retryAfter)
+
+				_ = tester.WriteJSONResponse(w, acme.Challenge{Type: "dns-01", Status: acme.StatusPending, URL: apiURL + "/chlg", Token: "token"})
+			})
+
+			mux.HandleFunc("POST /authz", func(w http.ResponseWriter, _ *http.Request) {
+				authz := acme.Authorization{Status: test.authzStatus}
+
+				if test.authzStatus == acme.StatusInvalid {
+					authz.Challenges = []acme.Challenge{{
+						Status: acme.StatusInvalid,
+						Error: &acme.ProblemDetails{
+							Type:       "urn:ietf:params:acme:error:unauthorized",
+							Detail:     "the TXT record is missing",
+							HTTPStatus: http.StatusForbidden,
+						},
+					}}
+				}
+
+				_ = tester.WriteJSONResponse(w, authz)
+			})
+
+			core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+			require.NoError(t, err)
+
+			manager := NewSolversManager(core, WithValidationTimeout(200*time.Millisecond))
+
+			start := time.Now()
+
+			err = manager.validate(core, "example.com", acme.Challenge{Type: "dns-01", Token: "token", URL: apiURL + "/chlg"})
+			require.ErrorContains(t, err, test.expectedError)
+
+			assert.Less(t, time.Since(start), 2*time.Second)
+
+			var timeoutErr *ValidationTimeoutError
+			assert.Equal(t, test.timeout, errors.As(err, &timeoutErr))
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package tlsalpn01
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509/pkix"
@@ -72,7 +72,14 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 
 // ChallengeBlocks returns PEM blocks (certPEMBlock, keyPEMBlock) with the acmeValidation-v1 extension
 // and domain name for the `tls-alpn-01` challenge.
+// The key of the certificate is an RSA 2048 key.
 func ChallengeBlocks(domain, keyAuth string) ([]byte, []byte, error) {
+	return ChallengeBlocksWithKeyType(domain, keyAuth, certcrypto.RSA2048)
+}
+
+// ChallengeBlocksWithKeyType is like ChallengeBlocks but generates a key of the given type (e.g. certcrypto.EC256).
+// Only the key differs, the acmeValidation-v1 extension is the same.
+func ChallengeBlocksWithKeyType(domain, keyAuth string, keyType certcrypto.KeyType) ([]byte, []byte, error) {
 	// Compute the SHA-256 digest of the key authorization.
 	zBytes := sha256.Sum256([]byte(keyAuth))
 
@@ -92,35 +99,44 @@ func ChallengeBlocks(domain, keyAuth string) ([]byte, []byte, error) {
 		},
 	}
 
-	// Generate a new RSA key for the certificates.
-	tempPrivateKey, err := certcrypto.GeneratePrivateKey(certcrypto.RSA2048)
+	// Generate a new key for the certificates.
+	tempPrivateKey, err := certcrypto.GeneratePrivateKey(keyType)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	rsaPrivateKey := tempPrivateKey.(*rsa.PrivateKey)
+	signer, ok := tempPrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported key type: %s", keyType)
+	}
 
 	// Generate the PEM certificate using the provided private key, domain, and extra extensions.
-	tempCertPEM, err := certcrypto.GeneratePemCert(rsaPrivateKey, domain, extensions)
+	tempCertPEM, err := certcrypto.GeneratePemCert(signer, domain, extensions)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Encode the private key into a PEM format. We'll need to use it to generate the x509 keypair.
-	rsaPrivatePEM := certcrypto.PEMEncode(rsaPrivateKey)
+	privatePEM := certcrypto.PEMEncode(tempPrivateKey)
 
-	return tempCertPEM, rsaPrivatePEM, nil
+	return tempCertPEM, privatePEM, nil
 }
 
 // ChallengeCert returns a certificate with the acmeValidation-v1 extension
 // and domain name for the `tls-alpn-01` challenge.
+// The key of the certificate is an RSA 2048 key.
 func ChallengeCert(domain, keyAuth string) (*tls.Certificate, error) {
-	tempCertPEM, rsaPrivatePEM, err := ChallengeBlocks(domain, keyAuth)
+	return ChallengeCertWithKeyType(domain, keyAuth, certcrypto.RSA2048)
+}
+
+// ChallengeCertWithKeyType is like ChallengeCert but generates a key of the given type (e.g. certcrypto.EC256).
+func ChallengeCertWithKeyType(domain, keyAuth string, keyType certcrypto.KeyType) (*tls.Certificate, error) {
+	tempCertPEM, privatePEM, err := ChallengeBlocksWithKeyType(domain, keyAuth, keyType)
 	if err != nil {
 		return nil, err
 	}
 
-	cert, err := tls.X509KeyPair(tempCertPEM, rsaPrivatePEM)
+	cert, err := tls.X509KeyPair(tempCertPEM, privatePEM)
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/log"
 )
 
@@ -27,13 +28,46 @@ type ProviderServer struct {
 	iface    string
 	port     string
 	listener net.Listener
+
+	keyType certcrypto.KeyType
+}
+
+// ProviderServerOption is an option of the ProviderServer.
+type ProviderServerOption func(*ProviderServer) error
+
+// WithKeyType defines the type of the key of the challenge certificate (default: certcrypto.RSA2048),
+// e.g. certcrypto.EC256 for an ECDSA certificate (faster to generate, and required by the validators only offering ECDSA cipher suites).
+func WithKeyType(keyType certcrypto.KeyType) ProviderServerOption {
+	return func(s *ProviderServer) error {
+		switch keyType {
+		case certcrypto.EC256, certcrypto.EC384, certcrypto.RSA2048, certcrypto.RSA3072, certcrypto.RSA4096:
+			s.keyType = keyType
+			return nil
+		default:
+			return fmt.Errorf("unsupported key type for the challenge certificate: %s", keyType)
+		}
+	}
 }
 
 // NewProviderServer creates a new ProviderServer on the selected interface and port.
 // Setting iface and / or port to an empty string will make the server fall back to
 // the "any" interface and port 443 respectively.
-func NewProviderServer(iface, port string) *ProviderServer {
-	return &ProviderServer{iface: iface, port: port}
+//
+// The port is only the local port the server binds to:
+// the ACME server will always connect to port 443 of the domain being validated.
+// When a different port is used (e.g. behind a NAT or a load balancer),
+// the external port 443 must be forwarded to the chosen local port.
+func NewProviderServer(iface, port string, opts ...ProviderServerOption) *ProviderServer {
+	s := &ProviderServer{iface: iface, port: port}
+
+	for _, opt := range opts {
+		err := opt(s)
+		if err != nil {
+			log.Infof("server option error: %v", err)
+		}
+	}
+
+	return s
 }
 
 func (s *ProviderServer) GetAddress() string {
@@ -49,7 +83,12 @@ func (s *ProviderServer) Present(domain, token, keyAuth string) error {
 	}
 
 	// Generate the challenge certificate using the provided keyAuth and domain.
-	cert, err := ChallengeCert(domain, keyAuth)
+	keyType := s.keyType
+	if keyType == "" {
+		keyType = certcrypto.RSA2048
+	}
+
+	cert, err := ChallengeCertWithKeyType(domain, keyAuth, keyType)
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/asn1"
 	"net"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/miekg/dns"
@@ -92,6 +94,59 @@ func TestChallenge(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestChallenge_customLocalPort(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	domain := "localhost"
+
+	// The provider binds to an ephemeral local port,
+	// the external port 443 is expected to be forwarded to it.
+	provider := NewProviderServer("127.0.0.1", "0")
+
+	mockValidate := func(_ *api.Core, _ string, chlng acme.Challenge) error {
+		require.NotNil(t, provider.listener)
+
+		conn, err := tls.Dial("tcp", provider.listener.Addr().String(), &tls.Config{
+			ServerName:         domain,
+			InsecureSkipVerify: true,
+			NextProtos:         []string{ACMETLS1Protocol},
+		})
+		require.NoError(t, err, "Expected to connect to challenge server without an error")
+
+		defer func() { _ = conn.Close() }()
+
+		connState := conn.ConnectionState()
+		assert.Equal(t, ACMETLS1Protocol, connState.NegotiatedProtocol)
+		require.Len(t, connState.PeerCertificates, 1, "Expected the challenge server to return exactly one certificate")
+
+		remoteCert := connState.PeerCertificates[0]
+		assert.Equal(t, []string{domain}, remoteCert.DNSNames)
+
+		return nil
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	solver := NewChallenge(core, mockValidate, provider)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{
+			Type:  "dns",
+			Value: domain,
+		},
+		Challenges: []acme.Challenge{
+			{Type: challenge.TLSALPN01.String(), Token: "tlsalpn1"},
+		},
+	}
+
+	err = solver.Solve(authz)
+	require.NoError(t, err)
+}
+
 func TestChallengeInvalidPort(t *testing.T) {
 	_, apiURL := tester.SetupFakeAPI(t)
 
@@ -191,3 +246,115 @@ func TestChallengeIPaddress(t *testing.T) {
 
 	require.NoError(t, solver.Solve(authz))
 }
+
+func TestChallenge_ecdsa(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	domain := "localhost"
+
+	provider := NewProviderServer("127.0.0.1", "0", WithKeyType(certcrypto.EC256))
+
+	mockValidate := func(_ *api.Core, _ string, chlng acme.Challenge) error {
+		require.NotNil(t, provider.listener)
+
+		// A validator only offering ECDSA cipher suites.
+		conn, err := tls.Dial("tcp", provider.listener.Addr().String(), &tls.Config{
+			ServerName:         domain,
+			InsecureSkipVerify: true,
+			NextProtos:         []string{ACMETLS1Protocol},
+			MaxVersion:         tls.VersionTLS12,
+			CipherSuites: []uint16{
+				tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+				tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			},
+		})
+		require.NoError(t, err, "Expected to connect to challenge server without an error")
+
+		defer func() { _ = conn.Close() }()
+
+		connState := conn.ConnectionState()
+		assert.Equal(t, ACMETLS1Protocol, connState.NegotiatedProtocol)
+		require.Len(t, connState.PeerCertificates, 1, "Expected the challenge server to return exactly one certificate")
+
+		remoteCert := connState.PeerCertificates[0]
+		assert.Equal(t, []string{domain}, remoteCert.DNSNames)
+		assert.Equal(t, x509.ECDSA, remoteCert.PublicKeyAlgorithm)
+
+		assertAcmeIdentifier(t, remoteCert, chlng.KeyAuthorization)
+
+		return nil
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	solver := NewChallenge(core, mockValidate, provider)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{
+			Type:  "dns",
+			Value: domain,
+		},
+		Challenges: []acme.Challenge{
+			{Type: challenge.TLSALPN01.String(), Token: "tlsalpn1"},
+		},
+	}
+
+	err = solver.Solve(authz)
+	require.NoError(t, err)
+}
+
+func TestChallengeCertWithKeyType(t *testing.T) {
+	testCases := []struct {
+		keyType           certcrypto.KeyType
+		expectedAlgorithm x509.PublicKeyAlgorithm
+		expectedKeyUsage  x509.KeyUsage
+	}{
+		{keyType: certcrypto.EC256, expectedAlgorithm: x509.ECDSA, expectedKeyUsage: x509.KeyUsageDigitalSignature},
+		{keyType: certcrypto.EC384, expectedAlgorithm: x509.ECDSA, expectedKeyUsage: x509.KeyUsageDigitalSignature},
+		{keyType: certcrypto.RSA2048, expectedAlgorithm: x509.RSA, expectedKeyUsage: x509.KeyUsageKeyEncipherment},
+	}
+
+	for _, test := range testCases {
+		t.Run(string(test.keyType), func(t *testing.T) {
+			cert, err := ChallengeCertWithKeyType("example.com", "keyAuth", test.keyType)
+			require.NoError(t, err)
+
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedAlgorithm, leaf.PublicKeyAlgorithm)
+			assert.Equal(t, test.expectedKeyUsage, leaf.KeyUsage)
+			assert.Equal(t, []string{"example.com"}, leaf.DNSNames)
+
+			assertAcmeIdentifier(t, leaf, "keyAuth")
+		})
+	}
+}
+
+func TestWithKeyType_unsupported(t *testing.T) {
+	err := WithKeyType(certcrypto.RSA8192)(&ProviderServer{})
+	require.EqualError(t, err, "unsupported key type for the challenge certificate: 8192")
+}
+
+func assertAcmeIdentifier(t *testing.T, cert *x509.Certificate, keyAuth string) {
+	t.Helper()
+
+	zBytes := sha256.Sum256([]byte(keyAuth))
+	value, err := asn1.Marshal(zBytes[:sha256.Size])
+	require.NoError(t, err)
+
+	for _, ext := range cert.Extensions {
+		if idPeAcmeIdentifierV1.Equal(ext.Id) {
+			assert.True(t, ext.Critical, "Expected the id-pe-acmeIdentifier extension to be marked as critical")
+			assert.Equal(t, value, ext.Value)
+
+			return
+		}
+	}
+
+	t.Error("Expected the challenge certificate to contain an extension with the id-pe-acmeIdentifier id")
+}
@@ -28,12 +28,14 @@ const (
 )
 
 const (
-	issuerExt   = ".issuer.crt"
-	certExt     = ".crt"
-	keyExt      = ".key"
-	pemExt      = ".pem"
-	pfxExt      = ".pfx"
-	resourceExt = ".json"
+	issuerExt    = ".issuer.crt"
+	certExt      = ".crt"
+	keyExt       = ".key"
+	pemExt       = ".pem"
+	derExt       = ".der"
+	issuerDERExt = ".issuer.der"
+	pfxExt       = ".pfx"
+	resourceExt  = ".json"
 )
 
 // CertificatesStorage a certificates' storage.
@@ -53,6 +55,7 @@ type CertificatesStorage struct {
 	rootPath    string
 	archivePath string
 	pem         bool
+	der         bool
 	pfx         bool
 	pfxPassword string
 	pfxFormat   string
@@ -73,6 +76,7 @@ func NewCertificatesStorage(ctx *cli.Context) *CertificatesStorage {
 		rootPath:    filepath.Join(ctx.String(flgPath), baseCertificatesFolderName),
 		archivePath: filepath.Join(ctx.String(flgPath), baseArchivesFolderName),
 		pem:         ctx.Bool(flgPEM),
+		der:         ctx.Bool(flgDER),
 		pfx:         ctx.Bool(flgPFX),
 		pfxPassword: ctx.String(flgPFXPass),
 		pfxFormat:   pfxFormat,
@@ -115,6 +119,13 @@ func (s *CertificatesStorage) SaveResource(certRes *certificate.Resource) {
 		}
 	}
 
+	if s.der {
+		err = s.WriteDERFiles(domain, certRes)
+		if err != nil {
+			log.Fatalf("Unable to save DER files for domain %s\n\t%v", domain, err)
+		}
+	}
+
 	// if we were given a CSR, we don't know the private key
 	if certRes.PrivateKey != nil {
 		err = s.WriteCertificateFiles(domain, certRes)
@@ -217,6 +228,26 @@ func (s *CertificatesStorage) WriteCertificateFiles(domain string, certRes *cert
 	return nil
 }
 
+// WriteDERFiles writes the certificate (leaf) in the .der file,
+// and the issuer certificates (concatenated, in the order of the chain) in the .issuer.der file.
+func (s *CertificatesStorage) WriteDERFiles(domain string, certRes *certificate.Resource) error {
+	ders, err := certRes.CertificateDER()
+	if err != nil {
+		return fmt.Errorf("unable to get the DER certificates for domain %s: %w", domain, err)
+	}
+
+	err = s.WriteFile(domain, derExt, ders[0])
+	if err != nil {
+		return err
+	}
+
+	if len(ders) == 1 {
+		return nil
+	}
+
+	return s.WriteFile(domain, issuerDERExt, bytes.Join(ders[1:], nil))
+}
+
 func (s *CertificatesStorage) WritePFXFile(domain string, certRes *certificate.Resource) error {
 	certPemBlock, _ := pem.Decode(certRes.Certificate)
 	if certPemBlock == nil {
@@ -278,7 +309,7 @@ func (s *CertificatesStorage) MoveToArchive(domain string) error {
 	}
 
 	for _, oldFile := range matches {
-		if strings.TrimSuffix(oldFile, filepath.Ext(oldFile)) != baseFilename && oldFile != baseFilename+issuerExt {
+		if strings.TrimSuffix(oldFile, filepath.Ext(oldFile)) != baseFilename && oldFile != baseFilename+issuerExt && oldFile != baseFilename+issuerDERExt {
 			continue
 		}
 
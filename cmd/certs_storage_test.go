@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"os"
 	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -98,12 +106,62 @@ func TestCertificatesStorage_MoveToArchive_ambiguousDomain(t *testing.T) {
 	assert.Regexp(t, `\d+\.`+regexp.QuoteMeta(domain), archive[0].Name())
 }
 
+func TestCertificatesStorage_WriteDERFiles(t *testing.T) {
+	storage := CertificatesStorage{rootPath: t.TempDir()}
+
+	issuerKey, err := certcrypto.GeneratePrivateKey(certcrypto.EC256)
+	require.NoError(t, err)
+
+	issuerTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "issuer"},
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	issuerDER, err := x509.CreateCertificate(rand.Reader, issuerTemplate, issuerTemplate, issuerKey.(crypto.Signer).Public(), issuerKey)
+	require.NoError(t, err)
+
+	leafKey, err := certcrypto.GeneratePrivateKey(certcrypto.EC256)
+	require.NoError(t, err)
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		DNSNames:     []string{"example.com"},
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, issuerTemplate, leafKey.(crypto.Signer).Public(), issuerKey)
+	require.NoError(t, err)
+
+	certRes := &certificate.Resource{
+		Domain: "example.com",
+		Certificate: append(certcrypto.PEMEncode(certcrypto.DERCertificateBytes(leafDER)),
+			certcrypto.PEMEncode(certcrypto.DERCertificateBytes(issuerDER))...),
+	}
+
+	err = storage.WriteDERFiles("example.com", certRes)
+	require.NoError(t, err)
+
+	leaf, err := os.ReadFile(filepath.Join(storage.rootPath, "example.com"+derExt))
+	require.NoError(t, err)
+
+	assert.Equal(t, leafDER, leaf)
+
+	issuer, err := os.ReadFile(filepath.Join(storage.rootPath, "example.com"+issuerDERExt))
+	require.NoError(t, err)
+
+	assert.Equal(t, issuerDER, issuer)
+}
+
 func generateTestFiles(t *testing.T, dir, domain string) []string {
 	t.Helper()
 
 	var filenames []string
 
-	for _, ext := range []string{issuerExt, certExt, keyExt, pemExt, pfxExt, resourceExt} {
+	for _, ext := range []string{issuerExt, certExt, keyExt, pemExt, derExt, issuerDERExt, pfxExt, resourceExt} {
 		filename := filepath.Join(dir, domain+ext)
 		err := os.WriteFile(filename, []byte("test"), 0o666)
 		require.NoError(t, err)
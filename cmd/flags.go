@@ -29,6 +29,9 @@ const (
 	flgHTTPWebroot              = "http.webroot"
 	flgHTTPMemcachedHost        = "http.memcached-host"
 	flgHTTPS3Bucket             = "http.s3-bucket"
+	flgHTTPGCSBucket            = "http.gcs-bucket"
+	flgHTTPGCSPrefix            = "http.gcs-prefix"
+	flgHTTPGCSNoACL             = "http.gcs-no-acl"
 	flgTLS                      = "tls"
 	flgTLSPort                  = "tls.port"
 	flgDNS                      = "dns"
@@ -41,6 +44,7 @@ const (
 	flgTLSSkipVerify            = "tls-skip-verify"
 	flgDNSTimeout               = "dns-timeout"
 	flgPEM                      = "pem"
+	flgDER                      = "der"
 	flgPFX                      = "pfx"
 	flgPFXPass                  = "pfx.pass"
 	flgPFXFormat                = "pfx.format"
@@ -136,6 +140,19 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Name:  flgHTTPS3Bucket,
 			Usage: "Set the S3 bucket name to use for HTTP-01 based challenges. Challenges will be written to the S3 bucket.",
 		},
+		&cli.StringFlag{
+			Name: flgHTTPGCSBucket,
+			Usage: "Set the Google Cloud Storage bucket name to use for HTTP-01 based challenges. Challenges will be written to the GCS bucket." +
+				" The credentials are read from GCS_SERVICE_ACCOUNT_FILE, or from the Application Default Credentials.",
+		},
+		&cli.StringFlag{
+			Name:  flgHTTPGCSPrefix,
+			Usage: "Set the prefix of the objects written to the GCS bucket.",
+		},
+		&cli.BoolFlag{
+			Name:  flgHTTPGCSNoACL,
+			Usage: "Do not set the public-read ACL on the objects written to the GCS bucket. Required for the buckets with uniform bucket-level access.",
+		},
 		&cli.BoolFlag{
 			Name:  flgTLS,
 			Usage: "Use the TLS-ALPN-01 challenge to solve challenges. Can be mixed with other types of challenges.",
@@ -189,6 +206,10 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Name:  flgPEM,
 			Usage: "Generate an additional .pem (base64) file by concatenating the .key and .crt files together.",
 		},
+		&cli.BoolFlag{
+			Name:  flgDER,
+			Usage: "Generate additional .der (binary) files: the certificate in the .der file, and the issuer certificates in the .issuer.der file.",
+		},
 		&cli.BoolFlag{
 			Name:    flgPFX,
 			Usage:   "Generate an additional .pfx (PKCS#12) file by concatenating the .key and .crt and issuer .crt files together.",
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"net"
+	"os"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/providers/dns"
+	"github.com/go-acme/lego/v4/providers/http/gcs"
 	"github.com/go-acme/lego/v4/providers/http/memcached"
 	"github.com/go-acme/lego/v4/providers/http/s3"
 	"github.com/go-acme/lego/v4/providers/http/webroot"
@@ -67,6 +69,17 @@ func setupHTTPProvider(ctx *cli.Context) challenge.Provider {
 			log.Fatal(err)
 		}
 		return ps
+	case ctx.IsSet(flgHTTPGCSBucket):
+		ps, err := gcs.NewHTTPProviderConfig(&gcs.Config{
+			Bucket:             ctx.String(flgHTTPGCSBucket),
+			Prefix:             ctx.String(flgHTTPGCSPrefix),
+			NoACL:              ctx.Bool(flgHTTPGCSNoACL),
+			ServiceAccountFile: os.Getenv("GCS_SERVICE_ACCOUNT_FILE"),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		return ps
 	case ctx.IsSet(flgHTTPPort):
 		iface := ctx.String(flgHTTPPort)
 		if !strings.Contains(iface, ":") {
@@ -84,6 +84,7 @@ func allDNSCodes() string {
 		"ipv64",
 		"iwantmyname",
 		"joker",
+		"knot",
 		"liara",
 		"lightsail",
 		"limacity",
@@ -711,7 +712,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "DESEC_HTTP_TIMEOUT":	API request timeout`)
 		ew.writeln(`	- "DESEC_POLLING_INTERVAL":	Time between DNS propagation check`)
 		ew.writeln(`	- "DESEC_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation`)
-		ew.writeln(`	- "DESEC_TTL":	The TTL of the TXT record used for the DNS challenge`)
+		ew.writeln(`	- "DESEC_TTL":	The TTL of the TXT record used for the DNS challenge (minimum 3600)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/desec`)
@@ -1177,7 +1178,9 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "GANDIV5_HTTP_TIMEOUT":	API request timeout`)
 		ew.writeln(`	- "GANDIV5_POLLING_INTERVAL":	Time between DNS propagation check`)
 		ew.writeln(`	- "GANDIV5_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation`)
+		ew.writeln(`	- "GANDIV5_SNAPSHOT":	Snapshot of the zone before the creation of the record, the TXT records are rolled back to the snapshot on failure (Default: false)`)
 		ew.writeln(`	- "GANDIV5_TTL":	The TTL of the TXT record used for the DNS challenge`)
+		ew.writeln(`	- "GANDIV5_ZONE":	Zone of the records (Default: determined through SOA requests)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/gandiv5`)
@@ -1690,6 +1693,25 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/joker`)
 
+	case "knot":
+		// generated from: providers/dns/knot/knot.toml
+		ew.writeln(`Configuration for Knot DNS.`)
+		ew.writeln(`Code:	'knot'`)
+		ew.writeln(`Since:	'v4.21.0'`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "KNOT_COMMAND_TIMEOUT":	Maximum execution time of a knotc command (Default: 30)`)
+		ew.writeln(`	- "KNOT_KNOTC_PATH":	Path of the knotc binary (Default: knotc)`)
+		ew.writeln(`	- "KNOT_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "KNOT_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
+		ew.writeln(`	- "KNOT_SOCKET":	Path of the control socket of the server (Default: the default socket of knotc)`)
+		ew.writeln(`	- "KNOT_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+		ew.writeln(`	- "KNOT_ZONE":	Zone managed by the server (Default: determined through SOA requests)`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/knot`)
+
 	case "liara":
 		// generated from: providers/dns/liara/liara.toml
 		ew.writeln(`Configuration for Liara.`)
@@ -2458,6 +2480,11 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Additional Configuration:`)
 		ew.writeln(`	- "RFC2136_DNS_TIMEOUT":	API request timeout`)
+		ew.writeln(`	- "RFC2136_GSS_CCACHE":	Kerberos credentials cache holding the current ticket (GSS-TSIG, Default: the default credentials cache)`)
+		ew.writeln(`	- "RFC2136_GSS_KEYTAB":	Path to the client keytab used to acquire the Kerberos credentials (GSS-TSIG)`)
+		ew.writeln(`	- "RFC2136_GSS_REALM":	Kerberos realm of the DNS server (Default: the default realm of krb5.conf)`)
+		ew.writeln(`	- "RFC2136_GSS_TSIG":	Signs the updates with GSS-TSIG (Kerberos) through 'nsupdate -g', instead of TSIG (Default: false)`)
+		ew.writeln(`	- "RFC2136_NSUPDATE_PATH":	Path to the nsupdate binary (GSS-TSIG, Default: nsupdate)`)
 		ew.writeln(`	- "RFC2136_POLLING_INTERVAL":	Time between DNS propagation check`)
 		ew.writeln(`	- "RFC2136_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation`)
 		ew.writeln(`	- "RFC2136_SEQUENCE_INTERVAL":	Time between sequential requests`)
@@ -3047,6 +3074,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "VULTR_POLLING_INTERVAL":	Time between DNS propagation check`)
 		ew.writeln(`	- "VULTR_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation`)
 		ew.writeln(`	- "VULTR_TTL":	The TTL of the TXT record used for the DNS challenge`)
+		ew.writeln(`	- "VULTR_ZONE":	Domain of the records (Default: determined through the list of the domains of the account)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/vultr`)
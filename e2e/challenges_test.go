@@ -1,6 +1,7 @@
 package e2e
 
 import (
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
@@ -422,6 +423,59 @@ func TestChallengeTLS_Client_ObtainForCSR(t *testing.T) {
 	assert.NotEmpty(t, resource.CSR)
 }
 
+func TestChallengeHTTP_Client_StartOrder_FinishOrder(t *testing.T) {
+	err := os.Setenv("LEGO_CA_CERTIFICATES", "./fixtures/certs/pebble.minica.pem")
+	require.NoError(t, err)
+	defer func() { _ = os.Unsetenv("LEGO_CA_CERTIFICATES") }()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	user := &fakeUser{privateKey: privateKey}
+	config := lego.NewConfig(user)
+	config.CADirURL = load.PebbleOptions.HealthCheckURL
+
+	client, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	err = client.Challenge.SetHTTP01Provider(http01.NewProviderServer("", "5002"))
+	require.NoError(t, err)
+
+	reg, err := client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+	user.registration = reg
+
+	order, err := client.Certificate.StartOrder(certificate.ObtainRequest{
+		Domains: []string{"acme.wtf"},
+		Bundle:  true,
+	})
+	require.NoError(t, err)
+
+	state, err := order.MarshalState()
+	require.NoError(t, err)
+
+	// The order is finished by another client (ex: another process), using the same account.
+	finishClient, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	err = finishClient.Challenge.SetHTTP01Provider(http01.NewProviderServer("", "5002"))
+	require.NoError(t, err)
+
+	restored := &certificate.OrderResource{}
+	err = restored.UnmarshalState(state)
+	require.NoError(t, err)
+
+	resource, err := finishClient.Certificate.FinishOrder(context.Background(), restored, nil)
+	require.NoError(t, err)
+
+	require.NotNil(t, resource)
+	assert.Equal(t, "acme.wtf", resource.Domain)
+	assert.Regexp(t, `https://localhost:14000/certZ/[\w\d]{14,}`, resource.CertURL)
+	assert.NotEmpty(t, resource.Certificate)
+	assert.NotEmpty(t, resource.IssuerCertificate)
+	assert.NotEmpty(t, resource.PrivateKey)
+}
+
 func TestRegistrar_UpdateAccount(t *testing.T) {
 	err := os.Setenv("LEGO_CA_CERTIFICATES", "./fixtures/certs/pebble.minica.pem")
 	require.NoError(t, err)
@@ -453,6 +507,39 @@ func TestRegistrar_UpdateAccount(t *testing.T) {
 	require.Equal(t, reg.URI, resource.URI)
 }
 
+func TestRegistrar_ResolveAccountByKey(t *testing.T) {
+	err := os.Setenv("LEGO_CA_CERTIFICATES", "./fixtures/certs/pebble.minica.pem")
+	require.NoError(t, err)
+	defer func() { _ = os.Unsetenv("LEGO_CA_CERTIFICATES") }()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	config := lego.NewConfig(&fakeUser{privateKey: privateKey})
+	config.CADirURL = load.PebbleOptions.HealthCheckURL
+
+	// The account doesn't exist yet.
+	worker, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	_, err = worker.Registration.ResolveAccountByKey()
+	require.ErrorIs(t, err, registration.ErrAccountNotFound)
+
+	// Another process creates the account.
+	owner, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	reg, err := owner.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	// The worker discovers the account from the key.
+	resource, err := worker.Registration.ResolveAccountByKey()
+	require.NoError(t, err)
+
+	assert.Equal(t, reg.URI, resource.URI)
+	assert.Equal(t, "valid", resource.Body.Status)
+}
+
 type fakeUser struct {
 	email        string
 	privateKey   crypto.PrivateKey
@@ -0,0 +1,83 @@
+package memorychallenge
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/e2e/loader"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The DNS server of the memory provider, queried by Pebble to validate the challenges.
+const memoryDNSServer = "127.0.0.1:8054"
+
+var load = loader.EnvLoader{
+	PebbleOptions: &loader.CmdOption{
+		HealthCheckURL: "https://localhost:16000/dir",
+		Args:           []string{"-strict", "-config", "fixtures/pebble-config-memory.json", "-dnsserver", memoryDNSServer},
+		Env:            []string{"PEBBLE_VA_NOSLEEP=1", "PEBBLE_WFE_NONCEREJECT=20"},
+		Dir:            "../",
+	},
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(load.MainTest(m))
+}
+
+func TestChallengeDNS_MemoryProvider_Obtain(t *testing.T) {
+	t.Setenv("LEGO_CA_CERTIFICATES", "../fixtures/certs/pebble.minica.pem")
+
+	provider, err := dns01.NewMemoryProviderOn(memoryDNSServer)
+	require.NoError(t, err)
+
+	defer func() { _ = provider.Close() }()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	user := &fakeUser{privateKey: privateKey}
+	config := lego.NewConfig(user)
+	config.CADirURL = "https://localhost:16000/dir"
+
+	client, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	err = client.Challenge.SetDNS01Provider(provider)
+	require.NoError(t, err)
+
+	reg, err := client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+	user.registration = reg
+
+	request := certificate.ObtainRequest{
+		Domains: []string{"*.memory.acme", "memory.acme"},
+		Bundle:  true,
+	}
+
+	resource, err := client.Certificate.Obtain(request)
+	require.NoError(t, err)
+
+	require.NotNil(t, resource)
+	assert.Equal(t, "*.memory.acme", resource.Domain)
+	assert.Regexp(t, `https://localhost:16000/certZ/[\w\d]{14,}`, resource.CertURL)
+	assert.NotEmpty(t, resource.Certificate)
+	assert.NotEmpty(t, resource.IssuerCertificate)
+}
+
+type fakeUser struct {
+	email        string
+	privateKey   crypto.PrivateKey
+	registration *registration.Resource
+}
+
+func (f *fakeUser) GetEmail() string                        { return f.email }
+func (f *fakeUser) GetRegistration() *registration.Resource { return f.registration }
+func (f *fakeUser) GetPrivateKey() crypto.PrivateKey        { return f.privateKey }
@@ -6,10 +6,14 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -25,6 +29,23 @@ const (
 //go:embed dns_providers.go.tmpl
 var srcTemplate string
 
+// Kinds of the NewDefaultConfig function of a provider.
+const (
+	configNone      = "none"      // no NewDefaultConfig function.
+	configValue     = "value"     // func NewDefaultConfig() *Config
+	configWithError = "withError" // func NewDefaultConfig() (*Config, error)
+)
+
+type providerData struct {
+	descriptors.Provider
+
+	RequiredKeys []string
+	OptionalKeys []string
+
+	// DefaultConfig the kind of the NewDefaultConfig function.
+	DefaultConfig string
+}
+
 func main() {
 	err := generate()
 	if err != nil {
@@ -38,6 +59,17 @@ func generate() error {
 		return err
 	}
 
+	data := struct{ Providers []providerData }{}
+
+	for _, provider := range info.Providers {
+		pd, errD := newProviderData(provider)
+		if errD != nil {
+			return errD
+		}
+
+		data.Providers = append(data.Providers, pd)
+	}
+
 	file, err := os.Create(filepath.Join(root, outputPath))
 	if err != nil {
 		return err
@@ -52,7 +84,7 @@ func generate() error {
 				return strings.ReplaceAll(src, "-", "")
 			},
 		}).Parse(srcTemplate),
-	).Execute(b, info)
+	).Execute(b, data)
 	if err != nil {
 		return err
 	}
@@ -72,3 +104,61 @@ func generate() error {
 
 	return nil
 }
+
+func newProviderData(provider descriptors.Provider) (providerData, error) {
+	pd := providerData{Provider: provider}
+
+	if provider.Configuration != nil {
+		pd.RequiredKeys = sortedKeys(provider.Configuration.Credentials)
+		pd.OptionalKeys = sortedKeys(provider.Configuration.Additional)
+	}
+
+	kind, err := getDefaultConfigKind(filepath.Join(root, filepath.Dir(provider.GeneratedFrom)))
+	if err != nil {
+		return providerData{}, fmt.Errorf("%s: %w", provider.Code, err)
+	}
+
+	pd.DefaultConfig = kind
+
+	return pd, nil
+}
+
+// getDefaultConfigKind finds the kind of the NewDefaultConfig function of the provider package.
+func getDefaultConfigKind(dir string) (string, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return "", err
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || fn.Name.Name != "NewDefaultConfig" {
+					continue
+				}
+
+				if fn.Type.Results.NumFields() == 2 {
+					return configWithError, nil
+				}
+
+				return configValue, nil
+			}
+		}
+	}
+
+	return configNone, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,157 @@
+package lego
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCAMock starts a minimal fake ACME server able to issue certificates (with one identifier).
+// The authorizations are created valid, and the certificates are self-signed.
+func setupCAMock(t *testing.T) (*http.ServeMux, string) {
+	t.Helper()
+
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	var csrDER []byte
+
+	mux.HandleFunc("POST /newOrder", func(w http.ResponseWriter, req *http.Request) {
+		var order acme.Order
+
+		err := readJWSPayload(req, &order)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		order.Status = acme.StatusReady
+		order.Finalize = apiURL + "/finalize"
+		order.Authorizations = []string{apiURL + "/authz"}
+
+		w.Header().Set("Location", apiURL+"/order")
+		w.WriteHeader(http.StatusCreated)
+
+		_ = json.NewEncoder(w).Encode(order)
+	})
+
+	mux.HandleFunc("POST /authz", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.Authorization{
+			Status:     acme.StatusValid,
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		})
+	})
+
+	mux.HandleFunc("POST /finalize", func(w http.ResponseWriter, req *http.Request) {
+		var msg acme.CSRMessage
+
+		err := readJWSPayload(req, &msg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		csrDER, err = base64.RawURLEncoding.DecodeString(msg.Csr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_ = tester.WriteJSONResponse(w, acme.Order{
+			Status:      acme.StatusValid,
+			Certificate: apiURL + "/cert",
+		})
+	})
+
+	mux.HandleFunc("POST /cert", func(w http.ResponseWriter, _ *http.Request) {
+		csr, err := x509.ParseCertificateRequest(csrDER)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: csr.Subject.CommonName},
+			DNSNames:     csr.DNSNames,
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(24 * time.Hour),
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, template, csr.PublicKey, key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		_, _ = w.Write(certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der)))
+	})
+
+	return mux, apiURL
+}
+
+// newMockClient creates a client for the fake ACME server.
+func newMockClient(t *testing.T, apiURL string) *Client {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	config := NewConfig(mockUser{
+		email:      "test@test.com",
+		regres:     &registration.Resource{URI: apiURL + "/account"},
+		privatekey: key,
+	})
+	config.CADirURL = apiURL + "/dir"
+
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	return client
+}
+
+// readJWSPayload decodes the payload of a JWS (flattened JSON serialization) without verifying it.
+func readJWSPayload(req *http.Request, v any) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+
+	err = json.Unmarshal(body, &jws)
+	if err != nil {
+		return err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, v)
+}
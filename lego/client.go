@@ -45,15 +45,21 @@ func NewClient(config *Config) (*Client, error) {
 		kid = reg.URI
 	}
 
-	core, err := api.New(config.HTTPClient, config.UserAgent, config.CADirURL, kid, privateKey)
+	core, err := api.New(config.HTTPClient, config.UserAgent, config.CADirURL, kid, privateKey, config.APIOptions...)
 	if err != nil {
 		return nil, err
 	}
 
-	solversManager := resolver.NewSolversManager(core)
+	solversManager := resolver.NewSolversManager(core, config.SolverOptions...)
 
 	prober := resolver.NewProber(solversManager)
-	certifier := certificate.NewCertifier(core, prober, certificate.CertifierOptions{KeyType: config.Certificate.KeyType, Timeout: config.Certificate.Timeout, OverallRequestLimit: config.Certificate.OverallRequestLimit})
+	certifier := certificate.NewCertifier(core, prober, certificate.CertifierOptions{
+		KeyType:             config.Certificate.KeyType,
+		Timeout:             config.Certificate.Timeout,
+		OverallRequestLimit: config.Certificate.OverallRequestLimit,
+		IssuanceAudit:       config.Certificate.IssuanceAudit,
+		RenewBefore:         config.Certificate.RenewBefore,
+	})
 
 	return &Client{
 		Certificate:  certifier,
@@ -68,6 +74,21 @@ func (c *Client) GetToSURL() string {
 	return c.core.GetDirectory().Meta.TermsOfService
 }
 
+// AccountURI returns the URI of the account used by the client (empty if the account is not registered yet).
+// The URI can be used to pin the account in the CAA records of the domains (RFC 8657):
+//
+//	example.com. CAA 0 issue "ca.example; accounturi=<account URI>"
+func (c *Client) AccountURI() string {
+	return c.core.GetAccountURI()
+}
+
+// RateLimitStatus returns the rate-limit status of the CA (limit, remaining requests, reset),
+// derived from the headers of the recent responses, to pace the batches of requests.
+// If the CA doesn't expose its rate limits, the status is unknown (api.RateLimitInfo.Known is false).
+func (c *Client) RateLimitStatus() (api.RateLimitInfo, error) {
+	return c.core.RateLimitStatus()
+}
+
 // GetExternalAccountRequired returns the External Account Binding requirement of the Directory.
 func (c *Client) GetExternalAccountRequired() bool {
 	return c.core.GetDirectory().Meta.ExternalAccountRequired
@@ -11,7 +11,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge/resolver"
 	"github.com/go-acme/lego/v4/registration"
 )
 
@@ -46,6 +49,12 @@ type Config struct {
 	UserAgent   string
 	HTTPClient  *http.Client
 	Certificate CertificateConfig
+
+	// APIOptions are the options of the ACME API client (e.g. api.WithJWSAlgorithm).
+	APIOptions []api.Option
+
+	// SolverOptions are the options of the challenge solvers manager (e.g. resolver.WithChallengePreference).
+	SolverOptions []resolver.Option
 }
 
 func NewConfig(user registration.User) *Config {
@@ -64,9 +73,18 @@ type CertificateConfig struct {
 	KeyType             certcrypto.KeyType
 	Timeout             time.Duration
 	OverallRequestLimit int
+
+	// IssuanceAudit is called with the record of each issued certificate, before the certificate is returned
+	// (see certificate.CertifierOptions.IssuanceAudit).
+	IssuanceAudit func(certificate.AuditRecord) error
+
+	// RenewBefore is the remaining validity under which a certificate is renewed when the renewalInfo endpoint is not available
+	// (see certificate.Certifier.RenewIfDue).
+	RenewBefore time.Duration
 }
 
-// createDefaultHTTPClient Creates an HTTP client with a reasonable timeout value
+// createDefaultHTTPClient Creates an HTTP client with a reasonable timeout value,
+// the TLS session resumption (see api.WithConnectionPool to tune the connection reuse),
 // and potentially a custom *x509.CertPool
 // based on the caCertificatesEnvVar environment variable (see the `initCertPool` function).
 func createDefaultHTTPClient() *http.Client {
@@ -81,8 +99,9 @@ func createDefaultHTTPClient() *http.Client {
 			TLSHandshakeTimeout:   30 * time.Second,
 			ResponseHeaderTimeout: 30 * time.Second,
 			TLSClientConfig: &tls.Config{
-				ServerName: os.Getenv(caServerNameEnvVar),
-				RootCAs:    initCertPool(),
+				ServerName:         os.Getenv(caServerNameEnvVar),
+				RootCAs:            initCertPool(),
+				ClientSessionCache: tls.NewLRUClientSessionCache(0),
 			},
 		},
 	}
@@ -43,3 +43,24 @@ type mockUser struct {
 func (u mockUser) GetEmail() string                        { return u.email }
 func (u mockUser) GetRegistration() *registration.Resource { return u.regres }
 func (u mockUser) GetPrivateKey() crypto.PrivateKey        { return u.privatekey }
+
+func TestClient_AccountURI(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	user := mockUser{
+		email:      "test@test.com",
+		regres:     &registration.Resource{URI: apiURL + "/acct/1"},
+		privatekey: key,
+	}
+
+	config := NewConfig(user)
+	config.CADirURL = apiURL + "/dir"
+
+	client, err := NewClient(config)
+	require.NoError(t, err, "Could not create client")
+
+	assert.Equal(t, apiURL+"/acct/1", client.AccountURI())
+}
@@ -0,0 +1,108 @@
+package lego
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// FailoverPolicy decides if an error returned by a CA allows to try the next CA.
+type FailoverPolicy func(err error) bool
+
+// DefaultFailoverPolicy allows to try the next CA on connection errors, rate limits, and server errors (outage).
+func DefaultFailoverPolicy(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var problem *acme.ProblemDetails
+	if errors.As(err, &problem) {
+		return problem.HTTPStatus == http.StatusTooManyRequests ||
+			problem.HTTPStatus >= http.StatusInternalServerError ||
+			problem.Type == acme.RateLimitedErr ||
+			problem.Type == acme.ServerInternalErr
+	}
+
+	return false
+}
+
+// CAClient is a client associated with the name of its CA.
+// Each client has its own directory and account,
+// and its own challenge providers.
+type CAClient struct {
+	Name   string
+	Client *Client
+}
+
+// MultiCAClient obtains certificates from several CAs:
+// the CAs are tried in order, until one of them issues the certificate,
+// or returns an error not allowed by the failover policy.
+type MultiCAClient struct {
+	clients []CAClient
+	policy  FailoverPolicy
+}
+
+// NewMultiCAClient creates a MultiCAClient.
+// If policy is nil, DefaultFailoverPolicy is used.
+func NewMultiCAClient(policy FailoverPolicy, clients ...CAClient) (*MultiCAClient, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("at least one CA client must be provided")
+	}
+
+	for i, c := range clients {
+		if c.Client == nil {
+			return nil, fmt.Errorf("the client of the CA %q (%d) is nil", c.Name, i)
+		}
+	}
+
+	if policy == nil {
+		policy = DefaultFailoverPolicy
+	}
+
+	return &MultiCAClient{clients: clients, policy: policy}, nil
+}
+
+// Obtain obtains a certificate (see certificate.Certifier.Obtain),
+// and returns the name of the CA which has issued it.
+func (m *MultiCAClient) Obtain(request certificate.ObtainRequest) (*certificate.Resource, string, error) {
+	return m.try(func(client *Client) (*certificate.Resource, error) {
+		return client.Certificate.Obtain(request)
+	})
+}
+
+// ObtainForCSR obtains a certificate for a CSR (see certificate.Certifier.ObtainForCSR),
+// and returns the name of the CA which has issued it.
+func (m *MultiCAClient) ObtainForCSR(request certificate.ObtainForCSRRequest) (*certificate.Resource, string, error) {
+	return m.try(func(client *Client) (*certificate.Resource, error) {
+		return client.Certificate.ObtainForCSR(request)
+	})
+}
+
+func (m *MultiCAClient) try(obtain func(client *Client) (*certificate.Resource, error)) (*certificate.Resource, string, error) {
+	var errs error
+
+	for i, c := range m.clients {
+		res, err := obtain(c.Client)
+		if err == nil {
+			return res, c.Name, nil
+		}
+
+		errs = errors.Join(errs, fmt.Errorf("CA %s: %w", c.Name, err))
+
+		if !m.policy(err) {
+			return nil, "", errs
+		}
+
+		if i < len(m.clients)-1 {
+			log.Warnf("acme: CA %s failed, trying the next CA: %v", c.Name, err)
+		}
+	}
+
+	return nil, "", errs
+}
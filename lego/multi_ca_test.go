@@ -0,0 +1,133 @@
+package lego
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFailingCA(t *testing.T, status int, problemType string) string {
+	t.Helper()
+
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("POST /newOrder", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(status)
+
+		_ = json.NewEncoder(w).Encode(acme.ProblemDetails{
+			Type:   problemType,
+			Detail: http.StatusText(status),
+		})
+	})
+
+	return apiURL
+}
+
+func TestMultiCAClient_Obtain(t *testing.T) {
+	_, primaryURL := setupCAMock(t)
+	_, secondaryURL := setupCAMock(t)
+
+	client, err := NewMultiCAClient(nil,
+		CAClient{Name: "unavailable", Client: newMockClient(t, setupFailingCA(t, http.StatusServiceUnavailable, "urn:ietf:params:acme:error:serverInternal"))},
+		CAClient{Name: "primary", Client: newMockClient(t, primaryURL)},
+		CAClient{Name: "secondary", Client: newMockClient(t, secondaryURL)},
+	)
+	require.NoError(t, err)
+
+	res, ca, err := client.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "primary", ca)
+	assert.Equal(t, "example.com", res.Domain)
+	assert.NotEmpty(t, res.Certificate)
+	assert.NotEmpty(t, res.PrivateKey)
+}
+
+func TestMultiCAClient_Obtain_notRetriable(t *testing.T) {
+	_, apiURL := setupCAMock(t)
+
+	client, err := NewMultiCAClient(nil,
+		CAClient{Name: "broken", Client: newMockClient(t, setupFailingCA(t, http.StatusBadRequest, "urn:ietf:params:acme:error:malformed"))},
+		CAClient{Name: "secondary", Client: newMockClient(t, apiURL)},
+	)
+	require.NoError(t, err)
+
+	_, ca, err := client.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.Error(t, err)
+
+	assert.Empty(t, ca)
+
+	var problem *acme.ProblemDetails
+	require.ErrorAs(t, err, &problem)
+	assert.Equal(t, http.StatusBadRequest, problem.HTTPStatus)
+}
+
+func TestMultiCAClient_Obtain_customPolicy(t *testing.T) {
+	_, apiURL := setupCAMock(t)
+
+	var calls int
+
+	policy := func(_ error) bool {
+		calls++
+		return true
+	}
+
+	client, err := NewMultiCAClient(policy,
+		CAClient{Name: "broken", Client: newMockClient(t, setupFailingCA(t, http.StatusBadRequest, "urn:ietf:params:acme:error:malformed"))},
+		CAClient{Name: "secondary", Client: newMockClient(t, apiURL)},
+	)
+	require.NoError(t, err)
+
+	_, ca, err := client.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "secondary", ca)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDefaultFailoverPolicy(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		expected bool
+	}{
+		{
+			desc:     "rate limited",
+			err:      &acme.ProblemDetails{HTTPStatus: http.StatusTooManyRequests, Type: "urn:ietf:params:acme:error:rateLimited"},
+			expected: true,
+		},
+		{
+			desc:     "outage",
+			err:      &acme.ProblemDetails{HTTPStatus: http.StatusServiceUnavailable},
+			expected: true,
+		},
+		{
+			desc:     "connection error",
+			err:      &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			expected: true,
+		},
+		{
+			desc: "rejected identifier",
+			err:  &acme.ProblemDetails{HTTPStatus: http.StatusBadRequest, Type: "urn:ietf:params:acme:error:rejectedIdentifier"},
+		},
+		{
+			desc: "other error",
+			err:  errors.New("OOPS"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, DefaultFailoverPolicy(test.err))
+		})
+	}
+}
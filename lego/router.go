@@ -0,0 +1,115 @@
+package lego
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/registration"
+)
+
+// ForDirectory returns a copy of the configuration for another ACME directory and account:
+// the HTTP client (and so the transport and its connections), the user agent,
+// the certificate configuration, and the options are shared.
+// It allows to create lightweight clients for several CAs (see NewClient and Router).
+func (c *Config) ForDirectory(caDirURL string, user registration.User) *Config {
+	config := *c
+	config.CADirURL = caDirURL
+	config.User = user
+	config.APIOptions = slices.Clone(c.APIOptions)
+	config.SolverOptions = slices.Clone(c.SolverOptions)
+
+	return &config
+}
+
+// RouteFunc returns the name of the CA client (see CAClient) to use to obtain a certificate for the domains,
+// e.g. to send a percentage of the issuance to a CA under evaluation.
+type RouteFunc func(domains []string) string
+
+// Router obtains each certificate from the CA client selected by a RouteFunc.
+type Router struct {
+	clients map[string]*Client
+	route   RouteFunc
+}
+
+// NewRouter creates a Router.
+// The names of the clients must be unique.
+func NewRouter(route RouteFunc, clients ...CAClient) (*Router, error) {
+	if route == nil {
+		return nil, errors.New("the route function cannot be nil")
+	}
+
+	if len(clients) == 0 {
+		return nil, errors.New("at least one CA client must be provided")
+	}
+
+	r := &Router{clients: make(map[string]*Client, len(clients)), route: route}
+
+	for i, c := range clients {
+		if c.Client == nil {
+			return nil, fmt.Errorf("the client of the CA %q (%d) is nil", c.Name, i)
+		}
+
+		if _, exists := r.clients[c.Name]; exists {
+			return nil, fmt.Errorf("duplicate CA client name: %q", c.Name)
+		}
+
+		r.clients[c.Name] = c.Client
+	}
+
+	return r, nil
+}
+
+// Client returns the client of the CA, or nil if the name is unknown.
+func (r *Router) Client(name string) *Client {
+	return r.clients[name]
+}
+
+// Obtain obtains a certificate from the CA selected for the domains of the request (see certificate.Certifier.Obtain),
+// and returns the name of the CA.
+func (r *Router) Obtain(request certificate.ObtainRequest) (*certificate.Resource, string, error) {
+	name, client, err := r.pick(request.Domains)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := client.Certificate.Obtain(request)
+	if err != nil {
+		return nil, name, fmt.Errorf("CA %s: %w", name, err)
+	}
+
+	return res, name, nil
+}
+
+// ObtainForCSR obtains a certificate for a CSR from the CA selected for the domains of the CSR
+// (see certificate.Certifier.ObtainForCSR), and returns the name of the CA.
+func (r *Router) ObtainForCSR(request certificate.ObtainForCSRRequest) (*certificate.Resource, string, error) {
+	if request.CSR == nil {
+		return nil, "", errors.New("cannot obtain resource for CSR: CSR is missing")
+	}
+
+	name, client, err := r.pick(certcrypto.ExtractDomainsCSR(request.CSR))
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := client.Certificate.ObtainForCSR(request)
+	if err != nil {
+		return nil, name, fmt.Errorf("CA %s: %w", name, err)
+	}
+
+	return res, name, nil
+}
+
+func (r *Router) pick(domains []string) (string, *Client, error) {
+	name := r.route(domains)
+
+	client, ok := r.clients[name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown CA client %q for %v", name, domains)
+	}
+
+	return name, client, nil
+}
@@ -0,0 +1,128 @@
+package lego
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockUser(t *testing.T, apiURL string) registration.User {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return mockUser{
+		email:      "test@test.com",
+		regres:     &registration.Resource{URI: apiURL + "/account"},
+		privatekey: key,
+	}
+}
+
+func TestConfig_ForDirectory(t *testing.T) {
+	_, incumbentURL := setupCAMock(t)
+	_, candidateURL := setupCAMock(t)
+
+	base := NewConfig(newMockUser(t, incumbentURL))
+	base.CADirURL = incumbentURL + "/dir"
+	base.UserAgent = "test"
+
+	config := base.ForDirectory(candidateURL+"/dir", newMockUser(t, candidateURL))
+
+	assert.Equal(t, candidateURL+"/dir", config.CADirURL)
+	assert.Equal(t, incumbentURL+"/dir", base.CADirURL)
+	assert.NotEqual(t, base.User, config.User)
+
+	assert.Same(t, base.HTTPClient, config.HTTPClient)
+	assert.Equal(t, base.UserAgent, config.UserAgent)
+	assert.Equal(t, base.Certificate, config.Certificate)
+}
+
+func TestRouter_Obtain(t *testing.T) {
+	_, incumbentURL := setupCAMock(t)
+	_, candidateURL := setupCAMock(t)
+
+	base := NewConfig(newMockUser(t, incumbentURL))
+	base.CADirURL = incumbentURL + "/dir"
+
+	incumbent, err := NewClient(base)
+	require.NoError(t, err)
+
+	candidate, err := NewClient(base.ForDirectory(candidateURL+"/dir", newMockUser(t, candidateURL)))
+	require.NoError(t, err)
+
+	route := func(domains []string) string {
+		if strings.HasSuffix(domains[0], ".candidate.example.com") {
+			return "candidate"
+		}
+
+		return "incumbent"
+	}
+
+	router, err := NewRouter(route,
+		CAClient{Name: "incumbent", Client: incumbent},
+		CAClient{Name: "candidate", Client: candidate},
+	)
+	require.NoError(t, err)
+
+	assert.Same(t, candidate, router.Client("candidate"))
+	assert.Nil(t, router.Client("unknown"))
+
+	testCases := []struct {
+		domain      string
+		expectedCA  string
+		expectedURL string
+	}{
+		{domain: "www.example.com", expectedCA: "incumbent", expectedURL: incumbentURL},
+		{domain: "www.candidate.example.com", expectedCA: "candidate", expectedURL: candidateURL},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.domain, func(t *testing.T) {
+			res, ca, err := router.Obtain(certificate.ObtainRequest{Domains: []string{test.domain}})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedCA, ca)
+			assert.Equal(t, test.domain, res.Domain)
+			assert.True(t, strings.HasPrefix(res.CertURL, test.expectedURL+"/"), res.CertURL)
+			assert.NotEmpty(t, res.Certificate)
+		})
+	}
+}
+
+func TestRouter_Obtain_unknownCA(t *testing.T) {
+	_, apiURL := setupCAMock(t)
+
+	router, err := NewRouter(func(_ []string) string { return "unknown" },
+		CAClient{Name: "incumbent", Client: newMockClient(t, apiURL)},
+	)
+	require.NoError(t, err)
+
+	_, _, err = router.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.EqualError(t, err, `unknown CA client "unknown" for [example.com]`)
+}
+
+func TestNewRouter_errors(t *testing.T) {
+	_, apiURL := setupCAMock(t)
+
+	client := newMockClient(t, apiURL)
+	route := func(_ []string) string { return "a" }
+
+	_, err := NewRouter(nil, CAClient{Name: "a", Client: client})
+	require.EqualError(t, err, "the route function cannot be nil")
+
+	_, err = NewRouter(route)
+	require.EqualError(t, err, "at least one CA client must be provided")
+
+	_, err = NewRouter(route, CAClient{Name: "a"})
+	require.EqualError(t, err, `the client of the CA "a" (0) is nil`)
+
+	_, err = NewRouter(route, CAClient{Name: "a", Client: client}, CAClient{Name: "a", Client: client})
+	require.EqualError(t, err, `duplicate CA client name: "a"`)
+}
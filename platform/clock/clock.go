@@ -0,0 +1,110 @@
+// Package clock provides an abstraction of the time, to make the time-dependent behaviors deterministic in tests.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time, and the ways to wait.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Sleep pauses the current goroutine for at least the duration d.
+	Sleep(d time.Duration)
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real is the clock of the system.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// OrReal returns the clock, or the Real clock if nil.
+func OrReal(clock Clock) Clock {
+	if clock == nil {
+		return Real
+	}
+
+	return clock
+}
+
+// Fake is a manual clock: the time only moves forward through Sleep and Advance.
+// Sleep doesn't block, it advances the time instantly,
+// so the loops waiting between their attempts run without wall-clock delay.
+// The channels returned by After receive the time when the clock reaches their deadline.
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFake creates a Fake clock starting at now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the current time of the clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Sleep advances the clock by the duration d.
+func (f *Fake) Sleep(d time.Duration) {
+	f.Advance(d)
+}
+
+// After returns a channel receiving the time when the clock reaches now+d.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+
+	f.timers = append(f.timers, fakeTimer{deadline: f.now.Add(d), ch: ch})
+
+	return ch
+}
+
+// Advance moves the clock forward by the duration d, and fires the timers reaching their deadline.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if d > 0 {
+		f.now = f.now.Add(d)
+	}
+
+	var pending []fakeTimer
+
+	for _, timer := range f.timers {
+		if timer.deadline.After(f.now) {
+			pending = append(pending, timer)
+			continue
+		}
+
+		timer.ch <- f.now
+	}
+
+	f.timers = pending
+}
@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	clock := NewFake(start)
+
+	timeUp := clock.After(time.Minute)
+
+	clock.Sleep(30 * time.Second)
+
+	assert.Equal(t, start.Add(30*time.Second), clock.Now())
+
+	select {
+	case <-timeUp:
+		t.Fatal("the timer fired before its deadline")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+
+	select {
+	case now := <-timeUp:
+		assert.Equal(t, start.Add(time.Minute), now)
+	default:
+		t.Fatal("the timer didn't fire at its deadline")
+	}
+}
+
+func TestFake_After_zero(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	clock := NewFake(start)
+
+	select {
+	case now := <-clock.After(0):
+		assert.Equal(t, start, now)
+	default:
+		t.Fatal("the timer didn't fire")
+	}
+}
+
+func TestOrReal(t *testing.T) {
+	assert.Equal(t, Real, OrReal(nil))
+
+	fake := NewFake(time.Now())
+	assert.Equal(t, fake, OrReal(fake))
+}
@@ -27,6 +27,7 @@ func SetupFakeAPI(t *testing.T) (*http.ServeMux, string) {
 			NewNonceURL:   server.URL + "/nonce",
 			NewAccountURL: server.URL + "/account",
 			NewOrderURL:   server.URL + "/newOrder",
+			NewAuthzURL:   server.URL + "/newAuthz",
 			RevokeCertURL: server.URL + "/revokeCert",
 			KeyChangeURL:  server.URL + "/keyChange",
 			RenewalInfo:   server.URL + "/renewalInfo",
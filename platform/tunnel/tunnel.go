@@ -0,0 +1,49 @@
+// Package tunnel routes the HTTP requests (e.g. the API calls of the DNS providers) through a custom dialer,
+// like an SSH connection to a bastion, without OS-level tunnels.
+package tunnel
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// DialContextFunc opens a connection to the address on the named network (see net.Dialer.DialContext).
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// HTTPClient returns a copy of the HTTP client (http.DefaultClient if nil) opening its connections with dial.
+// The other settings of the transport (TLS, timeouts, etc.) are preserved if it is an *http.Transport.
+func HTTPClient(client *http.Client, dial DialContextFunc) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok || transport == nil {
+		transport, _ = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport = transport.Clone()
+	transport.DialContext = dial
+	// the proxies are bypassed: the connections are opened by dial.
+	transport.Proxy = nil
+
+	clone := *client
+	clone.Transport = transport
+
+	return &clone
+}
+
+// SSHDialer returns a dial function opening the connections through the SSH client (TCP forwarding from the SSH server).
+// The addresses are resolved by the SSH server.
+func SSHDialer(client *ssh.Client) DialContextFunc {
+	return client.DialContext
+}
+
+// NewSSHHTTPClient returns an HTTP client sending the requests through the SSH client.
+func NewSSHHTTPClient(client *ssh.Client, timeout time.Duration) *http.Client {
+	return HTTPClient(&http.Client{Timeout: timeout}, SSHDialer(client))
+}
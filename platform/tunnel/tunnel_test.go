@@ -0,0 +1,208 @@
+package tunnel
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(rw, "lego")
+	}))
+	t.Cleanup(server.Close)
+
+	var dialed atomic.Int32
+
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed.Add(1)
+		assert.Equal(t, "api.internal:80", addr)
+
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	base := &http.Client{Timeout: 5 * time.Second}
+
+	client := HTTPClient(base, dial)
+
+	assert.Equal(t, base.Timeout, client.Timeout)
+	assert.Nil(t, base.Transport, "the original client must not be modified")
+
+	body := get(t, client, "http://api.internal/")
+
+	assert.Equal(t, "lego", body)
+	assert.EqualValues(t, 1, dialed.Load())
+}
+
+func TestHTTPClient_nil(t *testing.T) {
+	dial := func(_ context.Context, _, _ string) (net.Conn, error) {
+		return nil, net.ErrClosed
+	}
+
+	client := HTTPClient(nil, dial)
+
+	require.NotSame(t, http.DefaultClient, client)
+	assert.Nil(t, http.DefaultClient.Transport)
+
+	resp, err := client.Get("http://api.internal/")
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+
+	require.ErrorIs(t, err, net.ErrClosed)
+}
+
+func TestNewSSHHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(rw, "lego")
+	}))
+	t.Cleanup(server.Close)
+
+	var forwarded atomic.Int32
+
+	sshAddr := startSSHServer(t, func(addr string) string {
+		forwarded.Add(1)
+		assert.Equal(t, "api.internal:80", addr)
+
+		return server.Listener.Addr().String()
+	})
+
+	sshClient, err := ssh.Dial("tcp", sshAddr, &ssh.ClientConfig{
+		User:            "lego",
+		Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = sshClient.Close() })
+
+	client := NewSSHHTTPClient(sshClient, 5*time.Second)
+
+	body := get(t, client, "http://api.internal/")
+
+	assert.Equal(t, "lego", body)
+	assert.EqualValues(t, 1, forwarded.Load())
+}
+
+func get(t *testing.T, client *http.Client, rawURL string) string {
+	t.Helper()
+
+	resp, err := client.Get(rawURL)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+// startSSHServer starts an SSH server accepting the TCP forwarding (direct-tcpip),
+// the destination addresses are rewritten by resolve.
+func startSSHServer(t *testing.T, resolve func(addr string) string) string {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) != "secret" {
+				return nil, errors.New("invalid password")
+			}
+
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveSSH(conn, config, resolve)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig, resolve func(addr string) string) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+
+		// RFC 4254 section 7.2: host to connect, port to connect, originator IP address, originator port.
+		var payload struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+
+		err = ssh.Unmarshal(newChannel.ExtraData(), &payload)
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		target, err := net.Dial("tcp", resolve(net.JoinHostPort(payload.Host, fmt.Sprint(payload.Port))))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		channel, reqs, err := newChannel.Accept()
+		if err != nil {
+			_ = target.Close()
+			continue
+		}
+
+		go ssh.DiscardRequests(reqs)
+
+		go func() {
+			_, _ = io.Copy(channel, target)
+			_ = channel.CloseWrite()
+		}()
+
+		go func() {
+			_, _ = io.Copy(target, channel)
+			_ = target.Close()
+		}()
+	}
+}
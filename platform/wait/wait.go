@@ -5,15 +5,62 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/clock"
 )
 
+// Backoff computes the interval to wait after the given attempt (starting at 1).
+type Backoff func(attempt int) time.Duration
+
+// Linear always waits the same interval.
+func Linear(interval time.Duration) Backoff {
+	return func(_ int) time.Duration {
+		return interval
+	}
+}
+
+// Exponential waits initial after the first attempt, then multiplies the interval by factor after each attempt.
+// The interval is capped to maxInterval (no cap if maxInterval is 0).
+func Exponential(initial time.Duration, factor float64, maxInterval time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		interval := float64(initial)
+
+		for i := 1; i < attempt; i++ {
+			interval *= factor
+
+			if maxInterval > 0 && interval >= float64(maxInterval) {
+				return maxInterval
+			}
+		}
+
+		return time.Duration(interval)
+	}
+}
+
 // For polls the given function 'f', once every 'interval', up to 'timeout'.
 func For(msg string, timeout, interval time.Duration, f func() (bool, error)) error {
 	log.Infof("Wait for %s [timeout: %s, interval: %s]", msg, timeout, interval)
 
+	return poll(clock.Real, msg, timeout, Linear(interval), f)
+}
+
+// ForWithBackoff polls the given function 'f', up to 'timeout', waiting between the calls the interval computed by 'backoff'.
+func ForWithBackoff(msg string, timeout time.Duration, backoff Backoff, f func() (bool, error)) error {
+	log.Infof("Wait for %s [timeout: %s, first interval: %s]", msg, timeout, backoff(1))
+
+	return poll(clock.Real, msg, timeout, backoff, f)
+}
+
+// ForWithClock is like ForWithBackoff, but the time is provided by the clock (e.g. a fake clock in tests).
+func ForWithClock(clk clock.Clock, msg string, timeout time.Duration, backoff Backoff, f func() (bool, error)) error {
+	log.Infof("Wait for %s [timeout: %s, first interval: %s]", msg, timeout, backoff(1))
+
+	return poll(clock.OrReal(clk), msg, timeout, backoff, f)
+}
+
+func poll(clk clock.Clock, msg string, timeout time.Duration, backoff Backoff, f func() (bool, error)) error {
 	var lastErr error
-	timeUp := time.After(timeout)
-	for {
+	timeUp := clk.After(timeout)
+	for attempt := 1; ; attempt++ {
 		select {
 		case <-timeUp:
 			if lastErr == nil {
@@ -31,6 +78,6 @@ func For(msg string, timeout, interval time.Duration, f func() (bool, error)) er
 			lastErr = err
 		}
 
-		time.Sleep(interval)
+		clk.Sleep(backoff(attempt))
 	}
 }
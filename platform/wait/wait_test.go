@@ -24,3 +24,51 @@ func TestForTimeout(t *testing.T) {
 		t.Logf("%v", err)
 	}
 }
+
+func TestExponential(t *testing.T) {
+	backoff := Exponential(time.Second, 2, 10*time.Second)
+
+	expected := []time.Duration{
+		1 * time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+
+	for i, want := range expected {
+		if got := backoff(i + 1); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+}
+
+func TestExponential_uncapped(t *testing.T) {
+	backoff := Exponential(100*time.Millisecond, 1.5, 0)
+
+	if got := backoff(5); got != 506250*time.Microsecond {
+		t.Errorf("expected %s, got %s", 506250*time.Microsecond, got)
+	}
+}
+
+func TestForWithBackoff(t *testing.T) {
+	var calls int
+	var intervals []int
+
+	err := ForWithBackoff("", time.Second, func(attempt int) time.Duration {
+		intervals = append(intervals, attempt)
+		return time.Millisecond
+	}, func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the first interval is logged, then one interval after each unsuccessful attempt.
+	if len(intervals) != 3 || intervals[1] != 1 || intervals[2] != 2 {
+		t.Errorf("unexpected attempts: %v", intervals)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -51,6 +52,9 @@ const (
 	EnvGitHubOIDCRequestToken = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
 )
 
+// tokenRefreshBefore is the remaining validity under which a cached token is refreshed.
+const tokenRefreshBefore = 5 * time.Minute
+
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
@@ -162,6 +166,8 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("azuredns: Unable to retrieve valid credentials: %w", err)
 	}
 
+	credentials = newCachedTokenCredential(credentials)
+
 	var dnsProvider challenge.ProviderTimeout
 	if config.PrivateZone {
 		dnsProvider, err = NewDNSProviderPrivate(config, credentials)
@@ -262,6 +268,43 @@ func (w *timeoutTokenCredential) GetToken(ctx context.Context, opts policy.Token
 	return tk, err
 }
 
+// cachedTokenCredential wraps a TokenCredential to cache the tokens until they are close to expiration.
+// All the clients created by the provider share the same tokens, this avoids throttling during batch runs.
+type cachedTokenCredential struct {
+	cred azcore.TokenCredential
+
+	mu     sync.Mutex
+	tokens map[string]azcore.AccessToken
+}
+
+func newCachedTokenCredential(cred azcore.TokenCredential) *cachedTokenCredential {
+	return &cachedTokenCredential{
+		cred:   cred,
+		tokens: make(map[string]azcore.AccessToken),
+	}
+}
+
+// GetToken implements the azcore.TokenCredential interface.
+func (w *cachedTokenCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	key := opts.TenantID + "|" + strings.Join(opts.Scopes, " ")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if tk, ok := w.tokens[key]; ok && time.Until(tk.ExpiresOn) > tokenRefreshBefore {
+		return tk, nil
+	}
+
+	tk, err := w.cred.GetToken(ctx, opts)
+	if err != nil {
+		return tk, err
+	}
+
+	w.tokens[key] = tk
+
+	return tk, nil
+}
+
 func getZoneName(config *Config, fqdn string) (string, error) {
 	if config.ZoneName != "" {
 		return config.ZoneName, nil
@@ -1,9 +1,12 @@
 package azuredns
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -55,6 +58,59 @@ func TestNewDNSProvider(t *testing.T) {
 	}
 }
 
+type tokenCredentialMock struct {
+	calls    int
+	lifetime time.Duration
+}
+
+func (m *tokenCredentialMock) GetToken(_ context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	m.calls++
+
+	return azcore.AccessToken{
+		Token:     opts.Scopes[0],
+		ExpiresOn: time.Now().Add(m.lifetime),
+	}, nil
+}
+
+func Test_cachedTokenCredential(t *testing.T) {
+	mock := &tokenCredentialMock{lifetime: time.Hour}
+
+	cred := newCachedTokenCredential(mock)
+
+	opts := policy.TokenRequestOptions{Scopes: []string{"https://management.azure.com//.default"}}
+
+	for range 3 {
+		tk, err := cred.GetToken(context.Background(), opts)
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://management.azure.com//.default", tk.Token)
+	}
+
+	assert.Equal(t, 1, mock.calls)
+
+	tk, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"other"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "other", tk.Token)
+	assert.Equal(t, 2, mock.calls)
+}
+
+func Test_cachedTokenCredential_refresh(t *testing.T) {
+	// The token expires before the refresh threshold, so it's never reused.
+	mock := &tokenCredentialMock{lifetime: tokenRefreshBefore - time.Minute}
+
+	cred := newCachedTokenCredential(mock)
+
+	opts := policy.TokenRequestOptions{Scopes: []string{"https://management.azure.com//.default"}}
+
+	for range 3 {
+		_, err := cred.GetToken(context.Background(), opts)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, mock.calls)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -148,6 +148,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithOptions(domain, token, keyAuth, dns01.PresentOptions{})
+}
+
+// PresentWithOptions creates a TXT record, with the comment of the options, to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithOptions(domain, token, keyAuth string, opts dns01.PresentOptions) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -165,6 +170,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Name:    dns01.UnFqdn(info.EffectiveFQDN),
 		Content: info.Value,
 		TTL:     d.config.TTL,
+		Comment: opts.Comment,
 	}
 
 	response, err := d.client.CreateDNSRecord(context.Background(), zoneID, dnsRecord)
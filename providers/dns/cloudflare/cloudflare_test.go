@@ -1,9 +1,19 @@
 package cloudflare
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -298,3 +308,84 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func TestDNSProvider_PresentWithOptions(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /zones", func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "example.com", req.URL.Query().Get("name"))
+
+		writeJSON(t, rw, map[string]any{
+			"success":     true,
+			"result":      []map[string]any{{"id": "zone-id", "name": "example.com"}},
+			"result_info": map[string]any{"page": 1, "per_page": 50, "count": 1, "total_count": 1, "total_pages": 1},
+		})
+	})
+
+	var created map[string]any
+
+	mux.HandleFunc("POST /zones/zone-id/dns_records", func(rw http.ResponseWriter, req *http.Request) {
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&created))
+
+		writeJSON(t, rw, map[string]any{
+			"success": true,
+			"result":  map[string]any{"id": "record-id"},
+		})
+	})
+
+	config := NewDefaultConfig()
+	config.AuthToken = "token"
+	config.TTL = 120
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	client, err := cloudflare.NewWithAPIToken("token", cloudflare.BaseURL(server.URL))
+	require.NoError(t, err)
+
+	provider.client.clientEdit = client
+	provider.client.clientRead = client
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	// the zone is defined for the challenge: no SOA lookup.
+	chlg := dns01.NewChallenge(core, nil, provider,
+		dns01.WithZoneForDomain(map[string]string{"example.com": "example.com"}),
+		dns01.WithRecordComment(func(domain, _ string) string { return "lego order-42 " + domain }))
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "token"}},
+	}
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+
+	keyAuth, err := core.GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	assert.Equal(t, "TXT", created["type"])
+	assert.Equal(t, "_acme-challenge.example.com", created["name"])
+	assert.Equal(t, dns01.GetChallengeInfo("example.com", keyAuth).Value, created["content"])
+	assert.Equal(t, "lego order-42 example.com", created["comment"])
+	assert.Equal(t, "record-id", provider.recordIDs["token"])
+}
+
+func writeJSON(t *testing.T, rw http.ResponseWriter, v any) {
+	t.Helper()
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(v)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,50 @@
+package dns
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// ProviderDescriptor describes a DNS provider and its configuration.
+type ProviderDescriptor struct {
+	// Name the real name of the DNS provider.
+	Name string
+	// Code the code of the DNS provider (see NewDNSChallengeProviderByName).
+	Code string
+	// Aliases the alternative codes of the DNS provider (for compatibility).
+	Aliases []string
+	// URL the URL of the DNS provider.
+	URL string
+
+	// RequiredKeys the environment variables of the credentials.
+	// Some providers support several authentication methods or aliases:
+	// all the keys are not always required at the same time, see the documentation of the provider.
+	RequiredKeys []string
+	// OptionalKeys the environment variables of the additional configuration.
+	OptionalKeys []string
+
+	// PropagationTimeout the default propagation timeout.
+	// The value can be overridden by the environment variable of the provider (e.g. CLOUDFLARE_PROPAGATION_TIMEOUT).
+	PropagationTimeout time.Duration
+}
+
+// SupportedProviders returns the descriptors of the supported DNS providers, sorted by code.
+func SupportedProviders() []ProviderDescriptor {
+	descriptors := make([]ProviderDescriptor, 0, len(providerDescriptors))
+
+	for _, descriptor := range providerDescriptors {
+		descriptor.Aliases = slices.Clone(descriptor.Aliases)
+		descriptor.RequiredKeys = slices.Clone(descriptor.RequiredKeys)
+		descriptor.OptionalKeys = slices.Clone(descriptor.OptionalKeys)
+		descriptor.PropagationTimeout = getDefaultPropagationTimeout(descriptor.Code)
+
+		descriptors = append(descriptors, descriptor)
+	}
+
+	slices.SortFunc(descriptors, func(a, b ProviderDescriptor) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	return descriptors
+}
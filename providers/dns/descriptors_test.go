@@ -0,0 +1,57 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportedProviders(t *testing.T) {
+	descriptors := SupportedProviders()
+
+	find := func(code string) ProviderDescriptor {
+		t.Helper()
+
+		for _, descriptor := range descriptors {
+			if descriptor.Code == code {
+				return descriptor
+			}
+		}
+
+		require.Failf(t, "provider not found", "code: %s", code)
+
+		return ProviderDescriptor{}
+	}
+
+	codes := map[string]struct{}{}
+
+	for _, descriptor := range descriptors {
+		assert.NotContains(t, codes, descriptor.Code)
+		codes[descriptor.Code] = struct{}{}
+
+		assert.NotEmpty(t, descriptor.Name, descriptor.Code)
+	}
+
+	cloudflare := find("cloudflare")
+	assert.Equal(t, "Cloudflare", cloudflare.Name)
+	assert.Contains(t, cloudflare.RequiredKeys, "CF_DNS_API_TOKEN")
+	assert.Contains(t, cloudflare.RequiredKeys, "CLOUDFLARE_EMAIL")
+	assert.Contains(t, cloudflare.OptionalKeys, "CLOUDFLARE_PROPAGATION_TIMEOUT")
+	assert.Equal(t, 2*time.Minute, cloudflare.PropagationTimeout)
+
+	digitalocean := find("digitalocean")
+	assert.Equal(t, []string{"DO_AUTH_TOKEN"}, digitalocean.RequiredKeys)
+	assert.Equal(t, []string{"DO_API_URL", "DO_HTTP_TIMEOUT", "DO_POLLING_INTERVAL", "DO_PROPAGATION_TIMEOUT", "DO_TTL"}, digitalocean.OptionalKeys)
+	assert.Equal(t, 60*time.Second, digitalocean.PropagationTimeout)
+
+	linode := find("linode")
+	assert.Equal(t, []string{"linodev4"}, linode.Aliases)
+	assert.Equal(t, []string{"LINODE_TOKEN"}, linode.RequiredKeys)
+
+	manual := find("manual")
+	assert.Empty(t, manual.RequiredKeys)
+	assert.Equal(t, dns01.DefaultPropagationTimeout, manual.PropagationTimeout)
+}
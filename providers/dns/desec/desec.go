@@ -11,7 +11,9 @@ import (
 
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	legolog "github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/providers/dns/desec/internal"
 	"github.com/nrdcg/desec"
 )
 
@@ -29,7 +31,11 @@ const (
 
 // https://github.com/desec-io/desec-stack/issues/216
 // https://desec.readthedocs.io/_/downloads/en/latest/pdf/
-const defaultTTL int = 3600
+const (
+	defaultTTL int = 3600
+	// minTTL is the default minimum TTL of the deSEC domains.
+	minTTL int = 3600
+)
 
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
@@ -58,6 +64,10 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	config *Config
 	client *desec.Client
+
+	// findZoneByFqdn determines the DNS zone of a FQDN.
+	// It is overridden during tests.
+	findZoneByFqdn func(fqdn string) (string, error)
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for deSEC.
@@ -84,15 +94,33 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("desec: incomplete credentials, missing token")
 	}
 
-	opts := desec.NewDefaultClientOptions()
+	if config.TTL < minTTL {
+		legolog.Warnf("desec: the TTL (%d) is lower than the minimum TTL of deSEC, using %d", config.TTL, minTTL)
+		config.TTL = minTTL
+	}
+
+	httpClient := &http.Client{}
 	if config.HTTPClient != nil {
-		opts.HTTPClient = config.HTTPClient
+		*httpClient = *config.HTTPClient
+	}
+
+	// The throttled requests (429) are retried, until the end of the propagation timeout, after the delay requested by the API.
+	httpClient.Transport = &internal.RateLimitTransport{
+		DefaultDelay: config.PollingInterval,
+		Transport:    httpClient.Transport,
 	}
+
+	opts := desec.NewDefaultClientOptions()
+	opts.HTTPClient = httpClient
 	opts.Logger = log.Default()
 
 	client := desec.New(config.Token, opts)
 
-	return &DNSProvider{config: config, client: client}, nil
+	return &DNSProvider{
+		config:         config,
+		client:         client,
+		findZoneByFqdn: dns01.FindZoneByFqdn,
+	}, nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -103,10 +131,12 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	ctx, cancel := d.newContext()
+	defer cancel()
+
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("desec: could not find zone for domain %q: %w", domain, err)
 	}
@@ -155,10 +185,12 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	ctx, cancel := d.newContext()
+	defer cancel()
+
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("desec: could not find zone for domain %q: %w", domain, err)
 	}
@@ -189,3 +221,13 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	return nil
 }
+
+// newContext returns a context bounded by the propagation timeout:
+// the throttled requests are not retried beyond it.
+func (d *DNSProvider) newContext() (context.Context, context.CancelFunc) {
+	if d.config.PropagationTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), d.config.PropagationTimeout)
+}
@@ -1,10 +1,16 @@
 package desec
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/nrdcg/desec"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -87,6 +93,78 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestNewDNSProviderConfig_minTTL(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Token = "api_key"
+	config.TTL = 60
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, minTTL, p.config.TTL)
+}
+
+func TestDNSProvider_Present_rateLimited(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		created  desec.RRSet
+	)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /domains/example.com/rrsets/_acme-challenge/TXT/", func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		_, _ = rw.Write([]byte(`{"detail":"Not found."}`))
+	})
+
+	mux.HandleFunc("POST /domains/example.com/rrsets/", func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts++
+
+		if attempts == 1 {
+			rw.Header().Set("Retry-After", "1")
+			rw.WriteHeader(http.StatusTooManyRequests)
+			_, _ = rw.Write([]byte(`{"detail":"Request was throttled. Expected available in 1 second."}`))
+			return
+		}
+
+		err := json.NewDecoder(req.Body).Decode(&created)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rw.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(rw).Encode(created)
+	})
+
+	config := NewDefaultConfig()
+	config.Token = "api_key"
+	config.PropagationTimeout = 10 * time.Second
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	provider.client.BaseURL = server.URL + "/"
+	provider.findZoneByFqdn = func(fqdn string) (string, error) {
+		return "example.com.", nil
+	}
+
+	err = provider.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, "_acme-challenge", created.SubName)
+	assert.Equal(t, "TXT", created.Type)
+	assert.Equal(t, minTTL, created.TTL)
+	assert.Len(t, created.Records, 1)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
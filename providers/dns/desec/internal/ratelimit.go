@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// RateLimitTransport HTTP transport that retries the requests throttled by the API (429 Too Many Requests).
+// It waits the delay requested by the `Retry-After` header before each retry,
+// until the deadline of the request context.
+// https://github.com/desec-io/desec-stack/blob/main/docs/rate-limits.rst
+type RateLimitTransport struct {
+	// DefaultDelay is the delay used when the response has no (valid) `Retry-After` header.
+	DefaultDelay time.Duration
+
+	// Transport is the underlying HTTP transport to use when making requests.
+	// It will default to http.DefaultTransport if nil.
+	Transport http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction, retrying it while the API throttles it.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.transport().RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		delay := t.retryAfter(resp)
+
+		// The request cannot be replayed, or the deadline would be reached before the retry:
+		// the throttled response is returned as-is.
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return resp, nil
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		log.Infof("desec: rate limited (attempt %d), retrying %s %s in %s", attempt, req.Method, req.URL.Path, delay)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		req, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *RateLimitTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+// retryAfter returns the delay requested by the `Retry-After` header (delay-seconds or HTTP-date).
+func (t *RateLimitTransport) retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return t.DefaultDelay
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return t.DefaultDelay
+}
+
+// rewind returns a copy of the request with a fresh body.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = body
+
+	return clone, nil
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T, retryAfter string, throttled int) (*http.Client, string, *[]string) {
+	t.Helper()
+
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) <= throttled {
+			if retryAfter != "" {
+				rw.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(rw, `{"detail":"Request was throttled."}`, http.StatusTooManyRequests)
+			return
+		}
+
+		rw.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &RateLimitTransport{DefaultDelay: 10 * time.Millisecond}}
+
+	return client, server.URL, &bodies
+}
+
+func TestRateLimitTransport_RoundTrip(t *testing.T) {
+	client, serverURL, bodies := setupTest(t, "1", 1)
+
+	req, err := http.NewRequest(http.MethodPost, serverURL, strings.NewReader(`{"type":"TXT"}`))
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, []string{`{"type":"TXT"}`, `{"type":"TXT"}`}, *bodies)
+}
+
+func TestRateLimitTransport_RoundTrip_defaultDelay(t *testing.T) {
+	client, serverURL, bodies := setupTest(t, "", 3)
+
+	req, err := http.NewRequest(http.MethodGet, serverURL, http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Len(t, *bodies, 4)
+}
+
+func TestRateLimitTransport_RoundTrip_deadline(t *testing.T) {
+	client, serverURL, bodies := setupTest(t, "60", 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL, http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// The delay requested by the API exceeds the deadline: the throttled response is returned.
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestRateLimitTransport_retryAfter(t *testing.T) {
+	transport := &RateLimitTransport{DefaultDelay: 3 * time.Second}
+
+	testCases := []struct {
+		desc     string
+		value    string
+		expected time.Duration
+	}{
+		{desc: "missing", expected: 3 * time.Second},
+		{desc: "seconds", value: "42", expected: 42 * time.Second},
+		{desc: "past date", value: "Fri, 31 Dec 1999 23:59:59 GMT", expected: 0},
+		{desc: "invalid", value: "soon", expected: 3 * time.Second},
+		{desc: "negative", value: "-1", expected: 3 * time.Second},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if test.value != "" {
+				resp.Header.Set("Retry-After", test.value)
+			}
+
+			assert.Equal(t, test.expected, transport.retryAfter(resp))
+		})
+	}
+}
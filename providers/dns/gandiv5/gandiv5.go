@@ -24,6 +24,9 @@ const (
 	EnvAPIKey              = envNamespace + "API_KEY"
 	EnvPersonalAccessToken = envNamespace + "PERSONAL_ACCESS_TOKEN"
 
+	EnvZone     = envNamespace + "ZONE"
+	EnvSnapshot = envNamespace + "SNAPSHOT"
+
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -38,6 +41,9 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 type inProgressInfo struct {
 	fieldName string
 	authZone  string
+	value     string
+	// snapshotID is the ID of the snapshot of the zone taken before the creation of the record.
+	snapshotID string
 }
 
 // Config is used to configure the creation of the DNSProvider.
@@ -45,15 +51,22 @@ type Config struct {
 	BaseURL             string
 	APIKey              string // Deprecated use PersonalAccessToken
 	PersonalAccessToken string
-	PropagationTimeout  time.Duration
-	PollingInterval     time.Duration
-	TTL                 int
-	HTTPClient          *http.Client
+	// Zone is the zone of the records (determined through SOA requests if empty).
+	Zone string
+	// Snapshot enables the snapshot of the zone before the creation of the record:
+	// if the creation or the deletion of the record fails, the TXT records are rolled back to the snapshot.
+	Snapshot           bool
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+	HTTPClient         *http.Client
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		Zone:               env.GetOrDefaultString(EnvZone, ""),
+		Snapshot:           env.GetOrDefaultBool(EnvSnapshot, false),
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 20*time.Minute),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 20*time.Second),
@@ -130,10 +143,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	ctx := context.Background()
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	// find authZone
-	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
+	authZone, err := d.findZone(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("gandiv5: could not find zone for domain %q: %w", domain, err)
 	}
@@ -144,46 +158,77 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("gandiv5: %w", err)
 	}
 
+	zone := dns01.UnFqdn(authZone)
+
 	// acquire lock and check there is not a challenge already in
 	// progress for this value of authZone
 	d.inProgressMu.Lock()
 	defer d.inProgressMu.Unlock()
 
+	var snapshotID string
+	if d.config.Snapshot {
+		snapshotID, err = d.client.CreateSnapshot(ctx, zone, "lego "+subDomain)
+		if err != nil {
+			return fmt.Errorf("gandiv5: %w", err)
+		}
+	}
+
 	// add TXT record into authZone
-	err = d.client.AddTXTRecord(context.Background(), dns01.UnFqdn(authZone), subDomain, info.Value, d.config.TTL)
+	err = d.client.AddTXTRecord(ctx, zone, subDomain, info.Value, d.config.TTL)
 	if err != nil {
-		return err
+		if snapshotID != "" {
+			err = errors.Join(err, d.rollback(ctx, zone, subDomain, snapshotID))
+		}
+
+		return fmt.Errorf("gandiv5: %w", err)
 	}
 
 	// save data necessary for CleanUp
-	d.inProgressFQDNs[info.EffectiveFQDN] = inProgressInfo{
-		authZone:  authZone,
-		fieldName: subDomain,
+	d.inProgressFQDNs[token] = inProgressInfo{
+		authZone:   authZone,
+		fieldName:  subDomain,
+		value:      info.Value,
+		snapshotID: snapshotID,
 	}
+
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	ctx := context.Background()
 
 	// acquire lock and retrieve authZone
 	d.inProgressMu.Lock()
 	defer d.inProgressMu.Unlock()
-	if _, ok := d.inProgressFQDNs[info.EffectiveFQDN]; !ok {
+
+	inProgress, ok := d.inProgressFQDNs[token]
+	if !ok {
 		// if there is no cleanup information then just return
 		return nil
 	}
 
-	fieldName := d.inProgressFQDNs[info.EffectiveFQDN].fieldName
-	authZone := d.inProgressFQDNs[info.EffectiveFQDN].authZone
-	delete(d.inProgressFQDNs, info.EffectiveFQDN)
+	delete(d.inProgressFQDNs, token)
+
+	zone := dns01.UnFqdn(inProgress.authZone)
 
-	// delete TXT record from authZone
-	err := d.client.DeleteTXTRecord(context.Background(), dns01.UnFqdn(authZone), fieldName)
+	// remove the value from the TXT records of authZone, the other values are preserved.
+	err := d.client.RemoveTXTValue(ctx, zone, inProgress.fieldName, inProgress.value)
 	if err != nil {
+		if inProgress.snapshotID != "" {
+			err = errors.Join(err, d.rollback(ctx, zone, inProgress.fieldName, inProgress.snapshotID))
+		}
+
 		return fmt.Errorf("gandiv5: %w", err)
 	}
+
+	if inProgress.snapshotID != "" {
+		err = d.client.DeleteSnapshot(ctx, zone, inProgress.snapshotID)
+		if err != nil {
+			return fmt.Errorf("gandiv5: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -192,3 +237,33 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
+
+func (d *DNSProvider) findZone(fqdn string) (string, error) {
+	if d.config.Zone != "" {
+		return dns01.ToFqdn(d.config.Zone), nil
+	}
+
+	return d.findZoneByFqdn(fqdn)
+}
+
+// rollback restores the TXT records of the subdomain from the snapshot, then deletes the snapshot.
+func (d *DNSProvider) rollback(ctx context.Context, zone, subDomain, snapshotID string) error {
+	log.Infof("gandiv5: rollback of the TXT records of %s.%s to the snapshot %s", subDomain, zone, snapshotID)
+
+	snapshot, err := d.client.GetSnapshot(ctx, zone, snapshotID)
+	if err != nil {
+		return fmt.Errorf("rollback: %w", err)
+	}
+
+	err = d.client.RestoreTXTRecord(ctx, zone, subDomain, snapshot)
+	if err != nil {
+		return fmt.Errorf("rollback: %w", err)
+	}
+
+	err = d.client.DeleteSnapshot(ctx, zone, snapshotID)
+	if err != nil {
+		return fmt.Errorf("rollback: %w", err)
+	}
+
+	return nil
+}
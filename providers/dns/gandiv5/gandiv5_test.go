@@ -1,15 +1,20 @@
 package gandiv5
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"sync"
 	"testing"
 
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/providers/dns/gandiv5/internal"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -185,3 +190,270 @@ func TestDNSProvider(t *testing.T) {
 	err = provider.CleanUp("abc.def.example.com", "", fakeKeyAuth)
 	require.NoError(t, err)
 }
+
+// liveDNSMock is a minimal in-memory LiveDNS API: the PUT of a TXT RRSet replaces all its values.
+type liveDNSMock struct {
+	mu        sync.Mutex
+	records   map[string]internal.Record
+	snapshots map[string][]internal.Record
+
+	// failPuts is the number of the next PUT requests failing after being applied.
+	failPuts int
+	// partialPut are the values applied by the failing PUT requests, instead of the values of the request.
+	partialPut []string
+}
+
+func setupLiveDNSMock(t *testing.T, records ...internal.Record) (*liveDNSMock, *DNSProvider) {
+	t.Helper()
+
+	m := &liveDNSMock{
+		records:   make(map[string]internal.Record),
+		snapshots: make(map[string][]internal.Record),
+	}
+
+	for _, record := range records {
+		m.records[record.RRSetName] = record
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/domains/example.com/records/{name}/TXT", m.handleRecord)
+	mux.HandleFunc("POST /domains/example.com/snapshots", m.handleCreateSnapshot)
+	mux.HandleFunc("/domains/example.com/snapshots/{id}", m.handleSnapshot)
+
+	config := NewDefaultConfig()
+	config.PersonalAccessToken = "secret"
+	config.BaseURL = server.URL
+	config.Zone = "example.com"
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	return m, provider
+}
+
+func (m *liveDNSMock) handleRecord(rw http.ResponseWriter, req *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := req.PathValue("name")
+
+	switch req.Method {
+	case http.MethodGet:
+		record, ok := m.records[name]
+		if !ok {
+			http.Error(rw, `{"message": "Unknown record"}`, http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(record)
+
+	case http.MethodPut:
+		record := internal.Record{}
+
+		err := json.NewDecoder(req.Body).Decode(&record)
+		if err != nil {
+			http.Error(rw, `{"message": "invalid body"}`, http.StatusBadRequest)
+			return
+		}
+
+		record.RRSetName = name
+		record.RRSetType = "TXT"
+
+		if m.failPuts > 0 && m.partialPut != nil {
+			record.RRSetValues = m.partialPut
+		}
+
+		m.records[name] = record
+
+		if m.failPuts > 0 {
+			m.failPuts--
+			http.Error(rw, `{"message": "Internal error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"message": "DNS Record Created"}`))
+
+	case http.MethodDelete:
+		delete(m.records, name)
+
+		rw.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(rw, `{"message": "method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+func (m *liveDNSMock) handleCreateSnapshot(rw http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := fmt.Sprintf("snapshot-%d", len(m.snapshots)+1)
+
+	var zoneData []internal.Record
+	for _, record := range m.records {
+		zoneData = append(zoneData, record)
+	}
+
+	m.snapshots[id] = zoneData
+
+	rw.WriteHeader(http.StatusCreated)
+	_, _ = fmt.Fprintf(rw, `{"message": "Snapshot Created", "id": %q}`, id)
+}
+
+func (m *liveDNSMock) handleSnapshot(rw http.ResponseWriter, req *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := req.PathValue("id")
+
+	zoneData, ok := m.snapshots[id]
+	if !ok {
+		http.Error(rw, `{"message": "Unknown snapshot"}`, http.StatusNotFound)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(rw).Encode(internal.Snapshot{ID: id, ZoneData: zoneData})
+
+	case http.MethodDelete:
+		delete(m.snapshots, id)
+
+		rw.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(rw, `{"message": "method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+func (m *liveDNSMock) values(name string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.records[name]
+	if !ok {
+		return nil
+	}
+
+	return record.RRSetValues
+}
+
+func (m *liveDNSMock) snapshotCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.snapshots)
+}
+
+func TestDNSProvider_preserveExistingValues(t *testing.T) {
+	mock, provider := setupLiveDNSMock(t, internal.Record{
+		RRSetName:   "_acme-challenge",
+		RRSetType:   "TXT",
+		RRSetTTL:    3600,
+		RRSetValues: []string{"google-site-verification=abc"},
+	})
+
+	infoBase := dns01.GetChallengeInfo("example.com", "keyAuthBase")
+	infoWildcard := dns01.GetChallengeInfo("example.com", "keyAuthWildcard")
+
+	err := provider.Present("example.com", "tokenBase", "keyAuthBase")
+	require.NoError(t, err)
+
+	err = provider.Present("example.com", "tokenWildcard", "keyAuthWildcard")
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"google-site-verification=abc", infoBase.Value, infoWildcard.Value}, mock.values("_acme-challenge"))
+
+	err = provider.CleanUp("example.com", "tokenBase", "keyAuthBase")
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"google-site-verification=abc", infoWildcard.Value}, mock.values("_acme-challenge"))
+
+	err = provider.CleanUp("example.com", "tokenWildcard", "keyAuthWildcard")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"google-site-verification=abc"}, mock.values("_acme-challenge"))
+}
+
+func TestDNSProvider_deleteEmptyRRSet(t *testing.T) {
+	mock, provider := setupLiveDNSMock(t)
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Len(t, mock.values("_acme-challenge"), 1)
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Nil(t, mock.values("_acme-challenge"))
+}
+
+func TestDNSProvider_snapshot(t *testing.T) {
+	mock, provider := setupLiveDNSMock(t, internal.Record{
+		RRSetName:   "_acme-challenge",
+		RRSetType:   "TXT",
+		RRSetTTL:    3600,
+		RRSetValues: []string{"existing"},
+	})
+
+	provider.config.Snapshot = true
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, mock.snapshotCount())
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"existing"}, mock.values("_acme-challenge"))
+	assert.Zero(t, mock.snapshotCount())
+}
+
+func TestDNSProvider_snapshot_rollback(t *testing.T) {
+	mock, provider := setupLiveDNSMock(t, internal.Record{
+		RRSetName:   "_acme-challenge",
+		RRSetType:   "TXT",
+		RRSetTTL:    3600,
+		RRSetValues: []string{"existing"},
+	})
+
+	provider.config.Snapshot = true
+
+	// the RRSet is updated, but the API responds with an error.
+	mock.failPuts = 1
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.ErrorContains(t, err, "gandiv5: unable to create TXT record for domain example.com and name _acme-challenge: ")
+
+	assert.Equal(t, []string{"existing"}, mock.values("_acme-challenge"))
+	assert.Zero(t, mock.snapshotCount())
+}
+
+func TestDNSProvider_snapshot_rollbackCleanUp(t *testing.T) {
+	mock, provider := setupLiveDNSMock(t, internal.Record{
+		RRSetName:   "_acme-challenge",
+		RRSetType:   "TXT",
+		RRSetTTL:    3600,
+		RRSetValues: []string{"existing"},
+	})
+
+	provider.config.Snapshot = true
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	// the RRSet is updated with a partial state, and the API responds with an error.
+	mock.failPuts = 1
+	mock.partialPut = []string{}
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.ErrorContains(t, err, "gandiv5: unable to create TXT record for domain example.com and name _acme-challenge: ")
+
+	assert.Equal(t, []string{"existing"}, mock.values("_acme-challenge"))
+	assert.Zero(t, mock.snapshotCount())
+}
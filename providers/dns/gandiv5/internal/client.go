@@ -2,12 +2,14 @@ package internal
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/go-acme/lego/v4/log"
@@ -52,11 +54,13 @@ func (c *Client) AddTXTRecord(ctx context.Context, domain, name, value string, t
 		return err
 	}
 
-	values := []string{value}
-	if len(txtRecord.RRSetValues) > 0 {
-		values = append(values, txtRecord.RRSetValues...)
+	if slices.Contains(txtRecord.RRSetValues, value) {
+		return nil
 	}
 
+	// LiveDNS replaces the whole RRSet: the other values are preserved.
+	values := append([]string{value}, txtRecord.RRSetValues...)
+
 	newRecord := &Record{RRSetTTL: ttl, RRSetValues: values}
 
 	err = c.addTXTRecord(ctx, domain, name, newRecord)
@@ -67,6 +71,29 @@ func (c *Client) AddTXTRecord(ctx context.Context, domain, name, value string, t
 	return nil
 }
 
+// RemoveTXTValue removes a value from the TXT records, the other values are preserved.
+// The RRSet is deleted if there is no value left.
+func (c *Client) RemoveTXTValue(ctx context.Context, domain, name, value string) error {
+	txtRecord, err := c.getTXTRecord(ctx, domain, name)
+	if err != nil {
+		return err
+	}
+
+	values := slices.DeleteFunc(slices.Clone(txtRecord.RRSetValues), func(v string) bool {
+		return v == value
+	})
+
+	if len(values) == 0 {
+		return c.DeleteTXTRecord(ctx, domain, name)
+	}
+
+	if len(values) == len(txtRecord.RRSetValues) {
+		return nil
+	}
+
+	return c.addTXTRecord(ctx, domain, name, &Record{RRSetTTL: txtRecord.RRSetTTL, RRSetValues: values})
+}
+
 func (c *Client) getTXTRecord(ctx context.Context, domain, name string) (*Record, error) {
 	endpoint := c.BaseURL.JoinPath("domains", domain, "records", name, "TXT")
 
@@ -128,6 +155,82 @@ func (c *Client) DeleteTXTRecord(ctx context.Context, domain, name string) error
 	return nil
 }
 
+// CreateSnapshot creates a snapshot of the zone, and returns its ID.
+func (c *Client) CreateSnapshot(ctx context.Context, domain, name string) (string, error) {
+	endpoint := c.BaseURL.JoinPath("domains", domain, "snapshots")
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, &Snapshot{Name: name})
+	if err != nil {
+		return "", err
+	}
+
+	message := apiResponse{}
+	err = c.do(req, &message)
+	if err != nil {
+		return "", fmt.Errorf("unable to create snapshot for domain %s: %w", domain, err)
+	}
+
+	id := cmp.Or(message.ID, message.UUID)
+	if id == "" {
+		return "", fmt.Errorf("unable to create snapshot for domain %s: missing snapshot ID", domain)
+	}
+
+	return id, nil
+}
+
+// GetSnapshot gets a snapshot of the zone, with its records.
+func (c *Client) GetSnapshot(ctx context.Context, domain, id string) (*Snapshot, error) {
+	endpoint := c.BaseURL.JoinPath("domains", domain, "snapshots", id)
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	err = c.do(req, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get snapshot %s for domain %s: %w", id, domain, err)
+	}
+
+	if snapshot.ID == "" {
+		return nil, fmt.Errorf("snapshot %s not found for domain %s", id, domain)
+	}
+
+	return snapshot, nil
+}
+
+// DeleteSnapshot deletes a snapshot of the zone.
+func (c *Client) DeleteSnapshot(ctx context.Context, domain, id string) error {
+	endpoint := c.BaseURL.JoinPath("domains", domain, "snapshots", id)
+
+	req, err := newJSONRequest(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	err = c.do(req, nil)
+	if err != nil {
+		return fmt.Errorf("unable to delete snapshot %s for domain %s: %w", id, domain, err)
+	}
+
+	return nil
+}
+
+// RestoreTXTRecord restores the TXT records of the snapshot:
+// only the RRSet of the name is restored (or deleted if it doesn't exist in the snapshot), the rest of the zone is untouched.
+func (c *Client) RestoreTXTRecord(ctx context.Context, domain, name string, snapshot *Snapshot) error {
+	for _, record := range snapshot.ZoneData {
+		if record.RRSetName != name || record.RRSetType != "TXT" {
+			continue
+		}
+
+		return c.addTXTRecord(ctx, domain, name, &Record{RRSetTTL: record.RRSetTTL, RRSetValues: record.RRSetValues})
+	}
+
+	return c.DeleteTXTRecord(ctx, domain, name)
+}
+
 func (c *Client) do(req *http.Request, result any) error {
 	if c.apiKey != "" {
 		req.Header.Set(APIKeyHeader, c.apiKey)
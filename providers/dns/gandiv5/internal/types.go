@@ -4,6 +4,7 @@ package internal
 type apiResponse struct {
 	Message string `json:"message"`
 	UUID    string `json:"uuid,omitempty"`
+	ID      string `json:"id,omitempty"`
 }
 
 // Record TXT record representation.
@@ -13,3 +14,12 @@ type Record struct {
 	RRSetName   string   `json:"rrset_name,omitempty"`
 	RRSetType   string   `json:"rrset_type,omitempty"`
 }
+
+// Snapshot zone snapshot representation.
+type Snapshot struct {
+	ID        string   `json:"id,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Automatic bool     `json:"automatic,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+	ZoneData  []Record `json:"zone_data,omitempty"`
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/platform/tunnel"
 	"github.com/go-acme/lego/v4/providers/dns/internal/errutils"
 )
 
@@ -66,6 +68,13 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+// WithDialer opens the connections to the endpoint with dial (e.g. through an SSH tunnel, see tunnel.SSHDialer),
+// the other settings of the HTTP client are preserved.
+func (c *Config) WithDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) *Config {
+	c.HTTPClient = tunnel.HTTPClient(c.HTTPClient, dial)
+	return c
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
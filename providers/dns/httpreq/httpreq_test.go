@@ -1,15 +1,19 @@
 package httpreq
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"path"
+	"sync/atomic"
 	"testing"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -256,6 +260,43 @@ func TestNewDNSProvider_Cleanup(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_WithDialer(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/present", successHandler)
+	mux.HandleFunc("/cleanup", successHandler)
+
+	var dialed atomic.Int32
+
+	// the endpoint is not resolvable: the requests can only reach the server through the dialer.
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr != "dns.internal:80" {
+			return nil, fmt.Errorf("unexpected address: %s", addr)
+		}
+
+		dialed.Add(1)
+
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	config := NewDefaultConfig().WithDialer(dial)
+	config.Endpoint = mustParse("http://dns.internal")
+	config.HTTPClient.Transport.(*http.Transport).DisableKeepAlives = true
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("domain", "token", "key")
+	require.NoError(t, err)
+
+	err = p.CleanUp("domain", "token", "key")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, dialed.Load())
+}
+
 func successHandler(rw http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodPost {
 		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
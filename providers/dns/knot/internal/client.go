@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrNoSuchRecord is returned when the requested record doesn't exist in the zone.
+var ErrNoSuchRecord = errors.New("no such record in zone")
+
+// Client manages the zones of a Knot DNS server through knotc.
+type Client struct {
+	binary string
+	socket string
+}
+
+// NewClient creates a new Client.
+// If socket is empty, the default control socket of knotc is used.
+func NewClient(binary, socket string) *Client {
+	return &Client{binary: binary, socket: socket}
+}
+
+// Begin starts a transaction on the zone.
+func (c *Client) Begin(ctx context.Context, zone string) error {
+	_, err := c.run(ctx, "zone-begin", zone)
+	return err
+}
+
+// Commit commits the transaction of the zone.
+func (c *Client) Commit(ctx context.Context, zone string) error {
+	_, err := c.run(ctx, "zone-commit", zone)
+	return err
+}
+
+// Abort aborts the transaction of the zone.
+func (c *Client) Abort(ctx context.Context, zone string) error {
+	_, err := c.run(ctx, "zone-abort", zone)
+	return err
+}
+
+// SetTXT adds a TXT record to the transaction of the zone.
+func (c *Client) SetTXT(ctx context.Context, zone, owner string, ttl int, value string) error {
+	_, err := c.run(ctx, "zone-set", zone, owner, strconv.Itoa(ttl), "TXT", quote(value))
+	return err
+}
+
+// UnsetTXT removes a TXT record in the transaction of the zone.
+func (c *Client) UnsetTXT(ctx context.Context, zone, owner, value string) error {
+	_, err := c.run(ctx, "zone-unset", zone, owner, "TXT", quote(value))
+	return err
+}
+
+// ReadTXT returns the values of the TXT records of the owner, from the current (committed) content of the zone.
+func (c *Client) ReadTXT(ctx context.Context, zone, owner string) ([]string, error) {
+	output, err := c.run(ctx, "zone-read", zone, owner, "TXT")
+	if errors.Is(err, ErrNoSuchRecord) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+
+	// [<zone>] <owner> <ttl> TXT "<value>"
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		_, rdata, ok := strings.Cut(scanner.Text(), " TXT ")
+		if !ok {
+			continue
+		}
+
+		values = append(values, strings.Trim(strings.TrimSpace(rdata), `"`))
+	}
+
+	return values, scanner.Err()
+}
+
+func (c *Client) run(ctx context.Context, command string, args ...string) ([]byte, error) {
+	args = append([]string{command}, args...)
+
+	if c.socket != "" {
+		args = append([]string{"--socket", c.socket}, args...)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, c.binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+
+		if strings.Contains(msg, ErrNoSuchRecord.Error()) {
+			return nil, fmt.Errorf("knotc %s: %w", command, ErrNoSuchRecord)
+		}
+
+		return nil, &CommandError{Command: command, Message: msg, Err: err}
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// CommandError is returned when knotc fails.
+type CommandError struct {
+	Command string
+	Message string
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("knotc %s: %v", e.Command, e.Err)
+	}
+
+	return fmt.Sprintf("knotc %s: %s: %v", e.Command, e.Message, e.Err)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// quote quotes the TXT value, the values of the ACME challenges don't contain any character to escape.
+func quote(value string) string {
+	return `"` + value + `"`
+}
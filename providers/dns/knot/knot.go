@@ -0,0 +1,221 @@
+// Package knot implements a DNS provider for solving the DNS-01 challenge using Knot DNS (through knotc).
+package knot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/providers/dns/knot/internal"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "KNOT_"
+
+	EnvKnotcPath = envNamespace + "KNOTC_PATH"
+	EnvSocket    = envNamespace + "SOCKET"
+	EnvZone      = envNamespace + "ZONE"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvCommandTimeout     = envNamespace + "COMMAND_TIMEOUT"
+)
+
+const defaultKnotcPath = "knotc"
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// KnotcPath is the path of the knotc binary ("knotc" by default).
+	KnotcPath string
+	// Socket is the path of the control socket of the server (the default socket of knotc if empty).
+	Socket string
+	// Zone is the zone managed by the server (determined through SOA requests if empty).
+	Zone string
+
+	TTL                int
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	CommandTimeout     time.Duration
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		KnotcPath:          env.GetOrDefaultString(EnvKnotcPath, defaultKnotcPath),
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		CommandTimeout:     env.GetOrDefaultSecond(EnvCommandTimeout, 30*time.Second),
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+
+	// Knot DNS allows a single transaction per zone.
+	mu sync.Mutex
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Knot DNS.
+// KNOT_KNOTC_PATH, KNOT_SOCKET, and KNOT_ZONE are optional.
+func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+	config.Socket = env.GetOrDefaultString(EnvSocket, "")
+	config.Zone = env.GetOrDefaultString(EnvZone, "")
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for Knot DNS.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("knot: the configuration of the DNS provider is nil")
+	}
+
+	if config.KnotcPath == "" {
+		config.KnotcPath = defaultKnotcPath
+	}
+
+	return &DNSProvider{
+		config: config,
+		client: internal.NewClient(config.KnotcPath, config.Socket),
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+// Presenting the same record again is a no-op.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, err := d.findZone(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("knot: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.CommandTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	values, err := d.client.ReadTXT(ctx, zone, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("knot: read records: %w", err)
+	}
+
+	if slices.Contains(values, info.Value) {
+		log.Infof("knot: [%s] the TXT record already exists", domain)
+		return nil
+	}
+
+	err = d.transaction(ctx, zone, func() error {
+		return d.client.SetTXT(ctx, zone, info.EffectiveFQDN, d.config.TTL, info.Value)
+	})
+	if err != nil {
+		return fmt.Errorf("knot: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+// Removing a record which doesn't exist is a no-op.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, err := d.findZone(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("knot: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.CommandTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	values, err := d.client.ReadTXT(ctx, zone, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("knot: read records: %w", err)
+	}
+
+	if !slices.Contains(values, info.Value) {
+		return nil
+	}
+
+	err = d.transaction(ctx, zone, func() error {
+		return d.client.UnsetTXT(ctx, zone, info.EffectiveFQDN, info.Value)
+	})
+	if err != nil {
+		return fmt.Errorf("knot: %w", err)
+	}
+
+	return nil
+}
+
+// transaction runs the changes inside a transaction of the zone:
+// the transaction is committed if the changes succeed, aborted otherwise.
+func (d *DNSProvider) transaction(ctx context.Context, zone string, changes func() error) error {
+	err := d.client.Begin(ctx, zone)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	err = changes()
+	if err != nil {
+		return errors.Join(fmt.Errorf("change: %w", err), d.abort(zone))
+	}
+
+	err = d.client.Commit(ctx, zone)
+	if err != nil {
+		return errors.Join(fmt.Errorf("commit transaction: %w", err), d.abort(zone))
+	}
+
+	return nil
+}
+
+// abort aborts the transaction of the zone.
+// It doesn't use the context of the changes: the transaction must be aborted even if this context is done.
+func (d *DNSProvider) abort(zone string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.CommandTimeout)
+	defer cancel()
+
+	err := d.client.Abort(ctx, zone)
+	if err != nil {
+		return fmt.Errorf("abort transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) findZone(fqdn string) (string, error) {
+	if d.config.Zone != "" {
+		return dns01.ToFqdn(d.config.Zone), nil
+	}
+
+	zone, err := dns01.FindZoneByFqdn(fqdn)
+	if err != nil {
+		return "", fmt.Errorf("could not find zone for domain %q: %w", fqdn, err)
+	}
+
+	return zone, nil
+}
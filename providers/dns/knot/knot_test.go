@@ -0,0 +1,443 @@
+package knot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvKnotcPath, EnvSocket, EnvZone).
+	WithDomain(envDomain)
+
+// envFakeState is the path of the state of the fake knotc.
+// When this variable is defined, the test binary behaves like knotc.
+const envFakeState = "KNOT_FAKE_KNOTC_STATE"
+
+func TestMain(m *testing.M) {
+	if path := os.Getenv(envFakeState); path != "" {
+		os.Exit(fakeKnotc(path, os.Args[1:]))
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		envVars      map[string]string
+		expectedPath string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvKnotcPath: "/usr/sbin/knotc",
+				EnvSocket:    "/run/knot/knot.sock",
+				EnvZone:      "example.com",
+			},
+			expectedPath: "/usr/sbin/knotc",
+		},
+		{
+			desc:         "default knotc",
+			envVars:      map[string]string{},
+			expectedPath: "knotc",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+			require.NoError(t, err)
+			require.NotNil(t, p)
+
+			assert.Equal(t, test.expectedPath, p.config.KnotcPath)
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	p, err := NewDNSProviderConfig(nil)
+	require.EqualError(t, err, "knot: the configuration of the DNS provider is nil")
+	require.Nil(t, p)
+
+	p, err = NewDNSProviderConfig(&Config{})
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	assert.Equal(t, "knotc", p.config.KnotcPath)
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		state            fakeState
+		expectedRecords  map[string][]string
+		expectedCommands []string
+		expectedError    string
+	}{
+		{
+			desc: "new record",
+			state: fakeState{Records: map[string][]string{
+				"_acme-challenge.example.com.": {"other"},
+			}},
+			expectedRecords: map[string][]string{
+				"_acme-challenge.example.com.": {"other", "pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"},
+			},
+			expectedCommands: []string{
+				"zone-read example.com. _acme-challenge.example.com. TXT",
+				"zone-begin example.com.",
+				`zone-set example.com. _acme-challenge.example.com. 120 TXT "pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"`,
+				"zone-commit example.com.",
+			},
+		},
+		{
+			desc: "existing record",
+			state: fakeState{Records: map[string][]string{
+				"_acme-challenge.example.com.": {"pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"},
+			}},
+			expectedRecords: map[string][]string{
+				"_acme-challenge.example.com.": {"pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"},
+			},
+			expectedCommands: []string{
+				"zone-read example.com. _acme-challenge.example.com. TXT",
+			},
+		},
+		{
+			desc:  "set failure",
+			state: fakeState{Fail: "zone-set"},
+			expectedCommands: []string{
+				"zone-read example.com. _acme-challenge.example.com. TXT",
+				"zone-begin example.com.",
+				`zone-set example.com. _acme-challenge.example.com. 120 TXT "pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"`,
+				"zone-abort example.com.",
+			},
+			expectedError: "knot: change: knotc zone-set: error: (operation failed): exit status 1",
+		},
+		{
+			desc:  "commit failure",
+			state: fakeState{Fail: "zone-commit"},
+			expectedCommands: []string{
+				"zone-read example.com. _acme-challenge.example.com. TXT",
+				"zone-begin example.com.",
+				`zone-set example.com. _acme-challenge.example.com. 120 TXT "pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"`,
+				"zone-commit example.com.",
+				"zone-abort example.com.",
+			},
+			expectedError: "knot: commit transaction: knotc zone-commit: error: (operation failed): exit status 1",
+		},
+		{
+			desc:  "transaction already open",
+			state: fakeState{Transaction: map[string][]string{}},
+			expectedCommands: []string{
+				"zone-read example.com. _acme-challenge.example.com. TXT",
+				"zone-begin example.com.",
+			},
+			expectedError: "knot: begin transaction: knotc zone-begin: error: (too many transactions): exit status 1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider, statePath := setupFakeKnotc(t, test.state)
+
+			err := provider.Present("example.com", "token", "keyAuth")
+
+			state := readFakeState(t, statePath)
+
+			assert.Equal(t, test.expectedCommands, state.Commands)
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+
+				// the zone is left untouched, and the transaction is closed (except the one which was already open).
+				assert.Equal(t, test.state.Records, state.Records)
+				assert.Equal(t, test.state.Transaction, state.Transaction)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedRecords, state.Records)
+			assert.Nil(t, state.Transaction)
+		})
+	}
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		state            fakeState
+		expectedRecords  map[string][]string
+		expectedCommands []string
+	}{
+		{
+			desc: "existing record",
+			state: fakeState{Records: map[string][]string{
+				"_acme-challenge.example.com.": {"other", "pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"},
+			}},
+			expectedRecords: map[string][]string{
+				"_acme-challenge.example.com.": {"other"},
+			},
+			expectedCommands: []string{
+				"zone-read example.com. _acme-challenge.example.com. TXT",
+				"zone-begin example.com.",
+				`zone-unset example.com. _acme-challenge.example.com. TXT "pW9ZKG0xz_PCriK-nCMOjADy9eJcgGWIzkkj2fN4uZM"`,
+				"zone-commit example.com.",
+			},
+		},
+		{
+			desc: "missing record",
+			expectedCommands: []string{
+				"zone-read example.com. _acme-challenge.example.com. TXT",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider, statePath := setupFakeKnotc(t, test.state)
+
+			err := provider.CleanUp("example.com", "token", "keyAuth")
+			require.NoError(t, err)
+
+			state := readFakeState(t, statePath)
+
+			assert.Equal(t, test.expectedCommands, state.Commands)
+			assert.Equal(t, test.expectedRecords, state.Records)
+			assert.Nil(t, state.Transaction)
+		})
+	}
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+// fakeState is the state of the fake knotc, shared between its invocations.
+type fakeState struct {
+	Records     map[string][]string `json:"records,omitempty"`
+	Transaction map[string][]string `json:"transaction"`
+	Fail        string              `json:"fail,omitempty"`
+	Commands    []string            `json:"commands,omitempty"`
+}
+
+func setupFakeKnotc(t *testing.T, state fakeState) (*DNSProvider, string) {
+	t.Helper()
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	writeFakeState(t, statePath, state)
+
+	t.Setenv(envFakeState, statePath)
+
+	config := NewDefaultConfig()
+	config.KnotcPath = os.Args[0]
+	config.Socket = "/run/knot/knot.sock"
+	config.Zone = "example.com"
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	return provider, statePath
+}
+
+func readFakeState(t *testing.T, path string) fakeState {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var state fakeState
+
+	err = json.Unmarshal(data, &state)
+	require.NoError(t, err)
+
+	return state
+}
+
+func writeFakeState(t *testing.T, path string, state fakeState) {
+	t.Helper()
+
+	data, err := json.Marshal(state)
+	require.NoError(t, err)
+
+	err = os.WriteFile(path, data, 0o600)
+	require.NoError(t, err)
+}
+
+// fakeKnotc mimics the behavior of knotc for the zone transactions.
+func fakeKnotc(statePath string, args []string) int {
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var state fakeState
+
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if len(args) > 2 && args[0] == "--socket" {
+		if args[1] != "/run/knot/knot.sock" {
+			fmt.Fprintln(os.Stderr, "error: (connection refused)")
+			return 1
+		}
+
+		args = args[2:]
+	}
+
+	state.Commands = append(state.Commands, strings.Join(args, " "))
+
+	output, errMsg := fakeCommand(&state, args)
+
+	data, err = json.Marshal(state)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	err = os.WriteFile(statePath, data, 0o600)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if errMsg != "" {
+		fmt.Fprintf(os.Stderr, "error: (%s)\n", errMsg)
+		return 1
+	}
+
+	fmt.Print(output)
+
+	return 0
+}
+
+func fakeCommand(state *fakeState, args []string) (string, string) {
+	if args[0] == state.Fail {
+		return "", "operation failed"
+	}
+
+	switch args[0] {
+	case "zone-read":
+		values := state.Records[args[2]]
+		if len(values) == 0 {
+			return "", "no such record in zone"
+		}
+
+		var output string
+		for _, value := range values {
+			output += fmt.Sprintf("[%s] %s 120 TXT %q\n", args[1], args[2], value)
+		}
+
+		return output, ""
+
+	case "zone-begin":
+		if state.Transaction != nil {
+			return "", "too many transactions"
+		}
+
+		state.Transaction = map[string][]string{}
+		for owner, values := range state.Records {
+			state.Transaction[owner] = slices.Clone(values)
+		}
+
+		return "OK\n", ""
+
+	case "zone-set":
+		if state.Transaction == nil {
+			return "", "no active transaction"
+		}
+
+		value := strings.Trim(args[5], `"`)
+		if slices.Contains(state.Transaction[args[2]], value) {
+			return "", "such record already exists in zone"
+		}
+
+		state.Transaction[args[2]] = append(state.Transaction[args[2]], value)
+
+		return "OK\n", ""
+
+	case "zone-unset":
+		if state.Transaction == nil {
+			return "", "no active transaction"
+		}
+
+		value := strings.Trim(args[4], `"`)
+		if !slices.Contains(state.Transaction[args[2]], value) {
+			return "", "no such record in zone"
+		}
+
+		state.Transaction[args[2]] = slices.DeleteFunc(state.Transaction[args[2]], func(v string) bool { return v == value })
+		if len(state.Transaction[args[2]]) == 0 {
+			delete(state.Transaction, args[2])
+		}
+
+		return "OK\n", ""
+
+	case "zone-commit":
+		if state.Transaction == nil {
+			return "", "no active transaction"
+		}
+
+		state.Records = state.Transaction
+		state.Transaction = nil
+
+		return "OK\n", ""
+
+	case "zone-abort":
+		if state.Transaction == nil {
+			return "", "no active transaction"
+		}
+
+		state.Transaction = nil
+
+		return "OK\n", ""
+
+	default:
+		return "", "invalid command"
+	}
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Nsupdate sends dynamic updates signed with GSS-TSIG (Kerberos, RFC 3645) through `nsupdate -g`.
+// The Kerberos credentials come from the keytab (if defined), or from the credentials cache.
+type Nsupdate struct {
+	Binary string
+
+	// Realm is the Kerberos realm of the server (the default realm of krb5.conf if empty).
+	Realm string
+	// Keytab is the path of the client keytab used to acquire the credentials.
+	Keytab string
+	// CCache is the credentials cache (e.g. "FILE:/tmp/krb5cc_lego") holding the current ticket.
+	CCache string
+}
+
+// Update sends the update commands (e.g. `update add ...`) for the zone to the server (host:port).
+func (n *Nsupdate) Update(ctx context.Context, server, zone string, commands []string) error {
+	script, err := n.Script(server, zone, commands)
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, n.Binary, "-g")
+	cmd.Stdin = strings.NewReader(script)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = n.environ()
+
+	err = cmd.Run()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+
+		if msg == "" {
+			return fmt.Errorf("nsupdate: %w", err)
+		}
+
+		return fmt.Errorf("nsupdate: %s: %w", msg, err)
+	}
+
+	return nil
+}
+
+// Script builds the input of nsupdate.
+func (n *Nsupdate) Script(server, zone string, commands []string) (string, error) {
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		return "", fmt.Errorf("nsupdate: invalid server: %w", err)
+	}
+
+	var b strings.Builder
+
+	_, _ = fmt.Fprintf(&b, "server %s %s\n", host, port)
+
+	if n.Realm != "" {
+		_, _ = fmt.Fprintf(&b, "realm %s\n", n.Realm)
+	}
+
+	_, _ = fmt.Fprintf(&b, "zone %s\n", zone)
+
+	for _, command := range commands {
+		_, _ = fmt.Fprintln(&b, command)
+	}
+
+	b.WriteString("send\n")
+
+	return b.String(), nil
+}
+
+func (n *Nsupdate) environ() []string {
+	environ := os.Environ()
+
+	if n.Keytab != "" {
+		// MIT Kerberos acquires the initial credentials from the client keytab.
+		environ = append(environ, "KRB5_CLIENT_KTNAME="+n.Keytab)
+	}
+
+	if n.CCache != "" {
+		environ = append(environ, "KRB5CCNAME="+n.CCache)
+	}
+
+	return environ
+}
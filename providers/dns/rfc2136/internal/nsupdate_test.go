@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNsupdate_Script(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		nsupdate *Nsupdate
+		expected string
+	}{
+		{
+			desc:     "default realm",
+			nsupdate: &Nsupdate{},
+			expected: "server 192.0.2.1 53\nzone example.com.\nupdate add _acme-challenge.example.com. 120 TXT \"value\"\nsend\n",
+		},
+		{
+			desc:     "realm",
+			nsupdate: &Nsupdate{Realm: "AD.EXAMPLE.COM"},
+			expected: "server 192.0.2.1 53\nrealm AD.EXAMPLE.COM\nzone example.com.\nupdate add _acme-challenge.example.com. 120 TXT \"value\"\nsend\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			script, err := test.nsupdate.Script("192.0.2.1:53", "example.com.", []string{`update add _acme-challenge.example.com. 120 TXT "value"`})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, script)
+		})
+	}
+}
+
+func TestNsupdate_Script_invalidServer(t *testing.T) {
+	_, err := (&Nsupdate{}).Script("192.0.2.1", "example.com.", nil)
+	require.EqualError(t, err, "nsupdate: invalid server: address 192.0.2.1: missing port in address")
+}
@@ -2,6 +2,7 @@
 package rfc2136
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -25,6 +26,12 @@ const (
 	EnvTSIGSecret    = envNamespace + "TSIG_SECRET"
 	EnvTSIGAlgorithm = envNamespace + "TSIG_ALGORITHM"
 
+	EnvGSSTSIG      = envNamespace + "GSS_TSIG"
+	EnvGSSRealm     = envNamespace + "GSS_REALM"
+	EnvGSSKeytab    = envNamespace + "GSS_KEYTAB"
+	EnvGSSCCache    = envNamespace + "GSS_CCACHE"
+	EnvNsupdatePath = envNamespace + "NSUPDATE_PATH"
+
 	EnvNameserver = envNamespace + "NAMESERVER"
 	EnvDNSTimeout = envNamespace + "DNS_TIMEOUT"
 
@@ -46,6 +53,13 @@ type Config struct {
 	TSIGKey       string
 	TSIGSecret    string
 
+	// GSSTSIG signs the updates with GSS-TSIG (Kerberos, e.g. for Active Directory), through `nsupdate -g`.
+	GSSTSIG      bool
+	GSSRealm     string
+	GSSKeytab    string
+	GSSCCache    string
+	NsupdatePath string
+
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	TTL                int
@@ -57,6 +71,7 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		TSIGAlgorithm:      env.GetOrDefaultString(EnvTSIGAlgorithm, dns.HmacSHA1),
+		NsupdatePath:       env.GetOrDefaultString(EnvNsupdatePath, "nsupdate"),
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, env.GetOrDefaultSecond("RFC2136_TIMEOUT", 60*time.Second)),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 2*time.Second),
@@ -68,6 +83,8 @@ func NewDefaultConfig() *Config {
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
+
+	nsupdate *internal.Nsupdate
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for rfc2136
@@ -79,6 +96,7 @@ type DNSProvider struct {
 // RFC2136_TSIG_SECRET: Secret key payload.
 // RFC2136_PROPAGATION_TIMEOUT: DNS propagation timeout in time.ParseDuration format. (60s)
 // To disable TSIG authentication, leave the RFC2136_TSIG* variables unset.
+// RFC2136_GSS_TSIG: Signs the updates with GSS-TSIG (Kerberos) through `nsupdate -g`, instead of TSIG.
 func NewDNSProvider() (*DNSProvider, error) {
 	values, err := env.Get(EnvNameserver)
 	if err != nil {
@@ -93,6 +111,11 @@ func NewDNSProvider() (*DNSProvider, error) {
 	config.TSIGKey = env.GetOrFile(EnvTSIGKey)
 	config.TSIGSecret = env.GetOrFile(EnvTSIGSecret)
 
+	config.GSSTSIG = env.GetOrDefaultBool(EnvGSSTSIG, false)
+	config.GSSRealm = env.GetOrDefaultString(EnvGSSRealm, "")
+	config.GSSKeytab = env.GetOrDefaultString(EnvGSSKeytab, "")
+	config.GSSCCache = env.GetOrDefaultString(EnvGSSCCache, "")
+
 	return NewDNSProviderConfig(config)
 }
 
@@ -126,6 +149,23 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		}
 	}
 
+	if config.GSSTSIG {
+		if config.TSIGKey != "" || config.TSIGSecret != "" {
+			return nil, errors.New("rfc2136: TSIG and GSS-TSIG are mutually exclusive")
+		}
+
+		if config.NsupdatePath == "" {
+			config.NsupdatePath = "nsupdate"
+		}
+
+		return &DNSProvider{config: config, nsupdate: &internal.Nsupdate{
+			Binary: config.NsupdatePath,
+			Realm:  config.GSSRealm,
+			Keytab: config.GSSKeytab,
+			CCache: config.GSSCCache,
+		}}, nil
+	}
+
 	if config.TSIGKey == "" || config.TSIGSecret == "" {
 		config.TSIGKey = ""
 		config.TSIGSecret = ""
@@ -192,6 +232,10 @@ func (d *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
 		return err
 	}
 
+	if d.nsupdate != nil {
+		return d.changeRecordGSS(action, zone, fqdn, value, ttl)
+	}
+
 	// Create RR
 	rr := new(dns.TXT)
 	rr.Hdr = dns.RR_Header{Name: fqdn, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: uint32(ttl)}
@@ -234,3 +278,38 @@ func (d *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
 
 	return nil
 }
+
+// changeRecordGSS sends the dynamic update signed with GSS-TSIG, through nsupdate.
+func (d *DNSProvider) changeRecordGSS(action, zone, fqdn, value string, ttl int) error {
+	var commands []string
+
+	switch action {
+	case "INSERT":
+		// Always remove old challenge left over from who knows what.
+		commands = []string{
+			fmt.Sprintf("update delete %s TXT", fqdn),
+			fmt.Sprintf("update add %s %d TXT %q", fqdn, ttl, value),
+		}
+	case "REMOVE":
+		commands = []string{fmt.Sprintf("update delete %s TXT %q", fqdn, value)}
+	default:
+		return fmt.Errorf("unexpected action: %s", action)
+	}
+
+	ctx := context.Background()
+
+	if d.config.DNSTimeout > 0 {
+		var cancel context.CancelFunc
+
+		// The GSS-TSIG negotiation (TKEY) requires additional exchanges with the server and the KDC.
+		ctx, cancel = context.WithTimeout(ctx, 3*d.config.DNSTimeout)
+		defer cancel()
+	}
+
+	err := d.nsupdate.Update(ctx, d.config.Nameserver, zone, commands)
+	if err != nil {
+		return fmt.Errorf("DNS update failed: %w", err)
+	}
+
+	return nil
+}
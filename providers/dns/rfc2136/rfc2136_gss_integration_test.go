@@ -0,0 +1,73 @@
+//go:build gsstsig
+
+package rfc2136
+
+import (
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The integration tests require a DNS server accepting GSS-TSIG updates (e.g. Active Directory), a KDC,
+// and nsupdate with GSS-TSIG support:
+//
+//	RFC2136_DOMAIN=example.com \
+//	RFC2136_NAMESERVER=dc1.ad.example.com \
+//	RFC2136_GSS_TSIG=true \
+//	RFC2136_GSS_REALM=AD.EXAMPLE.COM \
+//	RFC2136_GSS_KEYTAB=/etc/lego/lego.keytab \
+//	go test -tags gsstsig -run TestIntegrationGSSTSIG ./providers/dns/rfc2136/
+//
+// The other variables (RFC2136_GSS_REALM, RFC2136_GSS_KEYTAB, etc.) are optional.
+var envTestGSS = tester.NewEnvTest(EnvNameserver, EnvGSSTSIG).WithDomain(envDomain)
+
+func TestIntegrationGSSTSIG(t *testing.T) {
+	if !envTestGSS.IsLiveTest() {
+		t.Skip("skipping integration test: RFC2136_DOMAIN, RFC2136_NAMESERVER, and RFC2136_GSS_TSIG are required")
+	}
+
+	envTestGSS.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+	require.NotNil(t, provider.nsupdate, "GSS-TSIG must be enabled (RFC2136_GSS_TSIG=true)")
+
+	domain := envTestGSS.GetDomain()
+	keyAuth := "123d=="
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	err = provider.Present(domain, "", keyAuth)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{info.Value}, queryTXT(t, provider.config.Nameserver, info.EffectiveFQDN))
+
+	err = provider.CleanUp(domain, "", keyAuth)
+	require.NoError(t, err)
+
+	assert.Empty(t, queryTXT(t, provider.config.Nameserver, info.EffectiveFQDN))
+}
+
+func queryTXT(t *testing.T, nameserver, fqdn string) []string {
+	t.Helper()
+
+	m := new(dns.Msg)
+	m.SetQuestion(fqdn, dns.TypeTXT)
+
+	r, _, err := new(dns.Client).Exchange(m, nameserver)
+	require.NoError(t, err)
+
+	var values []string
+
+	for _, rr := range r.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			values = append(values, txt.Txt...)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,204 @@
+package rfc2136
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envFakeNsupdate is the file where the fake nsupdate records its invocations.
+const envFakeNsupdate = "RFC2136_FAKE_NSUPDATE"
+
+// envFakeNsupdateError is the error message of the fake nsupdate.
+const envFakeNsupdateError = "RFC2136_FAKE_NSUPDATE_ERROR"
+
+type nsupdateCall struct {
+	Args   []string `json:"args"`
+	Script string   `json:"script"`
+	Keytab string   `json:"keytab"`
+	CCache string   `json:"ccache"`
+}
+
+func TestMain(m *testing.M) {
+	if path := os.Getenv(envFakeNsupdate); path != "" {
+		os.Exit(fakeNsupdate(path))
+	}
+
+	os.Exit(m.Run())
+}
+
+// fakeNsupdate is executed instead of nsupdate (the test binary is re-executed).
+func fakeNsupdate(path string) int {
+	script, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	call := nsupdateCall{
+		Args:   os.Args[1:],
+		Script: string(script),
+		Keytab: os.Getenv("KRB5_CLIENT_KTNAME"),
+		CCache: os.Getenv("KRB5CCNAME"),
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	defer func() { _ = file.Close() }()
+
+	err = json.NewEncoder(file).Encode(call)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if msg := os.Getenv(envFakeNsupdateError); msg != "" {
+		_, _ = fmt.Fprintln(os.Stderr, msg)
+		return 2
+	}
+
+	return 0
+}
+
+func readNsupdateCalls(t *testing.T, path string) []nsupdateCall {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	var calls []nsupdateCall
+
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		var call nsupdateCall
+		require.NoError(t, decoder.Decode(&call))
+
+		calls = append(calls, call)
+	}
+
+	return calls
+}
+
+func setupGSSProvider(t *testing.T) (*DNSProvider, string) {
+	t.Helper()
+
+	dns01.ClearFqdnCache()
+	dns.HandleFunc(fakeZone, serverHandlerReturnSuccess)
+	t.Cleanup(func() { dns.HandleRemove(fakeZone) })
+
+	server, addr, err := runLocalDNSTestServer(false)
+	require.NoError(t, err, "Failed to start test server")
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	output := t.TempDir() + "/calls.json"
+	t.Setenv(envFakeNsupdate, output)
+
+	config := NewDefaultConfig()
+	config.Nameserver = addr
+	config.TTL = fakeTTL
+	config.GSSTSIG = true
+	config.GSSRealm = "AD.EXAMPLE.COM"
+	config.GSSKeytab = "/etc/lego/lego.keytab"
+	config.GSSCCache = "MEMORY:lego"
+	config.NsupdatePath = os.Args[0]
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	return provider, output
+}
+
+func TestDNSProvider_GSSTSIG(t *testing.T) {
+	provider, output := setupGSSProvider(t)
+
+	err := provider.Present(fakeDomain, "", "1234d==")
+	require.NoError(t, err)
+
+	err = provider.CleanUp(fakeDomain, "", "1234d==")
+	require.NoError(t, err)
+
+	calls := readNsupdateCalls(t, output)
+	require.Len(t, calls, 2)
+
+	host, port, err := net.SplitHostPort(provider.config.Nameserver)
+	require.NoError(t, err)
+
+	expected := []nsupdateCall{
+		{
+			Args: []string{"-g"},
+			Script: fmt.Sprintf("server %s %s\nrealm AD.EXAMPLE.COM\nzone %s\n", host, port, fakeZone) +
+				fmt.Sprintf("update delete %s TXT\n", fakeFqdn) +
+				fmt.Sprintf("update add %s %d TXT %q\n", fakeFqdn, fakeTTL, fakeValue) +
+				"send\n",
+			Keytab: "/etc/lego/lego.keytab",
+			CCache: "MEMORY:lego",
+		},
+		{
+			Args: []string{"-g"},
+			Script: fmt.Sprintf("server %s %s\nrealm AD.EXAMPLE.COM\nzone %s\n", host, port, fakeZone) +
+				fmt.Sprintf("update delete %s TXT %q\n", fakeFqdn, fakeValue) +
+				"send\n",
+			Keytab: "/etc/lego/lego.keytab",
+			CCache: "MEMORY:lego",
+		},
+	}
+
+	assert.Equal(t, expected, calls)
+}
+
+func TestDNSProvider_GSSTSIG_error(t *testing.T) {
+	provider, _ := setupGSSProvider(t)
+
+	t.Setenv(envFakeNsupdateError, "update failed: REFUSED")
+
+	err := provider.Present(fakeDomain, "", fakeKeyAuth)
+	require.EqualError(t, err, "rfc2136: failed to insert: DNS update failed: nsupdate: update failed: REFUSED: exit status 2")
+}
+
+func TestNewDNSProviderConfig_GSSTSIG(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *Config
+		expected string
+	}{
+		{
+			desc:   "success",
+			config: &Config{Nameserver: "dc1.ad.example.com", GSSTSIG: true},
+		},
+		{
+			desc:     "with TSIG",
+			config:   &Config{Nameserver: "dc1.ad.example.com", GSSTSIG: true, TSIGKey: fakeTsigKey, TSIGSecret: fakeTsigSecret},
+			expected: "rfc2136: TSIG and GSS-TSIG are mutually exclusive",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			p, err := NewDNSProviderConfig(test.config)
+			if test.expected != "" {
+				require.EqualError(t, err, test.expected)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, p.nsupdate)
+
+			assert.Equal(t, "nsupdate", p.nsupdate.Binary)
+			assert.Equal(t, "dc1.ad.example.com:53", p.config.Nameserver)
+		})
+	}
+}
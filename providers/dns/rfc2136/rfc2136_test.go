@@ -34,6 +34,11 @@ var envTest = tester.NewEnvTest(
 	EnvTSIGKey,
 	EnvTSIGSecret,
 	EnvTSIGAlgorithm,
+	EnvGSSTSIG,
+	EnvGSSRealm,
+	EnvGSSKeytab,
+	EnvGSSCCache,
+	EnvNsupdatePath,
 	EnvNameserver,
 	EnvDNSTimeout,
 ).WithDomain(envDomain)
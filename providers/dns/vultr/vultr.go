@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge"
@@ -22,6 +23,7 @@ const (
 	envNamespace = "VULTR_"
 
 	EnvAPIKey = envNamespace + "API_KEY"
+	EnvZone   = envNamespace + "ZONE"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -33,7 +35,9 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey             string
+	APIKey string
+	// Zone is the domain of the records (determined through the list of the domains of the account if empty).
+	Zone               string
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	TTL                int
@@ -44,6 +48,7 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		Zone:               env.GetOrDefaultString(EnvZone, ""),
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
@@ -55,6 +60,9 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	config *Config
 	client *govultr.Client
+
+	recordIDs   map[string]string
+	recordIDsMu sync.Mutex
 }
 
 // NewDNSProvider returns a DNSProvider instance with a configured Vultr client.
@@ -86,17 +94,22 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	client := govultr.NewClient(authClient)
 
-	return &DNSProvider{client: client, config: config}, nil
+	return &DNSProvider{
+		client:    client,
+		config:    config,
+		recordIDs: make(map[string]string),
+	}, nil
 }
 
 // Present creates a TXT record to fulfill the DNS-01 challenge.
+// If a TXT record with the same value already exists (e.g. Present called again after a transient failure), it is reused.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
-	zoneDomain, err := d.getHostedZone(ctx, domain)
+	zoneDomain, records, err := d.findTxtRecords(ctx, domain, info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("vultr: %w", err)
 	}
@@ -114,20 +127,29 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Priority: func(v int) *int { return &v }(0),
 	}
 
-	_, resp, err := d.client.DomainRecord.Create(ctx, zoneDomain, &req)
+	recordID, err := d.upsertRecord(ctx, zoneDomain, records, &req)
 	if err != nil {
-		return fmt.Errorf("vultr: %w", extendError(resp, err))
+		return fmt.Errorf("vultr: %w", err)
 	}
 
+	d.recordIDsMu.Lock()
+	d.recordIDs[token] = recordID
+	d.recordIDsMu.Unlock()
+
 	return nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
+// The other TXT records of the FQDN (e.g. the record of the wildcard domain) are preserved.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
+	d.recordIDsMu.Lock()
+	recordID, ok := d.recordIDs[token]
+	d.recordIDsMu.Unlock()
+
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
 	zoneDomain, records, err := d.findTxtRecords(ctx, domain, info.EffectiveFQDN)
 	if err != nil {
@@ -136,6 +158,11 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	var allErr []string
 	for _, rec := range records {
+		// the record created by Present, or the records with the value if the ID is unknown (e.g. Present called by another process).
+		if ok && rec.ID != recordID || !ok && rec.Data != `"`+info.Value+`"` {
+			continue
+		}
+
 		err := d.client.DomainRecord.Delete(ctx, zoneDomain, rec.ID)
 		if err != nil {
 			allErr = append(allErr, err.Error())
@@ -143,9 +170,13 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	if len(allErr) > 0 {
-		return errors.New(strings.Join(allErr, ": "))
+		return fmt.Errorf("vultr: %s", strings.Join(allErr, ": "))
 	}
 
+	d.recordIDsMu.Lock()
+	delete(d.recordIDs, token)
+	d.recordIDsMu.Unlock()
+
 	return nil
 }
 
@@ -190,8 +221,42 @@ func (d *DNSProvider) getHostedZone(ctx context.Context, domain string) (string,
 	return hostedDomain.Domain, nil
 }
 
+// upsertRecord creates the record, unless a record with the same value already exists:
+// the existing record is reused (and updated if its TTL is different).
+func (d *DNSProvider) upsertRecord(ctx context.Context, zoneDomain string, records []govultr.DomainRecord, req *govultr.DomainRecordReq) (string, error) {
+	for _, record := range records {
+		if record.Data != req.Data {
+			continue
+		}
+
+		if record.TTL != req.TTL {
+			err := d.client.DomainRecord.Update(ctx, zoneDomain, record.ID, req)
+			if err != nil {
+				return "", extendError(nil, err)
+			}
+		}
+
+		return record.ID, nil
+	}
+
+	record, resp, err := d.client.DomainRecord.Create(ctx, zoneDomain, req)
+	if err != nil {
+		return "", extendError(resp, err)
+	}
+
+	return record.ID, nil
+}
+
+func (d *DNSProvider) findZone(ctx context.Context, domain string) (string, error) {
+	if d.config.Zone != "" {
+		return dns01.UnFqdn(d.config.Zone), nil
+	}
+
+	return d.getHostedZone(ctx, domain)
+}
+
 func (d *DNSProvider) findTxtRecords(ctx context.Context, domain, fqdn string) (string, []govultr.DomainRecord, error) {
-	zoneDomain, err := d.getHostedZone(ctx, domain)
+	zoneDomain, err := d.findZone(ctx, domain)
 	if err != nil {
 		return "", nil, err
 	}
@@ -7,9 +7,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -218,6 +220,189 @@ func TestDNSProvider_getHostedZone(t *testing.T) {
 	}
 }
 
+// recordsMock is a minimal in-memory Vultr DNS records API.
+type recordsMock struct {
+	mu      sync.Mutex
+	records []govultr.DomainRecord
+	lastID  int
+	created int
+}
+
+func setupRecordsMock(t *testing.T, records ...govultr.DomainRecord) (*recordsMock, *DNSProvider) {
+	t.Helper()
+
+	m := &recordsMock{records: records, lastID: len(records)}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /v2/domains/example.com/records", func(rw http.ResponseWriter, _ *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		_ = json.NewEncoder(rw).Encode(map[string]any{
+			"records": m.records,
+			"meta":    govultr.Meta{Total: len(m.records), Links: &govultr.Links{}},
+		})
+	})
+
+	mux.HandleFunc("POST /v2/domains/example.com/records", func(rw http.ResponseWriter, req *http.Request) {
+		var recordReq govultr.DomainRecordReq
+
+		err := json.NewDecoder(req.Body).Decode(&recordReq)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID++
+		m.created++
+
+		record := govultr.DomainRecord{
+			ID:   strconv.Itoa(m.lastID),
+			Type: recordReq.Type,
+			Name: recordReq.Name,
+			Data: recordReq.Data,
+			TTL:  recordReq.TTL,
+		}
+
+		m.records = append(m.records, record)
+
+		rw.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(rw).Encode(map[string]any{"record": record})
+	})
+
+	mux.HandleFunc("PATCH /v2/domains/example.com/records/{id}", func(rw http.ResponseWriter, req *http.Request) {
+		var recordReq govultr.DomainRecordReq
+
+		err := json.NewDecoder(req.Body).Decode(&recordReq)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for i, record := range m.records {
+			if record.ID == req.PathValue("id") {
+				m.records[i].TTL = recordReq.TTL
+			}
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("DELETE /v2/domains/example.com/records/{id}", func(rw http.ResponseWriter, req *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for i, record := range m.records {
+			if record.ID == req.PathValue("id") {
+				m.records = append(m.records[:i], m.records[i+1:]...)
+				break
+			}
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	config := NewDefaultConfig()
+	config.APIKey = "secret"
+	config.Zone = "example.com"
+	config.TTL = 120
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = provider.client.SetBaseURL(server.URL)
+	require.NoError(t, err)
+
+	return m, provider
+}
+
+func (m *recordsMock) values(name string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var values []string
+
+	for _, record := range m.records {
+		if record.Type == "TXT" && record.Name == name {
+			values = append(values, record.Data)
+		}
+	}
+
+	return values
+}
+
+func TestDNSProvider_Present_idempotent(t *testing.T) {
+	mock, provider := setupRecordsMock(t)
+
+	info := dns01.GetChallengeInfo("example.com", "keyAuth")
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	// Present called again (e.g. after a transient failure).
+	err = provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, mock.created)
+	assert.Equal(t, []string{`"` + info.Value + `"`}, mock.values("_acme-challenge"))
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Empty(t, mock.values("_acme-challenge"))
+}
+
+func TestDNSProvider_Present_existingRecord(t *testing.T) {
+	info := dns01.GetChallengeInfo("example.com", "keyAuth")
+
+	// the record has been created by a previous attempt, with another TTL.
+	mock, provider := setupRecordsMock(t,
+		govultr.DomainRecord{ID: "1", Type: "TXT", Name: "_acme-challenge", Data: `"` + info.Value + `"`, TTL: 3600},
+	)
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Zero(t, mock.created)
+	assert.Equal(t, []govultr.DomainRecord{{ID: "1", Type: "TXT", Name: "_acme-challenge", Data: `"` + info.Value + `"`, TTL: 120}}, mock.records)
+}
+
+func TestDNSProvider_wildcardAndApex(t *testing.T) {
+	mock, provider := setupRecordsMock(t,
+		govultr.DomainRecord{ID: "1", Type: "TXT", Name: "_acme-challenge", Data: `"other"`, TTL: 120},
+	)
+
+	infoApex := dns01.GetChallengeInfo("example.com", "keyAuthApex")
+	infoWildcard := dns01.GetChallengeInfo("example.com", "keyAuthWildcard")
+
+	err := provider.Present("example.com", "tokenApex", "keyAuthApex")
+	require.NoError(t, err)
+
+	err = provider.Present("example.com", "tokenWildcard", "keyAuthWildcard")
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{`"other"`, `"` + infoApex.Value + `"`, `"` + infoWildcard.Value + `"`}, mock.values("_acme-challenge"))
+
+	err = provider.CleanUp("example.com", "tokenApex", "keyAuthApex")
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{`"other"`, `"` + infoWildcard.Value + `"`}, mock.values("_acme-challenge"))
+
+	err = provider.CleanUp("example.com", "tokenWildcard", "keyAuthWildcard")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`"other"`}, mock.values("_acme-challenge"))
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
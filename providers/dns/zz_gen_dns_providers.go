@@ -4,6 +4,7 @@ package dns
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
@@ -78,6 +79,7 @@ import (
 	"github.com/go-acme/lego/v4/providers/dns/ipv64"
 	"github.com/go-acme/lego/v4/providers/dns/iwantmyname"
 	"github.com/go-acme/lego/v4/providers/dns/joker"
+	"github.com/go-acme/lego/v4/providers/dns/knot"
 	"github.com/go-acme/lego/v4/providers/dns/liara"
 	"github.com/go-acme/lego/v4/providers/dns/lightsail"
 	"github.com/go-acme/lego/v4/providers/dns/limacity"
@@ -299,6 +301,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return iwantmyname.NewDNSProvider()
 	case "joker":
 		return joker.NewDNSProvider()
+	case "knot":
+		return knot.NewDNSProvider()
 	case "liara":
 		return liara.NewDNSProvider()
 	case "lightsail":
@@ -447,3 +451,2552 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return nil, fmt.Errorf("unrecognized DNS provider: %s", name)
 	}
 }
+
+// providerDescriptors the descriptors of the DNS providers (without the default propagation timeouts).
+var providerDescriptors = []ProviderDescriptor{
+	{
+		Name: "Manual",
+		Code: "manual",
+	},
+	{
+		Name:    "Joohoi's ACME-DNS",
+		Code:    "acme-dns",
+		Aliases: []string{"acmedns"},
+		URL:     "https://github.com/joohoi/acme-dns",
+		RequiredKeys: []string{
+			"ACME_DNS_API_BASE",
+			"ACME_DNS_STORAGE_PATH",
+		},
+	},
+	{
+		Name: "Alibaba Cloud DNS",
+		Code: "alidns",
+		URL:  "https://www.alibabacloud.com/product/dns",
+		RequiredKeys: []string{
+			"ALICLOUD_ACCESS_KEY",
+			"ALICLOUD_RAM_ROLE",
+			"ALICLOUD_SECRET_KEY",
+			"ALICLOUD_SECURITY_TOKEN",
+		},
+		OptionalKeys: []string{
+			"ALICLOUD_HTTP_TIMEOUT",
+			"ALICLOUD_POLLING_INTERVAL",
+			"ALICLOUD_PROPAGATION_TIMEOUT",
+			"ALICLOUD_TTL",
+		},
+	},
+	{
+		Name: "all-inkl",
+		Code: "allinkl",
+		URL:  "https://all-inkl.com",
+		RequiredKeys: []string{
+			"ALL_INKL_LOGIN",
+			"ALL_INKL_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"ALL_INKL_HTTP_TIMEOUT",
+			"ALL_INKL_POLLING_INTERVAL",
+			"ALL_INKL_PROPAGATION_TIMEOUT",
+		},
+	},
+	{
+		Name: "ArvanCloud",
+		Code: "arvancloud",
+		URL:  "https://arvancloud.ir",
+		RequiredKeys: []string{
+			"ARVANCLOUD_API_KEY",
+		},
+		OptionalKeys: []string{
+			"ARVANCLOUD_HTTP_TIMEOUT",
+			"ARVANCLOUD_POLLING_INTERVAL",
+			"ARVANCLOUD_PROPAGATION_TIMEOUT",
+			"ARVANCLOUD_TTL",
+		},
+	},
+	{
+		Name: "Aurora DNS",
+		Code: "auroradns",
+		URL:  "https://www.pcextreme.com/dns-health-checks",
+		RequiredKeys: []string{
+			"AURORA_API_KEY",
+			"AURORA_SECRET",
+		},
+		OptionalKeys: []string{
+			"AURORA_ENDPOINT",
+			"AURORA_POLLING_INTERVAL",
+			"AURORA_PROPAGATION_TIMEOUT",
+			"AURORA_TTL",
+		},
+	},
+	{
+		Name: "Autodns",
+		Code: "autodns",
+		URL:  "https://www.internetx.com/domains/autodns/",
+		RequiredKeys: []string{
+			"AUTODNS_API_PASSWORD",
+			"AUTODNS_API_USER",
+		},
+		OptionalKeys: []string{
+			"AUTODNS_CONTEXT",
+			"AUTODNS_ENDPOINT",
+			"AUTODNS_HTTP_TIMEOUT",
+			"AUTODNS_POLLING_INTERVAL",
+			"AUTODNS_PROPAGATION_TIMEOUT",
+			"AUTODNS_TTL",
+		},
+	},
+	{
+		Name: "Azure (deprecated)",
+		Code: "azure",
+		URL:  "https://azure.microsoft.com/services/dns/",
+		RequiredKeys: []string{
+			"AZURE_CLIENT_ID",
+			"AZURE_CLIENT_SECRET",
+			"AZURE_ENVIRONMENT",
+			"AZURE_RESOURCE_GROUP",
+			"AZURE_SUBSCRIPTION_ID",
+			"AZURE_TENANT_ID",
+			"instance metadata service",
+		},
+		OptionalKeys: []string{
+			"AZURE_METADATA_ENDPOINT",
+			"AZURE_POLLING_INTERVAL",
+			"AZURE_PRIVATE_ZONE",
+			"AZURE_PROPAGATION_TIMEOUT",
+			"AZURE_TTL",
+			"AZURE_ZONE_NAME",
+		},
+	},
+	{
+		Name: "Azure DNS",
+		Code: "azuredns",
+		URL:  "https://azure.microsoft.com/services/dns/",
+		RequiredKeys: []string{
+			"AZURE_CLIENT_CERTIFICATE_PATH",
+			"AZURE_CLIENT_ID",
+			"AZURE_CLIENT_SECRET",
+			"AZURE_TENANT_ID",
+		},
+		OptionalKeys: []string{
+			"AZURE_AUTH_METHOD",
+			"AZURE_AUTH_MSI_TIMEOUT",
+			"AZURE_ENVIRONMENT",
+			"AZURE_POLLING_INTERVAL",
+			"AZURE_PRIVATE_ZONE",
+			"AZURE_PROPAGATION_TIMEOUT",
+			"AZURE_RESOURCE_GROUP",
+			"AZURE_SERVICEDISCOVERY_FILTER",
+			"AZURE_SUBSCRIPTION_ID",
+			"AZURE_TTL",
+			"AZURE_ZONE_NAME",
+		},
+	},
+	{
+		Name: "Bindman",
+		Code: "bindman",
+		URL:  "https://github.com/labbsr0x/bindman-dns-webhook",
+		RequiredKeys: []string{
+			"BINDMAN_MANAGER_ADDRESS",
+		},
+		OptionalKeys: []string{
+			"BINDMAN_HTTP_TIMEOUT",
+			"BINDMAN_POLLING_INTERVAL",
+			"BINDMAN_PROPAGATION_TIMEOUT",
+		},
+	},
+	{
+		Name: "Bluecat",
+		Code: "bluecat",
+		URL:  "https://www.bluecatnetworks.com",
+		RequiredKeys: []string{
+			"BLUECAT_CONFIG_NAME",
+			"BLUECAT_DNS_VIEW",
+			"BLUECAT_PASSWORD",
+			"BLUECAT_SERVER_URL",
+			"BLUECAT_USER_NAME",
+		},
+		OptionalKeys: []string{
+			"BLUECAT_HTTP_TIMEOUT",
+			"BLUECAT_POLLING_INTERVAL",
+			"BLUECAT_PROPAGATION_TIMEOUT",
+			"BLUECAT_SKIP_DEPLOY",
+			"BLUECAT_TTL",
+		},
+	},
+	{
+		Name: "Brandit (deprecated)",
+		Code: "brandit",
+		URL:  "https://www.brandit.com/",
+		RequiredKeys: []string{
+			"BRANDIT_API_KEY",
+			"BRANDIT_API_USERNAME",
+		},
+		OptionalKeys: []string{
+			"BRANDIT_HTTP_TIMEOUT",
+			"BRANDIT_POLLING_INTERVAL",
+			"BRANDIT_PROPAGATION_TIMEOUT",
+			"BRANDIT_TTL",
+		},
+	},
+	{
+		Name: "Bunny",
+		Code: "bunny",
+		URL:  "https://bunny.net",
+		RequiredKeys: []string{
+			"BUNNY_API_KEY",
+		},
+		OptionalKeys: []string{
+			"BUNNY_POLLING_INTERVAL",
+			"BUNNY_PROPAGATION_TIMEOUT",
+			"BUNNY_TTL",
+		},
+	},
+	{
+		Name: "Checkdomain",
+		Code: "checkdomain",
+		URL:  "https://checkdomain.de/",
+		RequiredKeys: []string{
+			"CHECKDOMAIN_TOKEN",
+		},
+		OptionalKeys: []string{
+			"CHECKDOMAIN_ENDPOINT",
+			"CHECKDOMAIN_HTTP_TIMEOUT",
+			"CHECKDOMAIN_POLLING_INTERVAL",
+			"CHECKDOMAIN_PROPAGATION_TIMEOUT",
+			"CHECKDOMAIN_TTL",
+		},
+	},
+	{
+		Name: "Civo",
+		Code: "civo",
+		URL:  "https://civo.com",
+		RequiredKeys: []string{
+			"CIVO_TOKEN",
+		},
+		OptionalKeys: []string{
+			"CIVO_POLLING_INTERVAL",
+			"CIVO_PROPAGATION_TIMEOUT",
+			"CIVO_TTL",
+		},
+	},
+	{
+		Name: "CloudDNS",
+		Code: "clouddns",
+		URL:  "https://vshosting.eu/",
+		RequiredKeys: []string{
+			"CLOUDDNS_CLIENT_ID",
+			"CLOUDDNS_EMAIL",
+			"CLOUDDNS_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"CLOUDDNS_HTTP_TIMEOUT",
+			"CLOUDDNS_POLLING_INTERVAL",
+			"CLOUDDNS_PROPAGATION_TIMEOUT",
+			"CLOUDDNS_TTL",
+		},
+	},
+	{
+		Name: "Cloudflare",
+		Code: "cloudflare",
+		URL:  "https://www.cloudflare.com/dns/",
+		RequiredKeys: []string{
+			"CF_API_EMAIL",
+			"CF_API_KEY",
+			"CF_DNS_API_TOKEN",
+			"CF_ZONE_API_TOKEN",
+			"CLOUDFLARE_API_KEY",
+			"CLOUDFLARE_DNS_API_TOKEN",
+			"CLOUDFLARE_EMAIL",
+			"CLOUDFLARE_ZONE_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"CLOUDFLARE_HTTP_TIMEOUT",
+			"CLOUDFLARE_POLLING_INTERVAL",
+			"CLOUDFLARE_PROPAGATION_TIMEOUT",
+			"CLOUDFLARE_TTL",
+		},
+	},
+	{
+		Name: "ClouDNS",
+		Code: "cloudns",
+		URL:  "https://www.cloudns.net",
+		RequiredKeys: []string{
+			"CLOUDNS_AUTH_ID",
+			"CLOUDNS_AUTH_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"CLOUDNS_HTTP_TIMEOUT",
+			"CLOUDNS_POLLING_INTERVAL",
+			"CLOUDNS_PROPAGATION_TIMEOUT",
+			"CLOUDNS_SUB_AUTH_ID",
+			"CLOUDNS_TTL",
+		},
+	},
+	{
+		Name: "Cloud.ru",
+		Code: "cloudru",
+		URL:  "https://cloud.ru",
+		RequiredKeys: []string{
+			"CLOUDRU_KEY_ID",
+			"CLOUDRU_SECRET",
+			"CLOUDRU_SERVICE_INSTANCE_ID",
+		},
+		OptionalKeys: []string{
+			"CLOUDRU_HTTP_TIMEOUT",
+			"CLOUDRU_POLLING_INTERVAL",
+			"CLOUDRU_PROPAGATION_TIMEOUT",
+			"CLOUDRU_SEQUENCE_INTERVAL",
+			"CLOUDRU_TTL",
+		},
+	},
+	{
+		Name: "CloudXNS (Deprecated)",
+		Code: "cloudxns",
+		URL:  "https://github.com/go-acme/lego/issues/2323",
+		RequiredKeys: []string{
+			"CLOUDXNS_API_KEY",
+			"CLOUDXNS_SECRET_KEY",
+		},
+		OptionalKeys: []string{
+			"CLOUDXNS_HTTP_TIMEOUT",
+			"CLOUDXNS_POLLING_INTERVAL",
+			"CLOUDXNS_PROPAGATION_TIMEOUT",
+			"CLOUDXNS_TTL",
+		},
+	},
+	{
+		Name: "ConoHa",
+		Code: "conoha",
+		URL:  "https://www.conoha.jp/",
+		RequiredKeys: []string{
+			"CONOHA_API_PASSWORD",
+			"CONOHA_API_USERNAME",
+			"CONOHA_TENANT_ID",
+		},
+		OptionalKeys: []string{
+			"CONOHA_HTTP_TIMEOUT",
+			"CONOHA_POLLING_INTERVAL",
+			"CONOHA_PROPAGATION_TIMEOUT",
+			"CONOHA_REGION",
+			"CONOHA_TTL",
+		},
+	},
+	{
+		Name: "Constellix",
+		Code: "constellix",
+		URL:  "https://constellix.com",
+		RequiredKeys: []string{
+			"CONSTELLIX_API_KEY",
+			"CONSTELLIX_SECRET_KEY",
+		},
+		OptionalKeys: []string{
+			"CONSTELLIX_HTTP_TIMEOUT",
+			"CONSTELLIX_POLLING_INTERVAL",
+			"CONSTELLIX_PROPAGATION_TIMEOUT",
+			"CONSTELLIX_TTL",
+		},
+	},
+	{
+		Name: "Core-Networks",
+		Code: "corenetworks",
+		URL:  "https://www.core-networks.de/",
+		RequiredKeys: []string{
+			"CORENETWORKS_LOGIN",
+			"CORENETWORKS_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"CORENETWORKS_HTTP_TIMEOUT",
+			"CORENETWORKS_POLLING_INTERVAL",
+			"CORENETWORKS_PROPAGATION_TIMEOUT",
+			"CORENETWORKS_SEQUENCE_INTERVAL",
+			"CORENETWORKS_TTL",
+		},
+	},
+	{
+		Name: "CPanel/WHM",
+		Code: "cpanel",
+		URL:  "https://cpanel.net/",
+		RequiredKeys: []string{
+			"CPANEL_BASE_URL",
+			"CPANEL_TOKEN",
+			"CPANEL_USERNAME",
+		},
+		OptionalKeys: []string{
+			"CPANEL_HTTP_TIMEOUT",
+			"CPANEL_MODE",
+			"CPANEL_POLLING_INTERVAL",
+			"CPANEL_PROPAGATION_TIMEOUT",
+			"CPANEL_REGION",
+			"CPANEL_TTL",
+		},
+	},
+	{
+		Name: "Derak Cloud",
+		Code: "derak",
+		URL:  "https://derak.cloud/",
+		RequiredKeys: []string{
+			"DERAK_API_KEY",
+		},
+		OptionalKeys: []string{
+			"DERAK_HTTP_TIMEOUT",
+			"DERAK_POLLING_INTERVAL",
+			"DERAK_PROPAGATION_TIMEOUT",
+			"DERAK_TTL",
+			"DERAK_WEBSITE_ID",
+		},
+	},
+	{
+		Name: "deSEC.io",
+		Code: "desec",
+		URL:  "https://desec.io",
+		RequiredKeys: []string{
+			"DESEC_TOKEN",
+		},
+		OptionalKeys: []string{
+			"DESEC_HTTP_TIMEOUT",
+			"DESEC_POLLING_INTERVAL",
+			"DESEC_PROPAGATION_TIMEOUT",
+			"DESEC_TTL",
+		},
+	},
+	{
+		Name: "Designate DNSaaS for Openstack",
+		Code: "designate",
+		URL:  "https://docs.openstack.org/designate/latest/",
+		RequiredKeys: []string{
+			"OS_APPLICATION_CREDENTIAL_ID",
+			"OS_APPLICATION_CREDENTIAL_NAME",
+			"OS_APPLICATION_CREDENTIAL_SECRET",
+			"OS_AUTH_URL",
+			"OS_PASSWORD",
+			"OS_PROJECT_NAME",
+			"OS_REGION_NAME",
+			"OS_USERNAME",
+			"OS_USER_ID",
+		},
+		OptionalKeys: []string{
+			"DESIGNATE_POLLING_INTERVAL",
+			"DESIGNATE_PROPAGATION_TIMEOUT",
+			"DESIGNATE_TTL",
+			"DESIGNATE_ZONE_NAME",
+			"OS_PROJECT_ID",
+			"OS_TENANT_NAME",
+		},
+	},
+	{
+		Name: "Digital Ocean",
+		Code: "digitalocean",
+		URL:  "https://www.digitalocean.com/docs/networking/dns/",
+		RequiredKeys: []string{
+			"DO_AUTH_TOKEN",
+		},
+		OptionalKeys: []string{
+			"DO_API_URL",
+			"DO_HTTP_TIMEOUT",
+			"DO_POLLING_INTERVAL",
+			"DO_PROPAGATION_TIMEOUT",
+			"DO_TTL",
+		},
+	},
+	{
+		Name: "DirectAdmin",
+		Code: "directadmin",
+		URL:  "https://www.directadmin.com",
+		RequiredKeys: []string{
+			"DIRECTADMIN_API_URL",
+			"DIRECTADMIN_PASSWORD",
+			"DIRECTADMIN_USERNAME",
+		},
+		OptionalKeys: []string{
+			"DIRECTADMIN_HTTP_TIMEOUT",
+			"DIRECTADMIN_POLLING_INTERVAL",
+			"DIRECTADMIN_PROPAGATION_TIMEOUT",
+			"DIRECTADMIN_TTL",
+			"DIRECTADMIN_ZONE_NAME",
+		},
+	},
+	{
+		Name: "dnsHome.de",
+		Code: "dnshomede",
+		URL:  "https://www.dnshome.de",
+		RequiredKeys: []string{
+			"DNSHOMEDE_CREDENTIALS",
+		},
+		OptionalKeys: []string{
+			"DNSHOMEDE_HTTP_TIMEOUT",
+			"DNSHOMEDE_POLLING_INTERVAL",
+			"DNSHOMEDE_PROPAGATION_TIMEOUT",
+			"DNSHOMEDE_SEQUENCE_INTERVAL",
+		},
+	},
+	{
+		Name: "DNSimple",
+		Code: "dnsimple",
+		URL:  "https://dnsimple.com/",
+		RequiredKeys: []string{
+			"DNSIMPLE_OAUTH_TOKEN",
+		},
+		OptionalKeys: []string{
+			"DNSIMPLE_BASE_URL",
+			"DNSIMPLE_POLLING_INTERVAL",
+			"DNSIMPLE_PROPAGATION_TIMEOUT",
+			"DNSIMPLE_TTL",
+		},
+	},
+	{
+		Name: "DNS Made Easy",
+		Code: "dnsmadeeasy",
+		URL:  "https://dnsmadeeasy.com/",
+		RequiredKeys: []string{
+			"DNSMADEEASY_API_KEY",
+			"DNSMADEEASY_API_SECRET",
+		},
+		OptionalKeys: []string{
+			"DNSMADEEASY_HTTP_TIMEOUT",
+			"DNSMADEEASY_POLLING_INTERVAL",
+			"DNSMADEEASY_PROPAGATION_TIMEOUT",
+			"DNSMADEEASY_SANDBOX",
+			"DNSMADEEASY_TTL",
+		},
+	},
+	{
+		Name: "DNSPod (deprecated)",
+		Code: "dnspod",
+		URL:  "https://www.dnspod.com/",
+		RequiredKeys: []string{
+			"DNSPOD_API_KEY",
+		},
+		OptionalKeys: []string{
+			"DNSPOD_HTTP_TIMEOUT",
+			"DNSPOD_POLLING_INTERVAL",
+			"DNSPOD_PROPAGATION_TIMEOUT",
+			"DNSPOD_TTL",
+		},
+	},
+	{
+		Name: "Domain Offensive (do.de)",
+		Code: "dode",
+		URL:  "https://www.do.de/",
+		RequiredKeys: []string{
+			"DODE_TOKEN",
+		},
+		OptionalKeys: []string{
+			"DODE_HTTP_TIMEOUT",
+			"DODE_POLLING_INTERVAL",
+			"DODE_PROPAGATION_TIMEOUT",
+			"DODE_SEQUENCE_INTERVAL",
+			"DODE_TTL",
+		},
+	},
+	{
+		Name:    "Domeneshop",
+		Code:    "domeneshop",
+		Aliases: []string{"domainnameshop"},
+		URL:     "https://domene.shop",
+		RequiredKeys: []string{
+			"DOMENESHOP_API_SECRET",
+			"DOMENESHOP_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"DOMENESHOP_HTTP_TIMEOUT",
+			"DOMENESHOP_POLLING_INTERVAL",
+			"DOMENESHOP_PROPAGATION_TIMEOUT",
+		},
+	},
+	{
+		Name: "DreamHost",
+		Code: "dreamhost",
+		URL:  "https://www.dreamhost.com",
+		RequiredKeys: []string{
+			"DREAMHOST_API_KEY",
+		},
+		OptionalKeys: []string{
+			"DREAMHOST_HTTP_TIMEOUT",
+			"DREAMHOST_POLLING_INTERVAL",
+			"DREAMHOST_PROPAGATION_TIMEOUT",
+			"DREAMHOST_TTL",
+		},
+	},
+	{
+		Name: "Duck DNS",
+		Code: "duckdns",
+		URL:  "https://www.duckdns.org/",
+		RequiredKeys: []string{
+			"DUCKDNS_TOKEN",
+		},
+		OptionalKeys: []string{
+			"DUCKDNS_HTTP_TIMEOUT",
+			"DUCKDNS_POLLING_INTERVAL",
+			"DUCKDNS_PROPAGATION_TIMEOUT",
+			"DUCKDNS_SEQUENCE_INTERVAL",
+			"DUCKDNS_TTL",
+		},
+	},
+	{
+		Name: "Dyn",
+		Code: "dyn",
+		URL:  "https://dyn.com/",
+		RequiredKeys: []string{
+			"DYN_CUSTOMER_NAME",
+			"DYN_PASSWORD",
+			"DYN_USER_NAME",
+		},
+		OptionalKeys: []string{
+			"DYN_HTTP_TIMEOUT",
+			"DYN_POLLING_INTERVAL",
+			"DYN_PROPAGATION_TIMEOUT",
+			"DYN_TTL",
+		},
+	},
+	{
+		Name: "Dynu",
+		Code: "dynu",
+		URL:  "https://www.dynu.com/",
+		RequiredKeys: []string{
+			"DYNU_API_KEY",
+		},
+		OptionalKeys: []string{
+			"DYNU_HTTP_TIMEOUT",
+			"DYNU_POLLING_INTERVAL",
+			"DYNU_PROPAGATION_TIMEOUT",
+			"DYNU_TTL",
+		},
+	},
+	{
+		Name: "EasyDNS",
+		Code: "easydns",
+		URL:  "https://easydns.com/",
+		RequiredKeys: []string{
+			"EASYDNS_KEY",
+			"EASYDNS_TOKEN",
+		},
+		OptionalKeys: []string{
+			"EASYDNS_ENDPOINT",
+			"EASYDNS_HTTP_TIMEOUT",
+			"EASYDNS_POLLING_INTERVAL",
+			"EASYDNS_PROPAGATION_TIMEOUT",
+			"EASYDNS_SEQUENCE_INTERVAL",
+			"EASYDNS_TTL",
+		},
+	},
+	{
+		Name:    "Akamai EdgeDNS",
+		Code:    "edgedns",
+		Aliases: []string{"fastdns"},
+		URL:     "https://www.akamai.com/us/en/products/security/edge-dns.jsp",
+		RequiredKeys: []string{
+			"AKAMAI_ACCESS_TOKEN",
+			"AKAMAI_CLIENT_SECRET",
+			"AKAMAI_CLIENT_TOKEN",
+			"AKAMAI_EDGERC",
+			"AKAMAI_EDGERC_SECTION",
+			"AKAMAI_HOST",
+		},
+		OptionalKeys: []string{
+			"AKAMAI_POLLING_INTERVAL",
+			"AKAMAI_PROPAGATION_TIMEOUT",
+			"AKAMAI_TTL",
+		},
+	},
+	{
+		Name: "Efficient IP",
+		Code: "efficientip",
+		URL:  "https://efficientip.com/",
+		RequiredKeys: []string{
+			"EFFICIENTIP_DNS_NAME",
+			"EFFICIENTIP_HOSTNAME",
+			"EFFICIENTIP_PASSWORD",
+			"EFFICIENTIP_USERNAME",
+		},
+		OptionalKeys: []string{
+			"EFFICIENTIP_HTTP_TIMEOUT",
+			"EFFICIENTIP_INSECURE_SKIP_VERIFY",
+			"EFFICIENTIP_POLLING_INTERVAL",
+			"EFFICIENTIP_PROPAGATION_TIMEOUT",
+			"EFFICIENTIP_TTL",
+			"EFFICIENTIP_VIEW_NAME",
+		},
+	},
+	{
+		Name: "Epik",
+		Code: "epik",
+		URL:  "https://www.epik.com/",
+		RequiredKeys: []string{
+			"EPIK_SIGNATURE",
+		},
+		OptionalKeys: []string{
+			"EPIK_HTTP_TIMEOUT",
+			"EPIK_POLLING_INTERVAL",
+			"EPIK_PROPAGATION_TIMEOUT",
+			"EPIK_TTL",
+		},
+	},
+	{
+		Name: "External program",
+		Code: "exec",
+		URL:  "/dns/exec",
+	},
+	{
+		Name: "Exoscale",
+		Code: "exoscale",
+		URL:  "https://www.exoscale.com/",
+		RequiredKeys: []string{
+			"EXOSCALE_API_KEY",
+			"EXOSCALE_API_SECRET",
+		},
+		OptionalKeys: []string{
+			"EXOSCALE_ENDPOINT",
+			"EXOSCALE_HTTP_TIMEOUT",
+			"EXOSCALE_POLLING_INTERVAL",
+			"EXOSCALE_PROPAGATION_TIMEOUT",
+			"EXOSCALE_TTL",
+		},
+	},
+	{
+		Name: "freemyip.com",
+		Code: "freemyip",
+		URL:  "https://freemyip.com/",
+		RequiredKeys: []string{
+			"FREEMYIP_TOKEN",
+		},
+		OptionalKeys: []string{
+			"FREEMYIP_HTTP_TIMEOUT",
+			"FREEMYIP_POLLING_INTERVAL",
+			"FREEMYIP_PROPAGATION_TIMEOUT",
+			"FREEMYIP_SEQUENCE_INTERVAL",
+			"FREEMYIP_TTL",
+		},
+	},
+	{
+		Name: "Gandi",
+		Code: "gandi",
+		URL:  "https://www.gandi.net",
+		RequiredKeys: []string{
+			"GANDI_API_KEY",
+		},
+		OptionalKeys: []string{
+			"GANDI_HTTP_TIMEOUT",
+			"GANDI_POLLING_INTERVAL",
+			"GANDI_PROPAGATION_TIMEOUT",
+			"GANDI_TTL",
+		},
+	},
+	{
+		Name: "Gandi Live DNS (v5)",
+		Code: "gandiv5",
+		URL:  "https://www.gandi.net",
+		RequiredKeys: []string{
+			"GANDIV5_API_KEY",
+			"GANDIV5_PERSONAL_ACCESS_TOKEN",
+		},
+		OptionalKeys: []string{
+			"GANDIV5_HTTP_TIMEOUT",
+			"GANDIV5_POLLING_INTERVAL",
+			"GANDIV5_PROPAGATION_TIMEOUT",
+			"GANDIV5_SNAPSHOT",
+			"GANDIV5_TTL",
+			"GANDIV5_ZONE",
+		},
+	},
+	{
+		Name: "Google Cloud",
+		Code: "gcloud",
+		URL:  "https://cloud.google.com",
+		RequiredKeys: []string{
+			"Application Default Credentials",
+			"GCE_PROJECT",
+			"GCE_SERVICE_ACCOUNT",
+			"GCE_SERVICE_ACCOUNT_FILE",
+		},
+		OptionalKeys: []string{
+			"GCE_ALLOW_PRIVATE_ZONE",
+			"GCE_POLLING_INTERVAL",
+			"GCE_PROPAGATION_TIMEOUT",
+			"GCE_TTL",
+			"GCE_ZONE_ID",
+		},
+	},
+	{
+		Name: "G-Core",
+		Code: "gcore",
+		URL:  "https://gcore.com/dns/",
+		RequiredKeys: []string{
+			"GCORE_PERMANENT_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"GCORE_HTTP_TIMEOUT",
+			"GCORE_POLLING_INTERVAL",
+			"GCORE_PROPAGATION_TIMEOUT",
+			"GCORE_TTL",
+		},
+	},
+	{
+		Name: "Glesys",
+		Code: "glesys",
+		URL:  "https://glesys.com/",
+		RequiredKeys: []string{
+			"GLESYS_API_KEY",
+			"GLESYS_API_USER",
+		},
+		OptionalKeys: []string{
+			"GLESYS_HTTP_TIMEOUT",
+			"GLESYS_POLLING_INTERVAL",
+			"GLESYS_PROPAGATION_TIMEOUT",
+			"GLESYS_TTL",
+		},
+	},
+	{
+		Name: "Go Daddy",
+		Code: "godaddy",
+		URL:  "https://godaddy.com",
+		RequiredKeys: []string{
+			"GODADDY_API_KEY",
+			"GODADDY_API_SECRET",
+		},
+		OptionalKeys: []string{
+			"GODADDY_HTTP_TIMEOUT",
+			"GODADDY_POLLING_INTERVAL",
+			"GODADDY_PROPAGATION_TIMEOUT",
+			"GODADDY_TTL",
+		},
+	},
+	{
+		Name: "Google Domains",
+		Code: "googledomains",
+		URL:  "https://domains.google",
+		RequiredKeys: []string{
+			"GOOGLE_DOMAINS_ACCESS_TOKEN",
+		},
+		OptionalKeys: []string{
+			"GOOGLE_DOMAINS_HTTP_TIMEOUT",
+			"GOOGLE_DOMAINS_POLLING_INTERVAL",
+			"GOOGLE_DOMAINS_PROPAGATION_TIMEOUT",
+		},
+	},
+	{
+		Name: "Hetzner",
+		Code: "hetzner",
+		URL:  "https://hetzner.com",
+		RequiredKeys: []string{
+			"HETZNER_API_KEY",
+		},
+		OptionalKeys: []string{
+			"HETZNER_HTTP_TIMEOUT",
+			"HETZNER_POLLING_INTERVAL",
+			"HETZNER_PROPAGATION_TIMEOUT",
+			"HETZNER_TTL",
+		},
+	},
+	{
+		Name: "Hosting.de",
+		Code: "hostingde",
+		URL:  "https://www.hosting.de/",
+		RequiredKeys: []string{
+			"HOSTINGDE_API_KEY",
+		},
+		OptionalKeys: []string{
+			"HOSTINGDE_HTTP_TIMEOUT",
+			"HOSTINGDE_POLLING_INTERVAL",
+			"HOSTINGDE_PROPAGATION_TIMEOUT",
+			"HOSTINGDE_TTL",
+			"HOSTINGDE_ZONE_NAME",
+		},
+	},
+	{
+		Name: "Hosttech",
+		Code: "hosttech",
+		URL:  "https://www.hosttech.eu/",
+		RequiredKeys: []string{
+			"HOSTTECH_API_KEY",
+			"HOSTTECH_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"HOSTTECH_HTTP_TIMEOUT",
+			"HOSTTECH_POLLING_INTERVAL",
+			"HOSTTECH_PROPAGATION_TIMEOUT",
+			"HOSTTECH_TTL",
+		},
+	},
+	{
+		Name: "http.net",
+		Code: "httpnet",
+		URL:  "https://www.http.net/",
+		RequiredKeys: []string{
+			"HTTPNET_API_KEY",
+		},
+		OptionalKeys: []string{
+			"HTTPNET_HTTP_TIMEOUT",
+			"HTTPNET_POLLING_INTERVAL",
+			"HTTPNET_PROPAGATION_TIMEOUT",
+			"HTTPNET_TTL",
+			"HTTPNET_ZONE_NAME",
+		},
+	},
+	{
+		Name: "HTTP request",
+		Code: "httpreq",
+		URL:  "/lego/dns/httpreq/",
+		RequiredKeys: []string{
+			"HTTPREQ_ENDPOINT",
+			"HTTPREQ_MODE",
+		},
+		OptionalKeys: []string{
+			"HTTPREQ_HTTP_TIMEOUT",
+			"HTTPREQ_PASSWORD",
+			"HTTPREQ_POLLING_INTERVAL",
+			"HTTPREQ_PROPAGATION_TIMEOUT",
+			"HTTPREQ_USERNAME",
+		},
+	},
+	{
+		Name: "Huawei Cloud",
+		Code: "huaweicloud",
+		URL:  "https://huaweicloud.com",
+		RequiredKeys: []string{
+			"HUAWEICLOUD_ACCESS_KEY_ID",
+			"HUAWEICLOUD_REGION",
+			"HUAWEICLOUD_SECRET_ACCESS_KEY",
+		},
+		OptionalKeys: []string{
+			"HUAWEICLOUD_HTTP_TIMEOUT",
+			"HUAWEICLOUD_POLLING_INTERVAL",
+			"HUAWEICLOUD_PROPAGATION_TIMEOUT",
+			"HUAWEICLOUD_TTL",
+		},
+	},
+	{
+		Name: "Hurricane Electric DNS",
+		Code: "hurricane",
+		URL:  "https://dns.he.net/",
+		RequiredKeys: []string{
+			"HURRICANE_TOKENS",
+		},
+		OptionalKeys: []string{
+			"HURRICANE_HTTP_TIMEOUT",
+			"HURRICANE_POLLING_INTERVAL",
+			"HURRICANE_PROPAGATION_TIMEOUT",
+			"HURRICANE_SEQUENCE_INTERVAL",
+		},
+	},
+	{
+		Name: "HyperOne",
+		Code: "hyperone",
+		URL:  "https://www.hyperone.com",
+		OptionalKeys: []string{
+			"HYPERONE_API_URL",
+			"HYPERONE_LOCATION_ID",
+			"HYPERONE_PASSPORT_LOCATION",
+			"HYPERONE_POLLING_INTERVAL",
+			"HYPERONE_PROPAGATION_TIMEOUT",
+			"HYPERONE_TTL",
+		},
+	},
+	{
+		Name: "IBM Cloud (SoftLayer)",
+		Code: "ibmcloud",
+		URL:  "https://www.ibm.com/cloud/",
+		RequiredKeys: []string{
+			"SOFTLAYER_API_KEY",
+			"SOFTLAYER_USERNAME",
+		},
+		OptionalKeys: []string{
+			"SOFTLAYER_POLLING_INTERVAL",
+			"SOFTLAYER_PROPAGATION_TIMEOUT",
+			"SOFTLAYER_TIMEOUT",
+			"SOFTLAYER_TTL",
+		},
+	},
+	{
+		Name: "Internet Initiative Japan",
+		Code: "iij",
+		URL:  "https://www.iij.ad.jp/en/",
+		RequiredKeys: []string{
+			"IIJ_API_ACCESS_KEY",
+			"IIJ_API_SECRET_KEY",
+			"IIJ_DO_SERVICE_CODE",
+		},
+		OptionalKeys: []string{
+			"IIJ_POLLING_INTERVAL",
+			"IIJ_PROPAGATION_TIMEOUT",
+			"IIJ_TTL",
+		},
+	},
+	{
+		Name: "IIJ DNS Platform Service",
+		Code: "iijdpf",
+		URL:  "https://www.iij.ad.jp/en/biz/dns-pfm/",
+		RequiredKeys: []string{
+			"IIJ_DPF_API_TOKEN",
+			"IIJ_DPF_DPM_SERVICE_CODE",
+		},
+		OptionalKeys: []string{
+			"IIJ_DPF_API_ENDPOINT",
+			"IIJ_DPF_POLLING_INTERVAL",
+			"IIJ_DPF_PROPAGATION_TIMEOUT",
+			"IIJ_DPF_TTL",
+		},
+	},
+	{
+		Name: "Infoblox",
+		Code: "infoblox",
+		URL:  "https://www.infoblox.com/",
+		RequiredKeys: []string{
+			"INFOBLOX_HOST",
+			"INFOBLOX_PASSWORD",
+			"INFOBLOX_USERNAME",
+		},
+		OptionalKeys: []string{
+			"INFOBLOX_DNS_VIEW",
+			"INFOBLOX_HTTP_TIMEOUT",
+			"INFOBLOX_POLLING_INTERVAL",
+			"INFOBLOX_PORT",
+			"INFOBLOX_PROPAGATION_TIMEOUT",
+			"INFOBLOX_SSL_VERIFY",
+			"INFOBLOX_TTL",
+			"INFOBLOX_WAPI_VERSION",
+		},
+	},
+	{
+		Name: "Infomaniak",
+		Code: "infomaniak",
+		URL:  "https://www.infomaniak.com/",
+		RequiredKeys: []string{
+			"INFOMANIAK_ACCESS_TOKEN",
+		},
+		OptionalKeys: []string{
+			"INFOMANIAK_ENDPOINT",
+			"INFOMANIAK_HTTP_TIMEOUT",
+			"INFOMANIAK_POLLING_INTERVAL",
+			"INFOMANIAK_PROPAGATION_TIMEOUT",
+			"INFOMANIAK_TTL",
+		},
+	},
+	{
+		Name: "Internet.bs",
+		Code: "internetbs",
+		URL:  "https://internetbs.net",
+		RequiredKeys: []string{
+			"INTERNET_BS_API_KEY",
+			"INTERNET_BS_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"INTERNET_BS_HTTP_TIMEOUT",
+			"INTERNET_BS_POLLING_INTERVAL",
+			"INTERNET_BS_PROPAGATION_TIMEOUT",
+			"INTERNET_BS_TTL",
+		},
+	},
+	{
+		Name: "INWX",
+		Code: "inwx",
+		URL:  "https://www.inwx.de/en",
+		RequiredKeys: []string{
+			"INWX_PASSWORD",
+			"INWX_USERNAME",
+		},
+		OptionalKeys: []string{
+			"INWX_POLLING_INTERVAL",
+			"INWX_PROPAGATION_TIMEOUT",
+			"INWX_SANDBOX",
+			"INWX_SHARED_SECRET",
+			"INWX_TTL",
+		},
+	},
+	{
+		Name: "Ionos",
+		Code: "ionos",
+		URL:  "https://ionos.com",
+		RequiredKeys: []string{
+			"IONOS_API_KEY",
+		},
+		OptionalKeys: []string{
+			"IONOS_HTTP_TIMEOUT",
+			"IONOS_POLLING_INTERVAL",
+			"IONOS_PROPAGATION_TIMEOUT",
+			"IONOS_TTL",
+		},
+	},
+	{
+		Name: "IPv64",
+		Code: "ipv64",
+		URL:  "https://ipv64.net/",
+		RequiredKeys: []string{
+			"IPV64_API_KEY",
+		},
+		OptionalKeys: []string{
+			"IPV64_HTTP_TIMEOUT",
+			"IPV64_POLLING_INTERVAL",
+			"IPV64_PROPAGATION_TIMEOUT",
+			"IPV64_TTL",
+		},
+	},
+	{
+		Name: "iwantmyname",
+		Code: "iwantmyname",
+		URL:  "https://iwantmyname.com",
+		RequiredKeys: []string{
+			"IWANTMYNAME_PASSWORD",
+			"IWANTMYNAME_USERNAME",
+		},
+		OptionalKeys: []string{
+			"IWANTMYNAME_HTTP_TIMEOUT",
+			"IWANTMYNAME_POLLING_INTERVAL",
+			"IWANTMYNAME_PROPAGATION_TIMEOUT",
+			"IWANTMYNAME_TTL",
+		},
+	},
+	{
+		Name: "Joker",
+		Code: "joker",
+		URL:  "https://joker.com",
+		RequiredKeys: []string{
+			"JOKER_API_KEY",
+			"JOKER_API_MODE",
+			"JOKER_PASSWORD",
+			"JOKER_USERNAME",
+		},
+		OptionalKeys: []string{
+			"JOKER_HTTP_TIMEOUT",
+			"JOKER_POLLING_INTERVAL",
+			"JOKER_PROPAGATION_TIMEOUT",
+			"JOKER_SEQUENCE_INTERVAL",
+			"JOKER_TTL",
+		},
+	},
+	{
+		Name: "Knot DNS",
+		Code: "knot",
+		URL:  "https://www.knot-dns.cz/",
+		OptionalKeys: []string{
+			"KNOT_COMMAND_TIMEOUT",
+			"KNOT_KNOTC_PATH",
+			"KNOT_POLLING_INTERVAL",
+			"KNOT_PROPAGATION_TIMEOUT",
+			"KNOT_SOCKET",
+			"KNOT_TTL",
+			"KNOT_ZONE",
+		},
+	},
+	{
+		Name: "Liara",
+		Code: "liara",
+		URL:  "https://liara.ir",
+		RequiredKeys: []string{
+			"LIARA_API_KEY",
+		},
+		OptionalKeys: []string{
+			"LIARA_HTTP_TIMEOUT",
+			"LIARA_POLLING_INTERVAL",
+			"LIARA_PROPAGATION_TIMEOUT",
+			"LIARA_TTL",
+		},
+	},
+	{
+		Name: "Amazon Lightsail",
+		Code: "lightsail",
+		URL:  "https://aws.amazon.com/lightsail/",
+		RequiredKeys: []string{
+			"AWS_ACCESS_KEY_ID",
+			"AWS_SECRET_ACCESS_KEY",
+			"DNS_ZONE",
+		},
+		OptionalKeys: []string{
+			"AWS_SHARED_CREDENTIALS_FILE",
+			"LIGHTSAIL_POLLING_INTERVAL",
+			"LIGHTSAIL_PROPAGATION_TIMEOUT",
+		},
+	},
+	{
+		Name: "Lima-City",
+		Code: "limacity",
+		URL:  "https://www.lima-city.de",
+		RequiredKeys: []string{
+			"LIMACITY_API_KEY",
+		},
+		OptionalKeys: []string{
+			"LIMACITY_HTTP_TIMEOUT",
+			"LIMACITY_POLLING_INTERVAL",
+			"LIMACITY_PROPAGATION_TIMEOUT",
+			"LIMACITY_SEQUENCE_INTERVAL",
+			"LIMACITY_TTL",
+		},
+	},
+	{
+		Name:    "Linode (v4)",
+		Code:    "linode",
+		Aliases: []string{"linodev4"},
+		URL:     "https://www.linode.com/",
+		RequiredKeys: []string{
+			"LINODE_TOKEN",
+		},
+		OptionalKeys: []string{
+			"LINODE_HTTP_TIMEOUT",
+			"LINODE_POLLING_INTERVAL",
+			"LINODE_PROPAGATION_TIMEOUT",
+			"LINODE_TTL",
+		},
+	},
+	{
+		Name: "Liquid Web",
+		Code: "liquidweb",
+		URL:  "https://liquidweb.com",
+		RequiredKeys: []string{
+			"LWAPI_PASSWORD",
+			"LWAPI_USERNAME",
+		},
+		OptionalKeys: []string{
+			"LWAPI_HTTP_TIMEOUT",
+			"LWAPI_POLLING_INTERVAL",
+			"LWAPI_PROPAGATION_TIMEOUT",
+			"LWAPI_TTL",
+			"LWAPI_URL",
+			"LWAPI_ZONE",
+		},
+	},
+	{
+		Name: "Loopia",
+		Code: "loopia",
+		URL:  "https://loopia.com",
+		RequiredKeys: []string{
+			"LOOPIA_API_PASSWORD",
+			"LOOPIA_API_USER",
+		},
+		OptionalKeys: []string{
+			"LOOPIA_API_URL",
+			"LOOPIA_HTTP_TIMEOUT",
+			"LOOPIA_POLLING_INTERVAL",
+			"LOOPIA_PROPAGATION_TIMEOUT",
+			"LOOPIA_TTL",
+		},
+	},
+	{
+		Name: "LuaDNS",
+		Code: "luadns",
+		URL:  "https://luadns.com",
+		RequiredKeys: []string{
+			"LUADNS_API_TOKEN",
+			"LUADNS_API_USERNAME",
+		},
+		OptionalKeys: []string{
+			"LUADNS_HTTP_TIMEOUT",
+			"LUADNS_POLLING_INTERVAL",
+			"LUADNS_PROPAGATION_TIMEOUT",
+			"LUADNS_TTL",
+		},
+	},
+	{
+		Name: "Mail-in-a-Box",
+		Code: "mailinabox",
+		URL:  "https://mailinabox.email",
+		RequiredKeys: []string{
+			"MAILINABOX_BASE_URL",
+			"MAILINABOX_EMAIL",
+			"MAILINABOX_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"MAILINABOX_POLLING_INTERVAL",
+			"MAILINABOX_PROPAGATION_TIMEOUT",
+		},
+	},
+	{
+		Name: "Metaname",
+		Code: "metaname",
+		URL:  "https://metaname.net",
+		RequiredKeys: []string{
+			"METANAME_ACCOUNT_REFERENCE",
+			"METANAME_API_KEY",
+		},
+		OptionalKeys: []string{
+			"METANAME_POLLING_INTERVAL",
+			"METANAME_PROPAGATION_TIMEOUT",
+			"METANAME_TTL",
+		},
+	},
+	{
+		Name: "mijn.host",
+		Code: "mijnhost",
+		URL:  "https://mijn.host/",
+		RequiredKeys: []string{
+			"MIJNHOST_API_KEY",
+		},
+		OptionalKeys: []string{
+			"MIJNHOST_HTTP_TIMEOUT",
+			"MIJNHOST_POLLING_INTERVAL",
+			"MIJNHOST_PROPAGATION_TIMEOUT",
+			"MIJNHOST_SEQUENCE_INTERVAL",
+			"MIJNHOST_TTL",
+		},
+	},
+	{
+		Name: "Mittwald",
+		Code: "mittwald",
+		URL:  "https://www.mittwald.de/",
+		RequiredKeys: []string{
+			"MITTWALD_TOKEN",
+		},
+		OptionalKeys: []string{
+			"MITTWALD_HTTP_TIMEOUT",
+			"MITTWALD_POLLING_INTERVAL",
+			"MITTWALD_PROPAGATION_TIMEOUT",
+			"MITTWALD_SEQUENCE_INTERVAL",
+			"MITTWALD_TTL",
+		},
+	},
+	{
+		Name: "MyDNS.jp",
+		Code: "mydnsjp",
+		URL:  "https://www.mydns.jp",
+		RequiredKeys: []string{
+			"MYDNSJP_MASTER_ID",
+			"MYDNSJP_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"MYDNSJP_HTTP_TIMEOUT",
+			"MYDNSJP_POLLING_INTERVAL",
+			"MYDNSJP_PROPAGATION_TIMEOUT",
+			"MYDNSJP_TTL",
+		},
+	},
+	{
+		Name: "MythicBeasts",
+		Code: "mythicbeasts",
+		URL:  "https://www.mythic-beasts.com/",
+		RequiredKeys: []string{
+			"MYTHICBEASTS_PASSWORD",
+			"MYTHICBEASTS_USERNAME",
+		},
+		OptionalKeys: []string{
+			"MYTHICBEASTS_API_ENDPOINT",
+			"MYTHICBEASTS_AUTH_API_ENDPOINT",
+			"MYTHICBEASTS_HTTP_TIMEOUT",
+			"MYTHICBEASTS_POLLING_INTERVAL",
+			"MYTHICBEASTS_PROPAGATION_TIMEOUT",
+			"MYTHICBEASTS_TTL",
+		},
+	},
+	{
+		Name: "Namecheap",
+		Code: "namecheap",
+		URL:  "https://www.namecheap.com",
+		RequiredKeys: []string{
+			"NAMECHEAP_API_KEY",
+			"NAMECHEAP_API_USER",
+		},
+		OptionalKeys: []string{
+			"NAMECHEAP_HTTP_TIMEOUT",
+			"NAMECHEAP_POLLING_INTERVAL",
+			"NAMECHEAP_PROPAGATION_TIMEOUT",
+			"NAMECHEAP_SANDBOX",
+			"NAMECHEAP_TTL",
+		},
+	},
+	{
+		Name: "Name.com",
+		Code: "namedotcom",
+		URL:  "https://www.name.com",
+		RequiredKeys: []string{
+			"NAMECOM_API_TOKEN",
+			"NAMECOM_USERNAME",
+		},
+		OptionalKeys: []string{
+			"NAMECOM_HTTP_TIMEOUT",
+			"NAMECOM_POLLING_INTERVAL",
+			"NAMECOM_PROPAGATION_TIMEOUT",
+			"NAMECOM_TTL",
+		},
+	},
+	{
+		Name: "Namesilo",
+		Code: "namesilo",
+		URL:  "https://www.namesilo.com/",
+		RequiredKeys: []string{
+			"NAMESILO_API_KEY",
+		},
+		OptionalKeys: []string{
+			"NAMESILO_POLLING_INTERVAL",
+			"NAMESILO_PROPAGATION_TIMEOUT",
+			"NAMESILO_TTL",
+		},
+	},
+	{
+		Name: "NearlyFreeSpeech.NET",
+		Code: "nearlyfreespeech",
+		URL:  "https://nearlyfreespeech.net/",
+		RequiredKeys: []string{
+			"NEARLYFREESPEECH_API_KEY",
+			"NEARLYFREESPEECH_LOGIN",
+		},
+		OptionalKeys: []string{
+			"NEARLYFREESPEECH_HTTP_TIMEOUT",
+			"NEARLYFREESPEECH_POLLING_INTERVAL",
+			"NEARLYFREESPEECH_PROPAGATION_TIMEOUT",
+			"NEARLYFREESPEECH_SEQUENCE_INTERVAL",
+			"NEARLYFREESPEECH_TTL",
+		},
+	},
+	{
+		Name: "Netcup",
+		Code: "netcup",
+		URL:  "https://www.netcup.eu/",
+		RequiredKeys: []string{
+			"NETCUP_API_KEY",
+			"NETCUP_API_PASSWORD",
+			"NETCUP_CUSTOMER_NUMBER",
+		},
+		OptionalKeys: []string{
+			"NETCUP_HTTP_TIMEOUT",
+			"NETCUP_POLLING_INTERVAL",
+			"NETCUP_PROPAGATION_TIMEOUT",
+			"NETCUP_TTL",
+		},
+	},
+	{
+		Name: "Netlify",
+		Code: "netlify",
+		URL:  "https://www.netlify.com",
+		RequiredKeys: []string{
+			"NETLIFY_TOKEN",
+		},
+		OptionalKeys: []string{
+			"NETLIFY_HTTP_TIMEOUT",
+			"NETLIFY_POLLING_INTERVAL",
+			"NETLIFY_PROPAGATION_TIMEOUT",
+			"NETLIFY_TTL",
+		},
+	},
+	{
+		Name: "Nicmanager",
+		Code: "nicmanager",
+		URL:  "https://www.nicmanager.com/",
+		RequiredKeys: []string{
+			"NICMANAGER_API_EMAIL",
+			"NICMANAGER_API_LOGIN",
+			"NICMANAGER_API_PASSWORD",
+			"NICMANAGER_API_USERNAME",
+		},
+		OptionalKeys: []string{
+			"NICMANAGER_API_MODE",
+			"NICMANAGER_API_OTP",
+			"NICMANAGER_HTTP_TIMEOUT",
+			"NICMANAGER_POLLING_INTERVAL",
+			"NICMANAGER_PROPAGATION_TIMEOUT",
+			"NICMANAGER_TTL",
+		},
+	},
+	{
+		Name: "NIFCloud",
+		Code: "nifcloud",
+		URL:  "https://www.nifcloud.com/",
+		RequiredKeys: []string{
+			"NIFCLOUD_ACCESS_KEY_ID",
+			"NIFCLOUD_SECRET_ACCESS_KEY",
+		},
+		OptionalKeys: []string{
+			"NIFCLOUD_HTTP_TIMEOUT",
+			"NIFCLOUD_POLLING_INTERVAL",
+			"NIFCLOUD_PROPAGATION_TIMEOUT",
+			"NIFCLOUD_TTL",
+		},
+	},
+	{
+		Name: "Njalla",
+		Code: "njalla",
+		URL:  "https://njal.la",
+		RequiredKeys: []string{
+			"NJALLA_TOKEN",
+		},
+		OptionalKeys: []string{
+			"NJALLA_HTTP_TIMEOUT",
+			"NJALLA_POLLING_INTERVAL",
+			"NJALLA_PROPAGATION_TIMEOUT",
+			"NJALLA_TTL",
+		},
+	},
+	{
+		Name: "Nodion",
+		Code: "nodion",
+		URL:  "https://www.nodion.com",
+		RequiredKeys: []string{
+			"NODION_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"NODION_HTTP_TIMEOUT",
+			"NODION_POLLING_INTERVAL",
+			"NODION_PROPAGATION_TIMEOUT",
+			"NODION_TTL",
+		},
+	},
+	{
+		Name: "NS1",
+		Code: "ns1",
+		URL:  "https://ns1.com",
+		RequiredKeys: []string{
+			"NS1_API_KEY",
+		},
+		OptionalKeys: []string{
+			"NS1_HTTP_TIMEOUT",
+			"NS1_POLLING_INTERVAL",
+			"NS1_PROPAGATION_TIMEOUT",
+			"NS1_TTL",
+		},
+	},
+	{
+		Name: "Oracle Cloud",
+		Code: "oraclecloud",
+		URL:  "https://cloud.oracle.com/home",
+		RequiredKeys: []string{
+			"OCI_COMPARTMENT_OCID",
+			"OCI_PRIVKEY_FILE",
+			"OCI_PRIVKEY_PASS",
+			"OCI_PUBKEY_FINGERPRINT",
+			"OCI_REGION",
+			"OCI_TENANCY_OCID",
+			"OCI_USER_OCID",
+		},
+		OptionalKeys: []string{
+			"OCI_POLLING_INTERVAL",
+			"OCI_PROPAGATION_TIMEOUT",
+			"OCI_TTL",
+		},
+	},
+	{
+		Name: "Open Telekom Cloud",
+		Code: "otc",
+		URL:  "https://cloud.telekom.de/en",
+		RequiredKeys: []string{
+			"OTC_DOMAIN_NAME",
+			"OTC_IDENTITY_ENDPOINT",
+			"OTC_PASSWORD",
+			"OTC_PROJECT_NAME",
+			"OTC_USER_NAME",
+		},
+		OptionalKeys: []string{
+			"OTC_HTTP_TIMEOUT",
+			"OTC_POLLING_INTERVAL",
+			"OTC_PROPAGATION_TIMEOUT",
+			"OTC_SEQUENCE_INTERVAL",
+			"OTC_TTL",
+		},
+	},
+	{
+		Name: "OVH",
+		Code: "ovh",
+		URL:  "https://www.ovh.com/",
+		RequiredKeys: []string{
+			"OVH_ACCESS_TOKEN",
+			"OVH_APPLICATION_KEY",
+			"OVH_APPLICATION_SECRET",
+			"OVH_CLIENT_ID",
+			"OVH_CLIENT_SECRET",
+			"OVH_CONSUMER_KEY",
+			"OVH_ENDPOINT",
+		},
+		OptionalKeys: []string{
+			"OVH_HTTP_TIMEOUT",
+			"OVH_POLLING_INTERVAL",
+			"OVH_PROPAGATION_TIMEOUT",
+			"OVH_TTL",
+		},
+	},
+	{
+		Name: "PowerDNS",
+		Code: "pdns",
+		URL:  "https://www.powerdns.com/",
+		RequiredKeys: []string{
+			"PDNS_API_KEY",
+			"PDNS_API_URL",
+		},
+		OptionalKeys: []string{
+			"PDNS_API_VERSION",
+			"PDNS_HTTP_TIMEOUT",
+			"PDNS_POLLING_INTERVAL",
+			"PDNS_PROPAGATION_TIMEOUT",
+			"PDNS_SERVER_NAME",
+			"PDNS_TTL",
+		},
+	},
+	{
+		Name: "plesk.com",
+		Code: "plesk",
+		URL:  "https://www.plesk.com/",
+		RequiredKeys: []string{
+			"PLESK_PASSWORD",
+			"PLESK_SERVER_BASE_URL",
+			"PLESK_USERNAME",
+		},
+		OptionalKeys: []string{
+			"PLESK_HTTP_TIMEOUT",
+			"PLESK_POLLING_INTERVAL",
+			"PLESK_PROPAGATION_TIMEOUT",
+			"PLESK_TTL",
+		},
+	},
+	{
+		Name: "Porkbun",
+		Code: "porkbun",
+		URL:  "https://porkbun.com/",
+		RequiredKeys: []string{
+			"PORKBUN_API_KEY",
+			"PORKBUN_SECRET_API_KEY",
+		},
+		OptionalKeys: []string{
+			"PORKBUN_HTTP_TIMEOUT",
+			"PORKBUN_POLLING_INTERVAL",
+			"PORKBUN_PROPAGATION_TIMEOUT",
+			"PORKBUN_TTL",
+		},
+	},
+	{
+		Name: "Rackspace",
+		Code: "rackspace",
+		URL:  "https://www.rackspace.com/",
+		RequiredKeys: []string{
+			"RACKSPACE_API_KEY",
+			"RACKSPACE_USER",
+		},
+		OptionalKeys: []string{
+			"RACKSPACE_HTTP_TIMEOUT",
+			"RACKSPACE_POLLING_INTERVAL",
+			"RACKSPACE_PROPAGATION_TIMEOUT",
+			"RACKSPACE_TTL",
+		},
+	},
+	{
+		Name: "Rain Yun/雨云",
+		Code: "rainyun",
+		URL:  "https://www.rainyun.com",
+		RequiredKeys: []string{
+			"RAINYUN_API_KEY",
+		},
+		OptionalKeys: []string{
+			"RAINYUN_HTTP_TIMEOUT",
+			"RAINYUN_POLLING_INTERVAL",
+			"RAINYUN_PROPAGATION_TIMEOUT",
+			"RAINYUN_TTL",
+		},
+	},
+	{
+		Name: "RcodeZero",
+		Code: "rcodezero",
+		URL:  "https://www.rcodezero.at/",
+		RequiredKeys: []string{
+			"RCODEZERO_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"RCODEZERO_HTTP_TIMEOUT",
+			"RCODEZERO_POLLING_INTERVAL",
+			"RCODEZERO_PROPAGATION_TIMEOUT",
+			"RCODEZERO_TTL",
+		},
+	},
+	{
+		Name: "Regfish",
+		Code: "regfish",
+		URL:  "https://regfish.de/",
+		RequiredKeys: []string{
+			"REGFISH_API_KEY",
+		},
+		OptionalKeys: []string{
+			"REGFISH_HTTP_TIMEOUT",
+			"REGFISH_POLLING_INTERVAL",
+			"REGFISH_PROPAGATION_TIMEOUT",
+			"REGFISH_TTL",
+		},
+	},
+	{
+		Name: "reg.ru",
+		Code: "regru",
+		URL:  "https://www.reg.ru/",
+		RequiredKeys: []string{
+			"REGRU_PASSWORD",
+			"REGRU_USERNAME",
+		},
+		OptionalKeys: []string{
+			"REGRU_HTTP_TIMEOUT",
+			"REGRU_POLLING_INTERVAL",
+			"REGRU_PROPAGATION_TIMEOUT",
+			"REGRU_TLS_CERT",
+			"REGRU_TLS_KEY",
+			"REGRU_TTL",
+		},
+	},
+	{
+		Name: "RFC2136",
+		Code: "rfc2136",
+		URL:  "https://www.rfc-editor.org/rfc/rfc2136.html",
+		RequiredKeys: []string{
+			"RFC2136_NAMESERVER",
+			"RFC2136_TSIG_ALGORITHM",
+			"RFC2136_TSIG_KEY",
+			"RFC2136_TSIG_SECRET",
+		},
+		OptionalKeys: []string{
+			"RFC2136_DNS_TIMEOUT",
+			"RFC2136_GSS_CCACHE",
+			"RFC2136_GSS_KEYTAB",
+			"RFC2136_GSS_REALM",
+			"RFC2136_GSS_TSIG",
+			"RFC2136_NSUPDATE_PATH",
+			"RFC2136_POLLING_INTERVAL",
+			"RFC2136_PROPAGATION_TIMEOUT",
+			"RFC2136_SEQUENCE_INTERVAL",
+			"RFC2136_TSIG_FILE",
+			"RFC2136_TTL",
+		},
+	},
+	{
+		Name: "RimuHosting",
+		Code: "rimuhosting",
+		URL:  "https://rimuhosting.com",
+		RequiredKeys: []string{
+			"RIMUHOSTING_API_KEY",
+		},
+		OptionalKeys: []string{
+			"RIMUHOSTING_HTTP_TIMEOUT",
+			"RIMUHOSTING_POLLING_INTERVAL",
+			"RIMUHOSTING_PROPAGATION_TIMEOUT",
+			"RIMUHOSTING_TTL",
+		},
+	},
+	{
+		Name: "Amazon Route 53",
+		Code: "route53",
+		URL:  "https://aws.amazon.com/route53/",
+		RequiredKeys: []string{
+			"AWS_ACCESS_KEY_ID",
+			"AWS_ASSUME_ROLE_ARN",
+			"AWS_EXTERNAL_ID",
+			"AWS_HOSTED_ZONE_ID",
+			"AWS_PROFILE",
+			"AWS_REGION",
+			"AWS_SDK_LOAD_CONFIG",
+			"AWS_SECRET_ACCESS_KEY",
+			"AWS_WAIT_FOR_RECORD_SETS_CHANGED",
+		},
+		OptionalKeys: []string{
+			"AWS_MAX_RETRIES",
+			"AWS_POLLING_INTERVAL",
+			"AWS_PROPAGATION_TIMEOUT",
+			"AWS_SHARED_CREDENTIALS_FILE",
+			"AWS_TTL",
+		},
+	},
+	{
+		Name: "UKFast SafeDNS",
+		Code: "safedns",
+		URL:  "https://www.ukfast.co.uk/dns-hosting.html",
+		RequiredKeys: []string{
+			"SAFEDNS_AUTH_TOKEN",
+		},
+		OptionalKeys: []string{
+			"SAFEDNS_HTTP_TIMEOUT",
+			"SAFEDNS_POLLING_INTERVAL",
+			"SAFEDNS_PROPAGATION_TIMEOUT",
+			"SAFEDNS_TTL",
+		},
+	},
+	{
+		Name: "Sakura Cloud",
+		Code: "sakuracloud",
+		URL:  "https://cloud.sakura.ad.jp/",
+		RequiredKeys: []string{
+			"SAKURACLOUD_ACCESS_TOKEN",
+			"SAKURACLOUD_ACCESS_TOKEN_SECRET",
+		},
+		OptionalKeys: []string{
+			"SAKURACLOUD_HTTP_TIMEOUT",
+			"SAKURACLOUD_POLLING_INTERVAL",
+			"SAKURACLOUD_PROPAGATION_TIMEOUT",
+			"SAKURACLOUD_TTL",
+		},
+	},
+	{
+		Name: "Scaleway",
+		Code: "scaleway",
+		URL:  "https://developers.scaleway.com/",
+		RequiredKeys: []string{
+			"SCW_PROJECT_ID",
+			"SCW_SECRET_KEY",
+		},
+		OptionalKeys: []string{
+			"SCW_ACCESS_KEY",
+			"SCW_POLLING_INTERVAL",
+			"SCW_PROPAGATION_TIMEOUT",
+			"SCW_TTL",
+		},
+	},
+	{
+		Name: "Selectel",
+		Code: "selectel",
+		URL:  "https://kb.selectel.com/",
+		RequiredKeys: []string{
+			"SELECTEL_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"SELECTEL_BASE_URL",
+			"SELECTEL_HTTP_TIMEOUT",
+			"SELECTEL_POLLING_INTERVAL",
+			"SELECTEL_PROPAGATION_TIMEOUT",
+			"SELECTEL_TTL",
+		},
+	},
+	{
+		Name: "Selectel v2",
+		Code: "selectelv2",
+		URL:  "https://selectel.ru",
+		RequiredKeys: []string{
+			"SELECTELV2_ACCOUNT_ID",
+			"SELECTELV2_PASSWORD",
+			"SELECTELV2_PROJECT_ID",
+			"SELECTELV2_USERNAME",
+		},
+		OptionalKeys: []string{
+			"SELECTELV2_BASE_URL",
+			"SELECTELV2_HTTP_TIMEOUT",
+			"SELECTELV2_POLLING_INTERVAL",
+			"SELECTELV2_PROPAGATION_TIMEOUT",
+			"SELECTELV2_TTL",
+		},
+	},
+	{
+		Name: "SelfHost.(de|eu)",
+		Code: "selfhostde",
+		URL:  "https://www.selfhost.de",
+		RequiredKeys: []string{
+			"SELFHOSTDE_PASSWORD",
+			"SELFHOSTDE_RECORDS_MAPPING",
+			"SELFHOSTDE_USERNAME",
+		},
+		OptionalKeys: []string{
+			"SELFHOSTDE_HTTP_TIMEOUT",
+			"SELFHOSTDE_POLLING_INTERVAL",
+			"SELFHOSTDE_PROPAGATION_TIMEOUT",
+			"SELFHOSTDE_TTL",
+		},
+	},
+	{
+		Name: "Servercow",
+		Code: "servercow",
+		URL:  "https://servercow.de/",
+		RequiredKeys: []string{
+			"SERVERCOW_PASSWORD",
+			"SERVERCOW_USERNAME",
+		},
+		OptionalKeys: []string{
+			"SERVERCOW_HTTP_TIMEOUT",
+			"SERVERCOW_POLLING_INTERVAL",
+			"SERVERCOW_PROPAGATION_TIMEOUT",
+			"SERVERCOW_TTL",
+		},
+	},
+	{
+		Name: "Shellrent",
+		Code: "shellrent",
+		URL:  "https://www.shellrent.com/",
+		RequiredKeys: []string{
+			"SHELLRENT_TOKEN",
+			"SHELLRENT_USERNAME",
+		},
+		OptionalKeys: []string{
+			"SHELLRENT_HTTP_TIMEOUT",
+			"SHELLRENT_POLLING_INTERVAL",
+			"SHELLRENT_PROPAGATION_TIMEOUT",
+			"SHELLRENT_TTL",
+		},
+	},
+	{
+		Name: "Simply.com",
+		Code: "simply",
+		URL:  "https://www.simply.com/en/domains/",
+		RequiredKeys: []string{
+			"SIMPLY_ACCOUNT_NAME",
+			"SIMPLY_API_KEY",
+		},
+		OptionalKeys: []string{
+			"SIMPLY_HTTP_TIMEOUT",
+			"SIMPLY_POLLING_INTERVAL",
+			"SIMPLY_PROPAGATION_TIMEOUT",
+			"SIMPLY_TTL",
+		},
+	},
+	{
+		Name: "Sonic",
+		Code: "sonic",
+		URL:  "https://www.sonic.com/",
+		RequiredKeys: []string{
+			"SONIC_API_KEY",
+			"SONIC_USER_ID",
+		},
+		OptionalKeys: []string{
+			"SONIC_HTTP_TIMEOUT",
+			"SONIC_POLLING_INTERVAL",
+			"SONIC_PROPAGATION_TIMEOUT",
+			"SONIC_SEQUENCE_INTERVAL",
+			"SONIC_TTL",
+		},
+	},
+	{
+		Name: "Stackpath",
+		Code: "stackpath",
+		URL:  "https://www.stackpath.com/",
+		RequiredKeys: []string{
+			"STACKPATH_CLIENT_ID",
+			"STACKPATH_CLIENT_SECRET",
+			"STACKPATH_STACK_ID",
+		},
+		OptionalKeys: []string{
+			"STACKPATH_POLLING_INTERVAL",
+			"STACKPATH_PROPAGATION_TIMEOUT",
+			"STACKPATH_TTL",
+		},
+	},
+	{
+		Name: "Technitium",
+		Code: "technitium",
+		URL:  "https://technitium.com/",
+		RequiredKeys: []string{
+			"TECHNITIUM_API_TOKEN",
+			"TECHNITIUM_SERVER_BASE_URL",
+		},
+		OptionalKeys: []string{
+			"TECHNITIUM_HTTP_TIMEOUT",
+			"TECHNITIUM_POLLING_INTERVAL",
+			"TECHNITIUM_PROPAGATION_TIMEOUT",
+			"TECHNITIUM_TTL",
+		},
+	},
+	{
+		Name: "Tencent Cloud DNS",
+		Code: "tencentcloud",
+		URL:  "https://cloud.tencent.com/product/cns",
+		RequiredKeys: []string{
+			"TENCENTCLOUD_SECRET_ID",
+			"TENCENTCLOUD_SECRET_KEY",
+		},
+		OptionalKeys: []string{
+			"TENCENTCLOUD_HTTP_TIMEOUT",
+			"TENCENTCLOUD_POLLING_INTERVAL",
+			"TENCENTCLOUD_PROPAGATION_TIMEOUT",
+			"TENCENTCLOUD_REGION",
+			"TENCENTCLOUD_SESSION_TOKEN",
+			"TENCENTCLOUD_TTL",
+		},
+	},
+	{
+		Name: "Timeweb Cloud",
+		Code: "timewebcloud",
+		URL:  "https://timeweb.cloud/",
+		RequiredKeys: []string{
+			"TIMEWEBCLOUD_AUTH_TOKEN",
+		},
+		OptionalKeys: []string{
+			"TIMEWEBCLOUD_HTTP_TIMEOUT",
+			"TIMEWEBCLOUD_POLLING_INTERVAL",
+			"TIMEWEBCLOUD_PROPAGATION_TIMEOUT",
+		},
+	},
+	{
+		Name: "TransIP",
+		Code: "transip",
+		URL:  "https://www.transip.nl/",
+		RequiredKeys: []string{
+			"TRANSIP_ACCOUNT_NAME",
+			"TRANSIP_PRIVATE_KEY_PATH",
+		},
+		OptionalKeys: []string{
+			"TRANSIP_POLLING_INTERVAL",
+			"TRANSIP_PROPAGATION_TIMEOUT",
+			"TRANSIP_TTL",
+		},
+	},
+	{
+		Name: "Ultradns",
+		Code: "ultradns",
+		URL:  "https://vercara.com/authoritative-dns",
+		RequiredKeys: []string{
+			"ULTRADNS_PASSWORD",
+			"ULTRADNS_USERNAME",
+		},
+		OptionalKeys: []string{
+			"ULTRADNS_ENDPOINT",
+			"ULTRADNS_POLLING_INTERVAL",
+			"ULTRADNS_PROPAGATION_TIMEOUT",
+			"ULTRADNS_TTL",
+		},
+	},
+	{
+		Name: "Variomedia",
+		Code: "variomedia",
+		URL:  "https://www.variomedia.de/",
+		RequiredKeys: []string{
+			"VARIOMEDIA_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"VARIOMEDIA_HTTP_TIMEOUT",
+			"VARIOMEDIA_POLLING_INTERVAL",
+			"VARIOMEDIA_PROPAGATION_TIMEOUT",
+			"VARIOMEDIA_SEQUENCE_INTERVAL",
+			"VARIOMEDIA_TTL",
+		},
+	},
+	{
+		Name: "VegaDNS",
+		Code: "vegadns",
+		URL:  "https://github.com/shupp/VegaDNS-API",
+		RequiredKeys: []string{
+			"SECRET_VEGADNS_KEY",
+			"SECRET_VEGADNS_SECRET",
+			"VEGADNS_URL",
+		},
+		OptionalKeys: []string{
+			"VEGADNS_POLLING_INTERVAL",
+			"VEGADNS_PROPAGATION_TIMEOUT",
+			"VEGADNS_TTL",
+		},
+	},
+	{
+		Name: "Vercel",
+		Code: "vercel",
+		URL:  "https://vercel.com",
+		RequiredKeys: []string{
+			"VERCEL_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"VERCEL_HTTP_TIMEOUT",
+			"VERCEL_POLLING_INTERVAL",
+			"VERCEL_PROPAGATION_TIMEOUT",
+			"VERCEL_TEAM_ID",
+			"VERCEL_TTL",
+		},
+	},
+	{
+		Name: "Versio.[nl|eu|uk]",
+		Code: "versio",
+		URL:  "https://www.versio.nl/domeinnamen",
+		RequiredKeys: []string{
+			"VERSIO_PASSWORD",
+			"VERSIO_USERNAME",
+		},
+		OptionalKeys: []string{
+			"VERSIO_ENDPOINT",
+			"VERSIO_HTTP_TIMEOUT",
+			"VERSIO_POLLING_INTERVAL",
+			"VERSIO_PROPAGATION_TIMEOUT",
+			"VERSIO_SEQUENCE_INTERVAL",
+			"VERSIO_TTL",
+		},
+	},
+	{
+		Name: "VinylDNS",
+		Code: "vinyldns",
+		URL:  "https://www.vinyldns.io",
+		RequiredKeys: []string{
+			"VINYLDNS_ACCESS_KEY",
+			"VINYLDNS_HOST",
+			"VINYLDNS_SECRET_KEY",
+		},
+		OptionalKeys: []string{
+			"VINYLDNS_POLLING_INTERVAL",
+			"VINYLDNS_PROPAGATION_TIMEOUT",
+			"VINYLDNS_TTL",
+		},
+	},
+	{
+		Name: "VK Cloud",
+		Code: "vkcloud",
+		URL:  "https://mcs.mail.ru/",
+		RequiredKeys: []string{
+			"VK_CLOUD_PASSWORD",
+			"VK_CLOUD_PROJECT_ID",
+			"VK_CLOUD_USERNAME",
+		},
+		OptionalKeys: []string{
+			"VK_CLOUD_DNS_ENDPOINT",
+			"VK_CLOUD_DOMAIN_NAME",
+			"VK_CLOUD_IDENTITY_ENDPOINT",
+			"VK_CLOUD_POLLING_INTERVAL",
+			"VK_CLOUD_PROPAGATION_TIMEOUT",
+			"VK_CLOUD_TTL",
+		},
+	},
+	{
+		Name: "Volcano Engine/火山引擎",
+		Code: "volcengine",
+		URL:  "https://www.volcengine.com/",
+		RequiredKeys: []string{
+			"VOLC_ACCESSKEY",
+			"VOLC_SECRETKEY",
+		},
+		OptionalKeys: []string{
+			"VOLC_HOST",
+			"VOLC_HTTP_TIMEOUT",
+			"VOLC_POLLING_INTERVAL",
+			"VOLC_PROPAGATION_TIMEOUT",
+			"VOLC_REGION",
+			"VOLC_SCHEME",
+			"VOLC_TTL",
+		},
+	},
+	{
+		Name: "Vscale",
+		Code: "vscale",
+		URL:  "https://vscale.io/",
+		RequiredKeys: []string{
+			"VSCALE_API_TOKEN",
+		},
+		OptionalKeys: []string{
+			"VSCALE_BASE_URL",
+			"VSCALE_HTTP_TIMEOUT",
+			"VSCALE_POLLING_INTERVAL",
+			"VSCALE_PROPAGATION_TIMEOUT",
+			"VSCALE_TTL",
+		},
+	},
+	{
+		Name: "Vultr",
+		Code: "vultr",
+		URL:  "https://www.vultr.com/",
+		RequiredKeys: []string{
+			"VULTR_API_KEY",
+		},
+		OptionalKeys: []string{
+			"VULTR_HTTP_TIMEOUT",
+			"VULTR_POLLING_INTERVAL",
+			"VULTR_PROPAGATION_TIMEOUT",
+			"VULTR_TTL",
+			"VULTR_ZONE",
+		},
+	},
+	{
+		Name: "Webnames",
+		Code: "webnames",
+		URL:  "https://www.webnames.ru/",
+		RequiredKeys: []string{
+			"WEBNAMES_API_KEY",
+		},
+		OptionalKeys: []string{
+			"WEBNAMES_HTTP_TIMEOUT",
+			"WEBNAMES_POLLING_INTERVAL",
+			"WEBNAMES_PROPAGATION_TIMEOUT",
+			"WEBNAMES_TTL",
+		},
+	},
+	{
+		Name: "Websupport",
+		Code: "websupport",
+		URL:  "https://websupport.sk",
+		RequiredKeys: []string{
+			"WEBSUPPORT_API_KEY",
+			"WEBSUPPORT_SECRET",
+		},
+		OptionalKeys: []string{
+			"WEBSUPPORT_HTTP_TIMEOUT",
+			"WEBSUPPORT_POLLING_INTERVAL",
+			"WEBSUPPORT_PROPAGATION_TIMEOUT",
+			"WEBSUPPORT_SEQUENCE_INTERVAL",
+			"WEBSUPPORT_TTL",
+		},
+	},
+	{
+		Name: "WEDOS",
+		Code: "wedos",
+		URL:  "https://www.wedos.com",
+		RequiredKeys: []string{
+			"WEDOS_USERNAME",
+			"WEDOS_WAPI_PASSWORD",
+		},
+		OptionalKeys: []string{
+			"WEDOS_HTTP_TIMEOUT",
+			"WEDOS_POLLING_INTERVAL",
+			"WEDOS_PROPAGATION_TIMEOUT",
+			"WEDOS_TTL",
+		},
+	},
+	{
+		Name: "West.cn/西部数码",
+		Code: "westcn",
+		URL:  "https://www.west.cn",
+		RequiredKeys: []string{
+			"WESTCN_PASSWORD",
+			"WESTCN_USERNAME",
+		},
+		OptionalKeys: []string{
+			"WESTCN_HTTP_TIMEOUT",
+			"WESTCN_POLLING_INTERVAL",
+			"WESTCN_PROPAGATION_TIMEOUT",
+			"WESTCN_TTL",
+		},
+	},
+	{
+		Name: "Yandex PDD",
+		Code: "yandex",
+		URL:  "https://pdd.yandex.com",
+		RequiredKeys: []string{
+			"YANDEX_PDD_TOKEN",
+		},
+		OptionalKeys: []string{
+			"YANDEX_HTTP_TIMEOUT",
+			"YANDEX_POLLING_INTERVAL",
+			"YANDEX_PROPAGATION_TIMEOUT",
+			"YANDEX_TTL",
+		},
+	},
+	{
+		Name: "Yandex 360",
+		Code: "yandex360",
+		URL:  "https://360.yandex.ru",
+		RequiredKeys: []string{
+			"YANDEX360_OAUTH_TOKEN",
+			"YANDEX360_ORG_ID",
+		},
+		OptionalKeys: []string{
+			"YANDEX360_HTTP_TIMEOUT",
+			"YANDEX360_POLLING_INTERVAL",
+			"YANDEX360_PROPAGATION_TIMEOUT",
+			"YANDEX360_TTL",
+		},
+	},
+	{
+		Name: "Yandex Cloud",
+		Code: "yandexcloud",
+		URL:  "https://cloud.yandex.com",
+		RequiredKeys: []string{
+			"YANDEX_CLOUD_FOLDER_ID",
+			"YANDEX_CLOUD_IAM_TOKEN",
+		},
+		OptionalKeys: []string{
+			"YANDEX_CLOUD_POLLING_INTERVAL",
+			"YANDEX_CLOUD_PROPAGATION_TIMEOUT",
+			"YANDEX_CLOUD_TTL",
+		},
+	},
+	{
+		Name: "Zone.ee",
+		Code: "zoneee",
+		URL:  "https://www.zone.ee/",
+		RequiredKeys: []string{
+			"ZONEEE_API_KEY",
+			"ZONEEE_API_USER",
+		},
+		OptionalKeys: []string{
+			"ZONEEE_ENDPOINT",
+			"ZONEEE_HTTP_TIMEOUT",
+			"ZONEEE_POLLING_INTERVAL",
+			"ZONEEE_PROPAGATION_TIMEOUT",
+			"ZONEEE_TTL",
+		},
+	},
+	{
+		Name: "Zonomi",
+		Code: "zonomi",
+		URL:  "https://zonomi.com",
+		RequiredKeys: []string{
+			"ZONOMI_API_KEY",
+		},
+		OptionalKeys: []string{
+			"ZONOMI_HTTP_TIMEOUT",
+			"ZONOMI_POLLING_INTERVAL",
+			"ZONOMI_PROPAGATION_TIMEOUT",
+			"ZONOMI_TTL",
+		},
+	},
+}
+
+// getDefaultPropagationTimeout returns the default propagation timeout of a DNS provider.
+func getDefaultPropagationTimeout(code string) time.Duration {
+	switch code {
+	case "alidns":
+		return alidns.NewDefaultConfig().PropagationTimeout
+	case "allinkl":
+		return allinkl.NewDefaultConfig().PropagationTimeout
+	case "arvancloud":
+		return arvancloud.NewDefaultConfig().PropagationTimeout
+	case "auroradns":
+		return auroradns.NewDefaultConfig().PropagationTimeout
+	case "autodns":
+		return autodns.NewDefaultConfig().PropagationTimeout
+	case "azure":
+		return azure.NewDefaultConfig().PropagationTimeout
+	case "azuredns":
+		return azuredns.NewDefaultConfig().PropagationTimeout
+	case "bindman":
+		return bindman.NewDefaultConfig().PropagationTimeout
+	case "bluecat":
+		return bluecat.NewDefaultConfig().PropagationTimeout
+	case "brandit":
+		return brandit.NewDefaultConfig().PropagationTimeout
+	case "bunny":
+		return bunny.NewDefaultConfig().PropagationTimeout
+	case "checkdomain":
+		return checkdomain.NewDefaultConfig().PropagationTimeout
+	case "civo":
+		return civo.NewDefaultConfig().PropagationTimeout
+	case "clouddns":
+		return clouddns.NewDefaultConfig().PropagationTimeout
+	case "cloudflare":
+		return cloudflare.NewDefaultConfig().PropagationTimeout
+	case "cloudns":
+		return cloudns.NewDefaultConfig().PropagationTimeout
+	case "cloudru":
+		return cloudru.NewDefaultConfig().PropagationTimeout
+	case "cloudxns":
+		return cloudxns.NewDefaultConfig().PropagationTimeout
+	case "conoha":
+		return conoha.NewDefaultConfig().PropagationTimeout
+	case "constellix":
+		return constellix.NewDefaultConfig().PropagationTimeout
+	case "corenetworks":
+		return corenetworks.NewDefaultConfig().PropagationTimeout
+	case "cpanel":
+		return cpanel.NewDefaultConfig().PropagationTimeout
+	case "derak":
+		return derak.NewDefaultConfig().PropagationTimeout
+	case "desec":
+		return desec.NewDefaultConfig().PropagationTimeout
+	case "designate":
+		return designate.NewDefaultConfig().PropagationTimeout
+	case "digitalocean":
+		return digitalocean.NewDefaultConfig().PropagationTimeout
+	case "directadmin":
+		return directadmin.NewDefaultConfig().PropagationTimeout
+	case "dnshomede":
+		return dnshomede.NewDefaultConfig().PropagationTimeout
+	case "dnsimple":
+		return dnsimple.NewDefaultConfig().PropagationTimeout
+	case "dnsmadeeasy":
+		return dnsmadeeasy.NewDefaultConfig().PropagationTimeout
+	case "dnspod":
+		return dnspod.NewDefaultConfig().PropagationTimeout
+	case "dode":
+		return dode.NewDefaultConfig().PropagationTimeout
+	case "domeneshop":
+		return domeneshop.NewDefaultConfig().PropagationTimeout
+	case "dreamhost":
+		return dreamhost.NewDefaultConfig().PropagationTimeout
+	case "duckdns":
+		return duckdns.NewDefaultConfig().PropagationTimeout
+	case "dyn":
+		return dyn.NewDefaultConfig().PropagationTimeout
+	case "dynu":
+		return dynu.NewDefaultConfig().PropagationTimeout
+	case "easydns":
+		return easydns.NewDefaultConfig().PropagationTimeout
+	case "edgedns":
+		return edgedns.NewDefaultConfig().PropagationTimeout
+	case "efficientip":
+		return efficientip.NewDefaultConfig().PropagationTimeout
+	case "epik":
+		return epik.NewDefaultConfig().PropagationTimeout
+	case "exec":
+		return exec.NewDefaultConfig().PropagationTimeout
+	case "exoscale":
+		return exoscale.NewDefaultConfig().PropagationTimeout
+	case "freemyip":
+		return freemyip.NewDefaultConfig().PropagationTimeout
+	case "gandi":
+		return gandi.NewDefaultConfig().PropagationTimeout
+	case "gandiv5":
+		return gandiv5.NewDefaultConfig().PropagationTimeout
+	case "gcloud":
+		return gcloud.NewDefaultConfig().PropagationTimeout
+	case "gcore":
+		return gcore.NewDefaultConfig().PropagationTimeout
+	case "glesys":
+		return glesys.NewDefaultConfig().PropagationTimeout
+	case "godaddy":
+		return godaddy.NewDefaultConfig().PropagationTimeout
+	case "googledomains":
+		return googledomains.NewDefaultConfig().PropagationTimeout
+	case "hetzner":
+		return hetzner.NewDefaultConfig().PropagationTimeout
+	case "hostingde":
+		return hostingde.NewDefaultConfig().PropagationTimeout
+	case "hosttech":
+		return hosttech.NewDefaultConfig().PropagationTimeout
+	case "httpnet":
+		return httpnet.NewDefaultConfig().PropagationTimeout
+	case "httpreq":
+		return httpreq.NewDefaultConfig().PropagationTimeout
+	case "huaweicloud":
+		return huaweicloud.NewDefaultConfig().PropagationTimeout
+	case "hurricane":
+		return hurricane.NewDefaultConfig().PropagationTimeout
+	case "hyperone":
+		return hyperone.NewDefaultConfig().PropagationTimeout
+	case "ibmcloud":
+		return ibmcloud.NewDefaultConfig().PropagationTimeout
+	case "iij":
+		return iij.NewDefaultConfig().PropagationTimeout
+	case "iijdpf":
+		return iijdpf.NewDefaultConfig().PropagationTimeout
+	case "infoblox":
+		return infoblox.NewDefaultConfig().PropagationTimeout
+	case "infomaniak":
+		return infomaniak.NewDefaultConfig().PropagationTimeout
+	case "internetbs":
+		return internetbs.NewDefaultConfig().PropagationTimeout
+	case "inwx":
+		return inwx.NewDefaultConfig().PropagationTimeout
+	case "ionos":
+		return ionos.NewDefaultConfig().PropagationTimeout
+	case "ipv64":
+		return ipv64.NewDefaultConfig().PropagationTimeout
+	case "iwantmyname":
+		return iwantmyname.NewDefaultConfig().PropagationTimeout
+	case "joker":
+		return joker.NewDefaultConfig().PropagationTimeout
+	case "knot":
+		return knot.NewDefaultConfig().PropagationTimeout
+	case "liara":
+		return liara.NewDefaultConfig().PropagationTimeout
+	case "lightsail":
+		return lightsail.NewDefaultConfig().PropagationTimeout
+	case "limacity":
+		return limacity.NewDefaultConfig().PropagationTimeout
+	case "linode":
+		return linode.NewDefaultConfig().PropagationTimeout
+	case "liquidweb":
+		return liquidweb.NewDefaultConfig().PropagationTimeout
+	case "loopia":
+		return loopia.NewDefaultConfig().PropagationTimeout
+	case "luadns":
+		return luadns.NewDefaultConfig().PropagationTimeout
+	case "mailinabox":
+		return mailinabox.NewDefaultConfig().PropagationTimeout
+	case "metaname":
+		return metaname.NewDefaultConfig().PropagationTimeout
+	case "mijnhost":
+		return mijnhost.NewDefaultConfig().PropagationTimeout
+	case "mittwald":
+		return mittwald.NewDefaultConfig().PropagationTimeout
+	case "mydnsjp":
+		return mydnsjp.NewDefaultConfig().PropagationTimeout
+	case "mythicbeasts":
+		config, err := mythicbeasts.NewDefaultConfig()
+		if err != nil {
+			return dns01.DefaultPropagationTimeout
+		}
+
+		return config.PropagationTimeout
+	case "namecheap":
+		return namecheap.NewDefaultConfig().PropagationTimeout
+	case "namedotcom":
+		return namedotcom.NewDefaultConfig().PropagationTimeout
+	case "namesilo":
+		return namesilo.NewDefaultConfig().PropagationTimeout
+	case "nearlyfreespeech":
+		return nearlyfreespeech.NewDefaultConfig().PropagationTimeout
+	case "netcup":
+		return netcup.NewDefaultConfig().PropagationTimeout
+	case "netlify":
+		return netlify.NewDefaultConfig().PropagationTimeout
+	case "nicmanager":
+		return nicmanager.NewDefaultConfig().PropagationTimeout
+	case "nifcloud":
+		return nifcloud.NewDefaultConfig().PropagationTimeout
+	case "njalla":
+		return njalla.NewDefaultConfig().PropagationTimeout
+	case "nodion":
+		return nodion.NewDefaultConfig().PropagationTimeout
+	case "ns1":
+		return ns1.NewDefaultConfig().PropagationTimeout
+	case "oraclecloud":
+		return oraclecloud.NewDefaultConfig().PropagationTimeout
+	case "otc":
+		return otc.NewDefaultConfig().PropagationTimeout
+	case "ovh":
+		return ovh.NewDefaultConfig().PropagationTimeout
+	case "pdns":
+		return pdns.NewDefaultConfig().PropagationTimeout
+	case "plesk":
+		return plesk.NewDefaultConfig().PropagationTimeout
+	case "porkbun":
+		return porkbun.NewDefaultConfig().PropagationTimeout
+	case "rackspace":
+		return rackspace.NewDefaultConfig().PropagationTimeout
+	case "rainyun":
+		return rainyun.NewDefaultConfig().PropagationTimeout
+	case "rcodezero":
+		return rcodezero.NewDefaultConfig().PropagationTimeout
+	case "regfish":
+		return regfish.NewDefaultConfig().PropagationTimeout
+	case "regru":
+		return regru.NewDefaultConfig().PropagationTimeout
+	case "rfc2136":
+		return rfc2136.NewDefaultConfig().PropagationTimeout
+	case "rimuhosting":
+		return rimuhosting.NewDefaultConfig().PropagationTimeout
+	case "route53":
+		return route53.NewDefaultConfig().PropagationTimeout
+	case "safedns":
+		return safedns.NewDefaultConfig().PropagationTimeout
+	case "sakuracloud":
+		return sakuracloud.NewDefaultConfig().PropagationTimeout
+	case "scaleway":
+		return scaleway.NewDefaultConfig().PropagationTimeout
+	case "selectel":
+		return selectel.NewDefaultConfig().PropagationTimeout
+	case "selectelv2":
+		return selectelv2.NewDefaultConfig().PropagationTimeout
+	case "selfhostde":
+		return selfhostde.NewDefaultConfig().PropagationTimeout
+	case "servercow":
+		return servercow.NewDefaultConfig().PropagationTimeout
+	case "shellrent":
+		return shellrent.NewDefaultConfig().PropagationTimeout
+	case "simply":
+		return simply.NewDefaultConfig().PropagationTimeout
+	case "sonic":
+		return sonic.NewDefaultConfig().PropagationTimeout
+	case "stackpath":
+		return stackpath.NewDefaultConfig().PropagationTimeout
+	case "technitium":
+		return technitium.NewDefaultConfig().PropagationTimeout
+	case "tencentcloud":
+		return tencentcloud.NewDefaultConfig().PropagationTimeout
+	case "timewebcloud":
+		return timewebcloud.NewDefaultConfig().PropagationTimeout
+	case "transip":
+		return transip.NewDefaultConfig().PropagationTimeout
+	case "ultradns":
+		return ultradns.NewDefaultConfig().PropagationTimeout
+	case "variomedia":
+		return variomedia.NewDefaultConfig().PropagationTimeout
+	case "vegadns":
+		return vegadns.NewDefaultConfig().PropagationTimeout
+	case "vercel":
+		return vercel.NewDefaultConfig().PropagationTimeout
+	case "versio":
+		return versio.NewDefaultConfig().PropagationTimeout
+	case "vinyldns":
+		return vinyldns.NewDefaultConfig().PropagationTimeout
+	case "vkcloud":
+		return vkcloud.NewDefaultConfig().PropagationTimeout
+	case "volcengine":
+		return volcengine.NewDefaultConfig().PropagationTimeout
+	case "vscale":
+		return vscale.NewDefaultConfig().PropagationTimeout
+	case "vultr":
+		return vultr.NewDefaultConfig().PropagationTimeout
+	case "webnames":
+		return webnames.NewDefaultConfig().PropagationTimeout
+	case "websupport":
+		return websupport.NewDefaultConfig().PropagationTimeout
+	case "wedos":
+		return wedos.NewDefaultConfig().PropagationTimeout
+	case "westcn":
+		return westcn.NewDefaultConfig().PropagationTimeout
+	case "yandex":
+		return yandex.NewDefaultConfig().PropagationTimeout
+	case "yandex360":
+		return yandex360.NewDefaultConfig().PropagationTimeout
+	case "yandexcloud":
+		return yandexcloud.NewDefaultConfig().PropagationTimeout
+	case "zoneee":
+		return zoneee.NewDefaultConfig().PropagationTimeout
+	case "zonomi":
+		return zonomi.NewDefaultConfig().PropagationTimeout
+	default:
+		return dns01.DefaultPropagationTimeout
+	}
+}
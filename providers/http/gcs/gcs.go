@@ -0,0 +1,179 @@
+// Package gcs implements an HTTP provider for solving the HTTP-01 challenge using Google Cloud Storage.
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+)
+
+// Config is used to configure the creation of the HTTPProvider.
+type Config struct {
+	// Bucket is the name of the bucket.
+	Bucket string
+
+	// Prefix is prepended to the name of the objects (ex: `www/` when the bucket serves a site from a sub-folder).
+	Prefix string
+
+	// ServiceAccountKey is the content of a service account JSON key.
+	// If empty, and ServiceAccountFile is empty, the Application Default Credentials are used.
+	ServiceAccountKey []byte
+
+	// ServiceAccountFile is the path to a service account JSON key.
+	ServiceAccountFile string
+
+	// NoACL doesn't set an ACL on the objects.
+	// Required for the buckets with uniform bucket-level access:
+	// the objects must be readable through a bucket-level IAM policy (ex: `allUsers` with the role `roles/storage.objectViewer`).
+	NoACL bool
+
+	// Endpoint overrides the endpoint of the Cloud Storage JSON API.
+	Endpoint string
+
+	// HTTPClient is the client used to call the API.
+	// If set, the credentials are not used: the client must handle the authentication.
+	HTTPClient *http.Client
+}
+
+// HTTPProvider implements ChallengeProvider for `http-01` challenge.
+type HTTPProvider struct {
+	config  *Config
+	service *storage.Service
+}
+
+// NewHTTPProvider returns a HTTPProvider instance with a configured GCS bucket,
+// using the Application Default Credentials.
+func NewHTTPProvider(bucket string) (*HTTPProvider, error) {
+	return NewHTTPProviderConfig(&Config{Bucket: bucket})
+}
+
+// NewHTTPProviderConfig returns a HTTPProvider instance configured for Google Cloud Storage.
+func NewHTTPProviderConfig(config *Config) (*HTTPProvider, error) {
+	if config == nil {
+		return nil, errors.New("gcs: the configuration of the HTTP provider is nil")
+	}
+
+	if config.Bucket == "" {
+		return nil, errors.New("gcs: bucket name missing")
+	}
+
+	ctx := context.Background()
+
+	opts, err := clientOptions(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: unable to create Cloud Storage service: %w", err)
+	}
+
+	return &HTTPProvider{
+		config:  config,
+		service: service,
+	}, nil
+}
+
+func clientOptions(ctx context.Context, config *Config) ([]option.ClientOption, error) {
+	var opts []option.ClientOption
+
+	if config.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(config.Endpoint))
+	}
+
+	if config.HTTPClient != nil {
+		return append(opts, option.WithHTTPClient(config.HTTPClient)), nil
+	}
+
+	saKey := config.ServiceAccountKey
+
+	if len(saKey) == 0 && config.ServiceAccountFile != "" {
+		var err error
+
+		saKey, err = os.ReadFile(config.ServiceAccountFile)
+		if err != nil {
+			return nil, fmt.Errorf("gcs: unable to read Service Account file: %w", err)
+		}
+	}
+
+	if len(saKey) == 0 {
+		client, err := google.DefaultClient(ctx, storage.DevstorageReadWriteScope)
+		if err != nil {
+			return nil, fmt.Errorf("gcs: unable to get Google Cloud client: %w", err)
+		}
+
+		return append(opts, option.WithHTTPClient(client)), nil
+	}
+
+	conf, err := google.JWTConfigFromJSON(saKey, storage.DevstorageReadWriteScope)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: unable to acquire config: %w", err)
+	}
+
+	return append(opts, option.WithHTTPClient(conf.Client(ctx))), nil
+}
+
+// Present makes the token available at `HTTP01ChallengePath(token)` by creating an object in the given GCS bucket.
+func (s *HTTPProvider) Present(domain, token, keyAuth string) error {
+	object := &storage.Object{
+		Name:         s.objectName(token),
+		ContentType:  "text/plain",
+		CacheControl: "no-store",
+	}
+
+	call := s.service.Objects.Insert(s.config.Bucket, object).
+		Media(strings.NewReader(keyAuth), googleapi.ContentType("text/plain"))
+
+	if !s.config.NoACL {
+		call = call.PredefinedAcl("publicRead")
+	}
+
+	_, err := call.Context(context.Background()).Do()
+	if err != nil {
+		if !s.config.NoACL && isUniformBucketLevelAccess(err) {
+			return fmt.Errorf("gcs: the bucket %s uses uniform bucket-level access, the object ACLs are not allowed: "+
+				"grant the role roles/storage.objectViewer to allUsers on the bucket, and disable the object ACLs (NoACL): %w",
+				s.config.Bucket, err)
+		}
+
+		return fmt.Errorf("gcs: failed to upload token to the bucket %s: %w", s.config.Bucket, err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the object created for the challenge.
+func (s *HTTPProvider) CleanUp(domain, token, keyAuth string) error {
+	err := s.service.Objects.Delete(s.config.Bucket, s.objectName(token)).Context(context.Background()).Do()
+	if err != nil {
+		return fmt.Errorf("gcs: could not remove object in the bucket %s after HTTP challenge: %w", s.config.Bucket, err)
+	}
+
+	return nil
+}
+
+func (s *HTTPProvider) objectName(token string) string {
+	return path.Join(strings.Trim(s.config.Prefix, "/"), strings.Trim(http01.ChallengePath(token), "/"))
+}
+
+// isUniformBucketLevelAccess returns true if the error is the rejection of an object ACL
+// by a bucket with uniform bucket-level access.
+func isUniformBucketLevelAccess(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(apiErr.Message), "uniform bucket-level access")
+}
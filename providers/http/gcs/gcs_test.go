@@ -0,0 +1,292 @@
+package gcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	domain  = "example.com"
+	token   = "foo"
+	keyAuth = "bar"
+)
+
+var envTest = tester.NewEnvTest(
+	"GOOGLE_APPLICATION_CREDENTIALS",
+	"GCS_BUCKET")
+
+type fakeObject struct {
+	data string
+	acl  string
+}
+
+// fakeGCS is a minimal fake of the Cloud Storage JSON API (objects insert and delete).
+type fakeGCS struct {
+	mu      sync.Mutex
+	bucket  string
+	uniform bool
+	objects map[string]fakeObject
+}
+
+func setupFakeGCS(t *testing.T, bucket string, uniform bool) (*fakeGCS, *Config) {
+	t.Helper()
+
+	fake := &fakeGCS{bucket: bucket, uniform: uniform, objects: map[string]fakeObject{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /upload/storage/v1/b/{bucket}/o", fake.insert)
+	mux.HandleFunc("DELETE /storage/v1/b/{bucket}/o/{object...}", fake.delete)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	config := &Config{
+		Bucket:     bucket,
+		Endpoint:   server.URL + "/storage/v1/",
+		HTTPClient: server.Client(),
+	}
+
+	return fake, config
+}
+
+func (f *fakeGCS) insert(rw http.ResponseWriter, req *http.Request) {
+	if req.PathValue("bucket") != f.bucket {
+		writeError(rw, http.StatusNotFound, "The specified bucket does not exist.")
+		return
+	}
+
+	acl := req.URL.Query().Get("predefinedAcl")
+	if f.uniform && acl != "" {
+		writeError(rw, http.StatusBadRequest,
+			"Cannot insert legacy ACL for an object when uniform bucket-level access is enabled. Read more at https://cloud.google.com/storage/docs/uniform-bucket-level-access")
+		return
+	}
+
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reader := multipart.NewReader(req.Body, params["boundary"])
+
+	metadataPart, err := reader.NextPart()
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var object struct {
+		Name string `json:"name"`
+	}
+
+	err = json.NewDecoder(metadataPart).Decode(&object)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mediaPart, err := reader.NextPart()
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(mediaPart)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	f.objects[object.Name] = fakeObject{data: string(data), acl: acl}
+	f.mu.Unlock()
+
+	_, _ = fmt.Fprintf(rw, `{"bucket":%q,"name":%q}`, f.bucket, object.Name)
+}
+
+func (f *fakeGCS) delete(rw http.ResponseWriter, req *http.Request) {
+	name := req.PathValue("object")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.objects[name]; req.PathValue("bucket") != f.bucket || !ok {
+		writeError(rw, http.StatusNotFound, "No such object: "+name)
+		return
+	}
+
+	delete(f.objects, name)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeGCS) get(name string) (fakeObject, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[name]
+
+	return object, ok
+}
+
+func writeError(rw http.ResponseWriter, code int, message string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	_, _ = fmt.Fprintf(rw, `{"error":{"code":%d,"message":%q,"errors":[{"message":%q,"reason":"invalid"}]}}`, code, message, message)
+}
+
+func TestNewHTTPProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *Config
+		expected string
+	}{
+		{
+			desc:     "nil config",
+			expected: "gcs: the configuration of the HTTP provider is nil",
+		},
+		{
+			desc:     "missing bucket",
+			config:   &Config{},
+			expected: "gcs: bucket name missing",
+		},
+		{
+			desc:     "invalid service account key",
+			config:   &Config{Bucket: "bucket", ServiceAccountKey: []byte("{}")},
+			expected: `gcs: unable to acquire config: google: read JWT from JSON credentials: 'type' field is "" (expected "service_account")`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := NewHTTPProviderConfig(test.config)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		prefix       string
+		expectedName string
+	}{
+		{
+			desc:         "without prefix",
+			expectedName: ".well-known/acme-challenge/foo",
+		},
+		{
+			desc:         "with prefix",
+			prefix:       "/www/",
+			expectedName: "www/.well-known/acme-challenge/foo",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			fake, config := setupFakeGCS(t, "bucket", false)
+			config.Prefix = test.prefix
+
+			provider, err := NewHTTPProviderConfig(config)
+			require.NoError(t, err)
+
+			err = provider.Present(domain, token, keyAuth)
+			require.NoError(t, err)
+
+			object, ok := fake.get(test.expectedName)
+			require.True(t, ok)
+
+			assert.Equal(t, keyAuth, object.data)
+			assert.Equal(t, "publicRead", object.acl)
+
+			err = provider.CleanUp(domain, token, keyAuth)
+			require.NoError(t, err)
+
+			_, ok = fake.get(test.expectedName)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestHTTPProvider_uniformBucketLevelAccess(t *testing.T) {
+	fake, config := setupFakeGCS(t, "bucket", true)
+
+	provider, err := NewHTTPProviderConfig(config)
+	require.NoError(t, err)
+
+	err = provider.Present(domain, token, keyAuth)
+	require.Error(t, err)
+
+	assert.True(t, strings.HasPrefix(err.Error(), "gcs: the bucket bucket uses uniform bucket-level access"), err.Error())
+	assert.True(t, isUniformBucketLevelAccess(err))
+
+	config.NoACL = true
+
+	provider, err = NewHTTPProviderConfig(config)
+	require.NoError(t, err)
+
+	err = provider.Present(domain, token, keyAuth)
+	require.NoError(t, err)
+
+	object, ok := fake.get(".well-known/acme-challenge/foo")
+	require.True(t, ok)
+
+	assert.Equal(t, keyAuth, object.data)
+	assert.Empty(t, object.acl)
+}
+
+func TestHTTPProvider_CleanUp_notFound(t *testing.T) {
+	_, config := setupFakeGCS(t, "bucket", false)
+
+	provider, err := NewHTTPProviderConfig(config)
+	require.NoError(t, err)
+
+	err = provider.CleanUp(domain, token, keyAuth)
+	require.Error(t, err)
+
+	assert.True(t, strings.HasPrefix(err.Error(), "gcs: could not remove object in the bucket bucket after HTTP challenge"), err.Error())
+}
+
+func TestLiveHTTPProvider(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	bucket := envTest.GetValue("GCS_BUCKET")
+
+	provider, err := NewHTTPProvider(bucket)
+	require.NoError(t, err)
+
+	err = provider.Present(domain, token, keyAuth)
+	require.NoError(t, err)
+
+	resp, err := http.Get(fmt.Sprintf("https://storage.googleapis.com/%s/.well-known/acme-challenge/%s", bucket, token))
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, keyAuth, string(data))
+
+	err = provider.CleanUp(domain, token, keyAuth)
+	require.NoError(t, err)
+}
@@ -0,0 +1,106 @@
+package registration
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/log"
+)
+
+const telScheme = "tel:"
+
+// telNumber matches a global number (`+33 1 23 45 67 89` written `+33-1-23-45-67-89`),
+// or a local number with a phone context, and optional parameters (RFC 3966).
+var telNumber = regexp.MustCompile(`^(\+[0-9][0-9().-]*|[0-9*#][0-9*#().-]*)(;[a-zA-Z0-9-]+(=[^;]+)?)*$`)
+
+// ValidateContact checks that a contact is a `mailto:` URI with a single address (RFC 8555, section 7.3),
+// or a `tel:` URI (RFC 3966).
+func ValidateContact(contact string) error {
+	switch {
+	case strings.HasPrefix(contact, mailTo):
+		address := strings.TrimPrefix(contact, mailTo)
+
+		if strings.ContainsAny(address, "?,") {
+			return fmt.Errorf("invalid contact %q: a mailto contact must contain a single address without header fields", contact)
+		}
+
+		addr, err := mail.ParseAddress(address)
+		if err != nil || addr.Address != address {
+			return fmt.Errorf("invalid contact %q: invalid email address", contact)
+		}
+
+		return nil
+
+	case strings.HasPrefix(contact, telScheme):
+		number := strings.TrimPrefix(contact, telScheme)
+
+		if !telNumber.MatchString(number) {
+			return fmt.Errorf("invalid contact %q: invalid telephone number", contact)
+		}
+
+		if !strings.HasPrefix(number, "+") && !strings.Contains(number, ";phone-context=") {
+			return fmt.Errorf("invalid contact %q: a local telephone number requires a phone-context parameter", contact)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("invalid contact %q: unsupported scheme (mailto: or tel: expected)", contact)
+	}
+}
+
+// buildContacts returns the contacts of the account: the email of the user (if any), followed by the additional contacts.
+func buildContacts(email string, contacts []string) ([]string, error) {
+	result := []string{}
+
+	if email != "" {
+		result = append(result, mailTo+email)
+	}
+
+	for _, contact := range contacts {
+		err := ValidateContact(contact)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(result, contact) {
+			result = append(result, contact)
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateContacts replaces the contacts of the account on the ACME server
+// (`mailto:` and `tel:` URIs, see ValidateContact).
+// The email of the user is not added to the contacts.
+func (r *Registrar) UpdateContacts(contacts []string) (*Resource, error) {
+	if r == nil || r.user == nil || r.user.GetRegistration() == nil {
+		return nil, errors.New("acme: cannot update the contacts of a nil client or user")
+	}
+
+	if len(contacts) == 0 {
+		return nil, errors.New("acme: no contacts")
+	}
+
+	accContacts, err := buildContacts("", contacts)
+	if err != nil {
+		return nil, fmt.Errorf("acme: %w", err)
+	}
+
+	accountURL := r.user.GetRegistration().URI
+
+	log.Infof("acme: Updating the contacts of the account %s", accountURL)
+
+	account, err := r.core.Accounts.Update(accountURL, acme.Account{Contact: accContacts})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.newResource(accountURL, account), nil
+}
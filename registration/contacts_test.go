@@ -0,0 +1,183 @@
+package registration
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateContact(t *testing.T) {
+	testCases := []struct {
+		contact       string
+		expectedError string
+	}{
+		{contact: "mailto:admin@example.com"},
+		{contact: "tel:+33-1-23-45-67-89"},
+		{contact: "tel:+1(555)0100"},
+		{contact: "tel:7042;phone-context=example.com"},
+		{
+			contact:       "admin@example.com",
+			expectedError: `invalid contact "admin@example.com": unsupported scheme (mailto: or tel: expected)`,
+		},
+		{
+			contact:       "https://example.com/contact",
+			expectedError: `invalid contact "https://example.com/contact": unsupported scheme (mailto: or tel: expected)`,
+		},
+		{
+			contact:       "mailto:admin@example.com,ops@example.com",
+			expectedError: `invalid contact "mailto:admin@example.com,ops@example.com": a mailto contact must contain a single address without header fields`,
+		},
+		{
+			contact:       "mailto:admin@example.com?subject=acme",
+			expectedError: `invalid contact "mailto:admin@example.com?subject=acme": a mailto contact must contain a single address without header fields`,
+		},
+		{
+			contact:       "mailto:Admin <admin@example.com>",
+			expectedError: `invalid contact "mailto:Admin <admin@example.com>": invalid email address`,
+		},
+		{
+			contact:       "mailto:",
+			expectedError: `invalid contact "mailto:": invalid email address`,
+		},
+		{
+			contact:       "tel:call-me",
+			expectedError: `invalid contact "tel:call-me": invalid telephone number`,
+		},
+		{
+			contact:       "tel:7042",
+			expectedError: `invalid contact "tel:7042": a local telephone number requires a phone-context parameter`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.contact, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateContact(test.contact)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+// setupAccountServer returns a fake API recording the contacts of the account requests.
+func setupAccountServer(t *testing.T) (string, *[][]string) {
+	t.Helper()
+
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	var contacts [][]string
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		account, err := readAccount(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		contacts = append(contacts, account.Contact)
+
+		account.Status = acme.StatusValid
+
+		w.Header().Set("Location", apiURL+"/account/1")
+		_ = tester.WriteJSONResponse(w, account)
+	}
+
+	mux.HandleFunc("POST /account", handler)
+	mux.HandleFunc("POST /account/1", handler)
+
+	return apiURL, &contacts
+}
+
+func readAccount(req *http.Request) (acme.Account, error) {
+	var account acme.Account
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return account, err
+	}
+
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+
+	err = json.Unmarshal(body, &jws)
+	if err != nil {
+		return account, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return account, err
+	}
+
+	err = json.Unmarshal(payload, &account)
+
+	return account, err
+}
+
+func TestRegistrar_Register_contacts(t *testing.T) {
+	apiURL, contacts := setupAccountServer(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, mockUser{email: "admin@example.com", privatekey: key})
+
+	_, err = registrar.Register(RegisterOptions{
+		TermsOfServiceAgreed: true,
+		Contacts:             []string{"mailto:ops@example.com", "tel:+33-1-23-45-67-89", "mailto:admin@example.com"},
+	})
+	require.NoError(t, err)
+
+	_, err = registrar.Register(RegisterOptions{TermsOfServiceAgreed: true, Contacts: []string{"sms:+33123456789"}})
+	require.EqualError(t, err, `acme: invalid contact "sms:+33123456789": unsupported scheme (mailto: or tel: expected)`)
+
+	expected := [][]string{{"mailto:admin@example.com", "mailto:ops@example.com", "tel:+33-1-23-45-67-89"}}
+	assert.Equal(t, expected, *contacts)
+}
+
+func TestRegistrar_UpdateContacts(t *testing.T) {
+	apiURL, contacts := setupAccountServer(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", apiURL+"/account/1", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, mockUser{
+		email:      "admin@example.com",
+		regres:     &Resource{URI: apiURL + "/account/1"},
+		privatekey: key,
+	})
+
+	res, err := registrar.UpdateContacts([]string{"mailto:ops@example.com", "tel:+1-555-0100"})
+	require.NoError(t, err)
+
+	assert.Equal(t, apiURL+"/account/1", res.URI)
+	assert.Equal(t, [][]string{{"mailto:ops@example.com", "tel:+1-555-0100"}}, *contacts)
+
+	_, err = registrar.UpdateContacts(nil)
+	require.EqualError(t, err, "acme: no contacts")
+
+	_, err = registrar.UpdateContacts([]string{"mailto:a@example.com,b@example.com"})
+	require.EqualError(t, err, `acme: invalid contact "mailto:a@example.com,b@example.com": a mailto contact must contain a single address without header fields`)
+}
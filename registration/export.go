@@ -0,0 +1,139 @@
+package registration
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-jose/go-jose/v4"
+)
+
+const (
+	exportType    = "acme-account"
+	exportVersion = 1
+)
+
+// exportedRegistration the portable representation of a registration.
+type exportedRegistration struct {
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+
+	URI                  string   `json:"uri"`
+	Status               string   `json:"status,omitempty"`
+	Contact              []string `json:"contact,omitempty"`
+	TermsOfServiceAgreed bool     `json:"termsOfServiceAgreed,omitempty"`
+	Orders               string   `json:"orders,omitempty"`
+
+	Key *exportedKey `json:"key,omitempty"`
+}
+
+// exportedKey a reference to the account key: the key itself is never exported.
+type exportedKey struct {
+	// Thumbprint the JWK thumbprint (RFC 7638, SHA-256, base64url encoded) of the account key.
+	Thumbprint string `json:"thumbprint"`
+}
+
+// Export exports the registration in a portable, versioned JSON format.
+// The account key is not included, only a reference to it (its JWK thumbprint) when KeyThumbprint is defined.
+func (r *Resource) Export() ([]byte, error) {
+	if r.URI == "" {
+		return nil, errors.New("registration: cannot export a registration without URI")
+	}
+
+	exp := exportedRegistration{
+		Type:                 exportType,
+		Version:              exportVersion,
+		URI:                  r.URI,
+		Status:               r.Body.Status,
+		Contact:              r.Body.Contact,
+		TermsOfServiceAgreed: r.Body.TermsOfServiceAgreed,
+		Orders:               r.Body.Orders,
+	}
+
+	if r.KeyThumbprint != "" {
+		exp.Key = &exportedKey{Thumbprint: r.KeyThumbprint}
+	}
+
+	return json.MarshalIndent(exp, "", "  ")
+}
+
+// ImportRegistration imports a registration exported by Resource.Export.
+// The account key must be provided separately, KeyMatches allows to check it.
+func ImportRegistration(data []byte) (*Resource, error) {
+	var exp exportedRegistration
+
+	err := json.Unmarshal(data, &exp)
+	if err != nil {
+		return nil, fmt.Errorf("registration: invalid export: %w", err)
+	}
+
+	if exp.Type != exportType {
+		return nil, fmt.Errorf("registration: unsupported export type %q", exp.Type)
+	}
+
+	if exp.Version != exportVersion {
+		return nil, fmt.Errorf("registration: unsupported export version %d", exp.Version)
+	}
+
+	if exp.URI == "" {
+		return nil, errors.New("registration: invalid export: missing URI")
+	}
+
+	res := &Resource{
+		URI: exp.URI,
+		Body: acme.Account{
+			Status:               exp.Status,
+			Contact:              exp.Contact,
+			TermsOfServiceAgreed: exp.TermsOfServiceAgreed,
+			Orders:               exp.Orders,
+		},
+	}
+
+	if exp.Key != nil {
+		res.KeyThumbprint = exp.Key.Thumbprint
+	}
+
+	return res, nil
+}
+
+// KeyMatches returns true if the key matches the key reference of the registration.
+// Always returns true if the registration has no key reference.
+func (r *Resource) KeyMatches(privateKey crypto.PrivateKey) (bool, error) {
+	if r.KeyThumbprint == "" {
+		return true, nil
+	}
+
+	thumbprint, err := KeyThumbprint(privateKey)
+	if err != nil {
+		return false, err
+	}
+
+	return thumbprint == r.KeyThumbprint, nil
+}
+
+// KeyThumbprint computes the JWK thumbprint (RFC 7638, SHA-256, base64url encoded) of an account key.
+func KeyThumbprint(privateKey crypto.PrivateKey) (string, error) {
+	var publicKey crypto.PublicKey
+	switch k := privateKey.(type) {
+	case *ecdsa.PrivateKey:
+		publicKey = k.Public()
+	case *rsa.PrivateKey:
+		publicKey = k.Public()
+	default:
+		return "", fmt.Errorf("registration: unsupported key type %T", privateKey)
+	}
+
+	jwk := &jose.JSONWebKey{Key: publicKey}
+
+	thumbBytes, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(thumbBytes), nil
+}
@@ -0,0 +1,96 @@
+package registration
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResource_Export_roundTrip(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	thumbprint, err := KeyThumbprint(key)
+	require.NoError(t, err)
+
+	res := &Resource{
+		URI: "https://ca.example.com/acme/acct/1",
+		Body: acme.Account{
+			Status:               "valid",
+			Contact:              []string{"mailto:admin@example.com"},
+			TermsOfServiceAgreed: true,
+			Orders:               "https://ca.example.com/acme/acct/1/orders",
+		},
+		KeyThumbprint: thumbprint,
+	}
+
+	data, err := res.Export()
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+
+	assert.Equal(t, "acme-account", raw["type"])
+	assert.EqualValues(t, 1, raw["version"])
+	assert.Equal(t, map[string]any{"thumbprint": thumbprint}, raw["key"])
+
+	imported, err := ImportRegistration(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, res, imported)
+
+	match, err := imported.KeyMatches(key)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	match, err = imported.KeyMatches(otherKey)
+	require.NoError(t, err)
+	assert.False(t, match)
+}
+
+func TestImportRegistration_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		data     string
+		expected string
+	}{
+		{
+			desc:     "invalid JSON",
+			data:     `{`,
+			expected: "registration: invalid export: unexpected end of JSON input",
+		},
+		{
+			desc:     "unknown type",
+			data:     `{"type":"foo","version":1,"uri":"https://ca.example.com/acme/acct/1"}`,
+			expected: `registration: unsupported export type "foo"`,
+		},
+		{
+			desc:     "unknown version",
+			data:     `{"type":"acme-account","version":2,"uri":"https://ca.example.com/acme/acct/1"}`,
+			expected: "registration: unsupported export version 2",
+		},
+		{
+			desc:     "missing URI",
+			data:     `{"type":"acme-account","version":1}`,
+			expected: "registration: invalid export: missing URI",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ImportRegistration([]byte(test.data))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package registration
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/go-acme/lego/v4/acme"
@@ -11,22 +12,36 @@ import (
 
 const mailTo = "mailto:"
 
+// ErrAccountNotFound is returned by ResolveAccountByKey when no account exists for the account key.
+var ErrAccountNotFound = errors.New("acme: no account exists for the key")
+
 // Resource represents all important information about a registration
 // of which the client needs to keep track itself.
 // WARNING: will be removed in the future (acme.ExtendedAccount), https://github.com/go-acme/lego/issues/855.
 type Resource struct {
 	Body acme.Account `json:"body,omitempty"`
 	URI  string       `json:"uri,omitempty"`
+
+	// KeyThumbprint a reference to the account key (JWK thumbprint), used by Export.
+	KeyThumbprint string `json:"keyThumbprint,omitempty"`
 }
 
 type RegisterOptions struct {
 	TermsOfServiceAgreed bool
+
+	// Contacts are additional contacts of the account (`mailto:` and `tel:` URIs, see ValidateContact),
+	// after the email of the user.
+	Contacts []string
 }
 
 type RegisterEABOptions struct {
 	TermsOfServiceAgreed bool
 	Kid                  string
 	HmacEncoded          string
+
+	// Contacts are additional contacts of the account (`mailto:` and `tel:` URIs, see ValidateContact),
+	// after the email of the user.
+	Contacts []string
 }
 
 type Registrar struct {
@@ -49,14 +64,19 @@ func (r *Registrar) Register(options RegisterOptions) (*Resource, error) {
 
 	accMsg := acme.Account{
 		TermsOfServiceAgreed: options.TermsOfServiceAgreed,
-		Contact:              []string{},
 	}
 
 	if r.user.GetEmail() != "" {
 		log.Infof("acme: Registering account for %s", r.user.GetEmail())
-		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
 	}
 
+	contacts, err := buildContacts(r.user.GetEmail(), options.Contacts)
+	if err != nil {
+		return nil, fmt.Errorf("acme: %w", err)
+	}
+
+	accMsg.Contact = contacts
+
 	account, err := r.core.Accounts.New(accMsg)
 	if err != nil {
 		// seems impossible
@@ -66,21 +86,26 @@ func (r *Registrar) Register(options RegisterOptions) (*Resource, error) {
 		}
 	}
 
-	return &Resource{URI: account.Location, Body: account.Account}, nil
+	return r.newResource(account.Location, account.Account), nil
 }
 
 // RegisterWithExternalAccountBinding Register the current account to the ACME server.
 func (r *Registrar) RegisterWithExternalAccountBinding(options RegisterEABOptions) (*Resource, error) {
 	accMsg := acme.Account{
 		TermsOfServiceAgreed: options.TermsOfServiceAgreed,
-		Contact:              []string{},
 	}
 
 	if r.user.GetEmail() != "" {
 		log.Infof("acme: Registering account for %s", r.user.GetEmail())
-		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
 	}
 
+	contacts, err := buildContacts(r.user.GetEmail(), options.Contacts)
+	if err != nil {
+		return nil, fmt.Errorf("acme: %w", err)
+	}
+
+	accMsg.Contact = contacts
+
 	account, err := r.core.Accounts.NewEAB(accMsg, options.Kid, options.HmacEncoded)
 	if err != nil {
 		// seems impossible
@@ -90,7 +115,7 @@ func (r *Registrar) RegisterWithExternalAccountBinding(options RegisterEABOption
 		}
 	}
 
-	return &Resource{URI: account.Location, Body: account.Account}, nil
+	return r.newResource(account.Location, account.Account), nil
 }
 
 // QueryRegistration runs a POST request on the client's registration and returns the result.
@@ -110,11 +135,8 @@ func (r *Registrar) QueryRegistration() (*Resource, error) {
 		return nil, err
 	}
 
-	return &Resource{
-		Body: account,
-		// Location: header is not returned so this needs to be populated off of existing URI
-		URI: r.user.GetRegistration().URI,
-	}, nil
+	// Location: header is not returned so this needs to be populated off of existing URI
+	return r.newResource(r.user.GetRegistration().URI, account), nil
 }
 
 // UpdateRegistration update the user registration on the ACME server.
@@ -125,14 +147,19 @@ func (r *Registrar) UpdateRegistration(options RegisterOptions) (*Resource, erro
 
 	accMsg := acme.Account{
 		TermsOfServiceAgreed: options.TermsOfServiceAgreed,
-		Contact:              []string{},
 	}
 
 	if r.user.GetEmail() != "" {
 		log.Infof("acme: Registering account for %s", r.user.GetEmail())
-		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
 	}
 
+	contacts, err := buildContacts(r.user.GetEmail(), options.Contacts)
+	if err != nil {
+		return nil, fmt.Errorf("acme: %w", err)
+	}
+
+	accMsg.Contact = contacts
+
 	accountURL := r.user.GetRegistration().URI
 
 	account, err := r.core.Accounts.Update(accountURL, accMsg)
@@ -140,7 +167,7 @@ func (r *Registrar) UpdateRegistration(options RegisterOptions) (*Resource, erro
 		return nil, err
 	}
 
-	return &Resource{URI: accountURL, Body: account}, nil
+	return r.newResource(accountURL, account), nil
 }
 
 // DeleteRegistration deletes the client's user registration from the ACME server.
@@ -156,14 +183,34 @@ func (r *Registrar) DeleteRegistration() error {
 
 // ResolveAccountByKey will attempt to look up an account using the given account key
 // and return its registration resource.
+//
+// The account is never created (onlyReturnExisting):
+// this allows several processes to share an account created by one of them, from the account key only.
+// ErrAccountNotFound is returned if no account exists for the key.
 func (r *Registrar) ResolveAccountByKey() (*Resource, error) {
 	log.Infof("acme: Trying to resolve account by key")
 
 	accMsg := acme.Account{OnlyReturnExisting: true}
 	account, err := r.core.Accounts.New(accMsg)
 	if err != nil {
+		var problem *acme.ProblemDetails
+		if errors.As(err, &problem) && problem.Type == acme.AccountDoesNotExistErr {
+			return nil, fmt.Errorf("%w: %w", ErrAccountNotFound, err)
+		}
+
 		return nil, err
 	}
 
-	return &Resource{URI: account.Location, Body: account.Account}, nil
+	return r.newResource(account.Location, account.Account), nil
+}
+
+func (r *Registrar) newResource(uri string, account acme.Account) *Resource {
+	res := &Resource{URI: uri, Body: account}
+
+	if r.user != nil {
+		// The thumbprint is only a reference to the key, an error is not blocking.
+		res.KeyThumbprint, _ = KeyThumbprint(r.user.GetPrivateKey())
+	}
+
+	return res
 }
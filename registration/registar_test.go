@@ -3,6 +3,7 @@ package registration
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -46,3 +47,28 @@ func TestRegistrar_ResolveAccountByKey(t *testing.T) {
 
 	assert.Equal(t, "valid", res.Body.Status, "Unexpected account status")
 }
+
+func TestRegistrar_ResolveAccountByKey_notFound(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("/account", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		_ = json.NewEncoder(w).Encode(acme.ProblemDetails{
+			Type:   "urn:ietf:params:acme:error:accountDoesNotExist",
+			Detail: "unable to find existing account for only-return-existing request",
+		})
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 512)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, mockUser{privatekey: key})
+
+	_, err = registrar.ResolveAccountByKey()
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}